@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"stock_data/internal/api"
 	"stock_data/internal/config"
 	"stock_data/internal/database"
@@ -17,6 +20,13 @@ import (
 	"time"
 )
 
+// @title Tushare 数据采集系统 API
+// @version 1.0
+// @description 从 Tushare 抓取股票行情数据并提供查询接口
+// @BasePath /api/v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	cfg, err := config.LoadConfig("./config/config.yaml")
 	if err != nil {
@@ -35,27 +45,94 @@ func main() {
 	}(logger)
 	logger.Info("配置加载成功")
 
+	// 日期解析使用与数据库会话一致的时区（已在加载配置时校验）
+	loc, _ := cfg.Database.Location()
+	service.SetLocation(loc)
+
 	// 初始化数据库
-	if err := database.InitDB(&cfg.Database); err != nil {
+	if err := database.InitDB(&cfg.Database, logger); err != nil {
 		logger.Fatal("初始化数据库失败", zap.Error(err))
 	}
 	defer database.Close()
 
 	// 创建 Tushare 客户端
-	tushareClient := service.NewTushareClient(&cfg.Tushare)
+	tushareClient := service.NewTushareClient(&cfg.Tushare, logger)
+	if cfg.Tushare.RawResponses {
+		store := service.NewRawResponseStore(database.GetDB(), &cfg.Tushare, logger)
+		if err := store.Migrate(); err != nil {
+			logger.Fatal("创建 raw_responses 表失败", zap.Error(err))
+		}
+		tushareClient.SetRecorder(store)
+		logger.Warn("已开启 Tushare 原始响应保存，仅用于排查数据问题",
+			zap.Int("max_count", cfg.Tushare.RawResponseMaxCount),
+			zap.Int("max_age_days", cfg.Tushare.RawResponseMaxAge))
+	}
 	logger.Info("Tushare 客户端初始化成功")
 
-	// 创建数据抓取服务
-	dataFetcher := service.NewDataFetcher(tushareClient, &cfg.Fetcher, logger)
+	// 创建新增的数据表、任务检查点表及最新行情快照表，升级后首次启动时按已有日线回填快照；只读模式下不建表
+	if !cfg.Server.ReadOnly {
+		if err := service.MigrateDataTables(database.GetDB()); err != nil {
+			logger.Fatal("创建数据表失败", zap.Error(err))
+		}
+		if err := service.MigrateStockLatest(database.GetDB()); err != nil {
+			logger.Fatal("创建 stock_latest 表失败", zap.Error(err))
+		}
+	}
+
+	// 创建数据抓取服务，任务执行中带 task_id 的警告及错误日志同时写入 task_logs；只读模式下不执行任务
+	fetcherLogger := logger
+	if !cfg.Server.ReadOnly {
+		if err := service.MigrateTaskLogs(database.GetDB()); err != nil {
+			logger.Fatal("创建 task_logs 表失败", zap.Error(err))
+		}
+		fetcherLogger = logger.WithOptions(service.TaskLogOption(database.GetDB()))
+	}
+	dataFetcher := service.NewDataFetcher(tushareClient, &cfg.Fetcher, fetcherLogger)
+	if cfg.Notify.WebhookURL != "" {
+		dataFetcher.AddListener(service.NewWebhookNotifier(&cfg.Notify, logger))
+		logger.Info("已启用任务结束通知", zap.Strings("events", cfg.Notify.Events))
+	}
+
+	if cfg.Fetcher.RecordFailures && !cfg.Server.ReadOnly {
+		if err := dataFetcher.MigrateFailures(); err != nil {
+			logger.Fatal("创建 fetch_failures 表失败", zap.Error(err))
+		}
+	}
+
+	// 启动抓取作业队列，继续执行上次退出时未完成的作业；只读模式下不执行作业
+	jobQueue := service.NewJobQueue(dataFetcher, cfg.Fetcher.JobWorkers, logger)
+	if cfg.Server.ReadOnly {
+		logger.Warn("服务处于只读模式，抓取和写入接口已禁用")
+	} else {
+		if err := jobQueue.Migrate(); err != nil {
+			logger.Fatal("创建 fetch_jobs 表失败", zap.Error(err))
+		}
+		if err := jobQueue.Start(); err != nil {
+			logger.Fatal("启动作业队列失败", zap.Error(err))
+		}
+	}
+
+	// 定时刷新股票列表，及时发现新上市的股票；只读模式下不刷新
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+	if cfg.Fetcher.StockBasicRefresh > 0 && !cfg.Server.ReadOnly {
+		go dataFetcher.RunStockBasicRefresh(refreshCtx, time.Duration(cfg.Fetcher.StockBasicRefresh)*time.Hour)
+		logger.Info("已启用股票列表定时刷新", zap.Int("interval_hours", cfg.Fetcher.StockBasicRefresh))
+	}
+
+	if len(cfg.Server.APIKeys) == 0 && !cfg.Server.ReadOnly {
+		logger.Warn("未配置 server.api_keys，抓取、删除和维护接口无需鉴权即可调用")
+	}
 
 	// 设置 Gin 模式
 	gin.SetMode(cfg.Server.Mode)
 
 	// 创建 Gin 引擎
 	r := gin.Default()
+	r.Use(api.BodyLimit(cfg.Server.MaxBodyBytes, api.DailyImportRoute))
 
 	// 创建 API 处理器
-	handler := api.NewHandler(dataFetcher, logger)
+	handler := api.NewHandler(dataFetcher, jobQueue, &cfg.Server, logger)
 	handler.RegisterRoutes(r)
 
 	// 启动服务器
@@ -86,36 +163,61 @@ func main() {
 		logger.Fatal("服务器强制关闭", zap.Error(err))
 	}
 
+	// 等待执行中的作业结束，超时后中断，重启后继续执行
+	jobCtx, jobCancel := context.WithTimeout(context.Background(), time.Duration(cfg.Fetcher.ShutdownTimeout)*time.Second)
+	defer jobCancel()
+	if err := jobQueue.Shutdown(jobCtx); err != nil {
+		logger.Warn("作业未在 shutdown_timeout 内结束，已中断", zap.Error(err))
+	}
+
 	logger.Info("服务器已关闭")
 }
 
 // initLogger 初始化日志
 func initLogger(cfg config.LogConfig) (*zap.Logger, error) {
-	// 创建日志目录
-	if err := os.MkdirAll("./logs", 0755); err != nil {
-		return nil, err
-	}
-
-	// 配置日志
-	zapCfg := zap.NewProductionConfig()
-	zapCfg.OutputPaths = []string{
-		"stdout",
-		cfg.File,
-	}
-
 	// 设置日志级别
+	var level zapcore.Level
 	switch cfg.Level {
 	case "debug":
-		zapCfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		level = zap.DebugLevel
 	case "info":
-		zapCfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		level = zap.InfoLevel
 	case "warn":
-		zapCfg.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+		level = zap.WarnLevel
 	case "error":
-		zapCfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+		level = zap.ErrorLevel
+	default:
+		level = zap.InfoLevel
+	}
+
+	// 设置日志格式
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch cfg.Format {
+	case "console":
+		encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
 	default:
-		zapCfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	}
+
+	// 输出到 stdout，配置了文件时同时输出到按大小切割的日志文件
+	writers := []zapcore.WriteSyncer{zapcore.AddSync(os.Stdout)}
+	if cfg.File != "" {
+		// 创建日志目录
+		if err := os.MkdirAll(filepath.Dir(cfg.File), 0755); err != nil {
+			return nil, err
+		}
+		writers = append(writers, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		}))
 	}
 
-	return zapCfg.Build()
+	core := zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(writers...), zap.NewAtomicLevelAt(level))
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)), nil
 }
@@ -0,0 +1,6191 @@
+// Package swagger Code generated by swaggo/swag. DO NOT EDIT
+package swagger
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/normalize-dates": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "检查 stock_basic.list_date 是否均为有效的 YYYYMMDD，返回格式不规范的记录；fix 为 true 时将可识别的其他格式（如 2023-12-01、2023/12/1）改写为 YYYYMMDD，无法解析的值保持不变",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "维护"
+                ],
+                "summary": "规范化上市日期",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否写回规范化后的值，默认只检查",
+                        "name": "fix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.NormalizeDatesResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/verify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "从 Tushare 实时拉取指定股票和交易日的日线，与数据库中的记录逐字段比较，返回差值超过 tolerance 的字段；Tushare 的值先按列声明的小数位数舍入后再比较，不修改数据库",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "维护"
+                ],
+                "summary": "核对日线数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码，如 000001.SZ",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.000001,
+                        "description": "允许的绝对误差",
+                        "name": "tolerance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.VerifyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/adj-factor": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询复权因子",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockAdjFactor"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/block-trade": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询大宗交易数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.BlockTrade"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/calendar": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询交易日历",
+                "parameters": [
+                    {
+                        "enum": [
+                            "SSE",
+                            "SZSE"
+                        ],
+                        "type": "string",
+                        "description": "交易所",
+                        "name": "exchange",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            0,
+                            1
+                        ],
+                        "type": "integer",
+                        "description": "是否交易 0休市 1交易",
+                        "name": "is_open",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.TradeCalendar"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/calendar/next": {
+            "get": {
+                "description": "返回 from 之后（不含 from）的 count 个交易日，任一交易所开市即为交易日。优先使用已存储的交易日历，不足或有缺失时从 Tushare 抓取交易日历并保存（只读模式下不抓取）；\n交易所尚未发布足够远的日历时返回的交易日少于 count 个",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询后续交易日",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始日期 YYYYMMDD，默认今天",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "交易日数量，最大 250",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/coverage": {
+            "get": {
+                "description": "按股票汇总 stock_daily 中最早/最新交易日期及行数，用于判断哪些股票需要补抓",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "日线数据覆盖情况",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码，多个用逗号分隔",
+                        "name": "ts_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.StockCoverage"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily": {
+            "get": {
+                "description": "默认按 page 分页（OFFSET），页数很深时变慢。传 cursor 参数时改为按 (trade_date, id) 倒序的游标分页，\ndata 为 CursorResult（list、next_cursor），不返回总数，next_cursor 为空表示已无更多数据",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "获取日线数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "游标分页：首页传空值（cursor=），之后传上一页返回的 next_cursor；不传时按 page 分页",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockDaily"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "在事务中删除日期范围内（可指定股票）的日线/周线/月线数据，需传 confirm=true 确认",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "删除行情数据",
+                "parameters": [
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "数据类型",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "确认删除，必须为 true",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/adjusted": {
+            "get": {
+                "description": "由已存储的日线和复权因子计算前复权/后复权价格，缺失因子的交易日沿用之前最近的因子",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询复权日线",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "qfq",
+                            "hfq"
+                        ],
+                        "type": "string",
+                        "description": "复权方式",
+                        "name": "adj",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.AdjustedResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/anomalies": {
+            "get": {
+                "description": "找出 |close - pre_close - change| 超过 epsilon 或 pct_chg 与计算值相差超过 pct_tolerance 的日线记录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "日线一致性检查",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码，为空时检查全部股票",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.01,
+                        "description": "涨跌额容差",
+                        "name": "epsilon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.01,
+                        "description": "涨跌幅容差（百分点）",
+                        "name": "pct_tolerance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.AnomaliesResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/candles": {
+            "get": {
+                "description": "按交易日期升序返回 [timestamp, open, high, low, close, volume] 数组，数量超过 limit 时通过 next_start_date 翻页",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "日 K 线数组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "最多返回的 K 线数量，超过 max_page_size 时取上限",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.CandlesResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/changes": {
+            "get": {
+                "description": "按 updated_at、id 升序返回 since 之后写入或更新的日线数据，使用 next_cursor 翻页",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "日线增量数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始时间 RFC3339，如 2023-12-01T00:00:00Z",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页返回的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.CursorResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockDaily"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/correlation": {
+            "get": {
+                "description": "基于已存储的未复权日线，取两只股票都有收盘价的共同交易日，按相邻共同交易日的收盘价计算对数收益 ln(close/前一共同交易日 close)，返回两组收益的 Pearson 相关系数；一只股票停牌的日期不参与对齐，复牌后的收益跨越停牌期间，两只股票按同一区间计算。收益对少于 3 个或任一股票收益没有波动时返回 422",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "收益相关系数",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码 A",
+                        "name": "ts_code_a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码 B",
+                        "name": "ts_code_b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.CorrelationResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/export": {
+            "get": {
+                "description": "按过滤条件流式导出日线数据，format=ndjson 时每行一个 JSON 对象，format=csv 时输出 CSV，format=parquet 时输出 Parquet 文件",
+                "produces": [
+                    "text/plain",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "导出日线数据",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "导出格式",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导出数据",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "以 multipart/form-data 上传 CSV（字段名 file），逐行解析后按 fetcher.insert_mode 分批写入，不会将整个文件读入内存。\n表头必须包含 ts_code、trade_date，其余列为 open、high、low、close、pre_close、change、pct_chg、vol、amount 中的任意几列，与 GET /data/daily/export?format=csv 的列一致；\n数值为空表示 null。解析失败的行跳过并在 errors 中返回行号（最多 100 条），表头错误返回 400，写入数据库失败时停止导入并返回 500",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "导入日线数据",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "日线 CSV 文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.DailyImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.DailyImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/ma": {
+            "get": {
+                "description": "基于已存储的日线收盘价计算 SMA，会额外加载区间前的数据作为回看窗口",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "计算日线移动平均",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "5,10,20",
+                        "description": "均线窗口，逗号分隔",
+                        "name": "windows",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.MAResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/returns": {
+            "get": {
+                "description": "基于已存储的未复权日线计算区间收益（首日开盘到末日收盘、首日收盘到末日收盘）、年化收益和最大回撤；收盘价缺失的交易日不参与计算，交易日不足两个时相关指标为 null",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "区间收益统计",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否返回逐日收益",
+                        "name": "daily",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.ReturnsResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/daily/volatility": {
+            "get": {
+                "description": "基于已存储的未复权日线计算日对数收益 ln(close/pre_close) 的样本标准差，annualize=true 时乘以 √252 折算为年化波动率；使用当日昨收价计算收益，停牌、漏抓造成的缺口不会把多日涨跌算作一日；昨收价缺失时使用前一交易日的收盘价，两者都没有的交易日跳过；有效收益少于 2 个时返回 422",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "区间波动率",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否年化",
+                        "name": "annualize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.VolatilityResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/dimensions": {
+            "get": {
+                "description": "返回 stock_basic 中非空的行业、地域取值及股票数，结果缓存 5 分钟",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "获取行业与地域筛选值",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.Dimensions"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/data/fund/daily": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询场内基金日线",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "基金代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "基金代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.FundDaily"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/funds": {
+            "get": {
+                "description": "分页查询已抓取的基金基本信息，可按市场、投资类型、存续状态、管理人过滤，name 按简称模糊匹配",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询基金列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "交易市场 E 场内、O 场外",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "投资类型",
+                        "name": "fund_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "存续状态 D 摘牌、I 发行、L 已上市",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "管理人",
+                        "name": "management",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "简称关键字",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.FundBasic"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/health/coverage": {
+            "get": {
+                "description": "以 stock_basic 中上市状态的股票为准，关联 stock_daily 的最新交易日期，按交易日历确定的最近交易日分为缺失、过期、完整三类",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "日线数据覆盖健康度",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.CoverageHealth"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/indices": {
+            "get": {
+                "description": "分页查询已抓取的指数基本信息，可按市场、类别、发布方过滤，name 按简称模糊匹配",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询指数列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "市场",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "指数类别",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "发布方",
+                        "name": "publisher",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "简称关键字",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.PageResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/data/latest": {
+            "get": {
+                "description": "从 stock_latest 读取每只股票最新一条日线，按 ts_code 升序；快照随日线写入更新，无需对 stock_daily 分组查询",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "最新行情快照",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockLatest"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/latest-date": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "获取最新交易日期",
+                "parameters": [
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "数据类型",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/margin": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询融资融券数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.MarginDetail"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/data/new-listings": {
+            "get": {
+                "description": "返回 stock_basic 中 list_date \u003e= since 的股票，按上市日期倒序、股票代码升序。股票列表由 fetcher.stock_basic_refresh 定时刷新或 POST /fetch/stock-basic 手动刷新，刷新后新股才会出现",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "新上市股票",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始上市日期 YYYYMMDD",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockBasic"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/stock/{ts_code}": {
+            "get": {
+                "description": "返回股票基本信息，已抓取上市公司信息时一并返回 company 字段",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "获取股票详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.StockInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/stocks": {
+            "get": {
+                "description": "支持按行业、地域、市场筛选，按白名单字段排序；存在 daily_basic（带表名前缀）且含 total_mv 列时可按最新总市值排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "获取股票列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "概念代码",
+                        "name": "concept",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "申万行业指数代码",
+                        "name": "industry_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "行业",
+                        "name": "industry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "地域",
+                        "name": "area",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "市场类型",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上市状态",
+                        "name": "list_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "为 true 时只返回当前在沪深股通名单中的股票",
+                        "name": "hs_connect",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "SH",
+                            "SZ"
+                        ],
+                        "type": "string",
+                        "description": "配合 hs_connect 只筛选沪股通 SH 或深股通 SZ",
+                        "name": "hs_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ts_code",
+                            "symbol",
+                            "name",
+                            "list_date",
+                            "industry",
+                            "area",
+                            "market",
+                            "total_mv"
+                        ],
+                        "type": "string",
+                        "default": "ts_code",
+                        "description": "排序字段",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "排序方向",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.StockBasic"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/top-list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据"
+                ],
+                "summary": "查询龙虎榜数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码列表，逗号分隔",
+                        "name": "ts_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.TopListEntry"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/adj-factor": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日异步抓取全部股票的复权因子，无数据的日期视为成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取复权因子",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/block-trade": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日异步抓取大宗交易明细，每个交易日整体替换已有记录，无大宗交易的日期视为成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取大宗交易数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/calendar": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "抓取沪深交易所在日期范围内的交易日历（含休市日及上一个交易日）并保存，重复抓取会更新已有记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取交易日历",
+                "parameters": [
+                    {
+                        "description": "抓取参数，只使用 start_date、end_date",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/concepts": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "异步抓取概念分类与申万一级行业成分",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取概念及行业分类",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日抓取全市场日线数据：作业加入队列后返回，由后台 worker 执行，返回的 task_id 为入队时创建的任务，可直接查询进度；dry_run 为 true 时返回任务预估 service.FetchPlan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取日线数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次作业",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "排队中的作业",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily-adj": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按股票异步调用 Tushare pro_bar 抓取日期范围内的日线，adj 为 qfq/hfq 时价格已由 Tushare 复权，保存到 stock_daily_adj。ts_codes 为空时抓取全部股票",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取复权日线",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily/date/{trade_date}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "删除指定交易日已存储的日线数据并重新抓取写入，Tushare 未返回数据时报错并保留已有数据",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "重新抓取单日日线数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "交易日期 YYYYMMDD",
+                        "name": "trade_date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily/resume/{task_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "继续执行中断的日线抓取任务，只抓取尚未完成的日期",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "断点续传日线抓取任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回日线抓取任务的进度及已发起的 Tushare 请求数（api_calls），运行中的任务同时返回预计还需的请求数（estimated_remaining_calls），\n按已完成日期/股票的平均请求数乘以剩余数量（total_count - success_count - failed_count）估算。不指定 task_id 时返回最近启动的日线任务",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "查询日线抓取任务状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "日线任务ID，如 task_1701417600",
+                        "name": "task_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchTask"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/daily/sync": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日逐日调用 Tushare（受限流控制），数据直接在响应中返回，不写入数据库；日期范围最多 60 个交易日",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "同步抓取单只股票日线",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SyncFetchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.SyncFetchResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/failures": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回 fetch_failures 中的失败单元及最后一次错误，按记录时间倒序。需开启 fetcher.record_failures；日线任务续传时清除该任务的记录，仍失败的日期重新记录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "查询抓取失败记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "task_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "股票代码",
+                        "name": "ts_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.FetchFailure"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/fina-indicator": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取财务指标数据",
+                "parameters": [
+                    {
+                        "description": "报告期范围",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/fund-basic": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "抓取基金列表（fund_basic），已存在的基金按 ts_code 更新",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取基金基本信息",
+                "parameters": [
+                    {
+                        "enum": [
+                            "E",
+                            "O"
+                        ],
+                        "type": "string",
+                        "description": "交易市场 E 场内、O 场外，为空时抓取场内基金",
+                        "name": "market",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/fund-daily": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日异步抓取全部场内基金（ETF、LOF 等）的日线行情（fund_daily），无数据的日期视为成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取场内基金日线",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/hs-const": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "抓取沪股通、深股通的当前成分及已剔除的历史记录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取沪深股通成分",
+                "parameters": [
+                    {
+                        "enum": [
+                            "SH",
+                            "SZ"
+                        ],
+                        "type": "string",
+                        "description": "类型 SH 沪股通、SZ 深股通，为空抓取两者",
+                        "name": "hs_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/index-basic": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取指数基本信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "市场 SSE/SZSE/CSI/SW 等，为空抓取全部市场",
+                        "name": "market",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/jobs/{job_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回作业状态（queued/running/completed/failed）；task_id 为关联的抓取任务，日线作业入队时即有值，可用于查询进度",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "查询抓取作业",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "作业ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/margin": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日异步抓取融资融券交易明细，无数据的日期视为成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取融资融券数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/minute": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按股票、交易日异步抓取分钟线数据",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取分钟线数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MinuteFetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/monthly": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "作业加入队列后返回，由后台 worker 执行，用 job_id 查询作业状态及关联的 task_id；dry_run 为 true 时返回任务预估 service.FetchPlan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取月线数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次作业",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "排队中的作业",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/progress/{task_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "查询抓取进度",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchTask"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/running": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回所有 status 为 running 的任务及其进度、已运行时长，以及全局任务名额占用情况",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "获取运行中的任务",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.RunningTasks"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/stock-basic": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "默认按 fetcher.stock_list_status 抓取；all 为 true 时按 fetcher.stock_list_statuses 依次抓取上市、退市、暂停上市的股票并合并保存，返回各状态的股票数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取股票基本信息",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否抓取全部上市状态",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/stock-company": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按股票列表异步抓取上市公司基本信息（注册资本、员工人数、经营范围等）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取上市公司信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "根据任务检查点按日、周（ISO 周）或月汇总日线、周线、月线抓取的成功/失败日期数，同一日期以最近一次抓取结果为准，没有抓取记录的周期不返回",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "抓取结果汇总",
+                "parameters": [
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "description": "数据类型",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期 YYYYMMDD",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期 YYYYMMDD",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "获取任务列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.FetchTask"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/tasks/{task_id}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回任务执行期间记录的警告及错误日志（如单个日期或股票抓取失败的原因），按记录顺序排列；fields 为日志的其余字段（JSON），如 date、ts_code、error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务"
+                ],
+                "summary": "查询任务日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "日志级别：warn、error",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，超过上限时取上限",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/api.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.TaskLog"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/top-list": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日异步抓取龙虎榜每日明细，无上榜股票的日期视为成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取龙虎榜数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run 为 true 时返回任务预估",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.FetchPlan"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/tushare/check": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "发起一次单日 trade_cal 请求，返回 Token 是否有效及 Tushare 的错误码和信息；无法连接 Tushare 时返回 502",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "校验 Tushare Token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TokenCheck"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/weekly": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "作业加入队列后返回，由后台 worker 执行，用 job_id 查询作业状态及关联的 task_id；dry_run 为 true 时返回任务预估 service.FetchPlan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "抓取周线数据",
+                "parameters": [
+                    {
+                        "description": "抓取参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次作业",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "排队中的作业",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FetchJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/fetch/weekly/derive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "按交易日历将已存储的日线按 ISO 周聚合写入周线表，不消耗 Tushare 行情接口额度；不生成复权价格",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "抓取"
+                ],
+                "summary": "由日线聚合周线",
+                "parameters": [
+                    {
+                        "description": "聚合参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FetchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，24 小时内相同的键只创建一次任务",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.DeriveWeeklyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "数据统计概览",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.AdjustedBar": {
+            "type": "object",
+            "properties": {
+                "adj_factor": {
+                    "description": "使用的复权因子，之前没有任何因子时为 null",
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "pre_close": {
+                    "type": "number"
+                },
+                "trade_date": {
+                    "type": "string"
+                },
+                "vol": {
+                    "description": "成交量（手），不复权",
+                    "type": "number"
+                }
+            }
+        },
+        "api.AdjustedResult": {
+            "type": "object",
+            "properties": {
+                "adj": {
+                    "type": "string"
+                },
+                "base_factor": {
+                    "description": "前复权的基准因子（区间内最后一个交易日的因子），后复权为 null",
+                    "type": "number"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AdjustedBar"
+                    }
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AnomaliesResult": {
+            "type": "object",
+            "properties": {
+                "anomalies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DailyAnomaly"
+                    }
+                },
+                "checked": {
+                    "description": "参与检查的记录数",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CandlesResult": {
+            "type": "object",
+            "properties": {
+                "candles": {
+                    "description": "timestamp 为交易日 00:00 UTC 的毫秒时间戳，价格缺失时为 null",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {}
+                    }
+                },
+                "next_start_date": {
+                    "description": "超出 limit 时下一页的 start_date，为空表示已无更多数据",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CorrelationResult": {
+            "type": "object",
+            "properties": {
+                "bars_a": {
+                    "description": "区间内 ts_code_a 有收盘价的交易日数",
+                    "type": "integer"
+                },
+                "bars_b": {
+                    "description": "区间内 ts_code_b 有收盘价的交易日数",
+                    "type": "integer"
+                },
+                "common_dates": {
+                    "description": "两只股票都有收盘价的交易日数",
+                    "type": "integer"
+                },
+                "correlation": {
+                    "description": "Pearson 相关系数，-1 到 1",
+                    "type": "number"
+                },
+                "end_date": {
+                    "description": "区间内最后一个共同交易日",
+                    "type": "string"
+                },
+                "sample_size": {
+                    "description": "参与计算的收益对数",
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "区间内第一个共同交易日",
+                    "type": "string"
+                },
+                "ts_code_a": {
+                    "type": "string"
+                },
+                "ts_code_b": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CoverageHealth": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "description": "已有 expected_latest_date 的日线",
+                    "type": "integer"
+                },
+                "expected_latest_date": {
+                    "description": "按交易日历截至今天的最近交易日",
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "没有任何日线数据",
+                    "type": "integer"
+                },
+                "stale": {
+                    "description": "最新日线早于 expected_latest_date",
+                    "type": "integer"
+                },
+                "total_stocks": {
+                    "description": "上市状态的股票数",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CursorResult": {
+            "type": "object",
+            "properties": {
+                "list": {},
+                "next_cursor": {
+                    "description": "为空表示已无更多数据",
+                    "type": "string"
+                }
+            }
+        },
+        "api.DailyAnomaly": {
+            "type": "object",
+            "properties": {
+                "anomalies": {
+                    "description": "不一致的字段：change、pct_chg",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "change": {
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "expected_change": {
+                    "description": "close - pre_close",
+                    "type": "number"
+                },
+                "expected_pct_chg": {
+                    "description": "昨收为 0 时为 null",
+                    "type": "number"
+                },
+                "pct_chg": {
+                    "type": "number"
+                },
+                "pre_close": {
+                    "type": "number"
+                },
+                "trade_date": {
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DailyReturn": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "cumulative_return": {
+                    "description": "相对区间首日收盘价的累计收益",
+                    "type": "number"
+                },
+                "return": {
+                    "description": "相对上一个交易日收盘价的收益，首日为 0",
+                    "type": "number"
+                },
+                "trade_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DeriveWeeklyResult": {
+            "type": "object",
+            "properties": {
+                "limitation": {
+                    "description": "聚合结果的局限说明",
+                    "type": "string"
+                }
+            }
+        },
+        "api.DimensionValue": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.Dimensions": {
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DimensionValue"
+                    }
+                },
+                "industries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DimensionValue"
+                    }
+                }
+            }
+        },
+        "api.FetchRequest": {
+            "type": "object",
+            "properties": {
+                "adj": {
+                    "description": "复权方式 qfq/hfq，为空不复权，仅复权日线抓取支持",
+                    "type": "string"
+                },
+                "concurrency": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "仅预估任务规模，不创建任务",
+                    "type": "boolean"
+                },
+                "end_date": {
+                    "description": "为空时使用 fetcher.end_date，未配置则为当天",
+                    "type": "string"
+                },
+                "newest_first": {
+                    "description": "从最近的日期开始抓取，仅日线抓取支持",
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "description": "为空时使用 fetcher.start_date",
+                    "type": "string"
+                },
+                "ts_codes": {
+                    "description": "指定股票代码，仅按股票抓取的接口支持（如财务指标）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段名，与请求 JSON 中的名称一致",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "错误说明",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "未通过的校验规则，如 required、oneof、type、format",
+                    "type": "string"
+                }
+            }
+        },
+        "api.MAPoint": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "ma": {
+                    "description": "键为 ma5、ma10 等，数据不足时为 null",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "trade_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.MAResult": {
+            "type": "object",
+            "properties": {
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MAPoint"
+                    }
+                },
+                "ts_code": {
+                    "type": "string"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.MinuteFetchRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "freq",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "freq": {
+                    "type": "string",
+                    "enum": [
+                        "1min",
+                        "5min",
+                        "15min",
+                        "30min",
+                        "60min"
+                    ]
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.PageResult": {
+            "type": "object",
+            "properties": {
+                "list": {},
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "errors": {
+                    "description": "参数校验失败时逐项列出出错的字段",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReturnsResult": {
+            "type": "object",
+            "properties": {
+                "annualized_return": {
+                    "description": "按每年 252 个交易日折算的 close_to_close_return",
+                    "type": "number"
+                },
+                "bars": {
+                    "description": "参与计算的交易日数",
+                    "type": "integer"
+                },
+                "close_to_close_return": {
+                    "description": "首日收盘价到末日收盘价",
+                    "type": "number"
+                },
+                "daily": {
+                    "description": "daily=true 时返回逐日收益",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DailyReturn"
+                    }
+                },
+                "drawdown_peak_date": {
+                    "description": "最大回撤的前高日期，无回撤时为空",
+                    "type": "string"
+                },
+                "drawdown_trough_date": {
+                    "description": "最大回撤的谷底日期，无回撤时为空",
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "区间内最后一个有收盘价的交易日",
+                    "type": "string"
+                },
+                "max_drawdown": {
+                    "description": "按收盘价计算的最大回撤（正数）",
+                    "type": "number"
+                },
+                "open_to_close_return": {
+                    "description": "首日开盘价到末日收盘价",
+                    "type": "number"
+                },
+                "start_date": {
+                    "description": "区间内第一个有收盘价的交易日",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RunningTask": {
+            "type": "object",
+            "properties": {
+                "api_calls": {
+                    "description": "已发起的 Tushare 请求数，失败的请求同样计入",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_concurrency": {
+                    "description": "当前有效并发数，数据库变慢时低于配置的并发数，仅运行中的任务返回",
+                    "type": "integer"
+                },
+                "elapsed_seconds": {
+                    "description": "已运行时长（秒）",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "结束日期",
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "error_msg": {
+                    "description": "错误信息",
+                    "type": "string"
+                },
+                "estimated_end_time": {
+                    "description": "预计完成时间：开始时按限流和并发估算，执行中按实际吞吐更新，总数未知时为空",
+                    "type": "string"
+                },
+                "estimated_remaining_calls": {
+                    "description": "预计还需的 Tushare 请求数，按已完成部分的平均请求数估算，仅运行中的任务返回",
+                    "type": "integer"
+                },
+                "failed_count": {
+                    "description": "失败数",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "idempotency_key": {
+                    "description": "请求头 Idempotency-Key，过期后置空以便复用",
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "进度（0-100）",
+                    "type": "integer"
+                },
+                "row_metrics": {
+                    "description": "按接口统计的返回/入库行数",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RowMetrics"
+                        }
+                    ]
+                },
+                "rows_fetched": {
+                    "description": "Tushare 返回的行数",
+                    "type": "integer"
+                },
+                "rows_stored": {
+                    "description": "成功入库的行数",
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "开始日期",
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态：pending/running/completed/failed/cancelled/interrupted/timeout",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaskStatus"
+                        }
+                    ]
+                },
+                "success_count": {
+                    "description": "成功数",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "任务ID",
+                    "type": "string"
+                },
+                "total_count": {
+                    "description": "总数",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RunningTasks": {
+            "type": "object",
+            "properties": {
+                "active_slots": {
+                    "description": "当前进程占用的任务名额",
+                    "type": "integer"
+                },
+                "max_slots": {
+                    "description": "任务名额上限",
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.RunningTask"
+                    }
+                }
+            }
+        },
+        "api.StockCoverage": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.StockInfo": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "description": "地域",
+                    "type": "string"
+                },
+                "company": {
+                    "$ref": "#/definitions/models.StockCompany"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "industry": {
+                    "description": "行业",
+                    "type": "string"
+                },
+                "list_date": {
+                    "description": "上市日期",
+                    "type": "string"
+                },
+                "list_status": {
+                    "description": "上市状态",
+                    "type": "string"
+                },
+                "market": {
+                    "description": "市场类型",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "股票名称",
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "股票简称",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SyncFetchRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date",
+                "ts_code"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SyncFetchResult": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.StockDailyData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TokenCheck": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Tushare 返回码，0 表示成功",
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Tushare 返回信息",
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.VolatilityResult": {
+            "type": "object",
+            "properties": {
+                "annualized": {
+                    "type": "boolean"
+                },
+                "bars": {
+                    "description": "区间内有收盘价的交易日数",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "区间内最后一个有收盘价的交易日",
+                    "type": "string"
+                },
+                "returns": {
+                    "description": "参与计算的日收益个数",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "缺少昨收价且前一交易日没有数据、无法计算收益的交易日数",
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "区间内第一个有收盘价的交易日",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                },
+                "volatility": {
+                    "description": "日收益标准差，annualize=true 时为乘以 √252 后的年化波动率",
+                    "type": "number"
+                }
+            }
+        },
+        "models.BlockTrade": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "成交金额（万元）",
+                    "type": "number"
+                },
+                "buyer": {
+                    "description": "买方营业部",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "成交价（元）",
+                    "type": "number"
+                },
+                "seller": {
+                    "description": "卖方营业部",
+                    "type": "string"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vol": {
+                    "description": "成交量（万股）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.FetchFailure": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "交易日期或报告期，按股票抓取整个区间时为空",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "最后一次错误",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "任务ID",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码，按日期抓取全部股票时为空",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FetchJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error_msg": {
+                    "description": "错误信息",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "idempotency_key": {
+                    "description": "请求头 Idempotency-Key，过期后置空以便复用",
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "作业ID",
+                    "type": "string"
+                },
+                "params": {
+                    "description": "抓取参数（JSON）",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态：queued/running/completed/failed",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "关联的抓取任务ID，日线作业入队时创建，其他作业开始执行后才有值",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "作业类型：daily/weekly/monthly",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FetchTask": {
+            "type": "object",
+            "properties": {
+                "api_calls": {
+                    "description": "已发起的 Tushare 请求数，失败的请求同样计入",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_concurrency": {
+                    "description": "当前有效并发数，数据库变慢时低于配置的并发数，仅运行中的任务返回",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "结束日期",
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "error_msg": {
+                    "description": "错误信息",
+                    "type": "string"
+                },
+                "estimated_end_time": {
+                    "description": "预计完成时间：开始时按限流和并发估算，执行中按实际吞吐更新，总数未知时为空",
+                    "type": "string"
+                },
+                "estimated_remaining_calls": {
+                    "description": "预计还需的 Tushare 请求数，按已完成部分的平均请求数估算，仅运行中的任务返回",
+                    "type": "integer"
+                },
+                "failed_count": {
+                    "description": "失败数",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "idempotency_key": {
+                    "description": "请求头 Idempotency-Key，过期后置空以便复用",
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "进度（0-100）",
+                    "type": "integer"
+                },
+                "row_metrics": {
+                    "description": "按接口统计的返回/入库行数",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RowMetrics"
+                        }
+                    ]
+                },
+                "rows_fetched": {
+                    "description": "Tushare 返回的行数",
+                    "type": "integer"
+                },
+                "rows_stored": {
+                    "description": "成功入库的行数",
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "开始日期",
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态：pending/running/completed/failed/cancelled/interrupted/timeout",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TaskStatus"
+                        }
+                    ]
+                },
+                "success_count": {
+                    "description": "成功数",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "description": "任务ID",
+                    "type": "string"
+                },
+                "total_count": {
+                    "description": "总数",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FundBasic": {
+            "type": "object",
+            "properties": {
+                "benchmark": {
+                    "description": "业绩比较基准",
+                    "type": "string"
+                },
+                "c_fee": {
+                    "description": "托管费",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custodian": {
+                    "description": "托管人",
+                    "type": "string"
+                },
+                "delist_date": {
+                    "description": "退市日期",
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "到期日期",
+                    "type": "string"
+                },
+                "found_date": {
+                    "description": "成立日期",
+                    "type": "string"
+                },
+                "fund_type": {
+                    "description": "投资类型",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invest_type": {
+                    "description": "投资风格",
+                    "type": "string"
+                },
+                "issue_amount": {
+                    "description": "发行份额（亿）",
+                    "type": "number"
+                },
+                "issue_date": {
+                    "description": "发行日期",
+                    "type": "string"
+                },
+                "list_date": {
+                    "description": "上市时间",
+                    "type": "string"
+                },
+                "m_fee": {
+                    "description": "管理费",
+                    "type": "number"
+                },
+                "management": {
+                    "description": "管理人",
+                    "type": "string"
+                },
+                "market": {
+                    "description": "E场内 O场外",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "简称",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "存续状态 D摘牌 I发行 L已上市",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "基金代码",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "基金类型",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FundDaily": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "成交额（千元）",
+                    "type": "number"
+                },
+                "change": {
+                    "description": "涨跌额",
+                    "type": "number"
+                },
+                "close": {
+                    "description": "收盘价",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "high": {
+                    "description": "最高价",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "low": {
+                    "description": "最低价",
+                    "type": "number"
+                },
+                "open": {
+                    "description": "开盘价",
+                    "type": "number"
+                },
+                "pct_chg": {
+                    "description": "涨跌幅",
+                    "type": "number"
+                },
+                "pre_close": {
+                    "description": "昨收价",
+                    "type": "number"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "基金代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vol": {
+                    "description": "成交量（手）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.MarginDetail": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rqchl": {
+                    "description": "融券偿还量（股）",
+                    "type": "number"
+                },
+                "rqmcl": {
+                    "description": "融券卖出量（股）",
+                    "type": "number"
+                },
+                "rqye": {
+                    "description": "融券余额（元）",
+                    "type": "number"
+                },
+                "rqyl": {
+                    "description": "融券余量（股）",
+                    "type": "number"
+                },
+                "rzche": {
+                    "description": "融资偿还额（元）",
+                    "type": "number"
+                },
+                "rzmre": {
+                    "description": "融资买入额（元）",
+                    "type": "number"
+                },
+                "rzrqye": {
+                    "description": "融资融券余额（元）",
+                    "type": "number"
+                },
+                "rzye": {
+                    "description": "融资余额（元）",
+                    "type": "number"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RowCount": {
+            "type": "object",
+            "properties": {
+                "fetched": {
+                    "description": "Tushare 返回的行数",
+                    "type": "integer"
+                },
+                "stored": {
+                    "description": "成功入库的行数，解析失败被丢弃或保存失败的行不计入",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RowMetrics": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/models.RowCount"
+            }
+        },
+        "models.StockAdjFactor": {
+            "type": "object",
+            "properties": {
+                "adj_factor": {
+                    "description": "复权因子",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockBasic": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "description": "地域",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "industry": {
+                    "description": "行业",
+                    "type": "string"
+                },
+                "list_date": {
+                    "description": "上市日期",
+                    "type": "string"
+                },
+                "list_status": {
+                    "description": "上市状态",
+                    "type": "string"
+                },
+                "market": {
+                    "description": "市场类型",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "股票名称",
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "股票简称",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockCompany": {
+            "type": "object",
+            "properties": {
+                "business_scope": {
+                    "description": "经营范围",
+                    "type": "string"
+                },
+                "chairman": {
+                    "description": "法人代表",
+                    "type": "string"
+                },
+                "city": {
+                    "description": "所在城市",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "电子邮件",
+                    "type": "string"
+                },
+                "employees": {
+                    "description": "员工人数",
+                    "type": "integer"
+                },
+                "exchange": {
+                    "description": "交易所代码",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "main_business": {
+                    "description": "主要业务及产品",
+                    "type": "string"
+                },
+                "manager": {
+                    "description": "总经理",
+                    "type": "string"
+                },
+                "province": {
+                    "description": "所在省份",
+                    "type": "string"
+                },
+                "reg_capital": {
+                    "description": "注册资本（万元）",
+                    "type": "number"
+                },
+                "secretary": {
+                    "description": "董秘",
+                    "type": "string"
+                },
+                "setup_date": {
+                    "description": "注册日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "website": {
+                    "description": "公司主页",
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockDaily": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "成交额（千元）",
+                    "type": "number"
+                },
+                "amplitude": {
+                    "description": "振幅（%），(最高价-最低价)/昨收价×100，开启 fetcher.compute_amplitude 时写入",
+                    "type": "number"
+                },
+                "change": {
+                    "description": "涨跌额",
+                    "type": "number"
+                },
+                "close": {
+                    "description": "收盘价",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "high": {
+                    "description": "最高价",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "low": {
+                    "description": "最低价",
+                    "type": "number"
+                },
+                "open": {
+                    "description": "开盘价",
+                    "type": "number"
+                },
+                "pct_chg": {
+                    "description": "涨跌幅",
+                    "type": "number"
+                },
+                "pre_close": {
+                    "description": "昨收价",
+                    "type": "number"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "增量同步按该字段查询",
+                    "type": "string"
+                },
+                "vol": {
+                    "description": "成交量（手）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.StockLatest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "成交额（千元）",
+                    "type": "number"
+                },
+                "change": {
+                    "description": "涨跌额",
+                    "type": "number"
+                },
+                "close": {
+                    "description": "收盘价",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "high": {
+                    "description": "最高价",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "low": {
+                    "description": "最低价",
+                    "type": "number"
+                },
+                "open": {
+                    "description": "开盘价",
+                    "type": "number"
+                },
+                "pct_chg": {
+                    "description": "涨跌幅",
+                    "type": "number"
+                },
+                "pre_close": {
+                    "description": "昨收价",
+                    "type": "number"
+                },
+                "trade_date": {
+                    "description": "最新交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vol": {
+                    "description": "成交量（手）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.TaskLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "日志时间",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "其余日志字段（JSON），如 date、ts_code、error",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "日志级别：warn、error",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "日志内容",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "任务ID",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaskStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed",
+                "cancelled",
+                "interrupted",
+                "timeout"
+            ],
+            "x-enum-comments": {
+                "TaskStatusCancelled": "已取消",
+                "TaskStatusCompleted": "已完成",
+                "TaskStatusFailed": "失败",
+                "TaskStatusInterrupted": "被中断（如服务关闭）",
+                "TaskStatusPending": "等待中",
+                "TaskStatusRunning": "运行中",
+                "TaskStatusTimeout": "超过 task_timeout 被取消"
+            },
+            "x-enum-descriptions": [
+                "等待中",
+                "运行中",
+                "已完成",
+                "失败",
+                "已取消",
+                "被中断（如服务关闭）",
+                "超过 task_timeout 被取消"
+            ],
+            "x-enum-varnames": [
+                "TaskStatusPending",
+                "TaskStatusRunning",
+                "TaskStatusCompleted",
+                "TaskStatusFailed",
+                "TaskStatusCancelled",
+                "TaskStatusInterrupted",
+                "TaskStatusTimeout"
+            ]
+        },
+        "models.TopListEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "总成交额",
+                    "type": "number"
+                },
+                "amount_rate": {
+                    "description": "龙虎榜成交额占比",
+                    "type": "number"
+                },
+                "close": {
+                    "description": "收盘价",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "float_values": {
+                    "description": "当日流通市值",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "l_amount": {
+                    "description": "龙虎榜成交额",
+                    "type": "number"
+                },
+                "l_buy": {
+                    "description": "龙虎榜买入额",
+                    "type": "number"
+                },
+                "l_sell": {
+                    "description": "龙虎榜卖出额",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "net_amount": {
+                    "description": "龙虎榜净买入额",
+                    "type": "number"
+                },
+                "net_rate": {
+                    "description": "龙虎榜净买额占比",
+                    "type": "number"
+                },
+                "pct_change": {
+                    "description": "涨跌幅",
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "上榜理由",
+                    "type": "string"
+                },
+                "trade_date": {
+                    "description": "交易日期",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "description": "股票代码",
+                    "type": "string"
+                },
+                "turnover_rate": {
+                    "description": "换手率",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TradeCalendar": {
+            "type": "object",
+            "properties": {
+                "cal_date": {
+                    "description": "日历日期 YYYYMMDD",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exchange": {
+                    "description": "交易所 SSE/SZSE",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_open": {
+                    "description": "是否交易 0休市 1交易",
+                    "type": "integer"
+                },
+                "pretrade_date": {
+                    "description": "上一个交易日",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.DailyImportResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "解析失败的行，最多 MaxImportErrors 条",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ImportRowError"
+                    }
+                },
+                "failed": {
+                    "description": "解析失败被跳过的行数",
+                    "type": "integer"
+                },
+                "imported": {
+                    "description": "入库行数，skip 模式下已存在的行、写入失败被跳过的行不计入",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "数据行数，不含表头",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.DateIssue": {
+            "type": "object",
+            "properties": {
+                "normalized": {
+                    "description": "规范化后的值，无法解析时为空",
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "原始值",
+                    "type": "string"
+                }
+            }
+        },
+        "service.FetchPlan": {
+            "type": "object",
+            "properties": {
+                "data_type": {
+                    "description": "数据类型",
+                    "type": "string"
+                },
+                "date_count": {
+                    "description": "需抓取的日期/报告期数量",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "结束日期",
+                    "type": "string"
+                },
+                "estimated_seconds": {
+                    "description": "按限流估算的耗时（秒）",
+                    "type": "number"
+                },
+                "rate_limit": {
+                    "description": "每分钟请求上限",
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "开始日期",
+                    "type": "string"
+                },
+                "stock_count": {
+                    "description": "股票数量",
+                    "type": "integer"
+                },
+                "total_tasks": {
+                    "description": "预计任务数（即数据接口调用次数）",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.FetchSummary": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.FetchSummaryPeriod"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.FetchSummaryPeriod": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "抓取次数，同一日期被多个任务抓取时分别计数",
+                    "type": "integer"
+                },
+                "completed": {
+                    "description": "最近一次抓取成功的日期数",
+                    "type": "integer"
+                },
+                "dates": {
+                    "description": "有抓取记录的日期数",
+                    "type": "integer"
+                },
+                "end_date": {
+                    "description": "周期最后一天",
+                    "type": "string"
+                },
+                "failed": {
+                    "description": "最近一次抓取失败的日期数",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "周期：日为 YYYYMMDD，周为 ISO 周（如 2023-W52），月为 YYYYMM",
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "周期第一天",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "completed：全部日期最近一次抓取成功；failed：存在失败的日期",
+                    "type": "string"
+                }
+            }
+        },
+        "service.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "description": "行号，表头为第 1 行",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.NormalizeDatesResult": {
+            "type": "object",
+            "properties": {
+                "empty": {
+                    "description": "日期为空的记录数，不视为问题",
+                    "type": "integer"
+                },
+                "fix": {
+                    "description": "是否已写回规范化后的值",
+                    "type": "boolean"
+                },
+                "fixable": {
+                    "description": "可以转换为 YYYYMMDD 的记录数",
+                    "type": "integer"
+                },
+                "fixed": {
+                    "description": "已更新的记录数",
+                    "type": "integer"
+                },
+                "invalid": {
+                    "description": "无法解析的记录数，需人工处理",
+                    "type": "integer"
+                },
+                "issues": {
+                    "description": "可修复和无法解析的记录明细",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DateIssue"
+                    }
+                },
+                "scanned": {
+                    "description": "检查的记录数",
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "已是 YYYYMMDD 的记录数",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.StockDailyData": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "change": {
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "pct_chg": {
+                    "type": "number"
+                },
+                "pre_close": {
+                    "type": "number"
+                },
+                "trade_date": {
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                },
+                "vol": {
+                    "type": "number"
+                }
+            }
+        },
+        "service.VerifyDiff": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "description": "stored - tushare",
+                    "type": "number"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "stored": {
+                    "description": "数据库中的值",
+                    "type": "number"
+                },
+                "tushare": {
+                    "description": "Tushare 返回的值（已按列小数位数舍入）",
+                    "type": "number"
+                }
+            }
+        },
+        "service.VerifyResult": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "核对的字段数",
+                    "type": "integer"
+                },
+                "diffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.VerifyDiff"
+                    }
+                },
+                "fetched": {
+                    "description": "Tushare 是否返回该行",
+                    "type": "boolean"
+                },
+                "match": {
+                    "description": "两边都有该行且各字段差异均在误差范围内",
+                    "type": "boolean"
+                },
+                "stored": {
+                    "description": "数据库中是否有该行",
+                    "type": "boolean"
+                },
+                "tolerance": {
+                    "type": "number"
+                },
+                "trade_date": {
+                    "type": "string"
+                },
+                "ts_code": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/api/v1",
+	Schemes:          []string{},
+	Title:            "Tushare 数据采集系统 API",
+	Description:      "从 Tushare 抓取股票行情数据并提供查询接口",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetAdjFactor 测试按股票和日期区间分页查询复权因子，按日期倒序
+func TestGetAdjFactor(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockAdjFactor{})
+	factors := []float64{108.031, 109.5, 12.3}
+	require.NoError(t, database.DB.Create(&[]models.StockAdjFactor{
+		{TSCode: "000001.SZ", TradeDate: time.Date(2023, 11, 29, 0, 0, 0, 0, time.UTC), AdjFactor: &factors[0]},
+		{TSCode: "000001.SZ", TradeDate: time.Date(2023, 11, 30, 0, 0, 0, 0, time.UTC), AdjFactor: &factors[0]},
+		{TSCode: "000001.SZ", TradeDate: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), AdjFactor: &factors[1]},
+		{TSCode: "600000.SH", TradeDate: time.Date(2023, 11, 30, 0, 0, 0, 0, time.UTC), AdjFactor: &factors[2]},
+	}).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/api/v1/data/adj-factor?ts_code=000001.SZ&start_date=2023-11-30&page_size=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data struct {
+			List  []models.StockAdjFactor `json:"list"`
+			Total int64                   `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	require.Len(t, resp.Data.List, 1)
+	assert.Equal(t, "2023-12-01", resp.Data.List[0].TradeDate.Format("2006-01-02"))
+	require.NotNil(t, resp.Data.List[0].AdjFactor)
+	assert.Equal(t, 109.5, *resp.Data.List[0].AdjFactor)
+}
+
+// TestGetAdjustedDaily_NullFactor 测试 null 复权因子视为缺失，沿用之前最近的因子
+func TestGetAdjustedDaily_NullFactor(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{}, &models.StockAdjFactor{})
+	closes := []float64{10.0, 10.4, 5.2}
+	factors := []float64{1.0, 2.0}
+	day := func(d int) time.Time { return time.Date(2023, 12, d, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, database.DB.Create(&[]models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: day(1), Close: &closes[0]},
+		{TSCode: "000001.SZ", TradeDate: day(4), Close: &closes[1]},
+		{TSCode: "000001.SZ", TradeDate: day(5), Close: &closes[2]},
+	}).Error)
+	require.NoError(t, database.DB.Create(&[]models.StockAdjFactor{
+		{TSCode: "000001.SZ", TradeDate: day(1), AdjFactor: &factors[0]},
+		{TSCode: "000001.SZ", TradeDate: day(4)},
+		{TSCode: "000001.SZ", TradeDate: day(5), AdjFactor: &factors[1]},
+	}).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/adjusted?ts_code=000001.SZ&adj=hfq", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data AdjustedResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Series, 3)
+	for i, want := range []float64{10.0, 10.4, 10.4} {
+		require.NotNil(t, resp.Data.Series[i].Close)
+		assert.InDelta(t, want, *resp.Data.Series[i].Close, 1e-9)
+	}
+	require.NotNil(t, resp.Data.Series[1].AdjFactor)
+	assert.Equal(t, 1.0, *resp.Data.Series[1].AdjFactor)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdjustedBar 某交易日的复权价格
+type AdjustedBar struct {
+	TradeDate string   `json:"trade_date"`
+	Open      *float64 `json:"open"`
+	High      *float64 `json:"high"`
+	Low       *float64 `json:"low"`
+	Close     *float64 `json:"close"`
+	PreClose  *float64 `json:"pre_close"`
+	Vol       *float64 `json:"vol"`        // 成交量（手），不复权
+	AdjFactor *float64 `json:"adj_factor"` // 使用的复权因子，之前没有任何因子时为 null
+}
+
+// AdjustedResult 复权日线查询结果
+type AdjustedResult struct {
+	TSCode     string        `json:"ts_code"`
+	Adj        string        `json:"adj"`
+	BaseFactor *float64      `json:"base_factor"` // 前复权的基准因子（区间内最后一个交易日的因子），后复权为 null
+	Series     []AdjustedBar `json:"series"`
+}
+
+// GetAdjustedDaily 查询复权日线
+//
+// @Summary 查询复权日线
+// @Description 由已存储的日线和复权因子计算前复权/后复权价格，缺失因子的交易日沿用之前最近的因子
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string true "股票代码"
+// @Param adj query string true "复权方式" Enums(qfq, hfq)
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Success 200 {object} Response{data=AdjustedResult}
+// @Failure 400 {object} Response
+// @Router /data/daily/adjusted [get]
+func (h *Handler) GetAdjustedDaily(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	adj := c.Query("adj")
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if tsCode == "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "ts_code 不能为空",
+		})
+		return
+	}
+	if adj != service.AdjQfq && adj != service.AdjHfq {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "adj 参数错误，应为 qfq 或 hfq",
+		})
+		return
+	}
+	for _, date := range []string{startDate, endDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := service.ParseDate(date); err != nil {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "日期格式错误，应为 YYYYMMDD",
+			})
+			return
+		}
+	}
+
+	db := database.GetDB()
+
+	dailyQuery := db.Select("trade_date, open, high, low, close, pre_close, vol").Where("ts_code = ?", tsCode)
+	factorQuery := db.Where("ts_code = ?", tsCode)
+	if startDate != "" {
+		dailyQuery = dailyQuery.Where("trade_date >= ?", startDate)
+		factorQuery = factorQuery.Where("trade_date >= ?", startDate)
+	}
+	if endDate != "" {
+		dailyQuery = dailyQuery.Where("trade_date <= ?", endDate)
+		factorQuery = factorQuery.Where("trade_date <= ?", endDate)
+	}
+
+	var bars []models.StockDaily
+	if err := dailyQuery.Order("trade_date asc").Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	var factors []models.StockAdjFactor
+	if err := factorQuery.Order("trade_date asc").Find(&factors).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	// 区间首日缺失因子时需要沿用区间前最近的一个因子
+	if startDate != "" {
+		var prior []models.StockAdjFactor
+		if err := db.Where("ts_code = ? AND trade_date < ? AND adj_factor IS NOT NULL", tsCode, startDate).
+			Order("trade_date desc").
+			Limit(1).
+			Find(&prior).Error; err != nil {
+			h.respondQueryError(c, err)
+			return
+		}
+		factors = append(prior, factors...)
+	}
+
+	dates := make([]time.Time, 0, len(bars))
+	for _, bar := range bars {
+		dates = append(dates, bar.TradeDate)
+	}
+	factorDates := make([]time.Time, 0, len(factors))
+	factorValues := make([]float64, 0, len(factors))
+	for _, factor := range factors {
+		if factor.AdjFactor == nil {
+			continue
+		}
+		factorDates = append(factorDates, factor.TradeDate)
+		factorValues = append(factorValues, *factor.AdjFactor)
+	}
+	matched := service.CarryForwardFactors(dates, factorDates, factorValues)
+
+	// 前复权以区间内最后一个交易日的因子为基准，使最新价格与未复权价格一致
+	var baseFactor *float64
+	if adj == service.AdjQfq && len(matched) > 0 {
+		baseFactor = matched[len(matched)-1]
+	}
+	var base float64
+	if baseFactor != nil {
+		base = *baseFactor
+	}
+
+	series := make([]AdjustedBar, 0, len(bars))
+	for i, bar := range bars {
+		factor := matched[i]
+		series = append(series, AdjustedBar{
+			TradeDate: bar.TradeDate.Format("20060102"),
+			Open:      service.AdjustPrice(bar.Open, factor, adj, base),
+			High:      service.AdjustPrice(bar.High, factor, adj, base),
+			Low:       service.AdjustPrice(bar.Low, factor, adj, base),
+			Close:     service.AdjustPrice(bar.Close, factor, adj, base),
+			PreClose:  service.AdjustPrice(bar.PreClose, factor, adj, base),
+			Vol:       bar.Vol,
+			AdjFactor: factor,
+		})
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: AdjustedResult{
+			TSCode:     tsCode,
+			Adj:        adj,
+			BaseFactor: baseFactor,
+			Series:     series,
+		},
+	})
+}
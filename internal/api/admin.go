@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NormalizeDates 检查并规范化股票列表的上市日期
+//
+// @Summary 规范化上市日期
+// @Description 检查 stock_basic.list_date 是否均为有效的 YYYYMMDD，返回格式不规范的记录；fix 为 true 时将可识别的其他格式（如 2023-12-01、2023/12/1）改写为 YYYYMMDD，无法解析的值保持不变
+// @Tags 维护
+// @Produce json
+// @Param fix query bool false "是否写回规范化后的值，默认只检查" default(false)
+// @Success 200 {object} Response{data=service.NormalizeDatesResult}
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /admin/normalize-dates [post]
+func (h *Handler) NormalizeDates(c *gin.Context) {
+	fix := c.Query("fix") == "true"
+	h.logger.Info("收到上市日期规范化请求", zap.Bool("fix", fix))
+
+	result, err := h.dataFetcher.NormalizeListDates(fix)
+	if err != nil {
+		h.logger.Error("规范化上市日期失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
+
+// VerifyDaily 将数据库中单只股票单日的日线与 Tushare 实时数据逐字段核对
+//
+// @Summary 核对日线数据
+// @Description 从 Tushare 实时拉取指定股票和交易日的日线，与数据库中的记录逐字段比较，返回差值超过 tolerance 的字段；Tushare 的值先按列声明的小数位数舍入后再比较，不修改数据库
+// @Tags 维护
+// @Produce json
+// @Param ts_code query string true "股票代码，如 000001.SZ"
+// @Param trade_date query string true "交易日期 YYYYMMDD"
+// @Param tolerance query number false "允许的绝对误差" default(0.000001)
+// @Success 200 {object} Response{data=service.VerifyResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /admin/verify [post]
+func (h *Handler) VerifyDaily(c *gin.Context) {
+	tsCode := strings.ToUpper(strings.TrimSpace(c.Query("ts_code")))
+	tradeDate := strings.TrimSpace(c.Query("trade_date"))
+
+	if !tsCodePattern.MatchString(tsCode) {
+		respondValidationError(c, newFieldError("ts_code", "format", "股票代码格式错误: %s", c.Query("ts_code")))
+		return
+	}
+	if _, err := service.ParseDate(tradeDate); err != nil {
+		respondValidationError(c, newFieldError("trade_date", "format", "trade_date 格式错误，应为 YYYYMMDD"))
+		return
+	}
+	tolerance := service.DefaultVerifyTolerance
+	if value := c.Query("tolerance"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			respondValidationError(c, newFieldError("tolerance", "min", "tolerance 必须为不小于 0 的数字"))
+			return
+		}
+		tolerance = parsed
+	}
+
+	h.logger.Info("收到日线数据核对请求", zap.String("ts_code", tsCode), zap.String("trade_date", tradeDate))
+
+	result, err := h.dataFetcher.VerifyDaily(c.Request.Context(), tsCode, tradeDate, tolerance)
+	if err != nil {
+		h.logger.Error("核对日线数据失败", zap.String("ts_code", tsCode), zap.String("trade_date", tradeDate), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !result.Match {
+		h.logger.Warn("日线数据与 Tushare 不一致",
+			zap.String("ts_code", tsCode),
+			zap.String("trade_date", tradeDate),
+			zap.Bool("stored", result.Stored),
+			zap.Bool("fetched", result.Fetched),
+			zap.Int("diffs", len(result.Diffs)))
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
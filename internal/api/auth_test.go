@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAPIKeyAuth 测试配置 api_keys 后抓取、删除接口缺少或携带错误的 Key 返回 401，查询接口按 protect_data 决定
+func TestAPIKeyAuth(t *testing.T) {
+	tables := []interface{}{&models.StockDaily{}, &models.FetchJob{}, &models.FetchTask{}}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		header      string
+		value       string
+		protectData bool
+		status      int
+	}{
+		{"缺少 Key", http.MethodGet, "/api/v1/fetch/tasks", "", "", false, http.StatusUnauthorized},
+		{"错误的 Key", http.MethodGet, "/api/v2/fetch/tasks", "X-API-Key", "wrong", false, http.StatusUnauthorized},
+		{"X-API-Key", http.MethodGet, "/api/v1/fetch/tasks", "X-API-Key", "key-b", false, http.StatusOK},
+		{"Bearer", http.MethodGet, "/api/v1/fetch/tasks", "Authorization", "Bearer key-a", false, http.StatusOK},
+		{"删除缺少 Key", http.MethodDelete, "/api/v1/data/daily?ts_code=000001.SZ", "", "", false, http.StatusUnauthorized},
+		{"维护缺少 Key", http.MethodPost, "/api/v1/admin/normalize-dates", "Authorization", "key-a", false, http.StatusUnauthorized},
+		{"查询默认开放", http.MethodGet, "/api/v1/data/daily", "", "", false, http.StatusOK},
+		{"查询要求 Key", http.MethodGet, "/api/v1/data/daily", "", "", true, http.StatusUnauthorized},
+		{"查询携带 Key", http.MethodGet, "/api/v1/data/daily", "X-API-Key", "key-a", true, http.StatusOK},
+		{"健康检查始终开放", http.MethodGet, "/api/v1/health", "", "", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.ServerConfig{MaxTSCodes: 10, DefaultPageSize: 20, MaxPageSize: 100,
+				APIKeys: []string{"key-a", "key-b"}, ProtectData: tt.protectData}
+			r := newTestRouter(t, cfg, tables...)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
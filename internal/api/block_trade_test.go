@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetBlockTrade_QueryError 测试查询出错（如表不存在）时返回 500，建表后正常返回
+func TestGetBlockTrade_QueryError(t *testing.T) {
+	r := newTestRouter(t, nil)
+
+	get := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/block-trade?trade_date=20231201", nil))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusInternalServerError, get())
+
+	require.NoError(t, service.MigrateDataTables(database.DB))
+	assert.True(t, database.DB.Migrator().HasTable(&models.BlockTrade{}))
+	assert.Equal(t, http.StatusOK, get())
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// cachedResult 带过期时间的查询结果缓存，用于降低仪表盘频繁刷新对数据库的压力
+type cachedResult struct {
+	mu       sync.Mutex
+	value    interface{}
+	expireAt time.Time
+}
+
+// get 获取缓存值，过期或为空时调用 load 重新加载
+func (c *cachedResult) get(ttl time.Duration, load func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.value != nil && time.Now().Before(c.expireAt) {
+		return c.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.value = value
+	c.expireAt = time.Now().Add(ttl)
+	return value, nil
+}
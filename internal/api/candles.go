@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CandlesResult K 线数据，每根 K 线为 [timestamp, open, high, low, close, volume]
+type CandlesResult struct {
+	TSCode        string          `json:"ts_code"`
+	Candles       [][]interface{} `json:"candles"`         // timestamp 为交易日 00:00 UTC 的毫秒时间戳，价格缺失时为 null
+	NextStartDate string          `json:"next_start_date"` // 超出 limit 时下一页的 start_date，为空表示已无更多数据
+}
+
+// GetDailyCandles 获取适用于图表库的日 K 线数组
+//
+// @Summary 日 K 线数组
+// @Description 按交易日期升序返回 [timestamp, open, high, low, close, volume] 数组，数量超过 limit 时通过 next_start_date 翻页
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string true "股票代码"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param limit query int false "最多返回的 K 线数量，超过 max_page_size 时取上限"
+// @Success 200 {object} Response{data=CandlesResult}
+// @Failure 400 {object} Response
+// @Router /data/daily/candles [get]
+func (h *Handler) GetDailyCandles(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if tsCode == "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "ts_code 不能为空",
+		})
+		return
+	}
+	for _, date := range []string{startDate, endDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := service.ParseDate(date); err != nil {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "日期格式错误，应为 YYYYMMDD",
+			})
+			return
+		}
+	}
+
+	limit := h.config.MaxPageSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "limit 必须是正整数",
+			})
+			return
+		}
+		if n < limit {
+			limit = n
+		}
+	}
+
+	db := database.GetDB().
+		Select("trade_date, open, high, low, close, vol").
+		Where("ts_code = ?", tsCode)
+	if startDate != "" {
+		db = db.Where("trade_date >= ?", startDate)
+	}
+	if endDate != "" {
+		db = db.Where("trade_date <= ?", endDate)
+	}
+
+	// 多取一条用于判断是否还有下一页
+	var bars []models.StockDaily
+	if err := db.Order("trade_date asc").Limit(limit + 1).Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	var nextStartDate string
+	if len(bars) > limit {
+		nextStartDate = bars[limit].TradeDate.Format("20060102")
+		bars = bars[:limit]
+	}
+
+	candles := make([][]interface{}, 0, len(bars))
+	for _, bar := range bars {
+		d := bar.TradeDate
+		timestamp := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC).UnixMilli()
+		candles = append(candles, []interface{}{timestamp, bar.Open, bar.High, bar.Low, bar.Close, bar.Vol})
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: CandlesResult{
+			TSCode:        tsCode,
+			Candles:       candles,
+			NextStartDate: nextStartDate,
+		},
+	})
+}
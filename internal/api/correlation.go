@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// minCorrelationReturns 计算相关系数所需的最少收益对数（两个样本的相关系数恒为 ±1，没有意义）
+const minCorrelationReturns = 3
+
+// CorrelationResult 两只股票的收益相关系数，按共同交易日对齐后的日对数收益计算
+type CorrelationResult struct {
+	TSCodeA     string   `json:"ts_code_a"`
+	TSCodeB     string   `json:"ts_code_b"`
+	StartDate   string   `json:"start_date"`   // 区间内第一个共同交易日
+	EndDate     string   `json:"end_date"`     // 区间内最后一个共同交易日
+	BarsA       int      `json:"bars_a"`       // 区间内 ts_code_a 有收盘价的交易日数
+	BarsB       int      `json:"bars_b"`       // 区间内 ts_code_b 有收盘价的交易日数
+	CommonDates int      `json:"common_dates"` // 两只股票都有收盘价的交易日数
+	SampleSize  int      `json:"sample_size"`  // 参与计算的收益对数
+	Correlation *float64 `json:"correlation"`  // Pearson 相关系数，-1 到 1
+}
+
+// GetDailyCorrelation 计算两只股票区间内日收益的相关系数
+//
+// @Summary 收益相关系数
+// @Description 基于已存储的未复权日线，取两只股票都有收盘价的共同交易日，按相邻共同交易日的收盘价计算对数收益 ln(close/前一共同交易日 close)，返回两组收益的 Pearson 相关系数；一只股票停牌的日期不参与对齐，复牌后的收益跨越停牌期间，两只股票按同一区间计算。收益对少于 3 个或任一股票收益没有波动时返回 422
+// @Tags 数据
+// @Produce json
+// @Param ts_code_a query string true "股票代码 A"
+// @Param ts_code_b query string true "股票代码 B"
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Success 200 {object} Response{data=CorrelationResult}
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Router /data/daily/correlation [get]
+func (h *Handler) GetDailyCorrelation(c *gin.Context) {
+	tsCodeA := c.Query("ts_code_a")
+	tsCodeB := c.Query("ts_code_b")
+	start, end, ok := parseDateRangeQuery(c, "ts_code_a", "ts_code_b")
+	if !ok {
+		return
+	}
+
+	barsA, err := loadCloses(tsCodeA, start, end)
+	if err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+	barsB, err := loadCloses(tsCodeB, start, end)
+	if err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	result := CorrelationResult{TSCodeA: tsCodeA, TSCodeB: tsCodeB, BarsA: len(barsA), BarsB: len(barsB)}
+
+	// 按共同交易日对齐收盘价，以 YYYYMMDD 为键，不受驱动返回的时区、时间部分影响
+	closesA := make(map[string]float64, len(barsA))
+	for _, bar := range barsA {
+		closesA[bar.TradeDate.Format("20060102")] = *bar.Close
+	}
+	var commonA, commonB []float64
+	for _, bar := range barsB {
+		tradeDate := bar.TradeDate.Format("20060102")
+		if closeA, ok := closesA[tradeDate]; ok {
+			if result.StartDate == "" {
+				result.StartDate = tradeDate
+			}
+			result.EndDate = tradeDate
+			commonA = append(commonA, closeA)
+			commonB = append(commonB, *bar.Close)
+		}
+	}
+	result.CommonDates = len(commonA)
+
+	// 相邻共同交易日之间的收益，任一价格不为正时跳过该对
+	returnsA := make([]float64, 0, len(commonA))
+	returnsB := make([]float64, 0, len(commonB))
+	for i := 1; i < len(commonA); i++ {
+		ra, okA := service.LogReturn(commonA[i-1], commonA[i])
+		rb, okB := service.LogReturn(commonB[i-1], commonB[i])
+		if okA && okB {
+			returnsA = append(returnsA, ra)
+			returnsB = append(returnsB, rb)
+		}
+	}
+	result.SampleSize = len(returnsA)
+
+	if result.SampleSize < minCorrelationReturns {
+		respond(c, http.StatusUnprocessableEntity, Response{
+			Code:    422,
+			Message: fmt.Sprintf("两只股票共同交易日的有效收益 %d 对，至少需要 %d 对", result.SampleSize, minCorrelationReturns),
+			Data:    result,
+		})
+		return
+	}
+
+	result.Correlation = service.Correlation(returnsA, returnsB)
+	if result.Correlation == nil {
+		respond(c, http.StatusUnprocessableEntity, Response{
+			Code:    422,
+			Message: "区间内收益没有波动，无法计算相关系数",
+			Data:    result,
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
+
+// loadCloses 按日期升序查询股票区间内有收盘价的日线
+func loadCloses(tsCode string, start, end time.Time) ([]models.StockDaily, error) {
+	var bars []models.StockDaily
+	err := database.GetDB().Select("trade_date, close").
+		Where("ts_code = ? AND trade_date >= ? AND trade_date <= ? AND close IS NOT NULL", tsCode, start, end).
+		Order("trade_date asc").
+		Find(&bars).Error
+	return bars, err
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetDailyCorrelation 测试按共同交易日对齐后计算收益相关系数，共同交易日不足时返回 422
+func TestGetDailyCorrelation(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{})
+	bar := func(tsCode, s string, close float64) models.StockDaily {
+		d, err := service.ParseDate(s)
+		require.NoError(t, err)
+		return models.StockDaily{TSCode: tsCode, TradeDate: d, Close: &close}
+	}
+	// 600000.SH 在 20231205 停牌，000001.SZ 的 20231205 和 600000.SH 的 20231208 没有对应日期，不参与计算
+	require.NoError(t, database.DB.Create(&[]models.StockDaily{
+		bar("000001.SZ", "20231201", 10),
+		bar("000001.SZ", "20231204", 11),
+		bar("000001.SZ", "20231205", 50),
+		bar("000001.SZ", "20231206", 9.9),
+		bar("000001.SZ", "20231207", 10.89),
+		bar("600000.SH", "20231201", 20),
+		bar("600000.SH", "20231204", 21),
+		bar("600000.SH", "20231206", 20),
+		bar("600000.SH", "20231207", 22),
+		bar("600000.SH", "20231208", 30),
+	}).Error)
+
+	get := func(query string) (int, CorrelationResult) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/correlation?ts_code_a=000001.SZ&ts_code_b=600000.SH&"+query, nil))
+		var resp struct {
+			Data CorrelationResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data
+	}
+
+	returnsA := []float64{math.Log(1.1), math.Log(0.9), math.Log(1.1)}
+	returnsB := []float64{math.Log(21.0 / 20), math.Log(20.0 / 21), math.Log(22.0 / 20)}
+	expected := service.Correlation(returnsA, returnsB)
+	require.NotNil(t, expected)
+
+	status, result := get("start_date=20231201&end_date=20231231")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 5, result.BarsA)
+	assert.Equal(t, 5, result.BarsB)
+	assert.Equal(t, 4, result.CommonDates)
+	assert.Equal(t, 3, result.SampleSize)
+	assert.Equal(t, "20231201", result.StartDate)
+	assert.Equal(t, "20231207", result.EndDate)
+	require.NotNil(t, result.Correlation)
+	assert.InDelta(t, *expected, *result.Correlation, 1e-9)
+	assert.Greater(t, *result.Correlation, 0.9)
+
+	status, result = get("start_date=20231201&end_date=20231206")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, 2, result.SampleSize)
+	assert.Nil(t, result.Correlation)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/correlation?ts_code_a=000001.SZ&start_date=20231201&end_date=20231231", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// cursorSeparator 游标中各字段的分隔符
+const cursorSeparator = "|"
+
+// encodeCursor 将排序键编码为不透明的游标
+func encodeCursor(values ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(values, cursorSeparator)))
+}
+
+// decodeCursor 解析游标，返回 n 个排序键
+func decodeCursor(cursor string, n int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("无效的游标")
+	}
+
+	values := strings.Split(string(raw), cursorSeparator)
+	if len(values) != n {
+		return nil, fmt.Errorf("无效的游标")
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getDailyChanges 请求日线增量数据，返回本页的股票代码及 next_cursor
+func getDailyChanges(t *testing.T, r http.Handler, query url.Values) ([]string, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/changes?"+query.Encode(), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			List       []models.StockDaily `json:"list"`
+			NextCursor string              `json:"next_cursor"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	codes := make([]string, 0, len(resp.Data.List))
+	for _, daily := range resp.Data.List {
+		codes = append(codes, daily.TSCode)
+	}
+	return codes, resp.Data.NextCursor
+}
+
+// TestGetDailyChanges 测试只返回 since 之后更新的日线，按 updated_at、id 升序，通过 next_cursor 翻页；
+// updated_at 相同的记录按 id 区分，翻页时不重复也不遗漏
+func TestGetDailyChanges(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{})
+
+	base := time.Date(2023, 12, 1, 16, 0, 0, 0, time.UTC)
+	tradeDate := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	rows := []struct {
+		tsCode    string
+		updatedAt time.Time
+	}{
+		{"000001.SZ", base.Add(-time.Hour)}, // since 之前
+		{"000002.SZ", base.Add(time.Minute)},
+		{"600000.SH", base.Add(2 * time.Minute)},
+		{"600001.SH", base.Add(2 * time.Minute)},
+		{"600519.SH", base.Add(3 * time.Minute)},
+	}
+	for _, row := range rows {
+		daily := models.StockDaily{TSCode: row.tsCode, TradeDate: tradeDate}
+		require.NoError(t, database.DB.Create(&daily).Error)
+		require.NoError(t, database.DB.Model(&daily).UpdateColumn("updated_at", row.updatedAt).Error)
+	}
+
+	query := url.Values{"since": {base.Format(time.RFC3339)}, "page_size": {"2"}}
+	codes, cursor := getDailyChanges(t, r, query)
+	assert.Equal(t, []string{"000002.SZ", "600000.SH"}, codes)
+	require.NotEmpty(t, cursor)
+
+	query.Set("cursor", cursor)
+	codes, cursor = getDailyChanges(t, r, query)
+	assert.Equal(t, []string{"600001.SH", "600519.SH"}, codes)
+	require.NotEmpty(t, cursor)
+
+	// 最后一页不足 page_size，next_cursor 为空
+	query.Set("cursor", cursor)
+	codes, cursor = getDailyChanges(t, r, query)
+	assert.Empty(t, codes)
+	assert.Empty(t, cursor)
+
+	// since 晚于全部更新时间
+	codes, cursor = getDailyChanges(t, r, url.Values{"since": {base.Add(time.Hour).Format(time.RFC3339)}})
+	assert.Empty(t, codes)
+	assert.Empty(t, cursor)
+
+	for _, query := range []string{"", "since=2023-12-01", "since=2023-12-01T16:00:00Z&cursor=invalid"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/changes?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
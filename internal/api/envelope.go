@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// API 版本的路由前缀，各版本共用同一套处理器，只在序列化响应时区分
+const (
+	apiV1Prefix = "/api/v1"
+	apiV2Prefix = "/api/v2"
+)
+
+// problemContentType RFC 7807 错误响应的 Content-Type
+const problemContentType = "application/problem+json"
+
+// V2Response v2 成功响应，分页列表的 data 为列表本身，分页信息放在 pagination 中
+type V2Response struct {
+	Data       interface{}   `json:"data"`
+	Pagination *V2Pagination `json:"pagination,omitempty"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// V2Pagination v2 分页信息
+type V2Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// Problem RFC 7807 错误响应，code 为扩展字段，与 v1 的错误码一致
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     int          `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"` // 参数校验失败的字段，与 v1 的 errors 一致
+}
+
+// respond 按请求的 API 版本输出响应：v1 原样输出 Response，v2 转换为 V2Response 或 Problem
+func respond(c *gin.Context, status int, resp Response) {
+	if !isV2(c) {
+		c.JSON(status, resp)
+		return
+	}
+
+	if status >= http.StatusBadRequest {
+		c.Header("Content-Type", problemContentType)
+		c.Render(status, render.JSON{Data: newProblem(c, status, resp)})
+		return
+	}
+	c.JSON(status, newV2Response(resp))
+}
+
+// isV2 判断当前请求是否命中 v2 路由，按路由路径判断，全局中间件中同样可用
+func isV2(c *gin.Context) bool {
+	return strings.HasPrefix(c.FullPath(), apiV2Prefix+"/")
+}
+
+// newV2Response 将 v1 响应转换为 v2 结构
+func newV2Response(resp Response) V2Response {
+	page, ok := resp.Data.(PageResult)
+	if !ok {
+		return V2Response{Data: resp.Data, Message: resp.Message}
+	}
+
+	pagination := &V2Pagination{
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		Total:    page.Total,
+	}
+	if page.PageSize > 0 {
+		pagination.TotalPages = (page.Total + int64(page.PageSize) - 1) / int64(page.PageSize)
+	}
+	return V2Response{Data: page.List, Pagination: pagination, Message: resp.Message}
+}
+
+// newProblem 将 v1 错误响应转换为 RFC 7807 格式
+func newProblem(c *gin.Context, status int, resp Response) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   resp.Message,
+		Instance: c.Request.URL.Path,
+		Code:     resp.Code,
+		Errors:   resp.Errors,
+	}
+}
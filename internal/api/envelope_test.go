@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEnvelopeRouter 在 v1、v2 下注册相同的测试接口
+func newEnvelopeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(BodyLimit(16))
+	for _, prefix := range []string{apiV1Prefix, apiV2Prefix} {
+		group := r.Group(prefix)
+		group.GET("/list", func(c *gin.Context) {
+			respond(c, http.StatusOK, Response{
+				Code:    0,
+				Message: "success",
+				Data:    PageResult{List: []string{"a", "b"}, Total: 45, Page: 2, PageSize: 20},
+			})
+		})
+		group.POST("/fail", func(c *gin.Context) {
+			respond(c, http.StatusBadRequest, Response{Code: 400, Message: "参数错误"})
+		})
+	}
+	return r
+}
+
+// TestRespond_V1 测试 v1 保持原有的 Response 结构
+func TestRespond_V1(t *testing.T) {
+	r := newEnvelopeRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/list", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"code":0,"message":"success","data":{"list":["a","b"],"total":45,"page":2}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/fail", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.JSONEq(t, `{"code":400,"message":"参数错误"}`, w.Body.String())
+}
+
+// TestRespond_V2 测试 v2 列表使用分页信封，错误使用 problem+json
+func TestRespond_V2(t *testing.T) {
+	r := newEnvelopeRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/list", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"data": ["a", "b"],
+		"pagination": {"page": 2, "page_size": 20, "total": 45, "total_pages": 3},
+		"message": "success"
+	}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/fail", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   400,
+		Detail:   "参数错误",
+		Instance: "/api/v2/fail",
+		Code:     400,
+	}, problem)
+
+	// 全局中间件同样按路由版本输出
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/fail", strings.NewReader(strings.Repeat("x", 32)))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportFlushRows 导出时每写入多少行刷新一次输出
+const exportFlushRows = 1000
+
+// dailyCSVHeader 日线 CSV 导出列
+var dailyCSVHeader = []string{
+	"ts_code", "trade_date", "open", "high", "low", "close",
+	"pre_close", "change", "pct_chg", "vol", "amount",
+}
+
+// ExportDailyData 流式导出日线数据
+//
+// @Summary 导出日线数据
+// @Description 按过滤条件流式导出日线数据，format=ndjson 时每行一个 JSON 对象，format=csv 时输出 CSV，format=parquet 时输出 Parquet 文件
+// @Tags 数据
+// @Produce plain
+// @Produce application/vnd.apache.parquet
+// @Param format query string false "导出格式" Enums(csv, ndjson, parquet) default(csv)
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Success 200 {string} string "导出数据"
+// @Failure 400 {object} Response
+// @Router /data/daily/export [get]
+func (h *Handler) ExportDailyData(c *gin.Context) {
+	if param := unknownParam(c, dailyFilters, "format"); param != "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "不支持的查询参数: " + param,
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	var write func(data *models.StockDaily) error
+	var flush func()
+
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="stock_daily.csv"`)
+		w := csv.NewWriter(c.Writer)
+		if err := w.Write(dailyCSVHeader); err != nil {
+			return
+		}
+		write = func(data *models.StockDaily) error {
+			return w.Write(dailyCSVRecord(data))
+		}
+		flush = func() {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	case "ndjson":
+		c.Header("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(c.Writer)
+		write = func(data *models.StockDaily) error {
+			return enc.Encode(data)
+		}
+		flush = c.Writer.Flush
+	case "parquet":
+		// Parquet 的元数据在文件末尾，不能边查询边输出，见 exportDailyParquet
+	default:
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "不支持的导出格式: " + format,
+		})
+		return
+	}
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.StockDaily{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	if format == "parquet" {
+		h.exportDailyParquet(c, db)
+		return
+	}
+	rows, err := db.Order("ts_code, trade_date").Rows()
+	if err != nil {
+		h.logger.Error("导出日线数据失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer rows.Close()
+
+	c.Status(http.StatusOK)
+
+	count := 0
+	for rows.Next() {
+		var data models.StockDaily
+		if err := db.ScanRows(rows, &data); err != nil {
+			h.logger.Error("读取日线数据失败", zap.Error(err))
+			break
+		}
+		if err := write(&data); err != nil {
+			// 客户端断开连接等写入错误，终止导出
+			h.logger.Warn("写入导出数据失败", zap.Int("rows", count), zap.Error(err))
+			break
+		}
+
+		count++
+		if count%exportFlushRows == 0 {
+			flush()
+		}
+	}
+	flush()
+
+	h.logger.Info("日线数据导出完成", zap.String("format", format), zap.Int("rows", count))
+}
+
+// dailyCSVRecord 将日线数据转换为 CSV 行
+func dailyCSVRecord(data *models.StockDaily) []string {
+	// 缺失值输出为空字符串
+	formatFloat := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+
+	return []string{
+		data.TSCode,
+		data.TradeDate.Format("20060102"),
+		formatFloat(data.Open),
+		formatFloat(data.High),
+		formatFloat(data.Low),
+		formatFloat(data.Close),
+		formatFloat(data.PreClose),
+		formatFloat(data.Change),
+		formatFloat(data.PctChg),
+		formatFloat(data.Vol),
+		formatFloat(data.Amount),
+	}
+}
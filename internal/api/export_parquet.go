@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"stock_data/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/parquet-go/parquet-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// parquetRowGroupRows Parquet 每个行组的行数，写入时只在内存中缓冲当前行组
+const parquetRowGroupRows = 100000
+
+// parquetContentType Parquet 文件的 Content-Type
+const parquetContentType = "application/vnd.apache.parquet"
+
+// dailyParquetRow 日线 Parquet 导出的行结构，列与 CSV 导出一致，缺失值为 null
+type dailyParquetRow struct {
+	TSCode    string   `parquet:"ts_code,dict"`
+	TradeDate int32    `parquet:"trade_date,date"` // 自 1970-01-01 起的天数
+	Open      *float64 `parquet:"open,optional"`
+	High      *float64 `parquet:"high,optional"`
+	Low       *float64 `parquet:"low,optional"`
+	Close     *float64 `parquet:"close,optional"`
+	PreClose  *float64 `parquet:"pre_close,optional"`
+	Change    *float64 `parquet:"change,optional"`
+	PctChg    *float64 `parquet:"pct_chg,optional"`
+	Vol       *float64 `parquet:"vol,optional"`
+	Amount    *float64 `parquet:"amount,optional"`
+}
+
+// exportDailyParquet 将过滤后的日线数据写入临时 Parquet 文件后输出
+// 查询结果逐行读取，每 exportFlushRows 行写入一次，内存中只保留当前行组；写入失败时仍可返回 500
+func (h *Handler) exportDailyParquet(c *gin.Context, db *gorm.DB) {
+	file, err := os.CreateTemp("", "stock_daily_*.parquet")
+	if err != nil {
+		h.logger.Error("创建导出临时文件失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	count, err := writeDailyParquet(file, db)
+	if err != nil {
+		h.logger.Error("导出日线数据失败", zap.String("format", "parquet"), zap.Int("rows", count), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	info, err := file.Stat()
+	if err == nil {
+		_, err = file.Seek(0, 0)
+	}
+	if err != nil {
+		h.logger.Error("读取导出临时文件失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	filename := "stock_daily.parquet"
+	if tsCode := c.Query("ts_code"); tsCode != "" {
+		filename = fmt.Sprintf("stock_daily_%s.parquet", tsCode)
+	}
+	c.DataFromReader(http.StatusOK, info.Size(), parquetContentType, file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, filename),
+	})
+
+	h.logger.Info("日线数据导出完成", zap.String("format", "parquet"), zap.Int("rows", count), zap.Int64("bytes", info.Size()))
+}
+
+// writeDailyParquet 按 ts_code、trade_date 顺序读取日线数据写入 Parquet 文件，返回写入的行数
+func writeDailyParquet(file *os.File, db *gorm.DB) (int, error) {
+	writer := parquet.NewGenericWriter[dailyParquetRow](file,
+		parquet.Compression(&parquet.Snappy),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupRows))
+
+	rows, err := db.Order("ts_code, trade_date").Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	buffer := make([]dailyParquetRow, 0, exportFlushRows)
+	writeBuffer := func() error {
+		if _, err := writer.Write(buffer); err != nil {
+			return fmt.Errorf("写入 Parquet 失败: %w", err)
+		}
+		count += len(buffer)
+		buffer = buffer[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		var data models.StockDaily
+		if err := db.ScanRows(rows, &data); err != nil {
+			return count, fmt.Errorf("读取日线数据失败: %w", err)
+		}
+		buffer = append(buffer, dailyParquetRecord(&data))
+		if len(buffer) == exportFlushRows {
+			if err := writeBuffer(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("读取日线数据失败: %w", err)
+	}
+	if err := writeBuffer(); err != nil {
+		return count, err
+	}
+	if err := writer.Close(); err != nil {
+		return count, fmt.Errorf("写入 Parquet 失败: %w", err)
+	}
+	return count, nil
+}
+
+// dailyParquetRecord 将日线数据转换为 Parquet 行
+func dailyParquetRecord(data *models.StockDaily) dailyParquetRow {
+	// 按日期部分计算天数，不受数据库返回时间的时区影响
+	year, month, day := data.TradeDate.Date()
+	days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+
+	return dailyParquetRow{
+		TSCode:    data.TSCode,
+		TradeDate: int32(days),
+		Open:      data.Open,
+		High:      data.High,
+		Low:       data.Low,
+		Close:     data.Close,
+		PreClose:  data.PreClose,
+		Change:    data.Change,
+		PctChg:    data.PctChg,
+		Vol:       data.Vol,
+		Amount:    data.Amount,
+	}
+}
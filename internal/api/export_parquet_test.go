@@ -0,0 +1,48 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportDailyData_Parquet 测试按股票导出 Parquet 文件，读取后与存储的数据一致
+func TestExportDailyData_Parquet(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{})
+
+	closePrice, vol := 10.12, 1234.5
+	rows := []models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC), Close: &closePrice, Vol: &vol},
+		{TSCode: "000001.SZ", TradeDate: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Close: &closePrice},
+		{TSCode: "600000.SH", TradeDate: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Close: &closePrice},
+	}
+	require.NoError(t, database.DB.Create(&rows).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/export?format=parquet&ts_code=000001.SZ", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, parquetContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "stock_daily_000001.SZ.parquet")
+
+	exported, err := parquet.Read[dailyParquetRow](bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+
+	// 按交易日期升序，日期为自 1970-01-01 起的天数
+	assert.Equal(t, "000001.SZ", exported[0].TSCode)
+	assert.Equal(t, int32(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC).Unix()/86400), exported[0].TradeDate)
+	assert.Nil(t, exported[0].Vol)
+	assert.Equal(t, int32(time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC).Unix()/86400), exported[1].TradeDate)
+	require.NotNil(t, exported[1].Close)
+	assert.Equal(t, 10.12, *exported[1].Close)
+	assert.Equal(t, 1234.5, *exported[1].Vol)
+	assert.Nil(t, exported[1].Open)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListFailures 查询重试后仍失败的抓取单元
+//
+// @Summary 查询抓取失败记录
+// @Description 返回 fetch_failures 中的失败单元及最后一次错误，按记录时间倒序。需开启 fetcher.record_failures；日线任务续传时清除该任务的记录，仍失败的日期重新记录
+// @Tags 任务
+// @Produce json
+// @Param task_id query string false "任务ID"
+// @Param ts_code query string false "股票代码"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.FetchFailure}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/failures [get]
+func (h *Handler) ListFailures(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.FetchFailure{}), failureFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	failures := make([]models.FetchFailure, 0)
+	if err := db.Order("id desc").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&failures).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     failures,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListFailures 测试按任务ID查询抓取失败记录，按记录时间倒序
+func TestListFailures(t *testing.T) {
+	r := newTestRouter(t, nil, &models.FetchFailure{})
+	require.NoError(t, database.DB.Create(&[]models.FetchFailure{
+		{TaskID: "task_1", Date: "20231201", Error: "timeout"},
+		{TaskID: "task_2", Date: "20231201", Error: "权限不足"},
+		{TaskID: "task_1", Date: "20231204", Error: "连接被重置"},
+	}).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/fetch/failures?task_id=task_1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data struct {
+			List  []models.FetchFailure `json:"list"`
+			Total int64                 `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	require.Len(t, resp.Data.List, 2)
+	assert.Equal(t, "20231204", resp.Data.List[0].Date)
+	assert.Equal(t, "连接被重置", resp.Data.List[0].Error)
+	assert.Equal(t, "20231201", resp.Data.List[1].Date)
+}
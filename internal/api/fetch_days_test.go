@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/config"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetch_MaxFetchDays 测试日线、周线、月线抓取的日期范围超过 max_fetch_days 时返回 400
+func TestFetch_MaxFetchDays(t *testing.T) {
+	r := newTestRouter(t, &config.ServerConfig{MaxTSCodes: 10, DefaultPageSize: 20, MaxPageSize: 100, MaxFetchDays: 366}, &models.FetchJob{}, &models.FetchTask{})
+
+	for _, path := range []string{"/api/v1/fetch/daily", "/api/v1/fetch/weekly", "/api/v1/fetch/monthly"} {
+		t.Run(path, func(t *testing.T) {
+			status, _ := postFetch(t, r, path, `{"start_date": "19900101", "end_date": "20231231"}`, "")
+			assert.Equal(t, http.StatusBadRequest, status)
+
+			// 闰年整年 366 天，恰好等于上限
+			status, job := postFetch(t, r, path, `{"start_date": "20240101", "end_date": "20241231"}`, "")
+			assert.Equal(t, http.StatusOK, status)
+			assert.NotEmpty(t, job.JobID)
+		})
+	}
+
+	var count int64
+	require.NoError(t, database.DB.Model(&models.FetchJob{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"stock_data/internal/service"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// queryFilter 查询参数与对应的过滤条件
+type queryFilter struct {
+	Param     string
+	Condition string
+	Multi     bool // 参数为逗号分隔的列表，条件形如 "col IN ?"
+}
+
+// dailyFilters 日线数据允许的过滤参数
+var dailyFilters = []queryFilter{
+	{Param: "ts_code", Condition: "ts_code = ?"},
+	{Param: "ts_codes", Condition: "ts_code IN ?", Multi: true},
+	{Param: "trade_date", Condition: "trade_date = ?"},
+	{Param: "start_date", Condition: "trade_date >= ?"},
+	{Param: "end_date", Condition: "trade_date <= ?"},
+}
+
+// latestFilters 最新行情快照允许的过滤参数
+var latestFilters = []queryFilter{
+	{Param: "ts_code", Condition: "ts_code = ?"},
+	{Param: "ts_codes", Condition: "ts_code IN ?", Multi: true},
+}
+
+// calendarFilters 交易日历允许的过滤参数，日期以 YYYYMMDD 字符串存储，按字符串比较即可
+var calendarFilters = []queryFilter{
+	{Param: "exchange", Condition: "exchange = ?"},
+	{Param: "start_date", Condition: "cal_date >= ?"},
+	{Param: "end_date", Condition: "cal_date <= ?"},
+	{Param: "is_open", Condition: "is_open = ?"},
+}
+
+// failureFilters 抓取失败记录允许的过滤参数，日期以 YYYYMMDD 字符串存储
+var failureFilters = []queryFilter{
+	{Param: "task_id", Condition: "task_id = ?"},
+	{Param: "ts_code", Condition: "ts_code = ?"},
+	{Param: "start_date", Condition: "date >= ?"},
+	{Param: "end_date", Condition: "date <= ?"},
+}
+
+// applyFilters 按允许列表将查询参数转换为过滤条件，忽略空值
+// 列表参数的元素个数不能超过 max_ts_codes 配置
+func (h *Handler) applyFilters(c *gin.Context, db *gorm.DB, filters []queryFilter) (*gorm.DB, error) {
+	for _, filter := range filters {
+		value := c.Query(filter.Param)
+		if value == "" {
+			continue
+		}
+
+		if !filter.Multi {
+			db = db.Where(filter.Condition, value)
+			continue
+		}
+
+		values := splitList(value)
+		if len(values) > h.config.MaxTSCodes {
+			return nil, fmt.Errorf("%s 数量超过上限 %d", filter.Param, h.config.MaxTSCodes)
+		}
+		if len(values) > 0 {
+			db = db.Where(filter.Condition, values)
+		}
+	}
+	return db, nil
+}
+
+// splitList 拆分逗号分隔的参数，去除空白和空项
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// unknownParam 返回第一个不在允许列表中的查询参数
+func unknownParam(c *gin.Context, filters []queryFilter, extra ...string) string {
+	allowed := make(map[string]bool, len(filters)+len(extra))
+	for _, filter := range filters {
+		allowed[filter.Param] = true
+	}
+	for _, param := range extra {
+		allowed[param] = true
+	}
+
+	for param := range c.Request.URL.Query() {
+		if !allowed[param] {
+			return param
+		}
+	}
+	return ""
+}
+
+// respondFilterError 返回过滤参数错误
+func respondFilterError(c *gin.Context, err error) {
+	respond(c, http.StatusBadRequest, Response{
+		Code:    400,
+		Message: "参数错误: " + err.Error(),
+	})
+}
+
+// parseDateRangeQuery 校验 required 中的参数及 start_date、end_date 非空，解析日期范围
+// 参数缺失、日期格式错误或开始日期晚于结束日期时返回 400，ok 为 false
+func parseDateRangeQuery(c *gin.Context, required ...string) (start, end time.Time, ok bool) {
+	params := append(required, "start_date", "end_date")
+	for _, param := range params {
+		if c.Query(param) == "" {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: strings.Join(params, "、") + " 不能为空",
+			})
+			return start, end, false
+		}
+	}
+
+	start, err := service.ParseDate(c.Query("start_date"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "开始日期格式错误，应为 YYYYMMDD",
+		})
+		return start, end, false
+	}
+	end, err = service.ParseDate(c.Query("end_date"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "结束日期格式错误，应为 YYYYMMDD",
+		})
+		return start, end, false
+	}
+	if start.After(end) {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "开始日期不能晚于结束日期",
+		})
+		return start, end, false
+	}
+	return start, end, true
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseDateRangeQuery 测试日期范围查询参数的必填、格式及先后顺序校验
+func TestParseDateRangeQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET(apiV1Prefix+"/range", func(c *gin.Context) {
+		start, end, ok := parseDateRangeQuery(c, "ts_code")
+		if !ok {
+			return
+		}
+		respond(c, http.StatusOK, Response{
+			Code:    0,
+			Message: "success",
+			Data:    start.Format("20060102") + "-" + end.Format("20060102"),
+		})
+	})
+
+	tests := []struct {
+		query   string
+		status  int
+		message string
+	}{
+		{query: "ts_code=000001.SZ&start_date=20231201&end_date=20231231", status: http.StatusOK, message: "success"},
+		{query: "ts_code=000001.SZ&start_date=20231201&end_date=20231201", status: http.StatusOK, message: "success"},
+		{query: "start_date=20231201&end_date=20231231", status: http.StatusBadRequest, message: "ts_code、start_date、end_date 不能为空"},
+		{query: "ts_code=000001.SZ&start_date=20231201", status: http.StatusBadRequest, message: "ts_code、start_date、end_date 不能为空"},
+		{query: "ts_code=000001.SZ&start_date=2023-12-01&end_date=20231231", status: http.StatusBadRequest, message: "开始日期格式错误，应为 YYYYMMDD"},
+		{query: "ts_code=000001.SZ&start_date=20231201&end_date=2023-12-31", status: http.StatusBadRequest, message: "结束日期格式错误，应为 YYYYMMDD"},
+		{query: "ts_code=000001.SZ&start_date=20231231&end_date=20231201", status: http.StatusBadRequest, message: "开始日期不能晚于结束日期"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, apiV1Prefix+"/range?"+tt.query, nil))
+			assert.Equal(t, tt.status, w.Code)
+
+			var resp Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// fundFilters 基金列表允许的过滤参数
+var fundFilters = []queryFilter{
+	{Param: "market", Condition: "market = ?"},
+	{Param: "fund_type", Condition: "fund_type = ?"},
+	{Param: "status", Condition: "status = ?"},
+	{Param: "management", Condition: "management = ?"},
+}
+
+// FetchFundBasic 抓取基金基本信息
+//
+// @Summary 抓取基金基本信息
+// @Description 抓取基金列表（fund_basic），已存在的基金按 ts_code 更新
+// @Tags 抓取
+// @Produce json
+// @Param market query string false "交易市场 E 场内、O 场外，为空时抓取场内基金" Enums(E, O)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/fund-basic [post]
+func (h *Handler) FetchFundBasic(c *gin.Context) {
+	market := strings.ToUpper(strings.TrimSpace(c.Query("market")))
+	if market != "" && market != models.FundMarketExchange && market != models.FundMarketOTC {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "market 只能为 E 或 O",
+		})
+		return
+	}
+	h.logger.Info("收到基金基本信息抓取请求", zap.String("market", market))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	count, err := h.dataFetcher.FetchFundBasic(c.Request.Context(), market)
+	if err != nil {
+		h.logger.Error("抓取基金基本信息失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "抓取成功",
+		Data:    gin.H{"count": count},
+	})
+}
+
+// FetchFundDaily 抓取场内基金日线
+//
+// @Summary 抓取场内基金日线
+// @Description 按交易日异步抓取全部场内基金（ETF、LOF 等）的日线行情（fund_daily），无数据的日期视为成功
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/fund-daily [post]
+func (h *Handler) FetchFundDaily(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeFundDaily, req)
+		return
+	}
+
+	h.logger.Info("收到基金日线抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "fund_daily_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchFundDaily(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("抓取基金日线数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "基金日线抓取任务已启动，请查询进度",
+	})
+}
+
+// GetFunds 查询基金列表
+//
+// @Summary 查询基金列表
+// @Description 分页查询已抓取的基金基本信息，可按市场、投资类型、存续状态、管理人过滤，name 按简称模糊匹配
+// @Tags 数据
+// @Produce json
+// @Param market query string false "交易市场 E 场内、O 场外"
+// @Param fund_type query string false "投资类型"
+// @Param status query string false "存续状态 D 摘牌、I 发行、L 已上市"
+// @Param management query string false "管理人"
+// @Param name query string false "简称关键字"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.FundBasic}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/funds [get]
+func (h *Handler) GetFunds(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.FundBasic{}), fundFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	if name := strings.TrimSpace(c.Query("name")); name != "" {
+		db = db.Where("name LIKE ?", "%"+name+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	funds := make([]models.FundBasic, 0)
+	if err := db.Order("ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&funds).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     funds,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// GetFundDaily 查询已存储的场内基金日线
+//
+// @Summary 查询场内基金日线
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "基金代码"
+// @Param ts_codes query string false "基金代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.FundDaily}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/fund/daily [get]
+func (h *Handler) GetFundDaily(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	// 过滤参数与日线数据一致
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.FundDaily{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	bars := make([]models.FundDaily, 0)
+	if err := db.Order("trade_date desc, ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     bars,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
@@ -1,76 +1,217 @@
 package api
 
 import (
-	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	_ "stock_data/docs/swagger"
+	"stock_data/internal/config"
 	"stock_data/internal/database"
 	"stock_data/internal/models"
 	"stock_data/internal/service"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // Handler API 处理器
 type Handler struct {
 	dataFetcher *service.DataFetcher
+	jobQueue    *service.JobQueue
+	config      *config.ServerConfig
 	logger      *zap.Logger
+	statsCache  cachedResult
+	dimsCache   cachedResult
 }
 
 // NewHandler 创建处理器
-func NewHandler(dataFetcher *service.DataFetcher, logger *zap.Logger) *Handler {
+func NewHandler(dataFetcher *service.DataFetcher, jobQueue *service.JobQueue, cfg *config.ServerConfig, logger *zap.Logger) *Handler {
 	return &Handler{
 		dataFetcher: dataFetcher,
+		jobQueue:    jobQueue,
+		config:      cfg,
 		logger:      logger,
 	}
 }
 
 // Response 统一响应结构
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"` // 参数校验失败时逐项列出出错的字段
+}
+
+// PageResult 分页列表数据
+type PageResult struct {
+	List     interface{} `json:"list"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"-"` // v1 不返回，v2 用于生成分页信息
 }
 
 // FetchRequest 抓取请求
 type FetchRequest struct {
-	StartDate   string `json:"start_date" binding:"required"`
-	EndDate     string `json:"end_date" binding:"required"`
-	Concurrency int    `json:"concurrency"`
+	StartDate   string   `json:"start_date"` // 为空时使用 fetcher.start_date
+	EndDate     string   `json:"end_date"`   // 为空时使用 fetcher.end_date，未配置则为当天
+	Concurrency int      `json:"concurrency"`
+	DryRun      bool     `json:"dry_run"`      // 仅预估任务规模，不创建任务
+	TSCodes     []string `json:"ts_codes"`     // 指定股票代码，仅按股票抓取的接口支持（如财务指标）
+	NewestFirst bool     `json:"newest_first"` // 从最近的日期开始抓取，仅日线抓取支持
+	Adj         string   `json:"adj"`          // 复权方式 qfq/hfq，为空不复权，仅复权日线抓取支持
+}
+
+// tsCodePattern 股票代码格式，如 000001.SZ
+var tsCodePattern = service.TSCodePattern
+
+// dataModels 数据类型与对应的行情模型
+var dataModels = map[string]interface{}{
+	"daily":   &models.StockDaily{},
+	"weekly":  &models.StockWeekly{},
+	"monthly": &models.StockMonthly{},
+}
+
+// statsCacheTTL 统计信息缓存时间
+const statsCacheTTL = 30 * time.Second
+
+// dimensionsCacheTTL 筛选维度缓存时间，股票列表变化较少
+const dimensionsCacheTTL = 5 * time.Minute
+
+// MinuteFetchRequest 分钟线抓取请求
+type MinuteFetchRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Freq      string `json:"freq" binding:"required,oneof=1min 5min 15min 30min 60min"`
 }
 
 // RegisterRoutes 注册路由
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
-	api := r.Group("/api/v1")
+	// 接口文档
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// 性能分析，默认关闭
+	if h.config.EnablePprof {
+		registerPprof(r)
+		h.logger.Warn("已开启 pprof 性能分析接口", zap.String("path", "/debug/pprof"))
+	}
+
+	// v1 与 v2 注册相同的接口，响应结构由 respond 按路由前缀区分
+	h.registerAPIRoutes(r.Group(apiV1Prefix))
+	h.registerAPIRoutes(r.Group(apiV2Prefix))
+}
+
+// registerAPIRoutes 在指定版本的路由组下注册接口
+func (h *Handler) registerAPIRoutes(api *gin.RouterGroup) {
+	// 健康检查
+	api.GET("/health", h.HealthCheck)
+
+	// 只读模式下禁用抓取和写入接口
+	readOnly := ReadOnly(h.config.ReadOnly)
+
+	// 配置 api_keys 后抓取和写入接口要求 API Key，查询接口按 protect_data 决定
+	auth := APIKeyAuth(h.config.APIKeys)
+	dataAuth := APIKeyAuth(nil)
+	if h.config.ProtectData {
+		dataAuth = auth
+	}
+
+	// 抓取相关
+	fetch := api.Group("/fetch", auth, readOnly)
 	{
-		// 健康检查
-		api.GET("/health", h.HealthCheck)
-
-		// 抓取相关
-		fetch := api.Group("/fetch")
-		{
-			fetch.POST("/stock-basic", h.FetchStockBasic)
-			fetch.POST("/daily", h.FetchDaily)
-			fetch.GET("/progress/:task_id", h.GetProgress)
-			fetch.GET("/tasks", h.ListTasks)
-			fetch.POST("/weekly", h.FetchWeekly) // 新增：周线数据抓取
-			fetch.POST("/monthly", h.FetchMonthly)
-		}
+		fetch.POST("/stock-basic", h.FetchStockBasic)
+		fetch.POST("/stock-company", h.FetchStockCompany)
+		fetch.POST("/index-basic", h.FetchIndexBasic)
+		fetch.POST("/fund-basic", h.FetchFundBasic)
+		fetch.POST("/hs-const", h.FetchHSConst)
+		fetch.POST("/calendar", h.FetchTradeCalendar)
+		fetch.POST("/daily", h.FetchDaily)
+		fetch.POST("/daily/date/:trade_date", h.RefetchDailyDate)
+		fetch.POST("/daily/sync", h.FetchDailySync)
+		fetch.POST("/daily/resume/:task_id", h.ResumeDaily)
+		fetch.GET("/daily/status", h.GetDailyStatus)
+		fetch.GET("/progress/:task_id", h.GetProgress)
+		fetch.GET("/jobs/:job_id", h.GetJob)
+		fetch.GET("/summary", h.GetFetchSummary)
+		fetch.GET("/tasks", h.ListTasks)
+		fetch.GET("/tasks/:task_id/logs", h.GetTaskLogs)
+		fetch.GET("/failures", h.ListFailures)
+		fetch.GET("/running", h.ListRunningTasks)
+		fetch.GET("/tushare/check", h.CheckTushareToken)
+		fetch.POST("/weekly", h.FetchWeekly) // 新增：周线数据抓取
+		fetch.POST("/weekly/derive", h.DeriveWeekly)
+		fetch.POST("/monthly", h.FetchMonthly)
+		fetch.POST("/fina-indicator", h.FetchFinaIndicator)
+		fetch.POST("/concepts", h.FetchConcepts)
+		fetch.POST("/minute", h.FetchMinute)
+		fetch.POST("/top-list", h.FetchTopList)
+		fetch.POST("/block-trade", h.FetchBlockTrade)
+		fetch.POST("/margin", h.FetchMarginDetail)
+		fetch.POST("/adj-factor", h.FetchAdjFactor)
+		fetch.POST("/daily-adj", h.FetchDailyAdj)
+		fetch.POST("/fund-daily", h.FetchFundDaily)
+	}
 
-		// 数据查询
-		data := api.Group("/data")
-		{
-			data.GET("/stocks", h.GetStocks)
-			data.GET("/daily", h.GetDailyData)
-			data.GET("/stock/:ts_code", h.GetStockInfo)
-		}
+	// 数据统计
+	api.GET("/stats", dataAuth, h.GetStats)
+
+	// 数据查询
+	data := api.Group("/data", dataAuth)
+	{
+		data.GET("/stocks", h.GetStocks)
+		data.GET("/new-listings", h.GetNewListings)
+		data.GET("/indices", h.GetIndices)
+		data.GET("/funds", h.GetFunds)
+		data.GET("/daily", h.GetDailyData)
+		data.DELETE("/daily", auth, readOnly, h.DeleteData)
+		data.POST(strings.TrimPrefix(DailyImportRoute, "/data"), auth, readOnly, BodyLimit(h.config.MaxImportBytes), h.ImportDailyData)
+		data.GET("/daily/export", h.ExportDailyData)
+		data.GET("/daily/ma", h.GetDailyMA)
+		data.GET("/daily/changes", h.GetDailyChanges)
+		data.GET("/daily/adjusted", h.GetAdjustedDaily)
+		data.GET("/daily/anomalies", h.GetDailyAnomalies)
+		data.GET("/daily/candles", h.GetDailyCandles)
+		data.GET("/daily/returns", h.GetDailyReturns)
+		data.GET("/daily/volatility", h.GetDailyVolatility)
+		data.GET("/daily/correlation", h.GetDailyCorrelation)
+		data.GET("/stock/:ts_code", h.GetStockInfo)
+		data.GET("/latest", h.GetLatest)
+		data.GET("/latest-date", h.GetLatestTradeDate)
+		data.GET("/coverage", h.GetCoverage)
+		data.GET("/health/coverage", h.GetCoverageHealth)
+		data.GET("/top-list", h.GetTopList)
+		data.GET("/block-trade", h.GetBlockTrade)
+		data.GET("/margin", h.GetMarginDetail)
+		data.GET("/adj-factor", h.GetAdjFactor)
+		data.GET("/fund/daily", h.GetFundDaily)
+		data.GET("/calendar", h.GetTradeCalendar)
+		data.GET("/calendar/next", h.GetNextTradingDays)
+		data.GET("/dimensions", h.GetDimensions)
+	}
+
+	// 维护操作
+	admin := api.Group("/admin", auth, readOnly)
+	{
+		admin.POST("/normalize-dates", h.NormalizeDates)
+		admin.POST("/verify", h.VerifyDaily)
 	}
 }
 
 // HealthCheck 健康检查
+//
+// @Summary 健康检查
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} Response
+// @Router /health [get]
 func (h *Handler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "OK",
 		Data: gin.H{
@@ -79,136 +220,2329 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 	})
 }
 
+// TokenCheck Tushare Token 校验结果
+type TokenCheck struct {
+	Valid     bool   `json:"valid"`
+	Code      int    `json:"code"`    // Tushare 返回码，0 表示成功
+	Message   string `json:"message"` // Tushare 返回信息
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// CheckTushareToken 校验 Tushare Token 是否有效
+//
+// @Summary 校验 Tushare Token
+// @Description 发起一次单日 trade_cal 请求，返回 Token 是否有效及 Tushare 的错误码和信息；无法连接 Tushare 时返回 502
+// @Tags 抓取
+// @Produce json
+// @Success 200 {object} Response{data=TokenCheck}
+// @Failure 502 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/tushare/check [get]
+func (h *Handler) CheckTushareToken(c *gin.Context) {
+	start := time.Now()
+	err := h.dataFetcher.CheckToken(c.Request.Context())
+	check := TokenCheck{
+		Valid:     err == nil,
+		Message:   "success",
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	var apiErr *service.APIError
+	switch {
+	case err == nil:
+	case errors.As(err, &apiErr):
+		check.Code = apiErr.Code
+		check.Message = apiErr.Msg
+		h.logger.Warn("Tushare Token 校验未通过", zap.Int("code", apiErr.Code), zap.String("msg", apiErr.Msg))
+	default:
+		h.logger.Error("连接 Tushare 失败", zap.Error(err))
+		respond(c, http.StatusBadGateway, Response{
+			Code:    502,
+			Message: "连接 Tushare 失败: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    check,
+	})
+}
+
 // FetchStockBasic 抓取股票基本信息
+//
+// @Summary 抓取股票基本信息
+// @Description 默认按 fetcher.stock_list_status 抓取；all 为 true 时按 fetcher.stock_list_statuses 依次抓取上市、退市、暂停上市的股票并合并保存，返回各状态的股票数
+// @Tags 抓取
+// @Produce json
+// @Param all query bool false "是否抓取全部上市状态" default(false)
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/stock-basic [post]
 func (h *Handler) FetchStockBasic(c *gin.Context) {
-	h.logger.Info("收到股票基本信息抓取请求")
+	all := c.Query("all") == "true"
+	h.logger.Info("收到股票基本信息抓取请求", zap.Bool("all", all))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	if all {
+		counts, err := h.dataFetcher.FetchAllStockBasic(c.Request.Context(), nil)
+		if err != nil {
+			h.logger.Error("抓取股票基本信息失败", zap.Error(err))
+			respond(c, http.StatusInternalServerError, Response{
+				Code:    500,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		respond(c, http.StatusOK, Response{
+			Code:    0,
+			Message: "抓取成功",
+			Data:    gin.H{"counts": counts},
+		})
+		return
+	}
 
-	if err := h.dataFetcher.FetchStockBasic(); err != nil {
+	if err := h.dataFetcher.FetchStockBasic(c.Request.Context()); err != nil {
 		h.logger.Error("抓取股票基本信息失败", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, Response{
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "抓取成功",
+	})
+}
+
+// FetchIndexBasic 抓取指数基本信息
+//
+// @Summary 抓取指数基本信息
+// @Tags 抓取
+// @Produce json
+// @Param market query string false "市场 SSE/SZSE/CSI/SW 等，为空抓取全部市场"
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/index-basic [post]
+func (h *Handler) FetchIndexBasic(c *gin.Context) {
+	market := strings.ToUpper(strings.TrimSpace(c.Query("market")))
+	h.logger.Info("收到指数基本信息抓取请求", zap.String("market", market))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	count, err := h.dataFetcher.FetchIndexBasic(c.Request.Context(), market)
+	if err != nil {
+		h.logger.Error("抓取指数基本信息失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "抓取成功",
+		Data:    gin.H{"count": count},
+	})
+}
+
+// FetchHSConst 抓取沪深股通成分
+//
+// @Summary 抓取沪深股通成分
+// @Description 抓取沪股通、深股通的当前成分及已剔除的历史记录
+// @Tags 抓取
+// @Produce json
+// @Param hs_type query string false "类型 SH 沪股通、SZ 深股通，为空抓取两者" Enums(SH, SZ)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/hs-const [post]
+func (h *Handler) FetchHSConst(c *gin.Context) {
+	hsType := strings.ToUpper(strings.TrimSpace(c.Query("hs_type")))
+	if hsType != "" && hsType != models.HSTypeSH && hsType != models.HSTypeSZ {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "hs_type 只能为 SH 或 SZ",
+		})
+		return
+	}
+	h.logger.Info("收到沪深股通成分抓取请求", zap.String("hs_type", hsType))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	count, err := h.dataFetcher.FetchHSConst(c.Request.Context(), hsType)
+	if err != nil {
+		h.logger.Error("抓取沪深股通成分失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "抓取成功",
+		Data:    gin.H{"count": count},
+	})
+}
+
+// FetchTradeCalendar 抓取交易日历
+//
+// @Summary 抓取交易日历
+// @Description 抓取沪深交易所在日期范围内的交易日历（含休市日及上一个交易日）并保存，重复抓取会更新已有记录
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数，只使用 start_date、end_date"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/calendar [post]
+func (h *Handler) FetchTradeCalendar(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+	h.logger.Info("收到交易日历抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	count, err := h.dataFetcher.FetchTradeCalendar(c.Request.Context(), req.StartDate, req.EndDate)
+	if err != nil {
+		h.logger.Error("抓取交易日历失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
 			Code:    500,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "抓取成功",
+		Data:    gin.H{"count": count},
+	})
+}
+
+// FetchConcepts 抓取概念及行业分类
+//
+// @Summary 抓取概念及行业分类
+// @Description 异步抓取概念分类与申万一级行业成分
+// @Tags 抓取
+// @Produce json
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/concepts [post]
+func (h *Handler) FetchConcepts(c *gin.Context) {
+	h.logger.Info("收到概念及行业分类抓取请求")
+
+	key, hash, ok := h.idempotentTask(c, "concept_task_", nil)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchConcepts(service.WithIdempotencyKey(ctx, key, hash))
+		if err != nil {
+			h.logger.Error("抓取概念及行业分类失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "概念及行业分类抓取任务已启动，请查询进度",
 	})
 }
 
 // FetchDaily 抓取日线数据
+//
+// @Summary 抓取日线数据
+// @Description 按交易日抓取全市场日线数据：作业加入队列后返回，由后台 worker 执行，返回的 task_id 为入队时创建的任务，可直接查询进度；dry_run 为 true 时返回任务预估 service.FetchPlan
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次作业"
+// @Success 200 {object} Response{data=models.FetchJob} "排队中的作业"
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 422 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily [post]
 func (h *Handler) FetchDaily(c *gin.Context) {
 	var req FetchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, Response{
-			Code:    400,
-			Message: "参数错误: " + err.Error(),
-		})
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeDaily, req)
+		return
+	}
+	if !h.checkFetchDays(c, req) {
+		return
+	}
+
+	h.logger.Info("收到日线数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate),
+		zap.Bool("newest_first", req.NewestFirst))
+
+	h.enqueueJob(c, service.JobTypeDaily, service.JobParams{
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		NewestFirst: req.NewestFirst,
+	})
+}
+
+// bindJSON 解析 JSON 请求体，请求体超过大小限制时返回 413，其余错误返回 400
+func (h *Handler) bindJSON(c *gin.Context, req interface{}) bool {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respond(c, http.StatusRequestEntityTooLarge, Response{
+			Code:    413,
+			Message: fmt.Sprintf("请求体超过大小限制（%d 字节）", maxBytesErr.Limit),
+		})
+		return false
+	}
+
+	respondValidationError(c, err)
+	return false
+}
+
+// bindFetchRequest 解析并校验抓取请求：补全默认日期后校验日期格式、日期范围、股票代码数量及格式
+// allowTSCodes 为 false 的接口（按日期抓取全市场）不接受 ts_codes
+func (h *Handler) bindFetchRequest(c *gin.Context, req *FetchRequest, allowTSCodes bool) bool {
+	if !h.bindJSON(c, req) {
+		return false
+	}
+
+	err := h.sanitizeFetchRequest(req)
+	if err == nil && !allowTSCodes && len(req.TSCodes) > 0 {
+		err = newFieldError("ts_codes", "unsupported", "该接口不支持 ts_codes")
+	}
+	if err != nil {
+		respondValidationError(c, err)
+		return false
+	}
+	return true
+}
+
+// checkFetchDays 检查日期范围是否超过 server.max_fetch_days，超过时返回 400 并提示拆分请求
+// 只用于日线、周线、月线抓取，dry run 不受限制，便于预估后再拆分
+func (h *Handler) checkFetchDays(c *gin.Context, req FetchRequest) bool {
+	if h.config.MaxFetchDays <= 0 {
+		return true
+	}
+
+	// 日期已由 sanitizeFetchRequest 校验
+	start, _ := service.ParseDate(req.StartDate)
+	end, _ := service.ParseDate(req.EndDate)
+	days := int(end.Sub(start).Hours()/24) + 1
+	if days <= h.config.MaxFetchDays {
+		return true
+	}
+
+	respondValidationError(c, newFieldError("end_date", "max_fetch_days",
+		"日期范围 %s-%s 共 %d 天，超过单次抓取上限 %d 天，请拆分为多个请求",
+		req.StartDate, req.EndDate, days, h.config.MaxFetchDays))
+	return false
+}
+
+// sanitizeFetchRequest 清理抓取请求中的空白字符并校验取值
+func (h *Handler) sanitizeFetchRequest(req *FetchRequest) error {
+	req.StartDate = strings.TrimSpace(req.StartDate)
+	req.EndDate = strings.TrimSpace(req.EndDate)
+
+	// 未传日期时使用配置的默认范围
+	defaultStart, defaultEnd := h.dataFetcher.DefaultDateRange()
+	if req.StartDate == "" {
+		if defaultStart == "" {
+			return newFieldError("start_date", "required", "start_date 不能为空（未配置 fetcher.start_date）")
+		}
+		req.StartDate = defaultStart
+	}
+	if req.EndDate == "" {
+		req.EndDate = defaultEnd
+	}
+
+	start, err := service.ParseDate(req.StartDate)
+	if err != nil {
+		return newFieldError("start_date", "format", "start_date 格式错误，应为 YYYYMMDD")
+	}
+	end, err := service.ParseDate(req.EndDate)
+	if err != nil {
+		return newFieldError("end_date", "format", "end_date 格式错误，应为 YYYYMMDD")
+	}
+	if end.Before(start) {
+		return newFieldError("end_date", "range", "end_date 不能早于 start_date")
+	}
+
+	if len(req.TSCodes) > h.config.MaxTSCodes {
+		return newFieldError("ts_codes", "max", "ts_codes 数量超过上限 %d", h.config.MaxTSCodes)
+	}
+	for i, code := range req.TSCodes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !tsCodePattern.MatchString(code) {
+			return newFieldError("ts_codes", "format", "股票代码格式错误: %s", req.TSCodes[i])
+		}
+		req.TSCodes[i] = code
+	}
+
+	return nil
+}
+
+// respondFetchPlan 返回抓取任务预估结果（dry run）
+func (h *Handler) respondFetchPlan(c *gin.Context, dataType string, req FetchRequest) {
+	plan, err := h.dataFetcher.PlanFetch(c.Request.Context(), dataType, req.StartDate, req.EndDate, req.TSCodes)
+	if err != nil {
+		h.logger.Error("预估抓取任务失败", zap.String("type", dataType), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "dry run",
+		Data:    plan,
+	})
+}
+
+// checkFetchWindow 不在 allowed_hours 时段内时返回 403
+func (h *Handler) checkFetchWindow(c *gin.Context) bool {
+	if h.dataFetcher.InFetchWindow(time.Now()) {
+		return true
+	}
+
+	h.logger.Warn("当前不在允许的抓取时段", zap.String("allowed_hours", h.dataFetcher.AllowedHours()))
+	respond(c, http.StatusForbidden, Response{
+		Code:    403,
+		Message: fmt.Sprintf("当前不在允许的抓取时段（%s，%s），请在该时段内再试", h.dataFetcher.AllowedHours(), service.Location()),
+	})
+	return false
+}
+
+// acquireTask 占用全局任务名额，不在 allowed_hours 时段内返回 403，已达上限时返回 429
+func (h *Handler) acquireTask(c *gin.Context) bool {
+	if !h.checkFetchWindow(c) {
+		return false
+	}
+
+	if h.dataFetcher.TryAcquireTask() {
+		return true
+	}
+
+	_, limit := h.dataFetcher.TaskSlots()
+	h.logger.Warn("运行中的抓取任务已达上限", zap.Int("limit", limit))
+	respond(c, http.StatusTooManyRequests, Response{
+		Code:    429,
+		Message: fmt.Sprintf("运行中的抓取任务已达上限（%d 个），请等待已有任务完成后再试", limit),
+	})
+	return false
+}
+
+// maxIdempotencyKeyLength Idempotency-Key 请求头的最大长度
+const maxIdempotencyKeyLength = 100
+
+// enqueueJob 将抓取作业加入队列并返回排队中的作业，不在 allowed_hours 时段内返回 403
+// 作业由后台 worker 执行，不占用请求时的任务名额，名额已满时排队等待而不是返回 429
+// 带 Idempotency-Key 请求头时，有效期内的重复请求返回已有作业，键已用于不同参数时返回 422
+func (h *Handler) enqueueJob(c *gin.Context, jobType string, params service.JobParams) {
+	key, ok := h.idempotencyKey(c)
+	if !ok {
+		return
+	}
+
+	if !h.checkFetchWindow(c) {
+		return
+	}
+
+	job, created, err := h.jobQueue.EnqueueIdempotent(jobType, params, key)
+	if errors.Is(err, service.ErrIdempotencyKeyMismatch) {
+		respond(c, http.StatusUnprocessableEntity, Response{
+			Code:    422,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("创建抓取作业失败", zap.String("type", jobType), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "作业已加入队列，请查询作业状态"
+	if !created {
+		h.logger.Info("重复的抓取请求，返回已有作业", zap.String("job_id", job.JobID), zap.String("idempotency_key", key))
+		message = "相同 Idempotency-Key 的作业已存在"
+	}
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: message,
+		Data:    job,
+	})
+}
+
+// idempotencyKey 读取 Idempotency-Key 请求头，超过长度限制时返回 400
+func (h *Handler) idempotencyKey(c *gin.Context) (string, bool) {
+	key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+	if len(key) > maxIdempotencyKeyLength {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: fmt.Sprintf("Idempotency-Key 不能超过 %d 个字符", maxIdempotencyKeyLength),
+		})
+		return "", false
+	}
+	return key, true
+}
+
+// idempotentTask 检查直接创建抓取任务的接口的 Idempotency-Key，返回需随任务保存的键和请求参数哈希（通过 service.WithIdempotencyKey）
+// params 为校验并补全默认值后的请求参数，没有参数时为 nil。有效期内已有任务使用该键时返回已有任务；
+// 该任务的类型（taskPrefix 为 task_id 前缀）或请求参数不同时返回 422。返回 false 表示已响应，不再创建任务
+func (h *Handler) idempotentTask(c *gin.Context, taskPrefix string, params interface{}) (string, string, bool) {
+	key, ok := h.idempotencyKey(c)
+	if !ok || key == "" {
+		return key, "", ok
+	}
+	hash := service.IdempotencyParamsHash(params)
+
+	task, err := h.dataFetcher.FindTaskByIdempotencyKey(key)
+	if err != nil {
+		h.logger.Error("查询幂等键失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return "", "", false
+	}
+	if task == nil {
+		return key, hash, true
+	}
+
+	if !strings.HasPrefix(task.TaskID, taskPrefix) || task.IdempotencyHash != hash {
+		respond(c, http.StatusUnprocessableEntity, Response{
+			Code:    422,
+			Message: service.ErrIdempotencyKeyMismatch.Error(),
+		})
+		return "", "", false
+	}
+	h.logger.Info("重复的抓取请求，返回已有任务", zap.String("task_id", task.TaskID), zap.String("idempotency_key", key))
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: service.ErrDuplicateIdempotencyKey.Error(),
+		Data:    task,
+	})
+	return "", "", false
+}
+
+// GetJob 查询抓取作业
+//
+// @Summary 查询抓取作业
+// @Description 返回作业状态（queued/running/completed/failed）；task_id 为关联的抓取任务，日线作业入队时即有值，可用于查询进度
+// @Tags 任务
+// @Produce json
+// @Param job_id path string true "作业ID"
+// @Success 200 {object} Response{data=models.FetchJob}
+// @Failure 404 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/jobs/{job_id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	job, err := h.jobQueue.GetJob(c.Param("job_id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, Response{
+			Code:    404,
+			Message: "作业不存在",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    job,
+	})
+}
+
+// GetFetchSummary 按周期汇总抓取结果
+//
+// @Summary 抓取结果汇总
+// @Description 根据任务检查点按日、周（ISO 周）或月汇总日线、周线、月线抓取的成功/失败日期数，同一日期以最近一次抓取结果为准，没有抓取记录的周期不返回
+// @Tags 任务
+// @Produce json
+// @Param type query string true "数据类型" Enums(daily, weekly, monthly)
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Success 200 {object} Response{data=service.FetchSummary}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/summary [get]
+func (h *Handler) GetFetchSummary(c *gin.Context) {
+	dataType := c.Query("type")
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	switch dataType {
+	case service.PlanTypeDaily, service.PlanTypeWeekly, service.PlanTypeMonthly:
+	default:
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "type 应为 daily、weekly 或 monthly",
+		})
+		return
+	}
+
+	if _, _, ok := parseDateRangeQuery(c); !ok {
+		return
+	}
+
+	summary, err := h.dataFetcher.SummarizeFetches(dataType, startDate, endDate)
+	if err != nil {
+		h.logger.Error("汇总抓取结果失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    summary,
+	})
+}
+
+// RefetchDailyDate 重新抓取指定交易日的日线数据（删除该日期已有数据后重新写入）
+//
+// @Summary 重新抓取单日日线数据
+// @Description 删除指定交易日已存储的日线数据并重新抓取写入，Tushare 未返回数据时报错并保留已有数据
+// @Tags 抓取
+// @Produce json
+// @Param trade_date path string true "交易日期 YYYYMMDD"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily/date/{trade_date} [post]
+func (h *Handler) RefetchDailyDate(c *gin.Context) {
+	tradeDate := c.Param("trade_date")
+
+	if _, err := service.ParseDate(tradeDate); err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "日期格式错误，应为 YYYYMMDD",
+		})
+		return
+	}
+
+	isTradeDay, err := h.dataFetcher.IsTradeDay(c.Request.Context(), tradeDate)
+	if err != nil {
+		h.logger.Error("获取交易日历失败", zap.String("trade_date", tradeDate), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+	if !isTradeDay {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: tradeDate + " 不是交易日",
+		})
+		return
+	}
+
+	h.logger.Info("收到单日日线数据重新抓取请求", zap.String("trade_date", tradeDate))
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	count, err := h.dataFetcher.RefetchDailyDate(c.Request.Context(), tradeDate)
+	if err != nil {
+		h.logger.Error("重新抓取日线数据失败", zap.String("trade_date", tradeDate), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "重新抓取成功",
+		Data: gin.H{
+			"trade_date": tradeDate,
+			"count":      count,
+		},
+	})
+}
+
+// SyncFetchRequest 同步抓取单只股票日线的请求
+type SyncFetchRequest struct {
+	TSCode    string `json:"ts_code" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+// SyncFetchResult 同步抓取结果
+type SyncFetchResult struct {
+	TSCode string                   `json:"ts_code"`
+	Total  int                      `json:"total"`
+	List   []service.StockDailyData `json:"list"`
+}
+
+// FetchDailySync 从 Tushare 同步拉取单只股票的日线并直接返回
+//
+// @Summary 同步抓取单只股票日线
+// @Description 按交易日逐日调用 Tushare（受限流控制），数据直接在响应中返回，不写入数据库；日期范围最多 60 个交易日
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body SyncFetchRequest true "抓取参数"
+// @Success 200 {object} Response{data=SyncFetchResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily/sync [post]
+func (h *Handler) FetchDailySync(c *gin.Context) {
+	var req SyncFetchRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if !tsCodePattern.MatchString(req.TSCode) {
+		respondValidationError(c, newFieldError("ts_code", "format", "股票代码格式错误: %s", req.TSCode))
+		return
+	}
+	start, err := service.ParseDate(req.StartDate)
+	if err != nil {
+		respondValidationError(c, newFieldError("start_date", "format", "start_date 格式错误，应为 YYYYMMDD"))
+		return
+	}
+	end, err := service.ParseDate(req.EndDate)
+	if err != nil {
+		respondValidationError(c, newFieldError("end_date", "format", "end_date 格式错误，应为 YYYYMMDD"))
+		return
+	}
+	if start.After(end) {
+		respondValidationError(c, newFieldError("end_date", "range", "end_date 不能早于 start_date"))
+		return
+	}
+
+	if !h.acquireTask(c) {
+		return
+	}
+	defer h.dataFetcher.ReleaseTask()
+
+	data, err := h.dataFetcher.FetchDailySync(c.Request.Context(), req.TSCode, req.StartDate, req.EndDate)
+	if errors.Is(err, service.ErrRangeTooLarge) {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("同步抓取日线数据失败", zap.String("ts_code", req.TSCode), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: SyncFetchResult{
+			TSCode: req.TSCode,
+			Total:  len(data),
+			List:   data,
+		},
+	})
+}
+
+// ResumeDaily 断点续传日线抓取任务
+//
+// @Summary 断点续传日线抓取任务
+// @Description 继续执行中断的日线抓取任务，只抓取尚未完成的日期
+// @Tags 抓取
+// @Produce json
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily/resume/{task_id} [post]
+func (h *Handler) ResumeDaily(c *gin.Context) {
+	taskID := c.Param("task_id")
+
+	if _, err := h.dataFetcher.GetTaskProgress(taskID); err != nil {
+		respond(c, http.StatusNotFound, Response{
+			Code:    404,
+			Message: "任务不存在",
+		})
+		return
+	}
+
+	h.logger.Info("收到日线抓取续传请求", zap.String("task_id", taskID))
+
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchDailyResume(ctx, taskID)
+		if err != nil {
+			h.logger.Error("续传日线抓取任务失败", zap.String("task_id", taskID), zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "续传任务已启动，请查询进度",
+	})
+}
+
+// GetProgress 获取抓取进度
+//
+// @Summary 查询抓取进度
+// @Tags 任务
+// @Produce json
+// @Param task_id path string true "任务ID"
+// @Success 200 {object} Response{data=models.FetchTask}
+// @Failure 404 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/progress/{task_id} [get]
+func (h *Handler) GetProgress(c *gin.Context) {
+	taskID := c.Param("task_id")
+
+	task, err := h.dataFetcher.GetTaskProgress(taskID)
+	if err != nil {
+		respond(c, http.StatusNotFound, Response{
+			Code:    404,
+			Message: "任务不存在",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    task,
+	})
+}
+
+// GetDailyStatus 获取日线抓取任务的状态
+//
+// @Summary 查询日线抓取任务状态
+// @Description 返回日线抓取任务的进度及已发起的 Tushare 请求数（api_calls），运行中的任务同时返回预计还需的请求数（estimated_remaining_calls），
+// @Description 按已完成日期/股票的平均请求数乘以剩余数量（total_count - success_count - failed_count）估算。不指定 task_id 时返回最近启动的日线任务
+// @Tags 任务
+// @Produce json
+// @Param task_id query string false "日线任务ID，如 task_1701417600"
+// @Success 200 {object} Response{data=models.FetchTask}
+// @Failure 404 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily/status [get]
+func (h *Handler) GetDailyStatus(c *gin.Context) {
+	task, err := h.dataFetcher.GetDailyTaskStatus(c.Query("task_id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, Response{
+			Code:    404,
+			Message: "日线任务不存在",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    task,
+	})
+}
+
+// ListTasks 获取任务列表
+//
+// @Summary 获取任务列表
+// @Tags 任务
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.FetchTask}}
+// @Security ApiKeyAuth
+// @Router /fetch/tasks [get]
+func (h *Handler) ListTasks(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	var tasks []models.FetchTask
+	var total int64
+
+	db := database.GetDB()
+	db.Model(&models.FetchTask{}).Count(&total)
+	db.Order("created_at desc").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&tasks)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     tasks,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// RunningTask 运行中的任务
+type RunningTask struct {
+	models.FetchTask
+	ElapsedSeconds int64 `json:"elapsed_seconds"` // 已运行时长（秒）
+}
+
+// RunningTasks 运行中的任务列表及任务名额占用情况
+type RunningTasks struct {
+	Tasks       []RunningTask `json:"tasks"`
+	ActiveSlots int           `json:"active_slots"` // 当前进程占用的任务名额
+	MaxSlots    int           `json:"max_slots"`    // 任务名额上限
+}
+
+// ListRunningTasks 获取所有运行中的任务
+//
+// @Summary 获取运行中的任务
+// @Description 返回所有 status 为 running 的任务及其进度、已运行时长，以及全局任务名额占用情况
+// @Tags 任务
+// @Produce json
+// @Success 200 {object} Response{data=RunningTasks}
+// @Security ApiKeyAuth
+// @Router /fetch/running [get]
+func (h *Handler) ListRunningTasks(c *gin.Context) {
+	var tasks []models.FetchTask
+	if err := database.GetDB().
+		Where("status = ?", models.TaskStatusRunning).
+		Order("start_time asc").
+		Find(&tasks).Error; err != nil {
+		h.logger.Error("查询运行中任务失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	running := make([]RunningTask, 0, len(tasks))
+	for _, task := range tasks {
+		running = append(running, RunningTask{
+			FetchTask:      task,
+			ElapsedSeconds: int64(now.Sub(task.StartTime).Seconds()),
+		})
+	}
+
+	active, limit := h.dataFetcher.TaskSlots()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: RunningTasks{
+			Tasks:       running,
+			ActiveSlots: active,
+			MaxSlots:    limit,
+		},
+	})
+}
+
+// stockFilters 股票列表允许的过滤参数
+var stockFilters = []queryFilter{
+	{Param: "industry", Condition: "stock_basic.industry = ?"},
+	{Param: "area", Condition: "stock_basic.area = ?"},
+	{Param: "market", Condition: "stock_basic.market = ?"},
+	{Param: "list_status", Condition: "stock_basic.list_status = ?"},
+}
+
+// stockSortColumns 股票列表允许的排序字段
+var stockSortColumns = map[string]string{
+	"ts_code":   "stock_basic.ts_code",
+	"symbol":    "stock_basic.symbol",
+	"name":      "stock_basic.name",
+	"list_date": "stock_basic.list_date",
+	"industry":  "stock_basic.industry",
+	"area":      "stock_basic.area",
+	"market":    "stock_basic.market",
+}
+
+// GetStocks 获取股票列表
+//
+// @Summary 获取股票列表
+// @Description 支持按行业、地域、市场筛选，按白名单字段排序；存在 daily_basic（带表名前缀）且含 total_mv 列时可按最新总市值排序
+// @Tags 数据
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Param concept query string false "概念代码"
+// @Param industry_code query string false "申万行业指数代码"
+// @Param industry query string false "行业"
+// @Param area query string false "地域"
+// @Param market query string false "市场类型"
+// @Param list_status query string false "上市状态"
+// @Param hs_connect query bool false "为 true 时只返回当前在沪深股通名单中的股票"
+// @Param hs_type query string false "配合 hs_connect 只筛选沪股通 SH 或深股通 SZ" Enums(SH, SZ)
+// @Param sort query string false "排序字段" Enums(ts_code, symbol, name, list_date, industry, area, market, total_mv) default(ts_code)
+// @Param order query string false "排序方向" Enums(asc, desc) default(asc)
+// @Success 200 {object} Response{data=PageResult{list=[]models.StockBasic}}
+// @Failure 400 {object} Response
+// @Router /data/stocks [get]
+func (h *Handler) GetStocks(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	concept := c.Query("concept")
+	industryCode := c.Query("industry_code")
+	hsConnect := c.Query("hs_connect") == "true"
+	hsType := strings.ToUpper(c.Query("hs_type"))
+	sort := c.DefaultQuery("sort", "ts_code")
+	order := strings.ToLower(c.DefaultQuery("order", "asc"))
+
+	if order != "asc" && order != "desc" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "不支持的排序方向: " + order,
+		})
+		return
+	}
+	if hsType != "" && hsType != models.HSTypeSH && hsType != models.HSTypeSZ {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "hs_type 只能为 SH 或 SZ",
+		})
+		return
+	}
+
+	// 以 stock_basic 为别名，过滤和排序条件中的列名不受表名前缀影响
+	db := database.GetDB().Table(models.StockBasic{}.TableName() + " AS stock_basic")
+
+	var orderBy string
+	if sort == "total_mv" {
+		// 每日指标表（加上表名前缀）存在且含 total_mv 列时才支持按总市值排序
+		dailyBasicTable := models.DailyBasicTable()
+		if migrator := database.GetDB().Migrator(); !migrator.HasTable(dailyBasicTable) || !migrator.HasColumn(dailyBasicTable, "total_mv") {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "暂无每日指标数据，不支持按总市值排序",
+			})
+			return
+		}
+		// 关联最新交易日的总市值，无市值数据的股票排在最后
+		db = db.Joins("LEFT JOIN (SELECT ts_code, total_mv FROM " + dailyBasicTable +
+			" WHERE trade_date = (SELECT MAX(trade_date) FROM " + dailyBasicTable + ")) mv ON mv.ts_code = stock_basic.ts_code")
+		orderBy = "mv.total_mv IS NULL, mv.total_mv " + order
+	} else {
+		column, ok := stockSortColumns[sort]
+		if !ok {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "不支持的排序字段: " + sort,
+			})
+			return
+		}
+		orderBy = column + " " + order
+	}
+
+	db, err := h.applyFilters(c, db, stockFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	// 按概念/申万行业筛选
+	if concept != "" {
+		db = db.Where("stock_basic.ts_code IN (?)", database.GetDB().Model(&models.StockConcept{}).
+			Select("ts_code").
+			Where("type = ? AND code = ?", models.ClassifyTypeConcept, concept))
+	}
+	if industryCode != "" {
+		db = db.Where("stock_basic.ts_code IN (?)", database.GetDB().Model(&models.StockConcept{}).
+			Select("ts_code").
+			Where("type = ? AND code = ?", models.ClassifyTypeIndustry, industryCode))
+	}
+	// 按沪深股通名单筛选
+	if hsConnect {
+		db = db.Where("stock_basic.ts_code IN (?)", service.ConnectEligibleCodes(database.GetDB(), hsType, time.Now()))
+	}
+
+	var stocks []models.StockBasic
+	var total int64
+
+	db.Count(&total)
+	db.Select("stock_basic.*").
+		Order(orderBy).
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&stocks)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     stocks,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// GetDailyData 获取日线数据
+//
+// @Summary 获取日线数据
+// @Description 默认按 page 分页（OFFSET），页数很深时变慢。传 cursor 参数时改为按 (trade_date, id) 倒序的游标分页，
+// @Description data 为 CursorResult（list、next_cursor），不返回总数，next_cursor 为空表示已无更多数据
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Param cursor query string false "游标分页：首页传空值（cursor=），之后传上一页返回的 next_cursor；不传时按 page 分页"
+// @Success 200 {object} Response{data=PageResult{list=[]models.StockDaily}}
+// @Failure 400 {object} Response
+// @Router /data/daily [get]
+func (h *Handler) GetDailyData(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.StockDaily{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.respondDailyCursorPage(c, db, cursor, p.PageSize)
+		return
+	}
+
+	var dailyData []models.StockDaily
+	var total int64
+
+	db.Count(&total)
+	db.Order("trade_date desc").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&dailyData)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     dailyData,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// respondDailyCursorPage 按 (trade_date, id) 倒序返回游标之后的一页日线，不统计总数
+// 与 OFFSET 分页不同，翻页耗时不随页数增加，翻页期间写入的新数据也不会导致重复或遗漏
+func (h *Handler) respondDailyCursorPage(c *gin.Context, db *gorm.DB, cursor string, pageSize int) {
+	if cursor != "" {
+		values, err := decodeCursor(cursor, 2)
+		var tradeDate time.Time
+		var id uint64
+		if err == nil {
+			tradeDate, err = service.ParseDate(values[0])
+		}
+		if err == nil {
+			id, err = strconv.ParseUint(values[1], 10, 64)
+		}
+		if err != nil {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "无效的游标",
+			})
+			return
+		}
+		db = db.Where("(trade_date < ? OR (trade_date = ? AND id < ?))", tradeDate, tradeDate, id)
+	}
+
+	dailyData := make([]models.StockDaily, 0)
+	if err := db.Order("trade_date desc, id desc").
+		Limit(pageSize).
+		Find(&dailyData).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	// 本页已满时才可能还有下一页
+	var nextCursor string
+	if len(dailyData) == pageSize {
+		last := dailyData[len(dailyData)-1]
+		nextCursor = encodeCursor(last.TradeDate.In(service.Location()).Format("20060102"), strconv.FormatUint(uint64(last.ID), 10))
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: CursorResult{
+			List:       dailyData,
+			NextCursor: nextCursor,
+		},
+	})
+}
+
+// GetLatest 获取每只股票的最新日线快照
+//
+// @Summary 最新行情快照
+// @Description 从 stock_latest 读取每只股票最新一条日线，按 ts_code 升序；快照随日线写入更新，无需对 stock_daily 分组查询
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.StockLatest}}
+// @Failure 400 {object} Response
+// @Router /data/latest [get]
+func (h *Handler) GetLatest(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.StockLatest{}), latestFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	latest := make([]models.StockLatest, 0)
+	if err := db.Order("ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&latest).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     latest,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// CursorResult 游标分页结果
+type CursorResult struct {
+	List       interface{} `json:"list"`
+	NextCursor string      `json:"next_cursor"` // 为空表示已无更多数据
+}
+
+// GetDailyChanges 获取指定时间之后新增或更新的日线数据
+//
+// @Summary 日线增量数据
+// @Description 按 updated_at、id 升序返回 since 之后写入或更新的日线数据，使用 next_cursor 翻页
+// @Tags 数据
+// @Produce json
+// @Param since query string true "起始时间 RFC3339，如 2023-12-01T00:00:00Z"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=CursorResult{list=[]models.StockDaily}}
+// @Failure 400 {object} Response
+// @Router /data/daily/changes [get]
+func (h *Handler) GetDailyChanges(c *gin.Context) {
+	since, err := time.Parse(time.RFC3339, c.Query("since"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "since 格式错误，应为 RFC3339",
+		})
+		return
+	}
+
+	db := database.GetDB().Model(&models.StockDaily{}).Where("updated_at > ?", since)
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		values, err := decodeCursor(cursor, 2)
+		var updatedAt time.Time
+		var id uint64
+		if err == nil {
+			updatedAt, err = time.Parse(time.RFC3339Nano, values[0])
+		}
+		if err == nil {
+			id, err = strconv.ParseUint(values[1], 10, 64)
+		}
+		if err != nil {
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "无效的游标",
+			})
+			return
+		}
+		db = db.Where("(updated_at > ? OR (updated_at = ? AND id > ?))", updatedAt, updatedAt, id)
+	}
+
+	p := h.parsePagination(c)
+
+	var dailyData []models.StockDaily
+	if err := db.Order("updated_at asc, id asc").
+		Limit(p.PageSize).
+		Find(&dailyData).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	// 本页已满时才可能还有下一页
+	var nextCursor string
+	if len(dailyData) == p.PageSize {
+		last := dailyData[len(dailyData)-1]
+		nextCursor = encodeCursor(last.UpdatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(last.ID), 10))
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: CursorResult{
+			List:       dailyData,
+			NextCursor: nextCursor,
+		},
+	})
+}
+
+// StockInfo 股票详细信息（包含上市公司信息）
+type StockInfo struct {
+	models.StockBasic
+	Company *models.StockCompany `json:"company"`
+}
+
+// GetStockInfo 获取股票详细信息
+//
+// @Summary 获取股票详情
+// @Description 返回股票基本信息，已抓取上市公司信息时一并返回 company 字段
+// @Tags 数据
+// @Produce json
+// @Param ts_code path string true "股票代码"
+// @Success 200 {object} Response{data=StockInfo}
+// @Failure 404 {object} Response
+// @Router /data/stock/{ts_code} [get]
+func (h *Handler) GetStockInfo(c *gin.Context) {
+	tsCode := c.Param("ts_code")
+
+	var stock models.StockBasic
+	if err := database.GetDB().Where("ts_code = ?", tsCode).First(&stock).Error; err != nil {
+		respond(c, http.StatusNotFound, Response{
+			Code:    404,
+			Message: "股票不存在",
+		})
+		return
+	}
+
+	info := StockInfo{StockBasic: stock}
+
+	var company models.StockCompany
+	err := database.GetDB().Where("ts_code = ?", tsCode).First(&company).Error
+	switch {
+	case err == nil:
+		info.Company = &company
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		h.logger.Warn("查询上市公司信息失败", zap.String("ts_code", tsCode), zap.Error(err))
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    info,
+	})
+}
+
+// FetchStockCompany 抓取上市公司基本信息
+//
+// @Summary 抓取上市公司信息
+// @Description 按股票列表异步抓取上市公司基本信息（注册资本、员工人数、经营范围等）
+// @Tags 抓取
+// @Produce json
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/stock-company [post]
+func (h *Handler) FetchStockCompany(c *gin.Context) {
+	h.logger.Info("收到上市公司信息抓取请求")
+
+	key, hash, ok := h.idempotentTask(c, "company_task_", nil)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchStockCompany(service.WithIdempotencyKey(ctx, key, hash))
+		if err != nil {
+			h.logger.Error("抓取上市公司信息失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "上市公司信息抓取任务已启动，请查询进度",
+	})
+}
+
+// FetchWeekly 抓取周线数据
+//
+// @Summary 抓取周线数据
+// @Description 作业加入队列后返回，由后台 worker 执行，用 job_id 查询作业状态及关联的 task_id；dry_run 为 true 时返回任务预估 service.FetchPlan
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次作业"
+// @Success 200 {object} Response{data=models.FetchJob} "排队中的作业"
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 422 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/weekly [post]
+func (h *Handler) FetchWeekly(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeWeekly, req)
+		return
+	}
+	if !h.checkFetchDays(c, req) {
+		return
+	}
+
+	h.logger.Info("收到周线数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	h.enqueueJob(c, service.JobTypeWeekly, service.JobParams{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+}
+
+// DeriveWeeklyResult 由日线聚合周线的响应
+type DeriveWeeklyResult struct {
+	Limitation string `json:"limitation"` // 聚合结果的局限说明
+}
+
+// DeriveWeekly 由已存储的日线聚合生成周线
+//
+// @Summary 由日线聚合周线
+// @Description 按交易日历将已存储的日线按 ISO 周聚合写入周线表，不消耗 Tushare 行情接口额度；不生成复权价格
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "聚合参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=DeriveWeeklyResult}
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Failure 429 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/weekly/derive [post]
+func (h *Handler) DeriveWeekly(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeWeeklyDerive, req)
+		return
+	}
+
+	h.logger.Info("收到周线聚合请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "weekly_derive_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行聚合任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.DeriveWeekly(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("聚合周线数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "周线聚合任务已启动，请查询进度",
+		Data:    DeriveWeeklyResult{Limitation: service.DeriveLimitation},
+	})
+}
+
+// FetchMonthly 抓取月线数据
+//
+// @Summary 抓取月线数据
+// @Description 作业加入队列后返回，由后台 worker 执行，用 job_id 查询作业状态及关联的 task_id；dry_run 为 true 时返回任务预估 service.FetchPlan
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次作业"
+// @Success 200 {object} Response{data=models.FetchJob} "排队中的作业"
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 422 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/monthly [post]
+func (h *Handler) FetchMonthly(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeMonthly, req)
+		return
+	}
+	if !h.checkFetchDays(c, req) {
+		return
+	}
+
+	h.logger.Info("收到月线数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	h.enqueueJob(c, service.JobTypeMonthly, service.JobParams{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+}
+
+// FetchFinaIndicator 抓取财务指标数据
+//
+// @Summary 抓取财务指标数据
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "报告期范围"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/fina-indicator [post]
+func (h *Handler) FetchFinaIndicator(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, true) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeFinaIndicator, req)
+		return
+	}
+
+	h.logger.Info("收到财务指标抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "fina_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchFinaIndicator(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate, req.TSCodes)
+		if err != nil {
+			h.logger.Error("抓取财务指标失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "财务指标抓取任务已启动，请查询进度",
+	})
+}
+
+// FetchMinute 抓取分钟线数据
+//
+// @Summary 抓取分钟线数据
+// @Description 按股票、交易日异步抓取分钟线数据
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body MinuteFetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/minute [post]
+func (h *Handler) FetchMinute(c *gin.Context) {
+	var req MinuteFetchRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	h.logger.Info("收到分钟线数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate),
+		zap.String("freq", req.Freq))
+
+	key, hash, ok := h.idempotentTask(c, "minute_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchMinuteData(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate, req.Freq)
+		if err != nil {
+			h.logger.Error("抓取分钟线数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "分钟线数据抓取任务已启动，请查询进度",
+	})
+}
+
+// FetchTopList 抓取龙虎榜数据
+//
+// @Summary 抓取龙虎榜数据
+// @Description 按交易日异步抓取龙虎榜每日明细，无上榜股票的日期视为成功
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/top-list [post]
+func (h *Handler) FetchTopList(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeTopList, req)
+		return
+	}
+
+	h.logger.Info("收到龙虎榜数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "top_list_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchTopList(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("抓取龙虎榜数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "龙虎榜数据抓取任务已启动，请查询进度",
+	})
+}
+
+// indexFilters 指数列表允许的过滤参数
+var indexFilters = []queryFilter{
+	{Param: "market", Condition: "market = ?"},
+	{Param: "category", Condition: "category = ?"},
+	{Param: "publisher", Condition: "publisher = ?"},
+}
+
+// GetIndices 查询指数列表
+//
+// @Summary 查询指数列表
+// @Description 分页查询已抓取的指数基本信息，可按市场、类别、发布方过滤，name 按简称模糊匹配
+// @Tags 数据
+// @Produce json
+// @Param market query string false "市场"
+// @Param category query string false "指数类别"
+// @Param publisher query string false "发布方"
+// @Param name query string false "简称关键字"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response{data=PageResult}
+// @Router /data/indices [get]
+func (h *Handler) GetIndices(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.IndexBasic{}), indexFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	if name := strings.TrimSpace(c.Query("name")); name != "" {
+		db = db.Where("name LIKE ?", "%"+name+"%")
+	}
+
+	var indices []models.IndexBasic
+	var total int64
+
+	db.Count(&total)
+	db.Order("ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&indices)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     indices,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// GetTopList 查询龙虎榜数据
+//
+// @Summary 查询龙虎榜数据
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.TopListEntry}}
+// @Router /data/top-list [get]
+func (h *Handler) GetTopList(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	// 过滤参数与日线数据一致
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.TopListEntry{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	var entries []models.TopListEntry
+	var total int64
+
+	db.Count(&total)
+	db.Order("trade_date desc, net_amount desc").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&entries)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     entries,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// FetchBlockTrade 抓取大宗交易数据
+//
+// @Summary 抓取大宗交易数据
+// @Description 按交易日异步抓取大宗交易明细，每个交易日整体替换已有记录，无大宗交易的日期视为成功
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/block-trade [post]
+func (h *Handler) FetchBlockTrade(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeBlockTrade, req)
+		return
+	}
+
+	h.logger.Info("收到大宗交易数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "block_trade_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchBlockTrade(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("抓取大宗交易数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "大宗交易数据抓取任务已启动，请查询进度",
+	})
+}
+
+// GetBlockTrade 查询大宗交易数据
+//
+// @Summary 查询大宗交易数据
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.BlockTrade}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/block-trade [get]
+func (h *Handler) GetBlockTrade(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	// 过滤参数与日线数据一致
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.BlockTrade{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	trades := make([]models.BlockTrade, 0)
+	if err := db.Order("trade_date desc, amount desc, id").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&trades).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     trades,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// FetchMarginDetail 抓取融资融券交易明细
+//
+// @Summary 抓取融资融券数据
+// @Description 按交易日异步抓取融资融券交易明细，无数据的日期视为成功
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/margin [post]
+func (h *Handler) FetchMarginDetail(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeMargin, req)
+		return
+	}
+
+	h.logger.Info("收到融资融券数据抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "margin_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchMarginDetail(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("抓取融资融券数据失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "融资融券数据抓取任务已启动，请查询进度",
+	})
+}
+
+// FetchAdjFactor 抓取复权因子
+//
+// @Summary 抓取复权因子
+// @Description 按交易日异步抓取全部股票的复权因子，无数据的日期视为成功
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/adj-factor [post]
+func (h *Handler) FetchAdjFactor(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, false) {
+		return
+	}
+
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeAdjFactor, req)
+		return
+	}
+
+	h.logger.Info("收到复权因子抓取请求",
+		zap.String("start_date", req.StartDate),
+		zap.String("end_date", req.EndDate))
+
+	key, hash, ok := h.idempotentTask(c, "adj_factor_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
+
+	// 异步执行抓取任务
+	go func() {
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchAdjFactor(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate)
+		if err != nil {
+			h.logger.Error("抓取复权因子失败", zap.Error(err))
+		}
+	}()
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "复权因子抓取任务已启动，请查询进度",
+	})
+}
+
+// FetchDailyAdj 通过 pro_bar 抓取复权日线
+//
+// @Summary 抓取复权日线
+// @Description 按股票异步调用 Tushare pro_bar 抓取日期范围内的日线，adj 为 qfq/hfq 时价格已由 Tushare 复权，保存到 stock_daily_adj。ts_codes 为空时抓取全部股票
+// @Tags 抓取
+// @Accept json
+// @Produce json
+// @Param request body FetchRequest true "抓取参数"
+// @Param Idempotency-Key header string false "幂等键，24 小时内相同的键只创建一次任务"
+// @Success 200 {object} Response{data=service.FetchPlan} "dry_run 为 true 时返回任务预估"
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/daily-adj [post]
+func (h *Handler) FetchDailyAdj(c *gin.Context) {
+	var req FetchRequest
+	if !h.bindFetchRequest(c, &req, true) {
+		return
+	}
+	if !service.IsValidAdj(req.Adj) {
+		respondValidationError(c, newFieldError("adj", "oneof", "adj 取值必须为以下之一: qfq hfq，为空不复权"))
 		return
 	}
 
-	h.logger.Info("收到日线数据抓取请求",
+	if req.DryRun {
+		h.respondFetchPlan(c, service.PlanTypeDailyAdj, req)
+		return
+	}
+
+	h.logger.Info("收到复权日线抓取请求",
 		zap.String("start_date", req.StartDate),
-		zap.String("end_date", req.EndDate))
+		zap.String("end_date", req.EndDate),
+		zap.String("adj", req.Adj))
+
+	key, hash, ok := h.idempotentTask(c, "daily_adj_task_", req)
+	if !ok {
+		return
+	}
+	if !h.acquireTask(c) {
+		return
+	}
 
 	// 异步执行抓取任务
 	go func() {
-		ctx := context.Background()
-		_, err := h.dataFetcher.FetchDailyDataOptimized(ctx, req.StartDate, req.EndDate)
+		defer h.dataFetcher.ReleaseTask()
+		ctx, cancel := h.dataFetcher.TaskContext()
+		defer cancel()
+		_, err := h.dataFetcher.FetchDailyAdj(service.WithIdempotencyKey(ctx, key, hash), req.StartDate, req.EndDate, req.TSCodes, req.Adj)
 		if err != nil {
-			h.logger.Error("抓取日线数据失败", zap.Error(err))
+			h.logger.Error("抓取复权日线失败", zap.Error(err))
 		}
 	}()
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
-		Message: "任务已启动，请查询进度",
+		Message: "复权日线抓取任务已启动，请查询进度",
 	})
 }
 
-// GetProgress 获取抓取进度
-func (h *Handler) GetProgress(c *gin.Context) {
-	taskID := c.Param("task_id")
+// GetMarginDetail 查询融资融券交易明细
+//
+// @Summary 查询融资融券数据
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.MarginDetail}}
+// @Router /data/margin [get]
+func (h *Handler) GetMarginDetail(c *gin.Context) {
+	p := h.parsePagination(c)
 
-	task, err := h.dataFetcher.GetTaskProgress(taskID)
+	// 过滤参数与日线数据一致
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.MarginDetail{}), dailyFilters)
 	if err != nil {
-		c.JSON(http.StatusNotFound, Response{
-			Code:    404,
-			Message: "任务不存在",
-		})
+		respondFilterError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, Response{
+	var margins []models.MarginDetail
+	var total int64
+
+	db.Count(&total)
+	db.Order("trade_date desc, ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&margins)
+
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
-		Data:    task,
+		Data: PageResult{
+			List:     margins,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
 	})
 }
 
-// ListTasks 获取任务列表
-func (h *Handler) ListTasks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+// GetAdjFactor 查询已存储的复权因子
+//
+// @Summary 查询复权因子
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码"
+// @Param ts_codes query string false "股票代码列表，逗号分隔"
+// @Param trade_date query string false "交易日期 YYYYMMDD"
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.StockAdjFactor}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/adj-factor [get]
+func (h *Handler) GetAdjFactor(c *gin.Context) {
+	p := h.parsePagination(c)
+
+	// 过滤参数与日线数据一致
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.StockAdjFactor{}), dailyFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
 
-	var tasks []models.FetchTask
 	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
 
-	db := database.GetDB()
-	db.Model(&models.FetchTask{}).Count(&total)
-	db.Order("created_at desc").
-		Limit(pageSize).
-		Offset((page - 1) * pageSize).
-		Find(&tasks)
+	factors := make([]models.StockAdjFactor, 0)
+	if err := db.Order("trade_date desc, ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&factors).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
-		Data: gin.H{
-			"list":  tasks,
-			"total": total,
-			"page":  page,
+		Data: PageResult{
+			List:     factors,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
 		},
 	})
 }
 
-// GetStocks 获取股票列表
-func (h *Handler) GetStocks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+// GetTradeCalendar 查询已存储的交易日历
+//
+// @Summary 查询交易日历
+// @Tags 数据
+// @Produce json
+// @Param exchange query string false "交易所" Enums(SSE, SZSE)
+// @Param start_date query string false "开始日期 YYYYMMDD"
+// @Param end_date query string false "结束日期 YYYYMMDD"
+// @Param is_open query int false "是否交易 0休市 1交易" Enums(0, 1)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.TradeCalendar}}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/calendar [get]
+func (h *Handler) GetTradeCalendar(c *gin.Context) {
+	if isOpen := c.Query("is_open"); isOpen != "" && isOpen != "0" && isOpen != "1" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "is_open 只能为 0 或 1",
+		})
+		return
+	}
+	p := h.parsePagination(c)
+
+	db, err := h.applyFilters(c, database.GetDB().Model(&models.TradeCalendar{}), calendarFilters)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
 
-	var stocks []models.StockBasic
 	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
 
-	db := database.GetDB()
-	db.Model(&models.StockBasic{}).Count(&total)
-	db.Limit(pageSize).
-		Offset((page - 1) * pageSize).
-		Find(&stocks)
+	days := make([]models.TradeCalendar, 0)
+	if err := db.Order("cal_date, exchange").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&days).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     days,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
+
+// GetNextTradingDays 查询指定日期之后的若干个交易日
+//
+// @Summary 查询后续交易日
+// @Description 返回 from 之后（不含 from）的 count 个交易日，任一交易所开市即为交易日。优先使用已存储的交易日历，不足或有缺失时从 Tushare 抓取交易日历并保存（只读模式下不抓取）；
+// @Description 交易所尚未发布足够远的日历时返回的交易日少于 count 个
+// @Tags 数据
+// @Produce json
+// @Param from query string false "起始日期 YYYYMMDD，默认今天"
+// @Param count query int false "交易日数量，最大 250" default(5)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /data/calendar/next [get]
+func (h *Handler) GetNextTradingDays(c *gin.Context) {
+	from := c.DefaultQuery("from", time.Now().In(service.Location()).Format("20060102"))
+	if _, err := service.ParseDate(from); err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "from 格式错误，应为 YYYYMMDD",
+		})
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", "5"))
+	if err != nil || count < 1 || count > service.MaxNextTradingDays {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: fmt.Sprintf("count 必须为 1 到 %d 之间的整数", service.MaxNextTradingDays),
+		})
+		return
+	}
+
+	// 只读模式下不写入交易日历，只返回已存储的交易日
+	tradeDates, err := h.dataFetcher.NextTradingDays(c.Request.Context(), from, count, !h.config.ReadOnly)
+	if err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
 		Data: gin.H{
-			"list":  stocks,
-			"total": total,
-			"page":  page,
+			"from":        from,
+			"count":       len(tradeDates),
+			"trade_dates": tradeDates,
 		},
 	})
 }
 
-// GetDailyData 获取日线数据
-func (h *Handler) GetDailyData(c *gin.Context) {
+// GetMonthlyData 获取月线数据
+func (h *Handler) GetMonthlyData(c *gin.Context) {
 	tsCode := c.Query("ts_code")
 	tradeDate := c.Query("trade_date")
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+	p := h.parsePagination(c)
 
-	db := database.GetDB().Model(&models.StockDaily{})
+	db := database.GetDB().Model(&models.StockMonthly{})
 
 	if tsCode != "" {
 		db = db.Where("ts_code = ?", tsCode)
@@ -223,146 +2557,440 @@ func (h *Handler) GetDailyData(c *gin.Context) {
 		db = db.Where("trade_date <= ?", endDate)
 	}
 
-	var dailyData []models.StockDaily
+	var monthlyData []models.StockMonthly
 	var total int64
 
 	db.Count(&total)
 	db.Order("trade_date desc").
-		Limit(pageSize).
-		Offset((page - 1) * pageSize).
-		Find(&dailyData)
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&monthlyData)
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
-		Data: gin.H{
-			"list":  dailyData,
-			"total": total,
-			"page":  page,
+		Data: PageResult{
+			List:     monthlyData,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
 		},
 	})
 }
 
-// GetStockInfo 获取股票详细信息
-func (h *Handler) GetStockInfo(c *gin.Context) {
-	tsCode := c.Param("ts_code")
+// GetLatestTradeDate 获取已存储数据的最新交易日期
+//
+// @Summary 获取最新交易日期
+// @Tags 数据
+// @Produce json
+// @Param type query string false "数据类型" Enums(daily, weekly, monthly) default(daily)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /data/latest-date [get]
+func (h *Handler) GetLatestTradeDate(c *gin.Context) {
+	dataType := c.DefaultQuery("type", "daily")
+	model, ok := dataModels[dataType]
+	if !ok {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "不支持的数据类型: " + dataType,
+		})
+		return
+	}
 
-	var stock models.StockBasic
-	if err := database.GetDB().Where("ts_code = ?", tsCode).First(&stock).Error; err != nil {
-		c.JSON(http.StatusNotFound, Response{
-			Code:    404,
-			Message: "股票不存在",
+	var latest sql.NullTime
+	if err := database.GetDB().Model(model).Select("MAX(trade_date)").Scan(&latest).Error; err != nil {
+		h.logger.Error("查询最新交易日期失败", zap.String("type", dataType), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, Response{
+	// 表中无数据时 latest_date 返回 null
+	var latestDate interface{}
+	if latest.Valid {
+		latestDate = latest.Time.Format("20060102")
+	}
+
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
-		Data:    stock,
+		Data: gin.H{
+			"type":        dataType,
+			"latest_date": latestDate,
+		},
 	})
 }
 
-// FetchWeekly 抓取周线数据
-func (h *Handler) FetchWeekly(c *gin.Context) {
-	var req FetchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, Response{
+// DeleteData 删除指定日期范围内的行情数据
+//
+// @Summary 删除行情数据
+// @Description 在事务中删除日期范围内（可指定股票）的日线/周线/月线数据，需传 confirm=true 确认
+// @Tags 数据
+// @Produce json
+// @Param type query string false "数据类型" Enums(daily, weekly, monthly) default(daily)
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Param ts_code query string false "股票代码"
+// @Param confirm query bool true "确认删除，必须为 true"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Security ApiKeyAuth
+// @Router /data/daily [delete]
+func (h *Handler) DeleteData(c *gin.Context) {
+	dataType := c.DefaultQuery("type", "daily")
+	model, ok := dataModels[dataType]
+	if !ok {
+		respond(c, http.StatusBadRequest, Response{
 			Code:    400,
-			Message: "参数错误: " + err.Error(),
+			Message: "不支持的数据类型: " + dataType,
 		})
 		return
 	}
 
-	h.logger.Info("收到周线数据抓取请求",
-		zap.String("start_date", req.StartDate),
-		zap.String("end_date", req.EndDate))
+	if c.Query("confirm") != "true" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "删除操作需要传 confirm=true 确认",
+		})
+		return
+	}
 
-	// 异步执行抓取任务
-	go func() {
-		ctx := context.Background()
-		_, err := h.dataFetcher.FetchWeeklyData(ctx, req.StartDate, req.EndDate)
-		if err != nil {
-			h.logger.Error("抓取周线数据失败", zap.Error(err))
+	start, end, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	tsCode := c.Query("ts_code")
+
+	var deleted int64
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("trade_date BETWEEN ? AND ?", start, end)
+		if tsCode != "" {
+			query = query.Where("ts_code = ?", tsCode)
 		}
-	}()
 
-	c.JSON(http.StatusOK, Response{
+		// 快照落在删除范围内的股票需要在删除后按剩余日线重建
+		var latestCodes []string
+		if dataType == "daily" {
+			latestQuery := tx.Model(&models.StockLatest{}).Where("trade_date BETWEEN ? AND ?", start, end)
+			if tsCode != "" {
+				latestQuery = latestQuery.Where("ts_code = ?", tsCode)
+			}
+			if err := latestQuery.Pluck("ts_code", &latestCodes).Error; err != nil {
+				return err
+			}
+		}
+
+		result := query.Delete(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return service.RebuildStockLatest(tx, latestCodes)
+	})
+	if err != nil {
+		h.logger.Error("删除行情数据失败", zap.String("type", dataType), zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("删除行情数据",
+		zap.String("type", dataType),
+		zap.String("start_date", c.Query("start_date")),
+		zap.String("end_date", c.Query("end_date")),
+		zap.String("ts_code", tsCode),
+		zap.Int64("deleted", deleted))
+
+	respond(c, http.StatusOK, Response{
 		Code:    0,
-		Message: "周线数据抓取任务已启动，请查询进度",
+		Message: "success",
+		Data: gin.H{
+			"type":    dataType,
+			"deleted": deleted,
+		},
 	})
 }
 
-// FetchMonthly 抓取月线数据
-func (h *Handler) FetchMonthly(c *gin.Context) {
-	var req FetchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, Response{
-			Code:    400,
-			Message: "参数错误: " + err.Error(),
+// StockCoverage 单只股票日线数据覆盖情况
+type StockCoverage struct {
+	TSCode    string `json:"ts_code"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	RowCount  int64  `json:"row_count"`
+}
+
+// GetCoverage 获取各股票已存储日线数据的日期范围和行数
+//
+// @Summary 日线数据覆盖情况
+// @Description 按股票汇总 stock_daily 中最早/最新交易日期及行数，用于判断哪些股票需要补抓
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码，多个用逗号分隔"
+// @Success 200 {object} Response{data=[]StockCoverage}
+// @Router /data/coverage [get]
+func (h *Handler) GetCoverage(c *gin.Context) {
+	db := database.GetDB().Model(&models.StockDaily{})
+
+	if tsCode := c.Query("ts_code"); tsCode != "" {
+		db = db.Where("ts_code IN ?", strings.Split(tsCode, ","))
+	}
+
+	var rows []struct {
+		TSCode   string
+		MinDate  time.Time
+		MaxDate  time.Time
+		RowCount int64
+	}
+	if err := db.Select("ts_code, MIN(trade_date) AS min_date, MAX(trade_date) AS max_date, COUNT(*) AS row_count").
+		Group("ts_code").
+		Order("ts_code").
+		Scan(&rows).Error; err != nil {
+		h.logger.Error("查询数据覆盖情况失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("收到月线数据抓取请求",
-		zap.String("start_date", req.StartDate),
-		zap.String("end_date", req.EndDate))
-
-	// 异步执行抓取任务
-	go func() {
-		ctx := context.Background()
-		_, err := h.dataFetcher.FetchMonthlyData(ctx, req.StartDate, req.EndDate)
-		if err != nil {
-			h.logger.Error("抓取月线数据失败", zap.Error(err))
-		}
-	}()
+	coverage := make([]StockCoverage, 0, len(rows))
+	for _, row := range rows {
+		coverage = append(coverage, StockCoverage{
+			TSCode:    row.TSCode,
+			StartDate: row.MinDate.Format("20060102"),
+			EndDate:   row.MaxDate.Format("20060102"),
+			RowCount:  row.RowCount,
+		})
+	}
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
-		Message: "月线数据抓取任务已启动，请查询进度",
+		Message: "success",
+		Data:    coverage,
 	})
 }
 
-// GetMonthlyData 获取月线数据
-func (h *Handler) GetMonthlyData(c *gin.Context) {
-	tsCode := c.Query("ts_code")
-	tradeDate := c.Query("trade_date")
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+// CoverageHealth 全市场日线数据覆盖情况汇总
+type CoverageHealth struct {
+	ExpectedLatestDate string `json:"expected_latest_date"` // 按交易日历截至今天的最近交易日
+	TotalStocks        int64  `json:"total_stocks"`         // 上市状态的股票数
+	Missing            int64  `json:"missing"`              // 没有任何日线数据
+	Stale              int64  `json:"stale"`                // 最新日线早于 expected_latest_date
+	Complete           int64  `json:"complete"`             // 已有 expected_latest_date 的日线
+}
 
-	db := database.GetDB().Model(&models.StockMonthly{})
+// GetCoverageHealth 汇总上市股票的日线数据缺失、过期和完整数量
+//
+// @Summary 日线数据覆盖健康度
+// @Description 以 stock_basic 中上市状态的股票为准，关联 stock_daily 的最新交易日期，按交易日历确定的最近交易日分为缺失、过期、完整三类
+// @Tags 数据
+// @Produce json
+// @Success 200 {object} Response{data=CoverageHealth}
+// @Failure 500 {object} Response
+// @Router /data/health/coverage [get]
+func (h *Handler) GetCoverageHealth(c *gin.Context) {
+	expected, err := h.dataFetcher.LatestTradeDate(c.Request.Context())
+	if err != nil {
+		h.logger.Error("获取最近交易日失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "获取交易日历失败: " + err.Error(),
+		})
+		return
+	}
+	expectedDate, err := service.ParseDate(expected)
+	if err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
 
-	if tsCode != "" {
-		db = db.Where("ts_code = ?", tsCode)
+	db := database.GetDB()
+	latest := db.Model(&models.StockDaily{}).
+		Select("ts_code, MAX(trade_date) AS max_date").
+		Group("ts_code")
+
+	var buckets struct {
+		Total   int64
+		Missing int64
+		Stale   int64
 	}
-	if tradeDate != "" {
-		db = db.Where("trade_date = ?", tradeDate)
+	if err := db.Table(models.StockBasic{}.TableName()+" AS b").
+		Select("COUNT(*) AS total, "+
+			"COALESCE(SUM(CASE WHEN d.max_date IS NULL THEN 1 ELSE 0 END), 0) AS missing, "+
+			"COALESCE(SUM(CASE WHEN d.max_date < ? THEN 1 ELSE 0 END), 0) AS stale", expectedDate).
+		Joins("LEFT JOIN (?) AS d ON d.ts_code = b.ts_code", latest).
+		Where("b.list_status = ?", "L").
+		Scan(&buckets).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
 	}
-	if startDate != "" {
-		db = db.Where("trade_date >= ?", startDate)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: CoverageHealth{
+			ExpectedLatestDate: expected,
+			TotalStocks:        buckets.Total,
+			Missing:            buckets.Missing,
+			Stale:              buckets.Stale,
+			Complete:           buckets.Total - buckets.Missing - buckets.Stale,
+		},
+	})
+}
+
+// TableStats 行情表统计信息
+type TableStats struct {
+	RowCount  int64       `json:"row_count"`
+	StartDate interface{} `json:"start_date"`
+	EndDate   interface{} `json:"end_date"`
+}
+
+// GetStats 获取已存储数据的统计概览
+//
+// @Summary 数据统计概览
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} Response
+// @Router /stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+	stats, err := h.statsCache.get(statsCacheTTL, h.loadStats)
+	if err != nil {
+		h.logger.Error("查询统计信息失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
 	}
-	if endDate != "" {
-		db = db.Where("trade_date <= ?", endDate)
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    stats,
+	})
+}
+
+// loadStats 执行统计查询
+func (h *Handler) loadStats() (interface{}, error) {
+	db := database.GetDB()
+
+	var stockCount int64
+	if err := db.Model(&models.StockBasic{}).Count(&stockCount).Error; err != nil {
+		return nil, err
 	}
 
-	var monthlyData []models.StockMonthly
-	var total int64
+	tables := make(map[string]TableStats, len(dataModels))
+	for dataType, model := range dataModels {
+		var row struct {
+			RowCount int64
+			MinDate  sql.NullTime
+			MaxDate  sql.NullTime
+		}
+		if err := db.Model(model).
+			Select("COUNT(*) AS row_count, MIN(trade_date) AS min_date, MAX(trade_date) AS max_date").
+			Scan(&row).Error; err != nil {
+			return nil, err
+		}
 
-	db.Count(&total)
-	db.Order("trade_date desc").
-		Limit(pageSize).
-		Offset((page - 1) * pageSize).
-		Find(&monthlyData)
+		stats := TableStats{RowCount: row.RowCount}
+		if row.MinDate.Valid {
+			stats.StartDate = row.MinDate.Time.Format("20060102")
+		}
+		if row.MaxDate.Valid {
+			stats.EndDate = row.MaxDate.Time.Format("20060102")
+		}
+		tables[dataType] = stats
+	}
+
+	// 最近一次抓取任务
+	var latestTask *models.FetchTask
+	var task models.FetchTask
+	err := db.Order("created_at desc").First(&task).Error
+	switch {
+	case err == nil:
+		latestTask = &task
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	return gin.H{
+		"stock_count": stockCount,
+		"tables":      tables,
+		"latest_task": latestTask,
+		"updated_at":  time.Now(),
+	}, nil
+}
+
+// DimensionValue 筛选维度取值及对应股票数
+type DimensionValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Dimensions 股票筛选维度
+type Dimensions struct {
+	Industries []DimensionValue `json:"industries"`
+	Areas      []DimensionValue `json:"areas"`
+}
+
+// GetDimensions 获取可用的行业、地域筛选值
+//
+// @Summary 获取行业与地域筛选值
+// @Description 返回 stock_basic 中非空的行业、地域取值及股票数，结果缓存 5 分钟
+// @Tags 数据
+// @Produce json
+// @Success 200 {object} Response{data=Dimensions}
+// @Router /data/dimensions [get]
+func (h *Handler) GetDimensions(c *gin.Context) {
+	dims, err := h.dimsCache.get(dimensionsCacheTTL, h.loadDimensions)
+	if err != nil {
+		h.logger.Error("查询筛选维度失败", zap.Error(err))
+		respond(c, http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, Response{
+	respond(c, http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
-		Data: gin.H{
-			"list":  monthlyData,
-			"total": total,
-			"page":  page,
-		},
+		Data:    dims,
 	})
 }
+
+// loadDimensions 查询行业、地域的去重取值
+func (h *Handler) loadDimensions() (interface{}, error) {
+	industries, err := distinctStockValues("industry")
+	if err != nil {
+		return nil, err
+	}
+
+	areas, err := distinctStockValues("area")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dimensions{
+		Industries: industries,
+		Areas:      areas,
+	}, nil
+}
+
+// distinctStockValues 统计 stock_basic 指定列的非空取值及数量
+func distinctStockValues(column string) ([]DimensionValue, error) {
+	values := make([]DimensionValue, 0)
+	err := database.GetDB().Model(&models.StockBasic{}).
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " <> ''").
+		Group(column).
+		Order("count desc").
+		Scan(&values).Error
+	return values, err
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRouter 创建使用内存 SQLite 并迁移指定表的路由，作业队列不启动 worker，作业保持排队状态
+// cfg 为 nil 时使用默认的服务配置
+func newTestRouter(t *testing.T, cfg *config.ServerConfig, tables ...interface{}) *gin.Engine {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(tables...))
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+
+	fetcher := service.NewDataFetcher(nil, &config.FetcherConfig{MaxConcurrentTasks: 1, StartDate: "20230101", BatchSize: 100}, zap.NewNop())
+	queue := service.NewJobQueue(fetcher, 1, zap.NewNop())
+
+	if cfg == nil {
+		cfg = &config.ServerConfig{MaxTSCodes: 10, DefaultPageSize: 20, MaxPageSize: 100}
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(fetcher, queue, cfg, zap.NewNop()).RegisterRoutes(r)
+	return r
+}
+
+// postFetchDaily 发起日线抓取请求并解析返回的作业
+func postFetchDaily(t *testing.T, r *gin.Engine, body, key string) (int, models.FetchJob) {
+	t.Helper()
+	return postFetch(t, r, "/api/v1/fetch/daily", body, key)
+}
+
+// postFetch 发起抓取请求并解析返回的作业
+func postFetch(t *testing.T, r *gin.Engine, path, body, key string) (int, models.FetchJob) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp struct {
+		Data models.FetchJob `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp.Data
+}
+
+// TestFetchDaily_IdempotencyKey 测试相同 Idempotency-Key 的重复请求只创建一个作业
+func TestFetchDaily_IdempotencyKey(t *testing.T) {
+	r := newTestRouter(t, nil, &models.FetchJob{}, &models.FetchTask{})
+	body := `{"start_date": "20230101", "end_date": "20231231"}`
+
+	status, first := postFetchDaily(t, r, body, "backfill-2023")
+	require.Equal(t, http.StatusOK, status)
+	status, second := postFetchDaily(t, r, body, "backfill-2023")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, first.JobID, second.JobID)
+	// 日线作业入队时即创建任务，重复请求返回同一个任务
+	require.NotEmpty(t, first.TaskID)
+	assert.Equal(t, first.TaskID, second.TaskID)
+
+	var count int64
+	require.NoError(t, database.DB.Model(&models.FetchJob{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	var task models.FetchTask
+	require.NoError(t, database.DB.Where("task_id = ?", first.TaskID).First(&task).Error)
+	assert.Equal(t, models.TaskStatusPending, task.Status)
+	assert.Equal(t, "20230101", task.StartDate)
+
+	// 相同的键用于不同参数时拒绝
+	status, _ = postFetchDaily(t, r, `{"start_date": "20220101", "end_date": "20221231"}`, "backfill-2023")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	// 不带键的请求每次都创建新作业
+	_, third := postFetchDaily(t, r, body, "")
+	_, fourth := postFetchDaily(t, r, body, "")
+	assert.NotEqual(t, third.JobID, fourth.JobID)
+	assert.NotEqual(t, third.TaskID, fourth.TaskID)
+	require.NoError(t, database.DB.Model(&models.FetchJob{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+	require.NoError(t, database.DB.Model(&models.FetchTask{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+// TestFetchTask_IdempotencyKey 测试直接创建任务的抓取接口：已有任务使用相同的键时返回该任务而不再创建，
+// 键已用于其他类型或请求参数（日期范围、复权方式等）不同的任务时返回 422
+func TestFetchTask_IdempotencyKey(t *testing.T) {
+	r := newTestRouter(t, nil, &models.FetchTask{})
+	key, adjKey := "top-list-2023", "daily-adj-2023"
+	require.NoError(t, database.DB.Create(&[]models.FetchTask{
+		{
+			TaskID:          "top_list_task_1701600000",
+			StartDate:       "20230101",
+			EndDate:         "20231231",
+			Status:          models.TaskStatusRunning,
+			IdempotencyKey:  &key,
+			IdempotencyHash: service.IdempotencyParamsHash(FetchRequest{StartDate: "20230101", EndDate: "20231231"}),
+		},
+		{
+			TaskID:          "daily_adj_task_1701600000",
+			StartDate:       "20230101",
+			EndDate:         "20231231",
+			Status:          models.TaskStatusRunning,
+			IdempotencyKey:  &adjKey,
+			IdempotencyHash: service.IdempotencyParamsHash(FetchRequest{StartDate: "20230101", EndDate: "20231231", Adj: "qfq"}),
+		},
+	}).Error)
+
+	post := func(path, body, key string) (int, models.FetchTask) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp struct {
+			Data models.FetchTask `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data
+	}
+
+	status, task := post("/api/v1/fetch/top-list", `{"start_date": "20230101", "end_date": "20231231"}`, key)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "top_list_task_1701600000", task.TaskID)
+
+	status, _ = post("/api/v1/fetch/top-list", `{"start_date": "20220101", "end_date": "20221231"}`, key)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	status, _ = post("/api/v1/fetch/block-trade", `{"start_date": "20230101", "end_date": "20231231"}`, key)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	// 日期范围相同、复权方式不同同样视为不同的请求
+	status, task = post("/api/v1/fetch/daily-adj", `{"start_date": "20230101", "end_date": "20231231", "adj": "qfq"}`, adjKey)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "daily_adj_task_1701600000", task.TaskID)
+	status, _ = post("/api/v1/fetch/daily-adj", `{"start_date": "20230101", "end_date": "20231231", "adj": "hfq"}`, adjKey)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	var count int64
+	require.NoError(t, database.DB.Model(&models.FetchTask{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"stock_data/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DailyImportRoute 日线 CSV 导入接口的路由，上传文件较大，使用 server.max_import_bytes 而非 max_body_bytes 限制大小
+const DailyImportRoute = "/data/daily/import"
+
+// importFileField 上传 CSV 的表单字段名
+const importFileField = "file"
+
+// ImportDailyData 从 CSV 文件批量导入日线数据
+//
+// @Summary 导入日线数据
+// @Description 以 multipart/form-data 上传 CSV（字段名 file），逐行解析后按 fetcher.insert_mode 分批写入，不会将整个文件读入内存。
+// @Description 表头必须包含 ts_code、trade_date，其余列为 open、high、low、close、pre_close、change、pct_chg、vol、amount 中的任意几列，与 GET /data/daily/export?format=csv 的列一致；
+// @Description 数值为空表示 null。解析失败的行跳过并在 errors 中返回行号（最多 100 条），表头错误返回 400，写入数据库失败时停止导入并返回 500
+// @Tags 数据
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "日线 CSV 文件"
+// @Success 200 {object} Response{data=service.DailyImportResult}
+// @Failure 400 {object} Response
+// @Failure 413 {object} Response
+// @Failure 500 {object} Response{data=service.DailyImportResult}
+// @Security ApiKeyAuth
+// @Router /data/daily/import [post]
+func (h *Handler) ImportDailyData(c *gin.Context) {
+	// 直接读取 multipart 流，不经过 ParseMultipartForm 落盘
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "请以 multipart/form-data 上传文件",
+		})
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				h.respondImportError(c, nil, err)
+				return
+			}
+			respond(c, http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "解析上传内容失败: " + err.Error(),
+			})
+			return
+		}
+		if part.FormName() != importFileField {
+			part.Close()
+			continue
+		}
+
+		result, err := h.dataFetcher.ImportDailyCSV(part)
+		part.Close()
+		if err != nil {
+			h.respondImportError(c, result, err)
+			return
+		}
+		respond(c, http.StatusOK, Response{
+			Code:    0,
+			Message: "导入完成",
+			Data:    result,
+		})
+		return
+	}
+
+	respond(c, http.StatusBadRequest, Response{
+		Code:    400,
+		Message: "缺少上传文件字段: " + importFileField,
+	})
+}
+
+// respondImportError 按错误类型返回导入失败：文件过大 413，表头或 CSV 格式错误 400，其余（如写入数据库失败）500
+// result 不为空时一并返回已导入部分的统计
+func (h *Handler) respondImportError(c *gin.Context, result *service.DailyImportResult, err error) {
+	var maxBytesErr *http.MaxBytesError
+	var parseErr *csv.ParseError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrInvalidCSVHeader), errors.As(err, &parseErr):
+		status = http.StatusBadRequest
+	default:
+		h.logger.Error("导入日线数据失败", zap.Error(err))
+	}
+
+	resp := Response{
+		Code:    status,
+		Message: err.Error(),
+	}
+	if result != nil {
+		resp.Data = result
+	}
+	respond(c, status, resp)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImportRequest 构造上传 CSV 的 multipart 请求
+func newImportRequest(t *testing.T, field, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "daily.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1"+DailyImportRoute, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestImportDailyData 测试上传 CSV 导入日线数据，表头错误返回 400，超过 max_import_bytes 返回 413
+func TestImportDailyData(t *testing.T) {
+	r := newTestRouter(t, &config.ServerConfig{MaxImportBytes: 1024}, &models.StockDaily{}, &models.StockLatest{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(t, "file", "ts_code,trade_date,close\n000001.SZ,20231201,10.5\n000001.SZ,20231232,10.6\n"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data service.DailyImportResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Data.Rows)
+	assert.Equal(t, 1, resp.Data.Imported)
+	require.Len(t, resp.Data.Errors, 1)
+	assert.Equal(t, 3, resp.Data.Errors[0].Line)
+
+	var count int64
+	require.NoError(t, database.DB.Model(&models.StockDaily{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(t, "file", "code,date\n"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(t, "upload", "ts_code,trade_date\n"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(t, "file", "ts_code,trade_date\n"+string(bytes.Repeat([]byte("000001.SZ,20231201\n"), 100))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 移动平均参数限制
+const (
+	maxMAWindows    = 6   // 单次请求最多计算的均线数量
+	maxMAWindowSize = 250 // 单条均线最大窗口
+)
+
+// MAPoint 某交易日的收盘价及各均线值
+type MAPoint struct {
+	TradeDate string              `json:"trade_date"`
+	Close     float64             `json:"close"`
+	MA        map[string]*float64 `json:"ma"` // 键为 ma5、ma10 等，数据不足时为 null
+}
+
+// MAResult 移动平均计算结果
+type MAResult struct {
+	TSCode  string    `json:"ts_code"`
+	Windows []int     `json:"windows"`
+	Series  []MAPoint `json:"series"`
+}
+
+// GetDailyMA 计算日线收盘价的简单移动平均
+//
+// @Summary 计算日线移动平均
+// @Description 基于已存储的日线收盘价计算 SMA，会额外加载区间前的数据作为回看窗口
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string true "股票代码"
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Param windows query string false "均线窗口，逗号分隔" default(5,10,20)
+// @Success 200 {object} Response{data=MAResult}
+// @Failure 400 {object} Response
+// @Router /data/daily/ma [get]
+func (h *Handler) GetDailyMA(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if tsCode == "" || startDate == "" || endDate == "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "ts_code、start_date、end_date 不能为空",
+		})
+		return
+	}
+
+	windows, err := parseMAWindows(c.DefaultQuery("windows", "5,10,20"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "参数错误: " + err.Error(),
+		})
+		return
+	}
+	lookback := windows[len(windows)-1] - 1
+
+	db := database.GetDB()
+
+	// 区间前的回看数据，按日期倒序取最近 lookback 条；收盘价缺失的交易日不参与计算
+	var history []models.StockDaily
+	if lookback > 0 {
+		if err := db.Select("trade_date, close").
+			Where("ts_code = ? AND trade_date < ? AND close IS NOT NULL", tsCode, startDate).
+			Order("trade_date desc").
+			Limit(lookback).
+			Find(&history).Error; err != nil {
+			h.respondQueryError(c, err)
+			return
+		}
+	}
+
+	var bars []models.StockDaily
+	if err := db.Select("trade_date, close").
+		Where("ts_code = ? AND trade_date >= ? AND trade_date <= ? AND close IS NOT NULL", tsCode, startDate, endDate).
+		Order("trade_date asc").
+		Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	closes := make([]float64, 0, len(history)+len(bars))
+	for i := len(history) - 1; i >= 0; i-- {
+		closes = append(closes, *history[i].Close)
+	}
+	for _, bar := range bars {
+		closes = append(closes, *bar.Close)
+	}
+
+	averages := make(map[string][]*float64, len(windows))
+	for _, window := range windows {
+		averages[fmt.Sprintf("ma%d", window)] = service.SimpleMovingAverage(closes, window)
+	}
+
+	offset := len(history)
+	series := make([]MAPoint, 0, len(bars))
+	for i, bar := range bars {
+		point := MAPoint{
+			TradeDate: bar.TradeDate.Format("20060102"),
+			Close:     *bar.Close,
+			MA:        make(map[string]*float64, len(windows)),
+		}
+		for key, values := range averages {
+			point.MA[key] = values[offset+i]
+		}
+		series = append(series, point)
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: MAResult{
+			TSCode:  tsCode,
+			Windows: windows,
+			Series:  series,
+		},
+	})
+}
+
+// parseMAWindows 解析均线窗口列表，去重后升序返回
+func parseMAWindows(raw string) ([]int, error) {
+	seen := make(map[int]bool)
+	windows := make([]int, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		window, err := strconv.Atoi(part)
+		if err != nil || window < 2 || window > maxMAWindowSize {
+			return nil, fmt.Errorf("均线窗口必须是 2-%d 之间的整数: %s", maxMAWindowSize, part)
+		}
+		if !seen[window] {
+			seen[window] = true
+			windows = append(windows, window)
+		}
+	}
+
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("windows 不能为空")
+	}
+	if len(windows) > maxMAWindows {
+		return nil, fmt.Errorf("均线数量不能超过 %d 个", maxMAWindows)
+	}
+
+	sort.Ints(windows)
+	return windows, nil
+}
+
+// 一致性检查的默认容差
+const (
+	defaultChangeEpsilon = 0.01 // 涨跌额容差（元）
+	defaultPctTolerance  = 0.01 // 涨跌幅容差（百分点）
+	anomalyScanBatchSize = 1000 // 每批扫描的日线条数
+)
+
+// DailyAnomaly 涨跌额或涨跌幅与收盘价、昨收不一致的日线记录
+type DailyAnomaly struct {
+	TSCode         string   `json:"ts_code"`
+	TradeDate      string   `json:"trade_date"`
+	Close          *float64 `json:"close"`
+	PreClose       *float64 `json:"pre_close"`
+	Change         *float64 `json:"change"`
+	PctChg         *float64 `json:"pct_chg"`
+	ExpectedChange float64  `json:"expected_change"`  // close - pre_close
+	ExpectedPctChg *float64 `json:"expected_pct_chg"` // 昨收为 0 时为 null
+	Anomalies      []string `json:"anomalies"`        // 不一致的字段：change、pct_chg
+}
+
+// AnomaliesResult 日线一致性检查结果
+type AnomaliesResult struct {
+	Checked   int64          `json:"checked"` // 参与检查的记录数
+	Anomalies []DailyAnomaly `json:"anomalies"`
+}
+
+// GetDailyAnomalies 检查已存储日线的涨跌额、涨跌幅一致性
+//
+// @Summary 日线一致性检查
+// @Description 找出 |close - pre_close - change| 超过 epsilon 或 pct_chg 与计算值相差超过 pct_tolerance 的日线记录
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string false "股票代码，为空时检查全部股票"
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Param epsilon query number false "涨跌额容差" default(0.01)
+// @Param pct_tolerance query number false "涨跌幅容差（百分点）" default(0.01)
+// @Success 200 {object} Response{data=AnomaliesResult}
+// @Failure 400 {object} Response
+// @Router /data/daily/anomalies [get]
+func (h *Handler) GetDailyAnomalies(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	if startDate == "" || endDate == "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "start_date、end_date 不能为空",
+		})
+		return
+	}
+
+	epsilon, err := strconv.ParseFloat(c.DefaultQuery("epsilon", strconv.FormatFloat(defaultChangeEpsilon, 'f', -1, 64)), 64)
+	if err != nil || epsilon < 0 {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "epsilon 必须是非负数",
+		})
+		return
+	}
+	pctTolerance, err := strconv.ParseFloat(c.DefaultQuery("pct_tolerance", strconv.FormatFloat(defaultPctTolerance, 'f', -1, 64)), 64)
+	if err != nil || pctTolerance < 0 {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "pct_tolerance 必须是非负数",
+		})
+		return
+	}
+
+	// 列名以切片传入由 gorm 加引号，change 在 MySQL 中是保留字
+	db := database.GetDB().
+		Select([]string{"id", "ts_code", "trade_date", "close", "pre_close", "change", "pct_chg"}).
+		Where("trade_date >= ? AND trade_date <= ? AND close IS NOT NULL AND pre_close IS NOT NULL", startDate, endDate)
+	if tsCode != "" {
+		db = db.Where("ts_code = ?", tsCode)
+	}
+
+	// 分批扫描，只保留异常记录，避免全市场检查时一次加载过多数据
+	result := AnomaliesResult{Anomalies: make([]DailyAnomaly, 0)}
+	var batch []models.StockDaily
+	err = db.FindInBatches(&batch, anomalyScanBatchSize, func(tx *gorm.DB, _ int) error {
+		result.Checked += int64(len(batch))
+		for _, bar := range batch {
+			anomalies := service.PriceAnomalies(bar.Close, bar.PreClose, bar.Change, bar.PctChg, epsilon, pctTolerance)
+			if len(anomalies) == 0 {
+				continue
+			}
+
+			anomaly := DailyAnomaly{
+				TSCode:         bar.TSCode,
+				TradeDate:      bar.TradeDate.Format("20060102"),
+				Close:          bar.Close,
+				PreClose:       bar.PreClose,
+				Change:         bar.Change,
+				PctChg:         bar.PctChg,
+				ExpectedChange: *bar.Close - *bar.PreClose,
+				Anomalies:      anomalies,
+			}
+			if *bar.PreClose != 0 {
+				pct := anomaly.ExpectedChange / *bar.PreClose * 100
+				anomaly.ExpectedPctChg = &pct
+			}
+			result.Anomalies = append(result.Anomalies, anomaly)
+		}
+		return nil
+	}).Error
+	if err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
+
+// respondQueryError 记录查询错误并返回 500
+func (h *Handler) respondQueryError(c *gin.Context, err error) {
+	h.logger.Error("查询数据失败", zap.String("path", c.FullPath()), zap.Error(err))
+	respond(c, http.StatusInternalServerError, Response{
+		Code:    500,
+		Message: err.Error(),
+	})
+}
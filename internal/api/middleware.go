@@ -0,0 +1,101 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit 限制请求体大小，超出时返回 413
+// Content-Length 已知时直接拒绝，否则通过 MaxBytesReader 在读取时截断
+// exemptRoutes 为不受该上限约束的路由（不含 /api/v1 等版本前缀），由路由自身挂载单独的上限，如导入接口
+func BodyLimit(maxBytes int64, exemptRoutes ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptRoutes))
+	for _, route := range exemptRoutes {
+		exempt[route] = true
+	}
+
+	return func(c *gin.Context) {
+		route := strings.TrimPrefix(strings.TrimPrefix(c.FullPath(), apiV1Prefix), apiV2Prefix)
+		if exempt[route] {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			respond(c, http.StatusRequestEntityTooLarge, Response{
+				Code:    413,
+				Message: fmt.Sprintf("请求体超过大小限制（%d 字节）", maxBytes),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// ReadOnly 只读模式下拒绝请求并返回 403，未开启时直接放行
+// 挂载在抓取、删除等会写入数据的接口上，查询接口不受影响
+func ReadOnly(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		respond(c, http.StatusForbidden, Response{
+			Code:    403,
+			Message: "服务处于只读模式（server.read_only），不支持抓取和写入操作",
+		})
+		c.Abort()
+	}
+}
+
+// APIKeyAuth 校验请求携带的 API Key，缺少或不匹配时返回 401，keys 为空时直接放行
+// Key 通过 X-API-Key 头或 Authorization: Bearer <key> 传递
+func APIKeyAuth(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(keys) == 0 {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			if auth := c.GetHeader("Authorization"); len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+				key = strings.TrimSpace(auth[len("Bearer "):])
+			}
+		}
+		if key != "" && validAPIKey(keys, key) {
+			c.Next()
+			return
+		}
+
+		c.Header("WWW-Authenticate", "Bearer")
+		message := "缺少 API Key，请通过 X-API-Key 或 Authorization: Bearer 请求头携带"
+		if key != "" {
+			message = "API Key 无效"
+		}
+		respond(c, http.StatusUnauthorized, Response{
+			Code:    401,
+			Message: message,
+		})
+		c.Abort()
+	}
+}
+
+// validAPIKey 按常量时间比较，避免通过响应耗时猜测 Key
+func validAPIKey(keys []string, key string) bool {
+	valid := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNewListings 查询指定日期以来上市的股票
+//
+// @Summary 新上市股票
+// @Description 返回 stock_basic 中 list_date >= since 的股票，按上市日期倒序、股票代码升序。股票列表由 fetcher.stock_basic_refresh 定时刷新或 POST /fetch/stock-basic 手动刷新，刷新后新股才会出现
+// @Tags 数据
+// @Produce json
+// @Param since query string true "起始上市日期 YYYYMMDD"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.StockBasic}}
+// @Failure 400 {object} Response
+// @Router /data/new-listings [get]
+func (h *Handler) GetNewListings(c *gin.Context) {
+	since := c.Query("since")
+	if since == "" {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "since 不能为空",
+		})
+		return
+	}
+	if _, err := service.ParseDate(since); err != nil {
+		respond(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "since 格式错误，应为 YYYYMMDD",
+		})
+		return
+	}
+	p := h.parsePagination(c)
+
+	// list_date 以 YYYYMMDD 字符串存储，按字符串比较即可
+	db := database.GetDB().Model(&models.StockBasic{}).Where("list_date >= ?", since)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	stocks := make([]models.StockBasic, 0)
+	if err := db.Order("list_date desc, ts_code").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&stocks).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     stocks,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetNewListings 测试按 list_date 返回指定日期以来上市的股票
+func TestGetNewListings(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockBasic{})
+	require.NoError(t, database.DB.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", Name: "平安银行", ListDate: "19910403", ListStatus: "L"},
+		{TSCode: "603275.SH", Name: "众辰科技", ListDate: "20231207", ListStatus: "L"},
+		{TSCode: "301589.SZ", Name: "诺瓦星云", ListDate: "20240208", ListStatus: "L"},
+	}).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/new-listings?since=20231201", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data struct {
+			List  []models.StockBasic `json:"list"`
+			Total int64               `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	require.Len(t, resp.Data.List, 2)
+	assert.Equal(t, "301589.SZ", resp.Data.List[0].TSCode)
+	assert.Equal(t, "603275.SH", resp.Data.List[1].TSCode)
+
+	for _, query := range []string{"", "?since=2023-12-01"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/new-listings"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pagination 分页参数
+type pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Offset 返回查询偏移量
+func (p pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// parsePagination 从 page、page_size 查询参数解析分页，超出范围的值按配置修正而不报错
+func (h *Handler) parsePagination(c *gin.Context) pagination {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	return clampPagination(page, pageSize, h.config.DefaultPageSize, h.config.MaxPageSize)
+}
+
+// clampPagination 修正分页参数：页码小于 1 时取 1，每页数量未指定或非法时取默认值，超过上限时取上限
+func clampPagination(page, pageSize, defaultSize, maxSize int) pagination {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultSize
+	}
+	if pageSize > maxSize {
+		pageSize = maxSize
+	}
+	return pagination{Page: page, PageSize: pageSize}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClampPagination 测试分页参数越界时被修正
+func TestClampPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		page         int
+		pageSize     int
+		wantPage     int
+		wantPageSize int
+	}{
+		{name: "正常值", page: 3, pageSize: 50, wantPage: 3, wantPageSize: 50},
+		{name: "未指定时使用默认值", page: 0, pageSize: 0, wantPage: 1, wantPageSize: 20},
+		{name: "负数页码", page: -2, pageSize: 10, wantPage: 1, wantPageSize: 10},
+		{name: "负数每页数量", page: 1, pageSize: -5, wantPage: 1, wantPageSize: 20},
+		{name: "超过上限", page: 1, pageSize: 1000000, wantPage: 1, wantPageSize: 1000},
+		{name: "等于上限", page: 2, pageSize: 1000, wantPage: 2, wantPageSize: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := clampPagination(tt.page, tt.pageSize, 20, 1000)
+			assert.Equal(t, tt.wantPage, p.Page)
+			assert.Equal(t, tt.wantPageSize, p.PageSize)
+		})
+	}
+}
+
+// TestPaginationOffset 测试偏移量计算
+func TestPaginationOffset(t *testing.T) {
+	assert.Equal(t, 0, pagination{Page: 1, PageSize: 20}.Offset())
+	assert.Equal(t, 40, pagination{Page: 3, PageSize: 20}.Offset())
+}
+
+// getDailyCursorPage 按游标查询一页日线
+func getDailyCursorPage(t *testing.T, r http.Handler, cursor string) ([]models.StockDaily, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily?page_size=2&cursor="+url.QueryEscape(cursor), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			List       []models.StockDaily `json:"list"`
+			NextCursor string              `json:"next_cursor"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data.List, resp.Data.NextCursor
+}
+
+// TestGetDailyData_Cursor 测试游标分页按 (trade_date, id) 倒序遍历，翻页期间写入新数据不会导致重复或遗漏
+func TestGetDailyData_Cursor(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{})
+
+	day := func(d int) time.Time { return time.Date(2023, 12, d, 0, 0, 0, 0, service.Location()) }
+	rows := []models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: day(1)},
+		{TSCode: "600000.SH", TradeDate: day(1)},
+		{TSCode: "000001.SZ", TradeDate: day(4)},
+		{TSCode: "600000.SH", TradeDate: day(4)},
+		{TSCode: "000001.SZ", TradeDate: day(5)},
+	}
+	require.NoError(t, database.DB.Create(&rows).Error)
+
+	var seen []string
+	list, cursor := getDailyCursorPage(t, r, "")
+	for _, row := range list {
+		seen = append(seen, row.TSCode+"@"+row.TradeDate.In(service.Location()).Format("20060102"))
+	}
+	require.NotEmpty(t, cursor)
+
+	// 翻页期间写入更新的交易日以及与已读行同一天的新股票，按偏移分页会使后续页重复返回已读的行
+	require.NoError(t, database.DB.Create(&[]models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: day(6)},
+		{TSCode: "000002.SZ", TradeDate: day(5)},
+	}).Error)
+
+	for cursor != "" {
+		list, cursor = getDailyCursorPage(t, r, cursor)
+		for _, row := range list {
+			seen = append(seen, row.TSCode+"@"+row.TradeDate.In(service.Location()).Format("20060102"))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"000001.SZ@20231205", "600000.SH@20231204",
+		"000001.SZ@20231204", "600000.SH@20231201",
+		"000001.SZ@20231201",
+	}, seen)
+
+	// 无效游标
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily?cursor=invalid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
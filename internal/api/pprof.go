@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPprof 在 /debug/pprof 下挂载标准库 pprof 处理器，用于排查大批量回填时的内存与 CPU 问题
+func registerPprof(r *gin.Engine) {
+	debug := r.Group("/debug/pprof")
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// heap、goroutine、allocs 等命名 profile
+		debug.GET("/:name", gin.WrapF(pprof.Index))
+	}
+}
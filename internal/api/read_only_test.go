@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadOnly 测试只读模式下抓取、删除接口返回 403，查询和健康检查正常
+func TestReadOnly(t *testing.T) {
+	cfg := &config.ServerConfig{MaxTSCodes: 10, DefaultPageSize: 20, MaxPageSize: 100, ReadOnly: true}
+	r := newTestRouter(t, cfg, &models.StockDaily{}, &models.FetchJob{}, &models.FetchTask{})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/api/v1/fetch/daily", `{"start_date": "20230101", "end_date": "20231231"}`, http.StatusForbidden},
+		{http.MethodPost, "/api/v2/fetch/stock-basic", "", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/fetch/tasks", "", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/data/daily?ts_code=000001.SZ", "", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/admin/normalize-dates", "", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/data/daily", "", http.StatusOK},
+		{http.MethodGet, "/api/v2/data/daily", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/health", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.status, w.Code, "%s %s", tt.method, tt.path)
+	}
+
+	// 未开启只读模式时抓取正常排队
+	r = newTestRouter(t, nil, &models.FetchJob{}, &models.FetchTask{})
+	status, _ := postFetchDaily(t, r, `{"start_date": "20230101", "end_date": "20231231"}`, "")
+	assert.Equal(t, http.StatusOK, status)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DailyReturn 某交易日的收益
+type DailyReturn struct {
+	TradeDate        string  `json:"trade_date"`
+	Close            float64 `json:"close"`
+	Return           float64 `json:"return"`            // 相对上一个交易日收盘价的收益，首日为 0
+	CumulativeReturn float64 `json:"cumulative_return"` // 相对区间首日收盘价的累计收益
+}
+
+// ReturnsResult 区间收益统计，收益均为小数（0.05 表示 5%），数据不足无法计算的指标为 null
+type ReturnsResult struct {
+	TSCode             string        `json:"ts_code"`
+	StartDate          string        `json:"start_date"`            // 区间内第一个有收盘价的交易日
+	EndDate            string        `json:"end_date"`              // 区间内最后一个有收盘价的交易日
+	Bars               int           `json:"bars"`                  // 参与计算的交易日数
+	OpenToCloseReturn  *float64      `json:"open_to_close_return"`  // 首日开盘价到末日收盘价
+	CloseToCloseReturn *float64      `json:"close_to_close_return"` // 首日收盘价到末日收盘价
+	AnnualizedReturn   *float64      `json:"annualized_return"`     // 按每年 252 个交易日折算的 close_to_close_return
+	MaxDrawdown        *float64      `json:"max_drawdown"`          // 按收盘价计算的最大回撤（正数）
+	DrawdownPeakDate   string        `json:"drawdown_peak_date"`    // 最大回撤的前高日期，无回撤时为空
+	DrawdownTroughDate string        `json:"drawdown_trough_date"`  // 最大回撤的谷底日期，无回撤时为空
+	Daily              []DailyReturn `json:"daily,omitempty"`       // daily=true 时返回逐日收益
+}
+
+// GetDailyReturns 计算区间收益、年化收益和最大回撤
+//
+// @Summary 区间收益统计
+// @Description 基于已存储的未复权日线计算区间收益（首日开盘到末日收盘、首日收盘到末日收盘）、年化收益和最大回撤；收盘价缺失的交易日不参与计算，交易日不足两个时相关指标为 null
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string true "股票代码"
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Param daily query bool false "是否返回逐日收益" default(false)
+// @Success 200 {object} Response{data=ReturnsResult}
+// @Failure 400 {object} Response
+// @Router /data/daily/returns [get]
+func (h *Handler) GetDailyReturns(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	start, end, ok := parseDateRangeQuery(c, "ts_code")
+	if !ok {
+		return
+	}
+
+	var bars []models.StockDaily
+	if err := database.GetDB().Select("trade_date, open, close").
+		Where("ts_code = ? AND trade_date >= ? AND trade_date <= ? AND close IS NOT NULL", tsCode, start, end).
+		Order("trade_date asc").
+		Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	result := ReturnsResult{TSCode: tsCode, Bars: len(bars)}
+	if len(bars) == 0 {
+		respond(c, http.StatusOK, Response{
+			Code:    0,
+			Message: "区间内没有日线数据",
+			Data:    result,
+		})
+		return
+	}
+
+	first, last := bars[0], bars[len(bars)-1]
+	result.StartDate = first.TradeDate.Format("20060102")
+	result.EndDate = last.TradeDate.Format("20060102")
+
+	closes := make([]float64, 0, len(bars))
+	for _, bar := range bars {
+		closes = append(closes, *bar.Close)
+	}
+
+	if first.Open != nil && *first.Open != 0 {
+		r := *last.Close / *first.Open - 1
+		result.OpenToCloseReturn = &r
+	}
+	if len(bars) >= 2 {
+		if closes[0] != 0 {
+			r := closes[len(closes)-1]/closes[0] - 1
+			result.CloseToCloseReturn = &r
+			result.AnnualizedReturn = service.AnnualizedReturn(r, len(bars)-1)
+		}
+
+		drawdown, peak, trough := service.MaxDrawdown(closes)
+		result.MaxDrawdown = &drawdown
+		if peak >= 0 {
+			result.DrawdownPeakDate = bars[peak].TradeDate.Format("20060102")
+			result.DrawdownTroughDate = bars[trough].TradeDate.Format("20060102")
+		}
+	}
+
+	if c.Query("daily") == "true" {
+		result.Daily = make([]DailyReturn, 0, len(bars))
+		for i, bar := range bars {
+			point := DailyReturn{
+				TradeDate: bar.TradeDate.Format("20060102"),
+				Close:     closes[i],
+			}
+			if i > 0 && closes[i-1] != 0 {
+				point.Return = closes[i]/closes[i-1] - 1
+			}
+			if closes[0] != 0 {
+				point.CumulativeReturn = closes[i]/closes[0] - 1
+			}
+			result.Daily = append(result.Daily, point)
+		}
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetStocks_SortTotalMVTablePrefix 测试配置表名前缀后按总市值排序关联带前缀的 daily_basic，
+// 表不存在或缺少 total_mv 列时返回 400
+func TestGetStocks_SortTotalMVTablePrefix(t *testing.T) {
+	models.SetTablePrefix("sd_")
+	t.Cleanup(func() { models.SetTablePrefix("") })
+
+	r := newTestRouter(t, nil, &models.StockBasic{})
+	require.NoError(t, database.DB.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", Name: "平安银行", ListStatus: "L"},
+		{TSCode: "600000.SH", Name: "浦发银行", ListStatus: "L"},
+		{TSCode: "600519.SH", Name: "贵州茅台", ListStatus: "L"},
+	}).Error)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/stocks?sort=total_mv&order=desc", nil))
+		return w
+	}
+
+	// 只有不带前缀的表时不关联
+	require.NoError(t, database.DB.Exec("CREATE TABLE daily_basic (ts_code TEXT, trade_date TEXT, total_mv REAL)").Error)
+	assert.Equal(t, http.StatusBadRequest, get().Code)
+
+	require.NoError(t, database.DB.Exec("CREATE TABLE sd_daily_basic (ts_code TEXT, trade_date TEXT)").Error)
+	assert.Equal(t, http.StatusBadRequest, get().Code)
+
+	require.NoError(t, database.DB.Exec("ALTER TABLE sd_daily_basic ADD COLUMN total_mv REAL").Error)
+	require.NoError(t, database.DB.Exec(`INSERT INTO sd_daily_basic (ts_code, trade_date, total_mv) VALUES
+		('000001.SZ', '20231201', 2000), ('600519.SH', '20231201', 21000),
+		('000001.SZ', '20231130', 99999)`).Error)
+
+	w := get()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			List []models.StockBasic `json:"list"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	codes := make([]string, 0, len(resp.Data.List))
+	for _, stock := range resp.Data.List {
+		codes = append(codes, stock.TSCode)
+	}
+	// 按最新交易日的总市值倒序，无市值数据的排在最后
+	assert.Equal(t, []string{"600519.SH", "000001.SZ", "600000.SH"}, codes)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetTaskLogs 查询任务执行期间的警告及错误日志
+//
+// @Summary 查询任务日志
+// @Description 返回任务执行期间记录的警告及错误日志（如单个日期或股票抓取失败的原因），按记录顺序排列；fields 为日志的其余字段（JSON），如 date、ts_code、error
+// @Tags 任务
+// @Produce json
+// @Param task_id path string true "任务ID"
+// @Param level query string false "日志级别：warn、error"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量，超过上限时取上限" default(20)
+// @Success 200 {object} Response{data=PageResult{list=[]models.TaskLog}}
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Security ApiKeyAuth
+// @Router /fetch/tasks/{task_id}/logs [get]
+func (h *Handler) GetTaskLogs(c *gin.Context) {
+	taskID := c.Param("task_id")
+	p := h.parsePagination(c)
+
+	db := database.GetDB()
+	if err := db.Select("id").Where("task_id = ?", taskID).First(&models.FetchTask{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respond(c, http.StatusNotFound, Response{
+				Code:    404,
+				Message: "任务不存在",
+			})
+			return
+		}
+		h.respondQueryError(c, err)
+		return
+	}
+
+	query := db.Model(&models.TaskLog{}).Where("task_id = ?", taskID)
+	if level := c.Query("level"); level != "" {
+		query = query.Where("level = ?", level)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	logs := make([]models.TaskLog, 0)
+	if err := query.Order("id asc").
+		Limit(p.PageSize).
+		Offset(p.Offset()).
+		Find(&logs).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data: PageResult{
+			List:     logs,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		},
+	})
+}
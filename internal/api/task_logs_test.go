@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetTaskLogs 测试按任务查询日志，按记录顺序返回，可按级别过滤；任务不存在时返回 404
+func TestGetTaskLogs(t *testing.T) {
+	r := newTestRouter(t, nil, &models.FetchTask{}, &models.TaskLog{})
+	require.NoError(t, database.DB.Create(&models.FetchTask{TaskID: "task_1", Status: models.TaskStatusCompleted}).Error)
+	require.NoError(t, database.DB.Create(&[]models.TaskLog{
+		{TaskID: "task_1", Level: "error", Message: "抓取日期数据失败", Fields: `{"date":"20231201"}`},
+		{TaskID: "task_2", Level: "error", Message: "抓取日期数据失败"},
+		{TaskID: "task_1", Level: "warn", Message: "保存任务检查点失败"},
+	}).Error)
+
+	var resp struct {
+		Data struct {
+			List  []models.TaskLog `json:"list"`
+			Total int64            `json:"total"`
+		} `json:"data"`
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/fetch/tasks/task_1/logs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	require.Len(t, resp.Data.List, 2)
+	assert.Equal(t, "抓取日期数据失败", resp.Data.List[0].Message)
+	assert.Equal(t, `{"date":"20231201"}`, resp.Data.List[0].Fields)
+	assert.Equal(t, "保存任务检查点失败", resp.Data.List[1].Message)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/fetch/tasks/task_1/logs?level=warn", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1), resp.Data.Total)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/fetch/tasks/task_2/logs", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
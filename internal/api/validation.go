@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 单个请求字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名，与请求 JSON 中的名称一致
+	Rule    string `json:"rule"`    // 未通过的校验规则，如 required、oneof、type、format
+	Message string `json:"message"` // 错误说明
+}
+
+// Error 实现 error 接口，返回错误说明
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// newFieldError 创建字段校验错误
+func newFieldError(field, rule, format string, args ...interface{}) *FieldError {
+	return &FieldError{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
+
+func init() {
+	// 校验错误中的字段名使用 JSON 名称，与客户端提交的字段一致
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName 返回结构体字段的 JSON 名称，未声明 json 标签时使用字段名
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// fieldErrors 将请求解析、校验错误转换为字段错误列表，无法对应到字段的错误返回 nil
+func fieldErrors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		result := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			result = append(result, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: validationMessage(fe),
+			})
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s 类型错误，应为 %s", typeErr.Field, typeErr.Type),
+		}}
+	}
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return []FieldError{*fieldErr}
+	}
+	return nil
+}
+
+// validationMessage 返回单条校验规则的错误说明
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + " 不能为空"
+	case "oneof":
+		return fmt.Sprintf("%s 取值必须为以下之一: %s", fe.Field(), fe.Param())
+	case "datetime":
+		return fmt.Sprintf("%s 格式错误，应为 %s", fe.Field(), fe.Param())
+	case "min", "gte":
+		return fmt.Sprintf("%s 不能小于 %s", fe.Field(), fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s 不能大于 %s", fe.Field(), fe.Param())
+	default:
+		return fmt.Sprintf("%s 不满足校验规则 %s", fe.Field(), fe.Tag())
+	}
+}
+
+// respondValidationError 返回 400，errors 中逐项列出未通过校验的字段
+// message 保持「参数错误: 」加错误说明的格式，能对应到字段时为各字段说明的拼接
+func respondValidationError(c *gin.Context, err error) {
+	errs := fieldErrors(err)
+	message := err.Error()
+	if len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, fe := range errs {
+			messages = append(messages, fe.Message)
+		}
+		message = strings.Join(messages, "; ")
+	}
+
+	respond(c, http.StatusBadRequest, Response{
+		Code:    400,
+		Message: "参数错误: " + message,
+		Errors:  errs,
+	})
+}
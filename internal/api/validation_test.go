@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetch_ValidationErrors 测试参数错误时逐项返回字段、规则和说明
+func TestFetch_ValidationErrors(t *testing.T) {
+	r := newTestRouter(t, nil)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		errors []FieldError
+	}{
+		{
+			name: "缺少必填字段且取值不合法",
+			path: "/api/v1/fetch/minute",
+			body: `{"start_date": "20230101", "freq": "2min"}`,
+			errors: []FieldError{
+				{Field: "end_date", Rule: "required", Message: "end_date 不能为空"},
+				{Field: "freq", Rule: "oneof", Message: "freq 取值必须为以下之一: 1min 5min 15min 30min 60min"},
+			},
+		},
+		{
+			name: "字段类型错误",
+			path: "/api/v1/fetch/daily",
+			body: `{"start_date": 20230101}`,
+			errors: []FieldError{
+				{Field: "start_date", Rule: "type", Message: "start_date 类型错误，应为 string"},
+			},
+		},
+		{
+			name: "日期格式错误",
+			path: "/api/v1/fetch/weekly",
+			body: `{"start_date": "2023-01-01", "end_date": "20231231"}`,
+			errors: []FieldError{
+				{Field: "start_date", Rule: "format", Message: "start_date 格式错误，应为 YYYYMMDD"},
+			},
+		},
+		{
+			name: "股票代码格式错误",
+			path: "/api/v1/fetch/daily/sync",
+			body: `{"ts_code": "000001", "start_date": "20230101", "end_date": "20230131"}`,
+			errors: []FieldError{
+				{Field: "ts_code", Rule: "format", Message: "股票代码格式错误: 000001"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var resp Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, 400, resp.Code)
+			assert.True(t, strings.HasPrefix(resp.Message, "参数错误: "), resp.Message)
+			assert.Equal(t, tt.errors, resp.Errors)
+		})
+	}
+}
+
+// TestFetch_ValidationErrorsV2 测试 v2 的 problem+json 同样包含字段错误
+func TestFetch_ValidationErrorsV2(t *testing.T) {
+	r := newTestRouter(t, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/fetch/minute", strings.NewReader(`{"start_date": "20230101", "end_date": "20230131"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "参数错误: freq 不能为空", problem.Detail)
+	assert.Equal(t, []FieldError{{Field: "freq", Rule: "required", Message: "freq 不能为空"}}, problem.Errors)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// minVolatilityReturns 计算波动率所需的最少日收益个数（样本标准差至少需要 2 个样本）
+const minVolatilityReturns = 2
+
+// VolatilityResult 区间波动率，按日对数收益的样本标准差计算
+type VolatilityResult struct {
+	TSCode     string   `json:"ts_code"`
+	StartDate  string   `json:"start_date"` // 区间内第一个有收盘价的交易日
+	EndDate    string   `json:"end_date"`   // 区间内最后一个有收盘价的交易日
+	Bars       int      `json:"bars"`       // 区间内有收盘价的交易日数
+	Returns    int      `json:"returns"`    // 参与计算的日收益个数
+	Skipped    int      `json:"skipped"`    // 缺少昨收价且前一交易日没有数据、无法计算收益的交易日数
+	Volatility *float64 `json:"volatility"` // 日收益标准差，annualize=true 时为乘以 √252 后的年化波动率
+	Annualized bool     `json:"annualized"`
+}
+
+// GetDailyVolatility 计算区间内日对数收益的标准差
+//
+// @Summary 区间波动率
+// @Description 基于已存储的未复权日线计算日对数收益 ln(close/pre_close) 的样本标准差，annualize=true 时乘以 √252 折算为年化波动率；使用当日昨收价计算收益，停牌、漏抓造成的缺口不会把多日涨跌算作一日；昨收价缺失时使用前一交易日的收盘价，两者都没有的交易日跳过；有效收益少于 2 个时返回 422
+// @Tags 数据
+// @Produce json
+// @Param ts_code query string true "股票代码"
+// @Param start_date query string true "开始日期 YYYYMMDD"
+// @Param end_date query string true "结束日期 YYYYMMDD"
+// @Param annualize query bool false "是否年化" default(false)
+// @Success 200 {object} Response{data=VolatilityResult}
+// @Failure 400 {object} Response
+// @Failure 422 {object} Response
+// @Router /data/daily/volatility [get]
+func (h *Handler) GetDailyVolatility(c *gin.Context) {
+	tsCode := c.Query("ts_code")
+	start, end, ok := parseDateRangeQuery(c, "ts_code")
+	if !ok {
+		return
+	}
+
+	var bars []models.StockDaily
+	if err := database.GetDB().Select("trade_date, close, pre_close").
+		Where("ts_code = ? AND trade_date >= ? AND trade_date <= ? AND close IS NOT NULL", tsCode, start, end).
+		Order("trade_date asc").
+		Find(&bars).Error; err != nil {
+		h.respondQueryError(c, err)
+		return
+	}
+
+	result := VolatilityResult{TSCode: tsCode, Bars: len(bars), Annualized: c.Query("annualize") == "true"}
+	if len(bars) > 0 {
+		result.StartDate = bars[0].TradeDate.Format("20060102")
+		result.EndDate = bars[len(bars)-1].TradeDate.Format("20060102")
+	}
+
+	returns := make([]float64, 0, len(bars))
+	for i, bar := range bars {
+		var base float64
+		switch {
+		case bar.PreClose != nil:
+			base = *bar.PreClose
+		case i > 0:
+			base = *bars[i-1].Close
+		default:
+			result.Skipped++
+			continue
+		}
+		r, ok := service.LogReturn(base, *bar.Close)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		returns = append(returns, r)
+	}
+	result.Returns = len(returns)
+
+	if len(returns) < minVolatilityReturns {
+		respond(c, http.StatusUnprocessableEntity, Response{
+			Code:    422,
+			Message: fmt.Sprintf("区间内有效日收益 %d 个，至少需要 %d 个", len(returns), minVolatilityReturns),
+			Data:    result,
+		})
+		return
+	}
+
+	result.Volatility = service.StdDev(returns)
+	if result.Annualized {
+		annualized := *result.Volatility * math.Sqrt(service.TradingDaysPerYear)
+		result.Volatility = &annualized
+	}
+
+	respond(c, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    result,
+	})
+}
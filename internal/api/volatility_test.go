@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/database"
+	"stock_data/internal/models"
+	"stock_data/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetDailyVolatility 测试按昨收价计算日对数收益，跳过无法计算的交易日，数据不足时返回 422
+func TestGetDailyVolatility(t *testing.T) {
+	r := newTestRouter(t, nil, &models.StockDaily{})
+	f := func(v float64) *float64 { return &v }
+	date := func(s string) models.StockDaily {
+		d, err := service.ParseDate(s)
+		require.NoError(t, err)
+		return models.StockDaily{TSCode: "000001.SZ", TradeDate: d}
+	}
+	// 20231201 缺少昨收价，20231205 没有数据，20231206 按当日昨收价计算
+	bars := []models.StockDaily{date("20231201"), date("20231204"), date("20231206")}
+	bars[0].Close = f(10)
+	bars[1].Close, bars[1].PreClose = f(11), f(10)
+	bars[2].Close, bars[2].PreClose = f(9.9), f(11)
+	require.NoError(t, database.DB.Create(&bars).Error)
+
+	get := func(query string) (int, VolatilityResult) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/data/daily/volatility?ts_code=000001.SZ&"+query, nil))
+		var resp struct {
+			Data VolatilityResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data
+	}
+
+	expected := math.Abs(math.Log(1.1)-math.Log(0.9)) / math.Sqrt2
+	status, result := get("start_date=20231201&end_date=20231231")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 3, result.Bars)
+	assert.Equal(t, 2, result.Returns)
+	assert.Equal(t, 1, result.Skipped)
+	assert.False(t, result.Annualized)
+	require.NotNil(t, result.Volatility)
+	assert.InDelta(t, expected, *result.Volatility, 1e-9)
+
+	status, result = get("start_date=20231201&end_date=20231231&annualize=true")
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, result.Annualized)
+	require.NotNil(t, result.Volatility)
+	assert.InDelta(t, expected*math.Sqrt(252), *result.Volatility, 1e-9)
+
+	status, result = get("start_date=20231201&end_date=20231204")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, 1, result.Returns)
+	assert.Nil(t, result.Volatility)
+
+	status, _ = get("start_date=20231231&end_date=20231201")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
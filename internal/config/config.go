@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,47 +16,97 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Fetcher  FetcherConfig  `mapstructure:"fetcher"`
 	Log      LogConfig      `mapstructure:"log"`
+	Notify   NotifyConfig   `mapstructure:"notify"`
 }
 
 // TushareConfig Tushare API 配置
 type TushareConfig struct {
-	Token   string `mapstructure:"token"`
-	BaseURL string `mapstructure:"base_url"`
-	Timeout int    `mapstructure:"timeout"`
-	Retry   int    `mapstructure:"retry"`
+	Token               string `mapstructure:"token" validate:"required"`
+	BaseURL             string `mapstructure:"base_url" validate:"required,url"`
+	UserAgent           string `mapstructure:"user_agent"`              // 请求 Tushare 时的 User-Agent，为空使用默认值
+	Timeout             int    `mapstructure:"timeout" validate:"gt=0"` // 请求超时（秒）
+	Retry               int    `mapstructure:"retry" validate:"gte=0"`
+	MaxIdleConns        int    `mapstructure:"max_idle_conns"`          // 最大空闲连接数
+	MaxIdleConnsPerHost int    `mapstructure:"max_idle_conns_per_host"` // 每个主机最大空闲连接数
+	IdleConnTimeout     int    `mapstructure:"idle_conn_timeout"`       // 空闲连接超时（秒）
+	BreakerThreshold    int    `mapstructure:"breaker_threshold"`       // 连续失败多少次后熔断
+	BreakerCooldown     int    `mapstructure:"breaker_cooldown"`        // 熔断持续时间（秒）
+	RawResponses        bool   `mapstructure:"raw_responses"`           // 调试用：保存每次请求的原始响应到 raw_responses 表，默认关闭
+	RawResponseMaxCount int    `mapstructure:"raw_response_max_count"`  // 原始响应最多保留条数，默认 1000
+	RawResponseMaxAge   int    `mapstructure:"raw_response_max_age"`    // 原始响应保留天数，默认 3
+	DedupTTLMs          int    `mapstructure:"dedup_ttl_ms"`            // 相同请求（接口名、参数、字段）的去重有效期（毫秒），并发的相同请求合并为一次，默认 2000，小于 0 关闭
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Type            string `mapstructure:"type"`
-	Host            string `mapstructure:"host"`
-	Port            int    `mapstructure:"port"`
-	User            string `mapstructure:"user"`
-	Password        string `mapstructure:"password"`
-	DBName          string `mapstructure:"dbname"`
-	MaxOpenConns    int    `mapstructure:"max_open_conns"`
-	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	Type              string `mapstructure:"type" validate:"oneof=postgres mysql"`
+	Host              string `mapstructure:"host" validate:"required"`
+	Port              int    `mapstructure:"port" validate:"min=1,max=65535"`
+	User              string `mapstructure:"user" validate:"required"`
+	Password          string `mapstructure:"password"`
+	DBName            string `mapstructure:"dbname" validate:"required"`
+	MaxOpenConns      int    `mapstructure:"max_open_conns"`
+	MaxIdleConns      int    `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime   int    `mapstructure:"conn_max_lifetime"`
+	ConnectRetry      int    `mapstructure:"connect_retry"`       // 启动时连接失败重试次数
+	ConnectRetryDelay int    `mapstructure:"connect_retry_delay"` // 重试间隔（秒）
+	TablePrefix       string `mapstructure:"table_prefix"`        // 表名前缀，如 sd_，与其他服务共用数据库时区分表
+	Timezone          string `mapstructure:"timezone"`            // 数据库会话时区，同时用于解析 YYYYMMDD 日期，默认 Asia/Shanghai
 }
 
 // ServerConfig 服务配置
 type ServerConfig struct {
-	Port int    `mapstructure:"port"`
-	Mode string `mapstructure:"mode"`
+	Port           int    `mapstructure:"port" validate:"min=1,max=65535"`
+	Mode           string `mapstructure:"mode" validate:"omitempty,oneof=debug release test"`
+	MaxBodyBytes   int64  `mapstructure:"max_body_bytes"`   // 请求体大小上限（字节）
+	MaxImportBytes int64  `mapstructure:"max_import_bytes"` // 导入接口上传文件的大小上限（字节）
+	MaxTSCodes     int    `mapstructure:"max_ts_codes"`     // 单次请求允许的股票代码数量上限
+	MaxFetchDays   int    `mapstructure:"max_fetch_days"`   // 日线、周线、月线单次抓取请求的日期范围上限（天），0 表示不限制
+
+	DefaultPageSize int `mapstructure:"default_page_size"` // 列表接口默认每页数量
+	MaxPageSize     int `mapstructure:"max_page_size"`     // 列表接口每页数量上限
+
+	EnablePprof bool `mapstructure:"enable_pprof"` // 在 /debug/pprof 挂载性能分析接口，默认关闭
+	ReadOnly    bool `mapstructure:"read_only"`    // 只读模式：抓取、删除和维护接口返回 403，不执行排队的作业
+
+	APIKeys     []string `mapstructure:"api_keys" validate:"dive,required"` // 抓取、删除、导入和维护接口要求的 API Key，为空不鉴权
+	ProtectData bool     `mapstructure:"protect_data"`                      // 配置 api_keys 后 /data 查询和 /stats 也要求 API Key，默认开放
 }
 
 // FetcherConfig 数据抓取配置
 type FetcherConfig struct {
-	Concurrency int    `mapstructure:"concurrency"`
-	BatchSize   int    `mapstructure:"batch_size"`
-	RateLimit   int    `mapstructure:"rate_limit"`
-	StartDate   string `mapstructure:"start_date"`
-	EndDate     string `mapstructure:"end_date"`
+	Concurrency        int      `mapstructure:"concurrency"`
+	BatchSize          int      `mapstructure:"batch_size"`
+	RateLimit          int      `mapstructure:"rate_limit"`
+	MaxConcurrentTasks int      `mapstructure:"max_concurrent_tasks"` // 全局同时运行的抓取任务上限
+	TaskTimeout        int      `mapstructure:"task_timeout"`         // 单个后台抓取任务的最长执行时间（分钟），0 表示不限制
+	JobWorkers         int      `mapstructure:"job_workers"`          // 处理排队抓取作业的 worker 数
+	ShutdownTimeout    int      `mapstructure:"shutdown_timeout"`     // 关闭服务时等待执行中作业结束的时间（秒），超时后中断并在重启后继续
+	StartDate          string   `mapstructure:"start_date" validate:"omitempty,datetime=20060102"`
+	EndDate            string   `mapstructure:"end_date" validate:"omitempty,datetime=20060102"`
+	StockListStatus    string   `mapstructure:"stock_list_status" validate:"omitempty,oneof=L D P"`                      // 股票列表上市状态 L/D/P，默认 L
+	StockListStatuses  []string `mapstructure:"stock_list_statuses" validate:"dive,oneof=L D P"`                         // 抓取全部状态股票列表时依次请求的上市状态，默认 L、D、P
+	StockMarket        string   `mapstructure:"stock_market"`                                                            // 股票列表市场类别，为空获取全部市场
+	InsertMode         string   `mapstructure:"insert_mode" validate:"oneof=upsert skip replace"`                        // 行情数据已存在时的写入方式 upsert/skip/replace，默认 upsert
+	DecimalRounding    string   `mapstructure:"decimal_rounding" validate:"oneof=half_up half_even none"`                // 写入前按列小数位数舍入的方式 half_up/half_even/none，默认 half_up
+	AllowedHours       string   `mapstructure:"allowed_hours"`                                                           // 允许发起抓取的时段（按 database.timezone），如 18:00-23:00，为空不限制
+	CalendarFallback   string   `mapstructure:"calendar_fallback" validate:"omitempty,oneof=weekend_filter fail cached"` // 无法获取交易日历时的降级策略 weekend_filter/fail/cached，为空时已存储交易日历则为 cached，否则为 fail
+
+	AutoFetchStockBasic bool `mapstructure:"auto_fetch_stock_basic"` // 按股票抓取前 stock_basic 为空或过期时自动抓取
+	StockBasicMaxAge    int  `mapstructure:"stock_basic_max_age"`    // stock_basic 过期天数，0 表示只在为空时抓取
+	StockBasicRefresh   int  `mapstructure:"stock_basic_refresh"`    // 定时刷新 stock_basic 的间隔（小时），用于及时发现新上市股票，0 表示不定时刷新
+	ComputeAmplitude    bool `mapstructure:"compute_amplitude"`      // 写入日线时计算振幅并保存到 amplitude 列，默认关闭
+	SlowInsertMs        int  `mapstructure:"slow_insert_ms"`         // 入库耗时阈值（毫秒），超过时自动降低并发数，0 表示不限制
+	InsertWorkers       int  `mapstructure:"insert_workers"`         // 日线抓取的写入 worker 数，抓取与写入并行，0 表示在抓取 goroutine 中直接写入
+	ProgressIntervalMs  int  `mapstructure:"progress_interval_ms"`   // 任务进度写库的最小间隔（毫秒），期间的更新合并写入，0 表示每次更新都写入
+	RecordFailures      bool `mapstructure:"record_failures"`        // 将重试后仍失败的抓取单元及错误写入 fetch_failures 表
+	DateRetry           int  `mapstructure:"date_retry"`             // 按日期抓取时单个日期失败后重新抓取整个日期的次数，与 tushare.retry 的单次请求重试分开计算，默认 1，小于 0 不重新抓取
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level      string `mapstructure:"level"`
+	Level      string `mapstructure:"level" validate:"omitempty,oneof=debug info warn error"`
+	Format     string `mapstructure:"format" validate:"omitempty,oneof=json console"`
 	File       string `mapstructure:"file"`
 	MaxSize    int    `mapstructure:"max_size"`
 	MaxBackups int    `mapstructure:"max_backups"`
@@ -61,6 +114,15 @@ type LogConfig struct {
 	Compress   bool   `mapstructure:"compress"`
 }
 
+// NotifyConfig 任务结束通知配置
+type NotifyConfig struct {
+	WebhookURL string   `mapstructure:"webhook_url" validate:"omitempty,url"`                                        // 任务结束时 POST 通知的地址，为空不通知
+	Events     []string `mapstructure:"events" validate:"dive,oneof=completed failed timeout cancelled interrupted"` // 需要通知的任务状态，默认 completed、failed、timeout
+	Timeout    int      `mapstructure:"timeout"`                                                                     // 单次请求超时（秒）
+	Retry      int      `mapstructure:"retry"`                                                                       // 失败重试次数
+	RetryDelay int      `mapstructure:"retry_delay"`                                                                 // 首次重试间隔（秒），之后每次翻倍
+}
+
 var GlobalConfig *Config
 
 // LoadConfig 加载配置文件
@@ -88,36 +150,188 @@ func LoadConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
-// validateConfig 验证配置
+// validateConfig 填充默认值后校验配置，所有问题汇总为一个错误返回
 func validateConfig(config *Config) error {
-	if config.Tushare.Token == "" || config.Tushare.Token == "your_tushare_token_here" {
-		return fmt.Errorf("请配置有效的 Tushare Token")
+	if config.Database.ConnectRetry < 0 {
+		config.Database.ConnectRetry = 0
+	}
+
+	if config.Database.ConnectRetryDelay <= 0 {
+		config.Database.ConnectRetryDelay = 3
+	}
+
+	if config.Tushare.RawResponseMaxCount <= 0 {
+		config.Tushare.RawResponseMaxCount = 1000
+	}
+	if config.Tushare.RawResponseMaxAge <= 0 {
+		config.Tushare.RawResponseMaxAge = 3
+	}
+	if config.Tushare.DedupTTLMs == 0 {
+		config.Tushare.DedupTTLMs = 2000
+	}
+
+	if config.Database.Timezone == "" {
+		config.Database.Timezone = defaultTimezone
+	}
+
+	if config.Server.MaxBodyBytes <= 0 {
+		config.Server.MaxBodyBytes = 1 << 20
+	}
+
+	if config.Server.MaxImportBytes <= 0 {
+		config.Server.MaxImportBytes = 1 << 30
 	}
 
-	if config.Database.Type != "postgres" && config.Database.Type != "mysql" {
-		return fmt.Errorf("数据库类型必须是 postgres 或 mysql")
+	if config.Server.MaxTSCodes <= 0 {
+		config.Server.MaxTSCodes = 500
+	}
+
+	if config.Server.MaxPageSize <= 0 {
+		config.Server.MaxPageSize = 1000
+	}
+
+	if config.Server.DefaultPageSize <= 0 {
+		config.Server.DefaultPageSize = 20
+	}
+
+	if config.Server.DefaultPageSize > config.Server.MaxPageSize {
+		config.Server.DefaultPageSize = config.Server.MaxPageSize
 	}
 
 	if config.Fetcher.Concurrency <= 0 {
 		config.Fetcher.Concurrency = 10
 	}
 
+	if config.Fetcher.RateLimit <= 0 {
+		config.Fetcher.RateLimit = 200
+	}
+
+	if config.Fetcher.MaxConcurrentTasks <= 0 {
+		config.Fetcher.MaxConcurrentTasks = 3
+	}
+
+	if config.Fetcher.JobWorkers <= 0 {
+		config.Fetcher.JobWorkers = 2
+	}
+
+	if config.Fetcher.ShutdownTimeout <= 0 {
+		config.Fetcher.ShutdownTimeout = 30
+	}
+
 	if config.Fetcher.BatchSize <= 0 {
 		config.Fetcher.BatchSize = 1000
 	}
 
-	return nil
+	if len(config.Fetcher.StockListStatuses) == 0 {
+		config.Fetcher.StockListStatuses = []string{"L", "D", "P"}
+	}
+
+	if config.Fetcher.InsertMode == "" {
+		config.Fetcher.InsertMode = "upsert"
+	}
+
+	if config.Fetcher.DecimalRounding == "" {
+		config.Fetcher.DecimalRounding = "half_up"
+	}
+
+	if config.Fetcher.DateRetry == 0 {
+		config.Fetcher.DateRetry = 1
+	}
+
+	if len(config.Notify.Events) == 0 {
+		config.Notify.Events = []string{"completed", "failed", "timeout"}
+	}
+
+	if config.Notify.Timeout <= 0 {
+		config.Notify.Timeout = 10
+	}
+
+	if config.Notify.Retry < 0 {
+		config.Notify.Retry = 0
+	}
+
+	if config.Notify.RetryDelay <= 0 {
+		config.Notify.RetryDelay = 1
+	}
+
+	return checkConfig(config)
 }
 
-// GetDSN 获取数据库连接字符串
+// ParseAllowedHours 解析 HH:MM-HH:MM 格式的时段，返回起止时间距零点的分钟数
+// 结束早于开始表示跨越零点（如 22:00-06:00）；为空时返回 -1, -1 表示不限制
+func ParseAllowedHours(value string) (start, end int, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return -1, -1, nil
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("应为 HH:MM-HH:MM: %s", value)
+	}
+	if start, err = parseClock(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(parts[1]); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("开始与结束时间不能相同: %s", value)
+	}
+	return start, end, nil
+}
+
+// parseClock 解析 HH:MM，返回距零点的分钟数，允许 24:00
+func parseClock(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("时间格式错误，应为 HH:MM: %s", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// defaultTimezone 未配置 database.timezone 时使用的时区
+const defaultTimezone = "Asia/Shanghai"
+
+// DefaultLocation 返回默认时区，运行环境缺少时区数据库时使用固定的 UTC+8
+func DefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		return time.FixedZone("CST", 8*3600)
+	}
+	return loc
+}
+
+// Location 返回 timezone 对应的时区，未配置时为 Asia/Shanghai
+func (c *DatabaseConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" || c.Timezone == defaultTimezone {
+		return DefaultLocation(), nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("时区无效: %s", c.Timezone)
+	}
+	return loc, nil
+}
+
+// GetDSN 获取数据库连接字符串，会话时区与 timezone 一致
 func (c *DatabaseConfig) GetDSN() string {
+	timezone := c.Timezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
 	switch c.Type {
 	case "postgres":
-		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable TimeZone=Asia/Shanghai",
-			c.Host, c.Port, c.User, c.Password, c.DBName)
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable TimeZone=%s",
+			c.Host, c.Port, c.User, c.Password, c.DBName, timezone)
 	case "mysql":
-		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			c.User, c.Password, c.Host, c.Port, c.DBName)
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=%s",
+			c.User, c.Password, c.Host, c.Port, c.DBName, url.QueryEscape(timezone))
 	default:
 		return ""
 	}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig 返回只包含必填项的合法配置
+func validConfig() *Config {
+	return &Config{
+		Tushare:  TushareConfig{Token: "test_token", BaseURL: "http://api.tushare.pro", Timeout: 30},
+		Database: DatabaseConfig{Type: "postgres", Host: "localhost", Port: 5432, User: "stock", DBName: "stock"},
+		Server:   ServerConfig{Port: 8080},
+	}
+}
+
+// TestValidateConfig_Defaults 测试未配置的选项填充默认值
+func TestValidateConfig_Defaults(t *testing.T) {
+	cfg := validConfig()
+	require.NoError(t, validateConfig(cfg))
+
+	assert.Equal(t, 3, cfg.Database.ConnectRetryDelay)
+	assert.Equal(t, "Asia/Shanghai", cfg.Database.Timezone)
+	assert.Equal(t, 1000, cfg.Tushare.RawResponseMaxCount)
+	assert.Equal(t, 3, cfg.Tushare.RawResponseMaxAge)
+	assert.Equal(t, 2000, cfg.Tushare.DedupTTLMs)
+
+	assert.Equal(t, int64(1<<20), cfg.Server.MaxBodyBytes)
+	assert.Equal(t, int64(1<<30), cfg.Server.MaxImportBytes)
+	assert.Equal(t, 500, cfg.Server.MaxTSCodes)
+	assert.Equal(t, 1000, cfg.Server.MaxPageSize)
+	assert.Equal(t, 20, cfg.Server.DefaultPageSize)
+
+	assert.Equal(t, 10, cfg.Fetcher.Concurrency)
+	assert.Equal(t, 200, cfg.Fetcher.RateLimit)
+	assert.Equal(t, 3, cfg.Fetcher.MaxConcurrentTasks)
+	assert.Equal(t, 2, cfg.Fetcher.JobWorkers)
+	assert.Equal(t, 30, cfg.Fetcher.ShutdownTimeout)
+	assert.Equal(t, 1000, cfg.Fetcher.BatchSize)
+	assert.Equal(t, []string{"L", "D", "P"}, cfg.Fetcher.StockListStatuses)
+	assert.Equal(t, "upsert", cfg.Fetcher.InsertMode)
+	assert.Equal(t, "half_up", cfg.Fetcher.DecimalRounding)
+	assert.Equal(t, 1, cfg.Fetcher.DateRetry)
+
+	assert.Equal(t, []string{"completed", "failed", "timeout"}, cfg.Notify.Events)
+	assert.Equal(t, 10, cfg.Notify.Timeout)
+	assert.Equal(t, 1, cfg.Notify.RetryDelay)
+}
+
+// TestValidateConfig_KeepsConfigured 测试已配置的值不被默认值覆盖，默认每页数量不超过上限
+func TestValidateConfig_KeepsConfigured(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tushare.DedupTTLMs = -1
+	cfg.Server.MaxPageSize = 50
+	cfg.Server.DefaultPageSize = 100
+	cfg.Fetcher.InsertMode = "skip"
+	cfg.Fetcher.DateRetry = -1
+	cfg.Database.ConnectRetry = -1
+	cfg.Notify.Retry = -1
+	require.NoError(t, validateConfig(cfg))
+
+	assert.Equal(t, -1, cfg.Tushare.DedupTTLMs)
+	assert.Equal(t, 50, cfg.Server.MaxPageSize)
+	assert.Equal(t, 50, cfg.Server.DefaultPageSize)
+	assert.Equal(t, "skip", cfg.Fetcher.InsertMode)
+	assert.Equal(t, -1, cfg.Fetcher.DateRetry)
+	assert.Equal(t, 0, cfg.Database.ConnectRetry)
+	assert.Equal(t, 0, cfg.Notify.Retry)
+}
+
+// TestValidateConfig_Invalid 测试非法配置返回的错误指明配置键
+func TestValidateConfig_Invalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   string
+	}{
+		{"token_empty", func(cfg *Config) { cfg.Tushare.Token = "" }, "tushare.token: 不能为空"},
+		{"token_placeholder", func(cfg *Config) { cfg.Tushare.Token = placeholderToken }, "tushare.token: 请配置有效的 Tushare Token"},
+		{"base_url", func(cfg *Config) { cfg.Tushare.BaseURL = "api.tushare.pro" }, "tushare.base_url: 必须是合法的 URL"},
+		{"timeout", func(cfg *Config) { cfg.Tushare.Timeout = 0 }, "tushare.timeout: 必须大于 0"},
+		{"retry", func(cfg *Config) { cfg.Tushare.Retry = -1 }, "tushare.retry: 不能小于 0"},
+		{"database_type", func(cfg *Config) { cfg.Database.Type = "sqlite" }, "database.type: 必须是 postgres、mysql 之一"},
+		{"database_port", func(cfg *Config) { cfg.Database.Port = 70000 }, "database.port: 不能大于 65535"},
+		{"timezone", func(cfg *Config) { cfg.Database.Timezone = "Mars/Base" }, "database.timezone: 时区无效: Mars/Base"},
+		{"server_mode", func(cfg *Config) { cfg.Server.Mode = "prod" }, "server.mode: 必须是 debug、release、test 之一"},
+		{"api_keys", func(cfg *Config) { cfg.Server.APIKeys = []string{"key", ""} }, "server.api_keys[1]: 不能为空"},
+		{"start_date", func(cfg *Config) { cfg.Fetcher.StartDate = "2020-01-01" }, "fetcher.start_date: 日期格式错误"},
+		{"stock_list_statuses", func(cfg *Config) { cfg.Fetcher.StockListStatuses = []string{"L", "X"} }, "fetcher.stock_list_statuses[1]"},
+		{"insert_mode", func(cfg *Config) { cfg.Fetcher.InsertMode = "overwrite" }, "fetcher.insert_mode: 必须是 upsert、skip、replace 之一"},
+		{"decimal_rounding", func(cfg *Config) { cfg.Fetcher.DecimalRounding = "floor" }, "fetcher.decimal_rounding"},
+		{"calendar_fallback", func(cfg *Config) { cfg.Fetcher.CalendarFallback = "skip" }, "fetcher.calendar_fallback"},
+		{"allowed_hours", func(cfg *Config) { cfg.Fetcher.AllowedHours = "18:00" }, "fetcher.allowed_hours: 应为 HH:MM-HH:MM"},
+		{"log_level", func(cfg *Config) { cfg.Log.Level = "trace" }, "log.level"},
+		{"webhook_url", func(cfg *Config) { cfg.Notify.WebhookURL = "not a url" }, "notify.webhook_url: 必须是合法的 URL"},
+		{"notify_events", func(cfg *Config) { cfg.Notify.Events = []string{"running"} }, "notify.events[0]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validConfig()
+			tc.modify(cfg)
+			err := validateConfig(cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+// TestValidateConfig_AllProblems 测试多项配置错误一次全部列出
+func TestValidateConfig_AllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tushare.Token = ""
+	cfg.Fetcher.InsertMode = "overwrite"
+	cfg.Fetcher.AllowedHours = "25:00-26:00"
+	cfg.Database.Timezone = "Mars/Base"
+
+	err := validateConfig(cfg)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "配置校验失败（4 项）"), err.Error())
+	assert.Contains(t, err.Error(), "tushare.token")
+	assert.Contains(t, err.Error(), "database.timezone")
+	assert.Contains(t, err.Error(), "fetcher.insert_mode")
+	assert.Contains(t, err.Error(), "fetcher.allowed_hours")
+}
+
+// TestLoadConfig 测试示例配置可以通过校验，配置文件中的值覆盖默认值
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("../../config/config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", cfg.Database.Type)
+	assert.Equal(t, 1, cfg.Fetcher.DateRetry)
+	assert.Equal(t, "upsert", cfg.Fetcher.InsertMode)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+tushare:
+  token: "test_token"
+  base_url: "http://api.tushare.pro"
+  timeout: 30
+database:
+  type: "oracle"
+  host: "localhost"
+  port: 5432
+  user: "stock"
+  dbname: "stock"
+server:
+  port: 8080
+`), 0o644))
+	_, err = LoadConfig(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.type")
+}
+
+// TestParseAllowedHours 测试时段解析，结束早于开始表示跨零点
+func TestParseAllowedHours(t *testing.T) {
+	start, end, err := ParseAllowedHours("")
+	require.NoError(t, err)
+	assert.Equal(t, -1, start)
+	assert.Equal(t, -1, end)
+
+	start, end, err = ParseAllowedHours(" 22:00-06:30 ")
+	require.NoError(t, err)
+	assert.Equal(t, 22*60, start)
+	assert.Equal(t, 6*60+30, end)
+
+	_, end, err = ParseAllowedHours("18:00-24:00")
+	require.NoError(t, err)
+	assert.Equal(t, 24*60, end)
+
+	for _, value := range []string{"18:00", "18:00-18:00", "18-23", "18:00-23:00-24:00"} {
+		_, _, err := ParseAllowedHours(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// placeholderToken 示例配置中的 Token 占位符
+const placeholderToken = "your_tushare_token_here"
+
+// newValidator 创建配置校验器，错误中的字段名使用配置文件中的键名
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("mapstructure"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// checkConfig 按 validate 标签及自定义规则校验配置，返回列出全部问题的错误
+func checkConfig(config *Config) error {
+	var problems []string
+
+	if err := newValidator().Struct(config); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return fmt.Errorf("校验配置失败: %w", err)
+		}
+		for _, fe := range validationErrors {
+			problems = append(problems, describeFieldError(fe))
+		}
+	}
+
+	if config.Tushare.Token == placeholderToken {
+		problems = append(problems, "tushare.token: 请配置有效的 Tushare Token")
+	}
+
+	if _, err := config.Database.Location(); err != nil {
+		problems = append(problems, "database.timezone: "+err.Error())
+	}
+
+	if _, _, err := ParseAllowedHours(config.Fetcher.AllowedHours); err != nil {
+		problems = append(problems, "fetcher.allowed_hours: "+err.Error())
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("配置校验失败（%d 项）:\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+}
+
+// describeFieldError 将校验错误转换为「配置键: 原因」形式的提示
+func describeFieldError(fe validator.FieldError) string {
+	// Namespace 形如 Config.tushare.token，去掉根结构体名
+	key := fe.Namespace()
+	if i := strings.Index(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+
+	var reason string
+	switch fe.Tag() {
+	case "required":
+		reason = "不能为空"
+	case "url":
+		reason = "必须是合法的 URL"
+	case "gt":
+		reason = "必须大于 " + fe.Param()
+	case "gte", "min":
+		reason = "不能小于 " + fe.Param()
+	case "max":
+		reason = "不能大于 " + fe.Param()
+	case "oneof":
+		reason = "必须是 " + strings.Join(strings.Fields(fe.Param()), "、") + " 之一"
+	case "datetime":
+		reason = "日期格式错误，应为 YYYYMMDD"
+	default:
+		reason = fmt.Sprintf("不满足校验规则 %s", fe.Tag())
+	}
+	if fe.Tag() == "required" {
+		return key + ": " + reason
+	}
+	return fmt.Sprintf("%s: %s（当前值: %v）", key, reason, fe.Value())
+}
@@ -2,19 +2,48 @@ package database
 
 import (
 	"fmt"
+	"reflect"
 	"stock_data/internal/config"
 	"stock_data/internal/models"
+	"strings"
 	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 )
 
 var DB *gorm.DB
 
-func InitDB(cfg *config.DatabaseConfig) error {
+// InitDB 初始化数据库连接，连接失败时按配置重试
+func InitDB(cfg *config.DatabaseConfig, log *zap.Logger) error {
+	attempts := cfg.ConnectRetry + 1
+	delay := time.Duration(cfg.ConnectRetryDelay) * time.Second
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = connect(cfg, log); err == nil {
+			return nil
+		}
+
+		log.Warn("连接数据库失败",
+			zap.Int("attempt", i),
+			zap.Int("max_attempts", attempts),
+			zap.Error(err))
+
+		if i < attempts {
+			time.Sleep(delay)
+		}
+	}
+
+	return fmt.Errorf("连接数据库失败（已尝试 %d 次）: %w", attempts, err)
+}
+
+// connect 打开数据库连接并测试连通性
+func connect(cfg *config.DatabaseConfig, log *zap.Logger) error {
 	var dialector gorm.Dialector
 
 	dsn := cfg.GetDSN()
@@ -27,20 +56,24 @@ func InitDB(cfg *config.DatabaseConfig) error {
 	default:
 		return fmt.Errorf("不支持的数据库类型: %s", cfg.Type)
 	}
+	// 模型的 TableName 不经过 NamingStrategy，需单独设置前缀；
+	// NamingStrategy 的前缀用于未实现 TableName 的表
+	models.SetTablePrefix(cfg.TablePrefix)
+
 	// 配置 GORM
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		NamingStrategy: schema.NamingStrategy{TablePrefix: cfg.TablePrefix},
 		NowFunc: func() time.Time {
 			return time.Now().Local()
 		},
 	}
-	var err error
-	DB, err = gorm.Open(dialector, gormConfig)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return err
 	}
 	// 获取底层数据库连接
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("获取数据库连接失败: %w", err)
 	}
@@ -51,9 +84,27 @@ func InitDB(cfg *config.DatabaseConfig) error {
 
 	// 测试连接
 	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
 		return fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 
+	DB = db
+
+	if cfg.TablePrefix != "" {
+		if err := prefixIndexNames(db); err != nil {
+			return fmt.Errorf("解析模型失败: %w", err)
+		}
+	}
+	if err := migrateColumns(); err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	if err := migrateIndexes(); err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	if err := migrateUniqueIndexes(log); err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+
 	//// 自动迁移
 	//if err := autoMigrate(); err != nil {
 	//	return fmt.Errorf("数据库迁移失败: %w", err)
@@ -62,11 +113,171 @@ func InitDB(cfg *config.DatabaseConfig) error {
 	return nil
 }
 
+// allModels 本服务创建的全部表
+var allModels = []interface{}{
+	&models.StockDaily{},
+	&models.StockLatest{},
+	&models.StockBasic{},
+	&models.StockCompany{},
+	&models.TopListEntry{},
+	&models.BlockTrade{},
+	&models.MarginDetail{},
+	&models.StockAdjFactor{},
+	&models.StockDailyAdj{},
+	&models.FundBasic{},
+	&models.FundDaily{},
+	&models.TradeCalendar{},
+	&models.IndexBasic{},
+	&models.HSConst{},
+	&models.FetchTask{},
+	&models.FetchTaskDate{},
+	&models.FetchFailure{},
+	&models.TaskLog{},
+	&models.RawResponse{},
+	&models.FetchJob{},
+	&models.StockWeekly{},
+	&models.StockMonthly{},
+	&models.FinaIndicator{},
+	&models.StockConcept{},
+	&models.StockMinute{},
+}
+
 // autoMigrate 自动迁移数据库表结构
 func autoMigrate() error {
-	return DB.AutoMigrate(
-		&models.StockDaily{},
-	)
+	return DB.AutoMigrate(allModels...)
+}
+
+// prefixIndexNames 为模型中显式命名的索引加上表名前缀（见 models.IndexName），配置了前缀时在连接后调用
+// GORM 直接使用标签中的索引名，不经过 NamingStrategy，这里改写 db 缓存的模型结构中字段的 gorm 标签，
+// 之后通过 db 建表、建索引都使用带前缀的索引名
+func prefixIndexNames(db *gorm.DB) error {
+	for _, model := range allModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		for _, field := range stmt.Schema.Fields {
+			tag := field.Tag.Get("gorm")
+			settings := strings.Split(tag, ";")
+			for i, setting := range settings {
+				key, name, found := strings.Cut(setting, ":")
+				upper := strings.ToUpper(strings.TrimSpace(key))
+				if !found || (upper != "INDEX" && upper != "UNIQUEINDEX") || !strings.HasPrefix(name, "idx_") {
+					continue
+				}
+				settings[i] = key + ":" + models.IndexName(name)
+			}
+			if prefixed := strings.Join(settings, ";"); prefixed != tag {
+				field.Tag = reflect.StructTag(strings.Replace(string(field.Tag), `gorm:"`+tag+`"`, `gorm:"`+prefixed+`"`, 1))
+			}
+		}
+	}
+	return nil
+}
+
+// addedColumns 已有表中新增的列，未开启自动迁移时在启动时检查并补充
+var addedColumns = []struct {
+	model interface{}
+	field string
+}{
+	{model: &models.StockDaily{}, field: "Amplitude"},
+	{model: &models.FetchTask{}, field: "RowsFetched"},
+	{model: &models.FetchTask{}, field: "RowsStored"},
+	{model: &models.FetchTask{}, field: "RowMetrics"},
+	{model: &models.FetchTask{}, field: "EstimatedEndTime"},
+	{model: &models.FetchTask{}, field: "APICalls"},
+	{model: &models.FetchTask{}, field: "IdempotencyKey"},
+}
+
+// migrateColumns 为已存在的表补充缺失的新增列，表不存在时跳过（由建表脚本或自动迁移创建）
+func migrateColumns() error {
+	migrator := DB.Migrator()
+	for _, column := range addedColumns {
+		if !migrator.HasTable(column.model) || migrator.HasColumn(column.model, column.field) {
+			continue
+		}
+		if err := migrator.AddColumn(column.model, column.field); err != nil {
+			return fmt.Errorf("添加列 %s 失败: %w", column.field, err)
+		}
+	}
+	return nil
+}
+
+// addedIndexes 已有表中新增的索引，未开启自动迁移时在启动时检查并补充，名称为不带前缀的标签名
+var addedIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{model: &models.StockDaily{}, name: "idx_daily_updated_at"},
+	{model: &models.FetchTask{}, name: "idx_task_idempotency_key"},
+}
+
+// migrateIndexes 为已存在的表补充缺失的新增索引，表不存在时跳过
+func migrateIndexes() error {
+	migrator := DB.Migrator()
+	for _, index := range addedIndexes {
+		name := models.IndexName(index.name)
+		if !migrator.HasTable(index.model) || migrator.HasIndex(index.model, name) {
+			continue
+		}
+		if err := migrator.CreateIndex(index.model, name); err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// uniqueIndexes 行情表 (ts_code, trade_date) 上的唯一索引，upsert、skip 写入依赖这些索引判断冲突
+// 早期版本在相同列上建的是普通索引（legacy），启动时去重后创建唯一索引并删除旧索引
+// name 为不带前缀的标签名；早期版本不支持表名前缀，legacy 不加前缀
+var uniqueIndexes = []struct {
+	model  interface{}
+	name   string
+	legacy string
+}{
+	{model: &models.StockDaily{}, name: "idx_daily_code_date_unique", legacy: "idx_ts_code_date"},
+	{model: &models.StockWeekly{}, name: "idx_weekly_code_date_unique", legacy: "idx_weekly_ts_code_date"},
+	{model: &models.StockMonthly{}, name: "idx_monthly_code_date_unique", legacy: "idx_monthly_ts_code_date"},
+}
+
+// migrateUniqueIndexes 为已存在的行情表创建 (ts_code, trade_date) 唯一索引，表不存在或已有索引时跳过
+// 创建前删除重复的记录，同一股票同一交易日只保留 id 最大（最后写入）的一条
+func migrateUniqueIndexes(log *zap.Logger) error {
+	migrator := DB.Migrator()
+	for _, index := range uniqueIndexes {
+		name := models.IndexName(index.name)
+		if !migrator.HasTable(index.model) || migrator.HasIndex(index.model, name) {
+			continue
+		}
+
+		deleted, err := deleteDuplicateRows(index.model)
+		if err != nil {
+			return fmt.Errorf("删除重复数据失败: %w", err)
+		}
+		if deleted > 0 {
+			log.Warn("创建唯一索引前已删除重复数据",
+				zap.String("index", name),
+				zap.Int64("deleted", deleted))
+		}
+
+		if err := migrator.CreateIndex(index.model, name); err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", name, err)
+		}
+		if migrator.HasIndex(index.model, index.legacy) {
+			if err := migrator.DropIndex(index.model, index.legacy); err != nil {
+				return fmt.Errorf("删除索引 %s 失败: %w", index.legacy, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteDuplicateRows 删除 (ts_code, trade_date) 重复的记录，每组保留 id 最大的一条，返回删除的行数
+// MySQL 不允许在删除语句的子查询中直接引用被删除的表，保留的 id 通过派生表查询
+func deleteDuplicateRows(model interface{}) (int64, error) {
+	keep := DB.Table("(?) AS keep_ids", DB.Model(model).Select("MAX(id) AS id").Group("ts_code, trade_date")).Select("id")
+	result := DB.Where("id NOT IN (?)", keep).Delete(model)
+	return result.RowsAffected, result.Error
 }
 
 // Close 关闭数据库连接
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// legacyStockDaily 早期版本的 stock_daily 表结构，(ts_code, trade_date) 上只有普通索引
+type legacyStockDaily struct {
+	ID        uint      `gorm:"primaryKey"`
+	TSCode    string    `gorm:"type:varchar(20);index:idx_ts_code_date,priority:1;not null"`
+	TradeDate time.Time `gorm:"type:date;index:idx_ts_code_date,priority:2;not null"`
+	Close     *float64  `gorm:"type:decimal(10,2)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (legacyStockDaily) TableName() string {
+	return "stock_daily"
+}
+
+// newTestDB 创建内存数据库并设置为全局 DB
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	previous := DB
+	DB = db
+	t.Cleanup(func() { DB = previous })
+	return db
+}
+
+// TestMigrateUniqueIndexes 测试已有表去重后创建唯一索引并删除旧的普通索引，重复执行不报错
+func TestMigrateUniqueIndexes(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&legacyStockDaily{}))
+
+	day := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) *float64 { return &v }
+	require.NoError(t, db.Create(&[]legacyStockDaily{
+		{TSCode: "000001.SZ", TradeDate: day, Close: price(9)},
+		{TSCode: "000001.SZ", TradeDate: day, Close: price(10)},
+		{TSCode: "000001.SZ", TradeDate: day.AddDate(0, 0, 3), Close: price(11)},
+		{TSCode: "600000.SH", TradeDate: day, Close: price(7)},
+	}).Error)
+
+	require.NoError(t, migrateUniqueIndexes(zap.NewNop()))
+	require.NoError(t, migrateUniqueIndexes(zap.NewNop()))
+
+	migrator := db.Migrator()
+	assert.True(t, migrator.HasIndex(&models.StockDaily{}, "idx_daily_code_date_unique"))
+	assert.False(t, migrator.HasIndex(&models.StockDaily{}, "idx_ts_code_date"))
+
+	// 重复的记录只保留最后写入的一条
+	var rows []legacyStockDaily
+	require.NoError(t, db.Order("id").Find(&rows).Error)
+	require.Len(t, rows, 3)
+	assert.Equal(t, uint(2), rows[0].ID)
+	assert.InDelta(t, 10, *rows[0].Close, 1e-9)
+
+	// 周线、月线表不存在时跳过
+	assert.False(t, migrator.HasTable(&models.StockWeekly{}))
+
+	err := db.Create(&legacyStockDaily{TSCode: "600000.SH", TradeDate: day}).Error
+	assert.Error(t, err)
+}
+
+// TestPrefixIndexNames 测试配置表名前缀后显式命名的索引同样带前缀，补建索引按带前缀的名称检查
+func TestPrefixIndexNames(t *testing.T) {
+	models.SetTablePrefix("sd_")
+	t.Cleanup(func() { models.SetTablePrefix("") })
+
+	db := newTestDB(t)
+	require.NoError(t, prefixIndexNames(db))
+	require.NoError(t, db.AutoMigrate(&models.StockDaily{}, &models.StockWeekly{}))
+
+	migrator := db.Migrator()
+	assert.True(t, migrator.HasTable("sd_stock_daily"))
+	for _, name := range []string{"idx_sd_daily_code_date_unique", "idx_sd_daily_updated_at", "idx_sd_trade_date", "idx_sd_weekly_code_date_unique"} {
+		assert.True(t, migrator.HasIndex(&models.StockDaily{}, name) || migrator.HasIndex(&models.StockWeekly{}, name), name)
+	}
+	assert.False(t, migrator.HasIndex(&models.StockDaily{}, "idx_daily_code_date_unique"))
+
+	require.NoError(t, migrateIndexes())
+	require.NoError(t, migrateUniqueIndexes(zap.NewNop()))
+	assert.False(t, migrator.HasIndex(&models.StockDaily{}, "idx_daily_updated_at"))
+}
@@ -1,30 +1,85 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// tablePrefix 表名前缀，对应 database.table_prefix
+var tablePrefix string
+
+// SetTablePrefix 设置表名前缀，需在首次访问数据库前调用
+func SetTablePrefix(prefix string) {
+	tablePrefix = prefix
+}
+
+// tableName 返回加上前缀的表名
+func tableName(name string) string {
+	return tablePrefix + name
+}
+
+// IndexName 返回显式命名的索引加上前缀后的名称，如前缀为 sd_ 时 idx_daily_code_date_unique 为 idx_sd_daily_code_date_unique
+func IndexName(name string) string {
+	if tablePrefix == "" {
+		return name
+	}
+	return "idx_" + tablePrefix + strings.TrimPrefix(name, "idx_")
+}
+
+// DailyBasicTable 返回每日指标表（加上前缀）的表名
+// 该表由外部导入，本服务不建表，使用前需检查表及所需列是否存在
+func DailyBasicTable() string {
+	return tableName("daily_basic")
+}
+
 // StockDaily 股票日线数据
 type StockDaily struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	TSCode    string    `gorm:"type:varchar(20);index:idx_ts_code_date,priority:1;not null" json:"ts_code"`                  // 股票代码
-	TradeDate time.Time `gorm:"type:date;index:idx_ts_code_date,priority:2;index:idx_trade_date;not null" json:"trade_date"` // 交易日期
-	Open      float64   `gorm:"type:decimal(10,2)" json:"open"`                                                              // 开盘价
-	High      float64   `gorm:"type:decimal(10,2)" json:"high"`                                                              // 最高价
-	Low       float64   `gorm:"type:decimal(10,2)" json:"low"`                                                               // 最低价
-	Close     float64   `gorm:"type:decimal(10,2)" json:"close"`                                                             // 收盘价
-	PreClose  float64   `gorm:"type:decimal(10,2)" json:"pre_close"`                                                         // 昨收价
-	Change    float64   `gorm:"type:decimal(10,2)" json:"change"`                                                            // 涨跌额
-	PctChg    float64   `gorm:"type:decimal(10,4)" json:"pct_chg"`                                                           // 涨跌幅
-	Vol       float64   `gorm:"type:decimal(20,2)" json:"vol"`                                                               // 成交量（手）
-	Amount    float64   `gorm:"type:decimal(20,2)" json:"amount"`                                                            // 成交额（千元）
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_daily_code_date_unique,priority:1;not null" json:"ts_code"`                  // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_daily_code_date_unique,priority:2;index:idx_trade_date;not null" json:"trade_date"` // 交易日期
+	Open      *float64  `gorm:"type:decimal(10,2)" json:"open"`                                                                              // 开盘价
+	High      *float64  `gorm:"type:decimal(10,2)" json:"high"`                                                                              // 最高价
+	Low       *float64  `gorm:"type:decimal(10,2)" json:"low"`                                                                               // 最低价
+	Close     *float64  `gorm:"type:decimal(10,2)" json:"close"`                                                                             // 收盘价
+	PreClose  *float64  `gorm:"type:decimal(10,2)" json:"pre_close"`                                                                         // 昨收价
+	Change    *float64  `gorm:"type:decimal(10,2)" json:"change"`                                                                            // 涨跌额
+	PctChg    *float64  `gorm:"type:decimal(10,4)" json:"pct_chg"`                                                                           // 涨跌幅
+	Vol       *float64  `gorm:"type:decimal(20,2)" json:"vol"`                                                                               // 成交量（手）
+	Amount    *float64  `gorm:"type:decimal(20,2)" json:"amount"`                                                                            // 成交额（千元）
+	Amplitude *float64  `gorm:"type:decimal(10,4)" json:"amplitude"`                                                                         // 振幅（%），(最高价-最低价)/昨收价×100，开启 fetcher.compute_amplitude 时写入
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt time.Time `gorm:"index:idx_daily_updated_at" json:"updated_at"` // 增量同步按该字段查询
 }
 
 // TableName 指定表名
 func (StockDaily) TableName() string {
-	return "stock_daily"
+	return tableName("stock_daily")
+}
+
+// StockLatest 每只股票最新一条日线的快照，随日线写入更新，避免查询最新行情时对 stock_daily 分组取最大日期
+type StockLatest struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"ts_code"` // 股票代码
+	TradeDate time.Time `gorm:"type:date;index;not null" json:"trade_date"`           // 最新交易日期
+	Open      *float64  `gorm:"type:decimal(10,2)" json:"open"`                       // 开盘价
+	High      *float64  `gorm:"type:decimal(10,2)" json:"high"`                       // 最高价
+	Low       *float64  `gorm:"type:decimal(10,2)" json:"low"`                        // 最低价
+	Close     *float64  `gorm:"type:decimal(10,2)" json:"close"`                      // 收盘价
+	PreClose  *float64  `gorm:"type:decimal(10,2)" json:"pre_close"`                  // 昨收价
+	Change    *float64  `gorm:"type:decimal(10,2)" json:"change"`                     // 涨跌额
+	PctChg    *float64  `gorm:"type:decimal(10,4)" json:"pct_chg"`                    // 涨跌幅
+	Vol       *float64  `gorm:"type:decimal(20,2)" json:"vol"`                        // 成交量（手）
+	Amount    *float64  `gorm:"type:decimal(20,2)" json:"amount"`                     // 成交额（千元）
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockLatest) TableName() string {
+	return tableName("stock_latest")
 }
 
 // StockBasic 股票基本信息
@@ -44,65 +99,519 @@ type StockBasic struct {
 
 // TableName 指定表名
 func (StockBasic) TableName() string {
-	return "stock_basic"
+	return tableName("stock_basic")
+}
+
+// StockCompany 上市公司基本信息
+type StockCompany struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TSCode        string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"ts_code"` // 股票代码
+	Exchange      string    `gorm:"type:varchar(10)" json:"exchange"`                     // 交易所代码
+	Chairman      string    `gorm:"type:varchar(50)" json:"chairman"`                     // 法人代表
+	Manager       string    `gorm:"type:varchar(50)" json:"manager"`                      // 总经理
+	Secretary     string    `gorm:"type:varchar(50)" json:"secretary"`                    // 董秘
+	RegCapital    float64   `gorm:"type:decimal(20,4)" json:"reg_capital"`                // 注册资本（万元）
+	SetupDate     string    `gorm:"type:varchar(8)" json:"setup_date"`                    // 注册日期
+	Province      string    `gorm:"type:varchar(20)" json:"province"`                     // 所在省份
+	City          string    `gorm:"type:varchar(20)" json:"city"`                         // 所在城市
+	Website       string    `gorm:"type:varchar(200)" json:"website"`                     // 公司主页
+	Email         string    `gorm:"type:varchar(100)" json:"email"`                       // 电子邮件
+	Employees     int       `gorm:"type:int" json:"employees"`                            // 员工人数
+	MainBusiness  string    `gorm:"type:text" json:"main_business"`                       // 主要业务及产品
+	BusinessScope string    `gorm:"type:text" json:"business_scope"`                      // 经营范围
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockCompany) TableName() string {
+	return tableName("stock_company")
+}
+
+// IndexBasic 指数基本信息
+type IndexBasic struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TSCode     string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"ts_code"` // 指数代码
+	Name       string    `gorm:"type:varchar(100)" json:"name"`                        // 简称
+	FullName   string    `gorm:"type:varchar(200)" json:"fullname"`                    // 全称
+	Market     string    `gorm:"type:varchar(20);index" json:"market"`                 // 市场
+	Publisher  string    `gorm:"type:varchar(50)" json:"publisher"`                    // 发布方
+	IndexType  string    `gorm:"type:varchar(50)" json:"index_type"`                   // 指数风格
+	Category   string    `gorm:"type:varchar(50);index" json:"category"`               // 指数类别
+	BaseDate   string    `gorm:"type:varchar(8)" json:"base_date"`                     // 基期
+	BasePoint  float64   `gorm:"type:decimal(20,4)" json:"base_point"`                 // 基点
+	ListDate   string    `gorm:"type:varchar(8)" json:"list_date"`                     // 发布日期
+	WeightRule string    `gorm:"type:varchar(100)" json:"weight_rule"`                 // 加权方式
+	Desc       string    `gorm:"column:description;type:text" json:"desc"`             // 描述（desc 为 SQL 关键字）
+	ExpDate    string    `gorm:"type:varchar(8)" json:"exp_date"`                      // 终止日期
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (IndexBasic) TableName() string {
+	return tableName("index_basic")
+}
+
+// 沪深股通类型
+const (
+	HSTypeSH = "SH" // 沪股通
+	HSTypeSZ = "SZ" // 深股通
+)
+
+// HSConst 沪深股通成分，同一股票可能多次调入调出，每次调入为一条记录
+type HSConst struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TSCode    string     `gorm:"type:varchar(20);uniqueIndex:idx_hs_const,priority:1;not null" json:"ts_code"` // 股票代码
+	HSType    string     `gorm:"type:varchar(2);uniqueIndex:idx_hs_const,priority:2;not null" json:"hs_type"`  // 类型：SH 沪股通、SZ 深股通
+	InDate    time.Time  `gorm:"type:date;uniqueIndex:idx_hs_const,priority:3;not null" json:"in_date"`        // 纳入日期
+	OutDate   *time.Time `gorm:"type:date;index" json:"out_date"`                                              // 剔除日期，仍在名单中时为空
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (HSConst) TableName() string {
+	return tableName("hs_const")
+}
+
+// TopListEntry 龙虎榜每日明细
+// 同一股票同一天可能因不同理由多次上榜
+type TopListEntry struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TradeDate    time.Time `gorm:"type:date;uniqueIndex:idx_top_list,priority:1;not null" json:"trade_date"`     // 交易日期
+	TSCode       string    `gorm:"type:varchar(20);uniqueIndex:idx_top_list,priority:2;not null" json:"ts_code"` // 股票代码
+	Reason       string    `gorm:"type:varchar(200);uniqueIndex:idx_top_list,priority:3;not null" json:"reason"` // 上榜理由
+	Name         string    `gorm:"type:varchar(50)" json:"name"`                                                 // 名称
+	Close        float64   `gorm:"type:decimal(10,2)" json:"close"`                                              // 收盘价
+	PctChange    float64   `gorm:"type:decimal(10,4)" json:"pct_change"`                                         // 涨跌幅
+	TurnoverRate float64   `gorm:"type:decimal(10,4)" json:"turnover_rate"`                                      // 换手率
+	Amount       float64   `gorm:"type:decimal(20,2)" json:"amount"`                                             // 总成交额
+	LSell        float64   `gorm:"type:decimal(20,2)" json:"l_sell"`                                             // 龙虎榜卖出额
+	LBuy         float64   `gorm:"type:decimal(20,2)" json:"l_buy"`                                              // 龙虎榜买入额
+	LAmount      float64   `gorm:"type:decimal(20,2)" json:"l_amount"`                                           // 龙虎榜成交额
+	NetAmount    float64   `gorm:"type:decimal(20,2)" json:"net_amount"`                                         // 龙虎榜净买入额
+	NetRate      float64   `gorm:"type:decimal(10,4)" json:"net_rate"`                                           // 龙虎榜净买额占比
+	AmountRate   float64   `gorm:"type:decimal(10,4)" json:"amount_rate"`                                        // 龙虎榜成交额占比
+	FloatValues  float64   `gorm:"type:decimal(20,2)" json:"float_values"`                                       // 当日流通市值
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TopListEntry) TableName() string {
+	return tableName("top_list")
+}
+
+// BlockTrade 大宗交易明细
+// 同一股票同一天可能有多笔价格、成交量相同的交易，没有唯一键，按交易日整体替换
+type BlockTrade struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TradeDate time.Time `gorm:"type:date;index:idx_block_trade_date_code,priority:1;not null" json:"trade_date"`     // 交易日期
+	TSCode    string    `gorm:"type:varchar(20);index:idx_block_trade_date_code,priority:2;not null" json:"ts_code"` // 股票代码
+	Price     *float64  `gorm:"type:decimal(10,2)" json:"price"`                                                     // 成交价（元）
+	Vol       *float64  `gorm:"type:decimal(20,2)" json:"vol"`                                                       // 成交量（万股）
+	Amount    *float64  `gorm:"type:decimal(20,2)" json:"amount"`                                                    // 成交金额（万元）
+	Buyer     string    `gorm:"type:varchar(200)" json:"buyer"`                                                      // 买方营业部
+	Seller    string    `gorm:"type:varchar(200)" json:"seller"`                                                     // 卖方营业部
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (BlockTrade) TableName() string {
+	return tableName("block_trade")
+}
+
+// MarginDetail 融资融券交易明细
+type MarginDetail struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_margin_code_date,priority:1;not null" json:"ts_code"`   // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_margin_code_date,priority:2;index;not null" json:"trade_date"` // 交易日期
+	Rzye      float64   `gorm:"type:decimal(20,2)" json:"rzye"`                                                         // 融资余额（元）
+	Rqye      float64   `gorm:"type:decimal(20,2)" json:"rqye"`                                                         // 融券余额（元）
+	Rzmre     float64   `gorm:"type:decimal(20,2)" json:"rzmre"`                                                        // 融资买入额（元）
+	Rqyl      float64   `gorm:"type:decimal(20,2)" json:"rqyl"`                                                         // 融券余量（股）
+	Rzche     float64   `gorm:"type:decimal(20,2)" json:"rzche"`                                                        // 融资偿还额（元）
+	Rqchl     float64   `gorm:"type:decimal(20,2)" json:"rqchl"`                                                        // 融券偿还量（股）
+	Rqmcl     float64   `gorm:"type:decimal(20,2)" json:"rqmcl"`                                                        // 融券卖出量（股）
+	Rzrqye    float64   `gorm:"type:decimal(20,2)" json:"rzrqye"`                                                       // 融资融券余额（元）
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (MarginDetail) TableName() string {
+	return tableName("margin_detail")
+}
+
+// StockAdjFactor 复权因子
+type StockAdjFactor struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_adj_code_date,priority:1;not null" json:"ts_code"`   // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_adj_code_date,priority:2;index;not null" json:"trade_date"` // 交易日期
+	AdjFactor *float64  `gorm:"type:decimal(20,4)" json:"adj_factor"`                                                // 复权因子
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockAdjFactor) TableName() string {
+	return tableName("stock_adj_factor")
+}
+
+// StockDailyAdj Tushare pro_bar 返回的复权日线，同一股票、日期按复权方式分别存储
+type StockDailyAdj struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_daily_adj_code_date,priority:1;not null" json:"ts_code"`   // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_daily_adj_code_date,priority:2;index;not null" json:"trade_date"` // 交易日期
+	Adj       string    `gorm:"type:varchar(3);uniqueIndex:idx_daily_adj_code_date,priority:3;not null" json:"adj"`        // 复权方式 qfq前复权 hfq后复权，空为不复权
+	Open      *float64  `gorm:"type:decimal(20,4)" json:"open"`                                                            // 开盘价
+	High      *float64  `gorm:"type:decimal(20,4)" json:"high"`                                                            // 最高价
+	Low       *float64  `gorm:"type:decimal(20,4)" json:"low"`                                                             // 最低价
+	Close     *float64  `gorm:"type:decimal(20,4)" json:"close"`                                                           // 收盘价
+	PreClose  *float64  `gorm:"type:decimal(20,4)" json:"pre_close"`                                                       // 昨收价
+	Change    *float64  `gorm:"type:decimal(20,4)" json:"change"`                                                          // 涨跌额
+	PctChg    *float64  `gorm:"type:decimal(10,4)" json:"pct_chg"`                                                         // 涨跌幅
+	Vol       *float64  `gorm:"type:decimal(20,2)" json:"vol"`                                                             // 成交量（手）
+	Amount    *float64  `gorm:"type:decimal(20,2)" json:"amount"`                                                          // 成交额（千元）
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockDailyAdj) TableName() string {
+	return tableName("stock_daily_adj")
+}
+
+// 基金交易市场
+const (
+	FundMarketExchange = "E" // 场内
+	FundMarketOTC      = "O" // 场外
+)
+
+// FundBasic 基金基本信息
+type FundBasic struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TSCode      string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"ts_code"` // 基金代码
+	Name        string    `gorm:"type:varchar(100)" json:"name"`                        // 简称
+	Management  string    `gorm:"type:varchar(100);index" json:"management"`            // 管理人
+	Custodian   string    `gorm:"type:varchar(100)" json:"custodian"`                   // 托管人
+	FundType    string    `gorm:"type:varchar(50);index" json:"fund_type"`              // 投资类型
+	FoundDate   string    `gorm:"type:varchar(8)" json:"found_date"`                    // 成立日期
+	DueDate     string    `gorm:"type:varchar(8)" json:"due_date"`                      // 到期日期
+	ListDate    string    `gorm:"type:varchar(8)" json:"list_date"`                     // 上市时间
+	IssueDate   string    `gorm:"type:varchar(8)" json:"issue_date"`                    // 发行日期
+	DelistDate  string    `gorm:"type:varchar(8)" json:"delist_date"`                   // 退市日期
+	IssueAmount *float64  `gorm:"type:decimal(20,4)" json:"issue_amount"`               // 发行份额（亿）
+	MFee        *float64  `gorm:"type:decimal(10,4)" json:"m_fee"`                      // 管理费
+	CFee        *float64  `gorm:"type:decimal(10,4)" json:"c_fee"`                      // 托管费
+	Benchmark   string    `gorm:"type:text" json:"benchmark"`                           // 业绩比较基准
+	Status      string    `gorm:"type:varchar(1);index" json:"status"`                  // 存续状态 D摘牌 I发行 L已上市
+	InvestType  string    `gorm:"type:varchar(100)" json:"invest_type"`                 // 投资风格
+	Type        string    `gorm:"type:varchar(50)" json:"type"`                         // 基金类型
+	Market      string    `gorm:"type:varchar(1);index" json:"market"`                  // E场内 O场外
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FundBasic) TableName() string {
+	return tableName("fund_basic")
+}
+
+// FundDaily 场内基金日线行情
+type FundDaily struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_fund_daily_code_date,priority:1;not null" json:"ts_code"`   // 基金代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_fund_daily_code_date,priority:2;index;not null" json:"trade_date"` // 交易日期
+	Open      *float64  `gorm:"type:decimal(20,4)" json:"open"`                                                             // 开盘价
+	High      *float64  `gorm:"type:decimal(20,4)" json:"high"`                                                             // 最高价
+	Low       *float64  `gorm:"type:decimal(20,4)" json:"low"`                                                              // 最低价
+	Close     *float64  `gorm:"type:decimal(20,4)" json:"close"`                                                            // 收盘价
+	PreClose  *float64  `gorm:"type:decimal(20,4)" json:"pre_close"`                                                        // 昨收价
+	Change    *float64  `gorm:"type:decimal(20,4)" json:"change"`                                                           // 涨跌额
+	PctChg    *float64  `gorm:"type:decimal(10,4)" json:"pct_chg"`                                                          // 涨跌幅
+	Vol       *float64  `gorm:"type:decimal(20,2)" json:"vol"`                                                              // 成交量（手）
+	Amount    *float64  `gorm:"type:decimal(20,3)" json:"amount"`                                                           // 成交额（千元）
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FundDaily) TableName() string {
+	return tableName("fund_daily")
+}
+
+// TradeCalendar 交易日历，包含休市日，每个交易所一条
+type TradeCalendar struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Exchange     string    `gorm:"type:varchar(10);uniqueIndex:idx_cal_exchange_date,priority:1;not null" json:"exchange"`      // 交易所 SSE/SZSE
+	CalDate      string    `gorm:"type:varchar(8);uniqueIndex:idx_cal_exchange_date,priority:2;index;not null" json:"cal_date"` // 日历日期 YYYYMMDD
+	IsOpen       int       `gorm:"type:smallint" json:"is_open"`                                                                // 是否交易 0休市 1交易
+	PreTradeDate string    `gorm:"column:pretrade_date;type:varchar(8)" json:"pretrade_date"`                                   // 上一个交易日
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TradeCalendar) TableName() string {
+	return tableName("trade_calendar")
 }
 
 // FetchTask 抓取任务记录
 type FetchTask struct {
-	ID           uint       `gorm:"primaryKey" json:"id"`
-	TaskID       string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"task_id"` // 任务ID
-	StartDate    string     `gorm:"type:varchar(8)" json:"start_date"`                    // 开始日期
-	EndDate      string     `gorm:"type:varchar(8)" json:"end_date"`                      // 结束日期
-	Status       string     `gorm:"type:varchar(20)" json:"status"`                       // 状态：pending/running/completed/failed
-	Progress     int        `gorm:"type:int" json:"progress"`                             // 进度（0-100）
-	TotalCount   int        `gorm:"type:int" json:"total_count"`                          // 总数
-	SuccessCount int        `gorm:"type:int" json:"success_count"`                        // 成功数
-	FailedCount  int        `gorm:"type:int" json:"failed_count"`                         // 失败数
-	ErrorMsg     string     `gorm:"type:text" json:"error_msg"`                           // 错误信息
-	StartTime    time.Time  `json:"start_time"`
-	EndTime      *time.Time `json:"end_time"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	TaskID           string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"task_id"` // 任务ID
+	StartDate        string     `gorm:"type:varchar(8)" json:"start_date"`                    // 开始日期
+	EndDate          string     `gorm:"type:varchar(8)" json:"end_date"`                      // 结束日期
+	Status           TaskStatus `gorm:"type:varchar(20)" json:"status"`                       // 状态：pending/running/completed/failed/cancelled/interrupted/timeout
+	Progress         int        `gorm:"type:int" json:"progress"`                             // 进度（0-100）
+	TotalCount       int        `gorm:"type:int" json:"total_count"`                          // 总数
+	SuccessCount     int        `gorm:"type:int" json:"success_count"`                        // 成功数
+	FailedCount      int        `gorm:"type:int" json:"failed_count"`                         // 失败数
+	ErrorMsg         string     `gorm:"type:text" json:"error_msg"`                           // 错误信息
+	RowsFetched      int64      `gorm:"type:bigint" json:"rows_fetched"`                      // Tushare 返回的行数
+	RowsStored       int64      `gorm:"type:bigint" json:"rows_stored"`                       // 成功入库的行数
+	RowMetrics       RowMetrics `gorm:"type:text" json:"row_metrics"`                         // 按接口统计的返回/入库行数
+	APICalls         int64      `gorm:"type:bigint" json:"api_calls"`                         // 已发起的 Tushare 请求数，失败的请求同样计入
+	StartTime        time.Time  `json:"start_time"`
+	EndTime          *time.Time `json:"end_time"`
+	EstimatedEndTime *time.Time `json:"estimated_end_time"`                                                                      // 预计完成时间：开始时按限流和并发估算，执行中按实际吞吐更新，总数未知时为空
+	IdempotencyKey   *string    `gorm:"type:varchar(100);uniqueIndex:idx_task_idempotency_key" json:"idempotency_key,omitempty"` // 请求头 Idempotency-Key，过期后置空以便复用
+	IdempotencyHash  string     `gorm:"type:varchar(64)" json:"-"`                                                               // 创建任务的请求参数的 SHA-256，相同的键用于参数不同的请求时返回 422
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	EffectiveConcurrency    int    `gorm:"-" json:"effective_concurrency,omitempty"`     // 当前有效并发数，数据库变慢时低于配置的并发数，仅运行中的任务返回
+	EstimatedRemainingCalls *int64 `gorm:"-" json:"estimated_remaining_calls,omitempty"` // 预计还需的 Tushare 请求数，按已完成部分的平均请求数估算，仅运行中的任务返回
 }
 
 // TableName 指定表名
 func (FetchTask) TableName() string {
-	return "fetch_tasks"
+	return tableName("fetch_tasks")
+}
+
+// RowCount 单个接口返回与入库的行数
+type RowCount struct {
+	Fetched int64 `json:"fetched"` // Tushare 返回的行数
+	Stored  int64 `json:"stored"`  // 成功入库的行数，解析失败被丢弃或保存失败的行不计入
+}
+
+// RowMetrics 按 Tushare 接口名统计的行数，以 JSON 文本存储
+type RowMetrics map[string]RowCount
+
+// Value 实现 driver.Valuer
+func (m RowMetrics) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (m *RowMetrics) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("不支持的行数统计类型: %T", value)
+	}
+	if len(data) == 0 {
+		*m = nil
+		return nil
+	}
+	return json.Unmarshal(data, m)
+}
+
+// TaskStatus 任务状态
+type TaskStatus string
+
+const (
+	TaskStatusPending     TaskStatus = "pending"     // 等待中
+	TaskStatusRunning     TaskStatus = "running"     // 运行中
+	TaskStatusCompleted   TaskStatus = "completed"   // 已完成
+	TaskStatusFailed      TaskStatus = "failed"      // 失败
+	TaskStatusCancelled   TaskStatus = "cancelled"   // 已取消
+	TaskStatusInterrupted TaskStatus = "interrupted" // 被中断（如服务关闭）
+	TaskStatusTimeout     TaskStatus = "timeout"     // 超过 task_timeout 被取消
+)
+
+// taskTransitions 允许的任务状态变更
+// 失败或被中断的任务可以重新进入 running（断点续传），已完成和已取消为终态
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:     {TaskStatusRunning, TaskStatusCancelled, TaskStatusFailed},
+	TaskStatusRunning:     {TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled, TaskStatusInterrupted, TaskStatusTimeout},
+	TaskStatusFailed:      {TaskStatusRunning},
+	TaskStatusInterrupted: {TaskStatusRunning, TaskStatusCancelled},
+	TaskStatusTimeout:     {TaskStatusRunning},
+}
+
+// CanTransitionTo 判断是否允许从当前状态变更为 next
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	for _, allowed := range taskTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal 判断是否为终态
+func (s TaskStatus) IsTerminal() bool {
+	return s == TaskStatusCompleted || s == TaskStatusCancelled
+}
+
+// TransitionTo 校验并变更任务状态，非法变更时返回错误且不修改状态
+func (t *FetchTask) TransitionTo(next TaskStatus) error {
+	if !t.Status.CanTransitionTo(next) {
+		return fmt.Errorf("任务状态不允许从 %s 变更为 %s", t.Status, next)
+	}
+	t.Status = next
+	return nil
+}
+
+// 任务日期检查点状态
+const (
+	TaskDateCompleted = "completed"
+	TaskDateFailed    = "failed"
+)
+
+// FetchTaskDate 抓取任务的单日完成状态，用于日线断点续传和按周期汇总抓取结果
+type FetchTaskDate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    string    `gorm:"type:varchar(50);uniqueIndex:idx_task_date,priority:1;not null" json:"task_id"` // 任务ID
+	Date      string    `gorm:"type:varchar(8);uniqueIndex:idx_task_date,priority:2;not null" json:"date"`     // 日期
+	Status    string    `gorm:"type:varchar(20)" json:"status"`                                                // 状态：completed/failed
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FetchTaskDate) TableName() string {
+	return tableName("fetch_task_dates")
+}
+
+// FetchFailure 重试后仍失败的抓取单元（日期、股票或二者组合），保留最后一次错误供排查和重新抓取
+type FetchFailure struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    string    `gorm:"type:varchar(50);index:idx_failure_task_date,priority:1;not null" json:"task_id"` // 任务ID
+	Date      string    `gorm:"type:varchar(8);index:idx_failure_task_date,priority:2" json:"date"`              // 交易日期或报告期，按股票抓取整个区间时为空
+	TSCode    string    `gorm:"type:varchar(20);index" json:"ts_code"`                                           // 股票代码，按日期抓取全部股票时为空
+	Error     string    `gorm:"type:text" json:"error"`                                                          // 最后一次错误
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (FetchFailure) TableName() string {
+	return tableName("fetch_failures")
+}
+
+// TaskLog 任务执行期间的警告及错误日志，带 task_id 字段的日志由日志 core 写入
+type TaskLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    string    `gorm:"type:varchar(50);index;not null" json:"task_id"` // 任务ID
+	Level     string    `gorm:"type:varchar(10)" json:"level"`                  // 日志级别：warn、error
+	Message   string    `gorm:"type:text" json:"message"`                       // 日志内容
+	Fields    string    `gorm:"type:text" json:"fields,omitempty"`              // 其余日志字段（JSON），如 date、ts_code、error
+	CreatedAt time.Time `json:"created_at"`                                     // 日志时间
+}
+
+// TableName 指定表名
+func (TaskLog) TableName() string {
+	return tableName("task_logs")
+}
+
+// 作业队列状态
+const (
+	JobStatusQueued    = "queued"    // 排队中，重启后继续执行
+	JobStatusRunning   = "running"   // 执行中
+	JobStatusCompleted = "completed" // 已完成，抓取结果见关联的任务
+	JobStatusFailed    = "failed"    // 创建或执行任务失败
+)
+
+// RawResponse Tushare 原始响应，仅在开启 tushare.raw_responses 时保存，用于排查数据问题
+type RawResponse struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	APIName    string    `gorm:"type:varchar(50);index:idx_raw_response_api_params,priority:1;not null" json:"api_name"`    // 接口名
+	ParamsHash string    `gorm:"type:varchar(64);index:idx_raw_response_api_params,priority:2;not null" json:"params_hash"` // 请求参数 JSON 的 SHA-256
+	Params     string    `gorm:"type:text" json:"params"`                                                                   // 请求参数 JSON，Token 已脱敏
+	Fields     string    `gorm:"type:text" json:"fields"`                                                                   // 请求的字段
+	Code       int       `gorm:"type:int" json:"code"`                                                                      // Tushare 返回码
+	Msg        string    `gorm:"type:text" json:"msg"`                                                                      // Tushare 返回信息
+	Data       string    `json:"data"`                                                                                      // 原始 data 字段，不指定类型以便 MySQL 使用 longtext
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (RawResponse) TableName() string {
+	return tableName("raw_responses")
+}
+
+// FetchJob 排队等待后台执行的抓取作业，开始执行后关联到对应的抓取任务
+type FetchJob struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	JobID          string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"job_id"`            // 作业ID
+	Type           string     `gorm:"type:varchar(20);not null" json:"type"`                          // 作业类型：daily/weekly/monthly
+	Params         string     `gorm:"type:text" json:"params"`                                        // 抓取参数（JSON）
+	Status         string     `gorm:"type:varchar(20);index" json:"status"`                           // 状态：queued/running/completed/failed
+	TaskID         string     `gorm:"type:varchar(50)" json:"task_id"`                                // 关联的抓取任务ID，日线作业入队时创建，其他作业开始执行后才有值
+	IdempotencyKey *string    `gorm:"type:varchar(100);uniqueIndex" json:"idempotency_key,omitempty"` // 请求头 Idempotency-Key，过期后置空以便复用
+	ErrorMsg       string     `gorm:"type:text" json:"error_msg"`                                     // 错误信息
+	StartedAt      *time.Time `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FetchJob) TableName() string {
+	return tableName("fetch_jobs")
 }
 
 // StockWeekly 股票周线数据（复权）
 type StockWeekly struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	TSCode    string    `gorm:"type:varchar(20);index:idx_weekly_ts_code_date,priority:1;not null" json:"ts_code"`                         // 股票代码
-	TradeDate time.Time `gorm:"type:date;index:idx_weekly_ts_code_date,priority:2;index:idx_weekly_trade_date;not null" json:"trade_date"` // 交易日期（周五或月末）
-	EndDate   time.Time `gorm:"type:date" json:"end_date"`                                                                                 // 计算截至日期
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_weekly_code_date_unique,priority:1;not null" json:"ts_code"`                         // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_weekly_code_date_unique,priority:2;index:idx_weekly_trade_date;not null" json:"trade_date"` // 交易日期（周五或月末）
+	EndDate   time.Time `gorm:"type:date" json:"end_date"`                                                                                           // 计算截至日期
 
 	// 未复权价格
-	Open     float64 `gorm:"type:decimal(10,2)" json:"open"`      // 周开盘价
-	High     float64 `gorm:"type:decimal(10,2)" json:"high"`      // 周最高价
-	Low      float64 `gorm:"type:decimal(10,2)" json:"low"`       // 周最低价
-	Close    float64 `gorm:"type:decimal(10,2)" json:"close"`     // 周收盘价
-	PreClose float64 `gorm:"type:decimal(10,2)" json:"pre_close"` // 上周收盘价（除权价，前复权）
+	Open     *float64 `gorm:"type:decimal(10,2)" json:"open"`      // 周开盘价
+	High     *float64 `gorm:"type:decimal(10,2)" json:"high"`      // 周最高价
+	Low      *float64 `gorm:"type:decimal(10,2)" json:"low"`       // 周最低价
+	Close    *float64 `gorm:"type:decimal(10,2)" json:"close"`     // 周收盘价
+	PreClose *float64 `gorm:"type:decimal(10,2)" json:"pre_close"` // 上周收盘价（除权价，前复权）
 
 	// 前复权价格
-	OpenQfq  float64 `gorm:"type:decimal(10,2)" json:"open_qfq"`  // 前复权周开盘价
-	HighQfq  float64 `gorm:"type:decimal(10,2)" json:"high_qfq"`  // 前复权周最高价
-	LowQfq   float64 `gorm:"type:decimal(10,2)" json:"low_qfq"`   // 前复权周最低价
-	CloseQfq float64 `gorm:"type:decimal(10,2)" json:"close_qfq"` // 前复权周收盘价
+	OpenQfq  *float64 `gorm:"type:decimal(10,2)" json:"open_qfq"`  // 前复权周开盘价
+	HighQfq  *float64 `gorm:"type:decimal(10,2)" json:"high_qfq"`  // 前复权周最高价
+	LowQfq   *float64 `gorm:"type:decimal(10,2)" json:"low_qfq"`   // 前复权周最低价
+	CloseQfq *float64 `gorm:"type:decimal(10,2)" json:"close_qfq"` // 前复权周收盘价
 
 	// 后复权价格
-	OpenHfq  float64 `gorm:"type:decimal(10,2)" json:"open_hfq"`  // 后复权周开盘价
-	HighHfq  float64 `gorm:"type:decimal(10,2)" json:"high_hfq"`  // 后复权周最高价
-	LowHfq   float64 `gorm:"type:decimal(10,2)" json:"low_hfq"`   // 后复权周最低价
-	CloseHfq float64 `gorm:"type:decimal(10,2)" json:"close_hfq"` // 后复权周收盘价
+	OpenHfq  *float64 `gorm:"type:decimal(10,2)" json:"open_hfq"`  // 后复权周开盘价
+	HighHfq  *float64 `gorm:"type:decimal(10,2)" json:"high_hfq"`  // 后复权周最高价
+	LowHfq   *float64 `gorm:"type:decimal(10,2)" json:"low_hfq"`   // 后复权周最低价
+	CloseHfq *float64 `gorm:"type:decimal(10,2)" json:"close_hfq"` // 后复权周收盘价
 
 	// 成交数据
-	Vol    float64 `gorm:"type:decimal(20,2)" json:"vol"`    // 周成交量（手）
-	Amount float64 `gorm:"type:decimal(20,2)" json:"amount"` // 周成交额（千元）
+	Vol    *float64 `gorm:"type:decimal(20,2)" json:"vol"`    // 周成交量（手）
+	Amount *float64 `gorm:"type:decimal(20,2)" json:"amount"` // 周成交额（千元）
 
 	// 涨跌数据
-	Change float64 `gorm:"type:decimal(10,2)" json:"change"`  // 周涨跌额
-	PctChg float64 `gorm:"type:decimal(10,4)" json:"pct_chg"` // 周涨跌幅（基于除权后的昨收）
+	Change *float64 `gorm:"type:decimal(10,2)" json:"change"`  // 周涨跌额
+	PctChg *float64 `gorm:"type:decimal(10,4)" json:"pct_chg"` // 周涨跌幅（基于除权后的昨收）
 
 	CreatedAt time.Time `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"updated_at"`
@@ -110,42 +619,42 @@ type StockWeekly struct {
 
 // TableName 指定表名
 func (StockWeekly) TableName() string {
-	return "stock_weekly"
+	return tableName("stock_weekly")
 }
 
 // StockMonthly 股票月线数据
 type StockMonthly struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	TSCode    string    `gorm:"type:varchar(20);index:idx_monthly_ts_code_date,priority:1;not null" json:"ts_code"`                          // 股票代码
-	TradeDate time.Time `gorm:"type:date;index:idx_monthly_ts_code_date,priority:2;index:idx_monthly_trade_date;not null" json:"trade_date"` // 交易日期（月末最后一个交易日）
-	EndDate   time.Time `gorm:"type:date" json:"end_date"`                                                                                   // 计算截至日期
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_monthly_code_date_unique,priority:1;not null" json:"ts_code"`                          // 股票代码
+	TradeDate time.Time `gorm:"type:date;uniqueIndex:idx_monthly_code_date_unique,priority:2;index:idx_monthly_trade_date;not null" json:"trade_date"` // 交易日期（月末最后一个交易日）
+	EndDate   time.Time `gorm:"type:date" json:"end_date"`                                                                                             // 计算截至日期
 
 	// 未复权价格
-	Open     float64 `gorm:"type:decimal(10,2)" json:"open"`      // 月开盘价
-	High     float64 `gorm:"type:decimal(10,2)" json:"high"`      // 月最高价
-	Low      float64 `gorm:"type:decimal(10,2)" json:"low"`       // 月最低价
-	Close    float64 `gorm:"type:decimal(10,2)" json:"close"`     // 月收盘价
-	PreClose float64 `gorm:"type:decimal(10,2)" json:"pre_close"` // 上月收盘价（除权价，前复权）
+	Open     *float64 `gorm:"type:decimal(10,2)" json:"open"`      // 月开盘价
+	High     *float64 `gorm:"type:decimal(10,2)" json:"high"`      // 月最高价
+	Low      *float64 `gorm:"type:decimal(10,2)" json:"low"`       // 月最低价
+	Close    *float64 `gorm:"type:decimal(10,2)" json:"close"`     // 月收盘价
+	PreClose *float64 `gorm:"type:decimal(10,2)" json:"pre_close"` // 上月收盘价（除权价，前复权）
 
 	// 前复权价格
-	OpenQfq  float64 `gorm:"type:decimal(10,2)" json:"open_qfq"`  // 前复权月开盘价
-	HighQfq  float64 `gorm:"type:decimal(10,2)" json:"high_qfq"`  // 前复权月最高价
-	LowQfq   float64 `gorm:"type:decimal(10,2)" json:"low_qfq"`   // 前复权月最低价
-	CloseQfq float64 `gorm:"type:decimal(10,2)" json:"close_qfq"` // 前复权月收盘价
+	OpenQfq  *float64 `gorm:"type:decimal(10,2)" json:"open_qfq"`  // 前复权月开盘价
+	HighQfq  *float64 `gorm:"type:decimal(10,2)" json:"high_qfq"`  // 前复权月最高价
+	LowQfq   *float64 `gorm:"type:decimal(10,2)" json:"low_qfq"`   // 前复权月最低价
+	CloseQfq *float64 `gorm:"type:decimal(10,2)" json:"close_qfq"` // 前复权月收盘价
 
 	// 后复权价格
-	OpenHfq  float64 `gorm:"type:decimal(10,2)" json:"open_hfq"`  // 后复权月开盘价
-	HighHfq  float64 `gorm:"type:decimal(10,2)" json:"high_hfq"`  // 后复权月最高价
-	LowHfq   float64 `gorm:"type:decimal(10,2)" json:"low_hfq"`   // 后复权月最低价
-	CloseHfq float64 `gorm:"type:decimal(10,2)" json:"close_hfq"` // 后复权月收盘价
+	OpenHfq  *float64 `gorm:"type:decimal(10,2)" json:"open_hfq"`  // 后复权月开盘价
+	HighHfq  *float64 `gorm:"type:decimal(10,2)" json:"high_hfq"`  // 后复权月最高价
+	LowHfq   *float64 `gorm:"type:decimal(10,2)" json:"low_hfq"`   // 后复权月最低价
+	CloseHfq *float64 `gorm:"type:decimal(10,2)" json:"close_hfq"` // 后复权月收盘价
 
 	// 成交数据
-	Vol    float64 `gorm:"type:decimal(20,2)" json:"vol"`    // 月成交量（手）
-	Amount float64 `gorm:"type:decimal(20,2)" json:"amount"` // 月成交额（千元）
+	Vol    *float64 `gorm:"type:decimal(20,2)" json:"vol"`    // 月成交量（手）
+	Amount *float64 `gorm:"type:decimal(20,2)" json:"amount"` // 月成交额（千元）
 
 	// 涨跌数据
-	Change float64 `gorm:"type:decimal(10,2)" json:"change"`  // 月涨跌额
-	PctChg float64 `gorm:"type:decimal(10,4)" json:"pct_chg"` // 月涨跌幅（基于除权后的昨收）
+	Change *float64 `gorm:"type:decimal(10,2)" json:"change"`  // 月涨跌额
+	PctChg *float64 `gorm:"type:decimal(10,4)" json:"pct_chg"` // 月涨跌幅（基于除权后的昨收）
 
 	CreatedAt time.Time `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"updated_at"`
@@ -153,5 +662,77 @@ type StockMonthly struct {
 
 // TableName 指定表名
 func (StockMonthly) TableName() string {
-	return "stock_monthly"
+	return tableName("stock_monthly")
+}
+
+// FinaIndicator 财务指标数据
+type FinaIndicator struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	TSCode            string    `gorm:"type:varchar(20);uniqueIndex:idx_fina_ts_code_end_date,priority:1;not null" json:"ts_code"` // 股票代码
+	EndDate           time.Time `gorm:"type:date;uniqueIndex:idx_fina_ts_code_end_date,priority:2;not null" json:"end_date"`       // 报告期
+	AnnDate           string    `gorm:"type:varchar(8)" json:"ann_date"`                                                           // 公告日期
+	EPS               float64   `gorm:"type:decimal(20,4)" json:"eps"`                                                             // 基本每股收益
+	DtEPS             float64   `gorm:"type:decimal(20,4)" json:"dt_eps"`                                                          // 稀释每股收益
+	BPS               float64   `gorm:"type:decimal(20,4)" json:"bps"`                                                             // 每股净资产
+	OCFPS             float64   `gorm:"type:decimal(20,4)" json:"ocfps"`                                                           // 每股经营活动产生的现金流量净额
+	ROE               float64   `gorm:"type:decimal(20,4)" json:"roe"`                                                             // 净资产收益率
+	ROEDt             float64   `gorm:"type:decimal(20,4)" json:"roe_dt"`                                                          // 净资产收益率（扣除非经常损益）
+	ROA               float64   `gorm:"type:decimal(20,4)" json:"roa"`                                                             // 总资产报酬率
+	GrossprofitMargin float64   `gorm:"type:decimal(20,4)" json:"grossprofit_margin"`                                              // 销售毛利率
+	NetprofitMargin   float64   `gorm:"type:decimal(20,4)" json:"netprofit_margin"`                                                // 销售净利率
+	DebtToAssets      float64   `gorm:"type:decimal(20,4)" json:"debt_to_assets"`                                                  // 资产负债率
+	CurrentRatio      float64   `gorm:"type:decimal(20,4)" json:"current_ratio"`                                                   // 流动比率
+	QuickRatio        float64   `gorm:"type:decimal(20,4)" json:"quick_ratio"`                                                     // 速动比率
+	OrYoy             float64   `gorm:"type:decimal(20,4)" json:"or_yoy"`                                                          // 营业收入同比增长率
+	NetprofitYoy      float64   `gorm:"type:decimal(20,4)" json:"netprofit_yoy"`                                                   // 归母净利润同比增长率
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FinaIndicator) TableName() string {
+	return tableName("fina_indicator")
+}
+
+// 股票分类类型
+const (
+	ClassifyTypeConcept  = "concept"  // 概念
+	ClassifyTypeIndustry = "industry" // 申万行业
+)
+
+// StockConcept 股票与概念/行业分类的对应关系
+type StockConcept struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_concept_ts_code_type_code,priority:1;not null" json:"ts_code"`    // 股票代码
+	Type      string    `gorm:"type:varchar(10);uniqueIndex:idx_concept_ts_code_type_code,priority:2;not null" json:"type"`       // 分类类型：concept/industry
+	Code      string    `gorm:"type:varchar(20);uniqueIndex:idx_concept_ts_code_type_code,priority:3;index;not null" json:"code"` // 概念代码或行业指数代码
+	Name      string    `gorm:"type:varchar(50)" json:"name"`                                                                     // 概念或行业名称
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockConcept) TableName() string {
+	return tableName("stock_concept")
+}
+
+// StockMinute 股票分钟线数据
+type StockMinute struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TSCode    string    `gorm:"type:varchar(20);uniqueIndex:idx_minute_ts_code_freq_time,priority:1;not null" json:"ts_code"`  // 股票代码
+	Freq      string    `gorm:"type:varchar(10);uniqueIndex:idx_minute_ts_code_freq_time,priority:2;not null" json:"freq"`     // 分钟频度
+	TradeTime time.Time `gorm:"type:timestamp;uniqueIndex:idx_minute_ts_code_freq_time,priority:3;not null" json:"trade_time"` // 交易时间
+	Open      float64   `gorm:"type:decimal(10,2)" json:"open"`                                                                // 开盘价
+	High      float64   `gorm:"type:decimal(10,2)" json:"high"`                                                                // 最高价
+	Low       float64   `gorm:"type:decimal(10,2)" json:"low"`                                                                 // 最低价
+	Close     float64   `gorm:"type:decimal(10,2)" json:"close"`                                                               // 收盘价
+	Vol       float64   `gorm:"type:decimal(20,2)" json:"vol"`                                                                 // 成交量（股）
+	Amount    float64   `gorm:"type:decimal(20,2)" json:"amount"`                                                              // 成交额（元）
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockMinute) TableName() string {
+	return tableName("stock_minute")
 }
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 交易日历不可用时生成日期列表的降级策略（fetcher.calendar_fallback）
+const (
+	CalendarFallbackWeekendFilter = "weekend_filter" // 仅过滤周末，节假日会多抓
+	CalendarFallbackFail          = "fail"           // 不生成日期列表，任务直接失败
+	CalendarFallbackCached        = "cached"         // 使用已存储的交易日历（trade_calendar）
+)
+
+// ErrTradeCalendarUnavailable 无法获取交易日历，且降级策略不能生成日期列表
+var ErrTradeCalendarUnavailable = errors.New("交易日历不可用")
+
+// generateDateRangeFallback 从 Tushare 获取交易日历失败（cause）时按 calendar_fallback 生成日期列表
+// 未配置时已存储交易日历则使用 cached，否则使用 fail；cached 要求已存储的日历覆盖整个日期范围
+func (f *DataFetcher) generateDateRangeFallback(startDate, endDate string, cause error) ([]string, error) {
+	strategy := f.config.CalendarFallback
+	if strategy == "" {
+		strategy = CalendarFallbackFail
+		var count int64
+		if err := f.db.Model(&models.TradeCalendar{}).Limit(1).Count(&count).Error; err == nil && count > 0 {
+			strategy = CalendarFallbackCached
+		}
+	}
+
+	f.logger.Warn("获取交易日历失败，使用降级策略生成日期列表",
+		zap.String("strategy", strategy),
+		zap.String("start_date", startDate),
+		zap.String("end_date", endDate),
+		zap.Error(cause))
+
+	switch strategy {
+	case CalendarFallbackWeekendFilter:
+		return weekdayDateRange(startDate, endDate), nil
+	case CalendarFallbackCached:
+		dates, err := f.storedTradeDates(startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v，且%v", ErrTradeCalendarUnavailable, cause, err)
+		}
+		return dates, nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrTradeCalendarUnavailable, cause)
+	}
+}
+
+// storedTradeDates 从已存储的交易日历获取日期范围内的交易日，任一交易所开市即为交易日
+// 已存储的日历须包含范围内的每一天，否则缺失的日期可能是交易日，返回错误
+func (f *DataFetcher) storedTradeDates(startDate, endDate string) ([]string, error) {
+	start, err := ParseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("开始日期格式错误: %w", err)
+	}
+	end, err := ParseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("结束日期格式错误: %w", err)
+	}
+
+	var days int64
+	if err := f.db.Model(&models.TradeCalendar{}).
+		Where("cal_date >= ? AND cal_date <= ?", startDate, endDate).
+		Distinct("cal_date").
+		Count(&days).Error; err != nil {
+		return nil, fmt.Errorf("查询已存储的交易日历失败: %w", err)
+	}
+	if expected := int64(end.Sub(start).Hours()/24+0.5) + 1; days < expected {
+		return nil, fmt.Errorf("已存储的交易日历未覆盖 %s 至 %s（%d 天中有 %d 天）", startDate, endDate, expected, days)
+	}
+
+	var dates []string
+	if err := f.db.Model(&models.TradeCalendar{}).
+		Where("cal_date >= ? AND cal_date <= ? AND is_open = 1", startDate, endDate).
+		Distinct("cal_date").
+		Order("cal_date").
+		Pluck("cal_date", &dates).Error; err != nil {
+		return nil, fmt.Errorf("查询已存储的交易日历失败: %w", err)
+	}
+	return dates, nil
+}
+
+// weekdayDateRange 生成日期范围内的工作日（仅过滤周末）
+func weekdayDateRange(startDate, endDate string) []string {
+	start, _ := ParseDate(startDate)
+	end, _ := ParseDate(endDate)
+
+	var dates []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			dates = append(dates, d.Format("20060102"))
+		}
+	}
+	return dates
+}
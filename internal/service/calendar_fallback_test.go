@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestGenerateDateRangeFallback 测试交易日历不可用时按 calendar_fallback 降级：
+// 未配置时没有已存储的日历则失败、有则使用已存储的日历，weekend_filter 仅过滤周末
+func TestGenerateDateRangeFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.TradeCalendar{}, &models.FetchTask{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+	_, err := fetcher.generateDateRange(context.Background(), "20231229", "20240102")
+	assert.ErrorIs(t, err, ErrTradeCalendarUnavailable)
+
+	fetcher.config.CalendarFallback = CalendarFallbackWeekendFilter
+	dates, err := fetcher.generateDateRange(context.Background(), "20231229", "20240102")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229", "20240101", "20240102"}, dates)
+
+	// 任务直接失败，失败原因写入 error_msg
+	fetcher.config.CalendarFallback = CalendarFallbackFail
+	task, err := fetcher.FetchTopList(context.Background(), "20231229", "20240102")
+	assert.ErrorIs(t, err, ErrTradeCalendarUnavailable)
+	require.NotNil(t, task)
+	var stored models.FetchTask
+	require.NoError(t, fetcher.db.Where("task_id = ?", task.TaskID).First(&stored).Error)
+	assert.Equal(t, models.TaskStatusFailed, stored.Status)
+	assert.Contains(t, stored.ErrorMsg, "交易日历不可用")
+
+	// 已存储交易日历时默认使用 cached，深交所单独开市的日期同样是交易日
+	records := []models.TradeCalendar{
+		{Exchange: "SSE", CalDate: "20231229", IsOpen: 1, PreTradeDate: "20231228"},
+		{Exchange: "SSE", CalDate: "20231230", IsOpen: 0, PreTradeDate: "20231229"},
+		{Exchange: "SSE", CalDate: "20231231", IsOpen: 0, PreTradeDate: "20231229"},
+		{Exchange: "SSE", CalDate: "20240101", IsOpen: 0, PreTradeDate: "20231229"},
+		{Exchange: "SSE", CalDate: "20240102", IsOpen: 1, PreTradeDate: "20231229"},
+		{Exchange: "SSE", CalDate: "20240103", IsOpen: 1, PreTradeDate: "20240102"},
+		{Exchange: "SZSE", CalDate: "20231230", IsOpen: 1},
+	}
+	require.NoError(t, fetcher.db.Create(&records).Error)
+	fetcher.config.CalendarFallback = ""
+	dates, err = fetcher.generateDateRange(context.Background(), "20231229", "20240102")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229", "20231230", "20240102"}, dates)
+
+	// 已存储的日历未覆盖整个范围
+	_, err = fetcher.generateDateRange(context.Background(), "20231228", "20240102")
+	assert.ErrorIs(t, err, ErrTradeCalendarUnavailable)
+	assert.ErrorContains(t, err, "未覆盖")
+
+	fetcher.config.CalendarFallback = CalendarFallbackFail
+	_, err = fetcher.generateDateRange(context.Background(), "20231229", "20240102")
+	assert.ErrorIs(t, err, ErrTradeCalendarUnavailable)
+}
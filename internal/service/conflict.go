@@ -0,0 +1,40 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// upsertClause 生成写入冲突时更新已有记录的子句，updateColumns 为空时更新除主键、创建时间外的全部列
+// conflictColumns 为判断冲突的唯一索引列（如 ts_code、trade_date），见 conflictTarget
+func upsertClause(db *gorm.DB, conflictColumns []string, updateColumns ...string) clause.OnConflict {
+	onConflict := clause.OnConflict{Columns: conflictTarget(db, conflictColumns)}
+	if len(updateColumns) == 0 {
+		onConflict.UpdateAll = true
+	} else {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	}
+	return onConflict
+}
+
+// skipClause 生成写入冲突时保留已有记录的子句
+// MySQL 没有 DO NOTHING，驱动生成 ON DUPLICATE KEY UPDATE id=id，同样不修改已有记录
+func skipClause(db *gorm.DB, conflictColumns []string) clause.OnConflict {
+	return clause.OnConflict{Columns: conflictTarget(db, conflictColumns), DoNothing: true}
+}
+
+// conflictTarget 按数据库方言生成冲突目标
+// PostgreSQL、SQLite 的 ON CONFLICT (列) 要求这些列恰好对应一个唯一索引，否则写入报错；
+// MySQL 的 ON DUPLICATE KEY UPDATE 不能指定冲突目标，任一唯一索引冲突都会触发，返回 nil，
+// 因此 MySQL 下表中除主键外只应有一个唯一索引
+// 日线、周线、月线的 (ts_code, trade_date) 唯一索引在已有表上由服务启动时补建，见 database.migrateUniqueIndexes
+func conflictTarget(db *gorm.DB, columns []string) []clause.Column {
+	if db.Dialector.Name() == "mysql" {
+		return nil
+	}
+	target := make([]clause.Column, 0, len(columns))
+	for _, name := range columns {
+		target = append(target, clause.Column{Name: name})
+	}
+	return target
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunSQL 使用指定方言生成写入一条日线的 SQL，不连接数据库
+func dryRunSQL(t *testing.T, dialector gorm.Dialector, build func(db *gorm.DB) clause.OnConflict) string {
+	t.Helper()
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	result := db.Clauses(build(db)).Create(&models.StockDaily{TSCode: "000001.SZ", TradeDate: time.Now()})
+	require.NoError(t, result.Error)
+	return result.Statement.SQL.String()
+}
+
+// TestConflictClause_Dialects 测试 PostgreSQL 按唯一索引列指定冲突目标，MySQL 生成不带目标的 ON DUPLICATE KEY UPDATE
+func TestConflictClause_Dialects(t *testing.T) {
+	columns := []string{"ts_code", "trade_date"}
+	pg := postgres.New(postgres.Config{DSN: "host=localhost user=test dbname=test"})
+	my := mysql.New(mysql.Config{DSN: "test:test@tcp(localhost:3306)/test", SkipInitializeWithVersion: true})
+
+	sql := dryRunSQL(t, pg, func(db *gorm.DB) clause.OnConflict { return upsertClause(db, columns) })
+	assert.Contains(t, sql, `ON CONFLICT ("ts_code","trade_date") DO UPDATE SET`)
+	assert.Contains(t, sql, `"close"="excluded"."close"`)
+
+	sql = dryRunSQL(t, pg, func(db *gorm.DB) clause.OnConflict { return skipClause(db, columns) })
+	assert.Contains(t, sql, `ON CONFLICT ("ts_code","trade_date") DO NOTHING`)
+
+	sql = dryRunSQL(t, my, func(db *gorm.DB) clause.OnConflict { return upsertClause(db, columns, "close", "updated_at") })
+	assert.Contains(t, sql, "ON DUPLICATE KEY UPDATE `close`=VALUES(`close`),`updated_at`=VALUES(`updated_at`)")
+	assert.NotContains(t, sql, "ON CONFLICT")
+
+	sql = dryRunSQL(t, my, func(db *gorm.DB) clause.OnConflict { return skipClause(db, columns) })
+	assert.Contains(t, sql, "ON DUPLICATE KEY UPDATE `id`=`id`")
+}
+
+// TestConflictClause_Idempotent 测试重复写入相同的股票和日期不产生重复记录：upsert 更新已有记录，skip 保留已有记录
+func TestConflictClause_Idempotent(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{})
+	db := fetcher.db
+	tradeDate, err := ParseDate("20231201")
+	require.NoError(t, err)
+	row := func(close float64) []models.StockDaily {
+		return []models.StockDaily{{TSCode: "000001.SZ", TradeDate: tradeDate, Close: &close}}
+	}
+	columns := []string{"ts_code", "trade_date"}
+
+	require.NoError(t, db.Clauses(upsertClause(db, columns)).Create(row(10)).Error)
+	require.NoError(t, db.Clauses(upsertClause(db, columns)).Create(row(11)).Error)
+	require.NoError(t, db.Clauses(skipClause(db, columns)).Create(row(12)).Error)
+	require.NoError(t, db.Clauses(upsertClause(db, columns, "close")).Create(row(13)).Error)
+	require.NoError(t, db.Clauses(skipClause(db, columns)).Create(row(14)).Error)
+
+	var stored []models.StockDaily
+	require.NoError(t, db.Find(&stored).Error)
+	require.Len(t, stored, 1)
+	assert.Equal(t, 13.0, *stored[0].Close)
+}
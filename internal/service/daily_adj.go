@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FetchDailyAdj 通过 Tushare pro_bar 按股票抓取复权日线并保存到 stock_daily_adj
+// 每只股票一次请求整个日期范围，价格由 Tushare 按 adj（qfq/hfq，为空不复权）计算，无需另行抓取复权因子；
+// tsCodes 为空时抓取全部股票
+func (f *DataFetcher) FetchDailyAdj(ctx context.Context, startDate, endDate string, tsCodes []string, adj string) (*models.FetchTask, error) {
+	if !IsValidAdj(adj) {
+		return nil, fmt.Errorf("不支持的复权方式: %s", adj)
+	}
+
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "daily_adj_task_",
+		name:      "复权日线",
+		unit:      unitTSCode,
+		startDate: startDate,
+		endDate:   endDate,
+		units: func(ctx context.Context) ([]string, error) {
+			stocks, err := f.loadStocks(ctx, tsCodes...)
+			if err != nil {
+				return nil, err
+			}
+			codes := make([]string, 0, len(stocks))
+			for _, stock := range stocks {
+				codes = append(codes, stock.TSCode)
+			}
+			return codes, nil
+		},
+		fetch: func(ctx context.Context, tsCode string, rows *rowTracker) (int, error) {
+			bars, err := f.tushareClient.GetProBar(ctx, tsCode, startDate, endDate, adj)
+			if err != nil {
+				return 0, err
+			}
+			// 停牌或未上市时无数据也算成功
+			stored, err := f.batchUpsertDailyAdj(bars, adj)
+			rows.record("pro_bar", len(bars), stored)
+			return len(bars), err
+		},
+	})
+}
+
+// batchUpsertDailyAdj 按 insert_mode 批量保存复权日线
+func (f *DataFetcher) batchUpsertDailyAdj(bars []StockDailyData, adj string) (int, error) {
+	records := make([]models.StockDailyAdj, 0, len(bars))
+	for _, bar := range bars {
+		tradeDate, err := ParseDate(bar.TradeDate)
+		if err != nil {
+			f.logger.Warn("复权日线交易日期格式错误", zap.String("trade_date", bar.TradeDate))
+			continue
+		}
+
+		records = append(records, models.StockDailyAdj{
+			TSCode:    bar.TSCode,
+			TradeDate: tradeDate,
+			Adj:       adj,
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Close:     bar.Close,
+			PreClose:  bar.PreClose,
+			Change:    bar.Change,
+			PctChg:    bar.PctChg,
+			Vol:       bar.Vol,
+			Amount:    bar.Amount,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code", "trade_date", "adj"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.TradeDate)
+		}
+		return codeDateScope(tx.Where("adj = ?", adj), "trade_date", tsCodes, dates)
+	})
+}
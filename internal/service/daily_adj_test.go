@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFetchDailyAdj 测试按股票调用 pro_bar 并按复权方式分别保存
+func TestFetchDailyAdj(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "pro_bar", req.APIName)
+		assert.Equal(t, "20231201", req.Params["start_date"])
+
+		// 后复权价格为前复权的 2 倍，便于区分
+		scale := 1.0
+		if req.Params["adj"] == "hfq" {
+			scale = 2
+		}
+		tsCode := req.Params["ts_code"]
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount"},
+			Items: [][]interface{}{
+				{tsCode, "20231204", 10.1 * scale, 10.6 * scale, 10.0 * scale, 10.5 * scale, 10.2 * scale, 0.3 * scale, 2.9412, 1000.0, 10500.0},
+				{tsCode, "20231201", 10.0 * scale, 10.3 * scale, 9.9 * scale, 10.2 * scale, 10.0 * scale, 0.2 * scale, 2.0, 900.0, 9180.0},
+			},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockBasic{}, &models.StockDailyAdj{}, &models.FetchTask{})
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	require.NoError(t, fetcher.db.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", Name: "平安银行", ListStatus: "L"},
+		{TSCode: "600000.SH", Name: "浦发银行", ListStatus: "L"},
+	}).Error)
+
+	task, err := fetcher.FetchDailyAdj(context.Background(), "20231201", "20231204", nil, AdjQfq)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+	assert.Equal(t, 2, task.SuccessCount)
+	assert.Equal(t, int64(4), task.RowsStored)
+
+	// 只抓取指定股票的后复权数据，与前复权数据分别保存；重复抓取更新已有记录
+	for i := 0; i < 2; i++ {
+		_, err = fetcher.FetchDailyAdj(context.Background(), "20231201", "20231204", []string{"000001.SZ"}, AdjHfq)
+		require.NoError(t, err)
+	}
+
+	date, err := ParseDate("20231204")
+	require.NoError(t, err)
+	var bars []models.StockDailyAdj
+	require.NoError(t, fetcher.db.Where("ts_code = ? AND trade_date = ?", "000001.SZ", date).Order("adj desc").Find(&bars).Error)
+	require.Len(t, bars, 2)
+	assert.Equal(t, AdjQfq, bars[0].Adj)
+	assert.InDelta(t, 10.5, *bars[0].Close, 1e-9)
+	assert.Equal(t, AdjHfq, bars[1].Adj)
+	assert.InDelta(t, 21.0, *bars[1].Close, 1e-9)
+
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.StockDailyAdj{}).Count(&count).Error)
+	assert.Equal(t, int64(6), count)
+
+	_, err = fetcher.FetchDailyAdj(context.Background(), "20231201", "20231204", nil, "none")
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// TSCodePattern 股票代码格式，如 000001.SZ
+var TSCodePattern = regexp.MustCompile(`^[0-9]{6}\.(SZ|SH|BJ)$`)
+
+// ErrInvalidCSVHeader CSV 表头缺少必需列、包含未知列或重复列
+var ErrInvalidCSVHeader = errors.New("CSV 表头错误")
+
+// MaxImportErrors 导入结果中最多返回的错误行数，超出的只计数
+const MaxImportErrors = 100
+
+// dailyImportValues 日线 CSV 中可选的数值列，列名与导出的 CSV 及 Tushare 字段一致
+var dailyImportValues = map[string]func(d *StockDailyData) **float64{
+	"open":      func(d *StockDailyData) **float64 { return &d.Open },
+	"high":      func(d *StockDailyData) **float64 { return &d.High },
+	"low":       func(d *StockDailyData) **float64 { return &d.Low },
+	"close":     func(d *StockDailyData) **float64 { return &d.Close },
+	"pre_close": func(d *StockDailyData) **float64 { return &d.PreClose },
+	"change":    func(d *StockDailyData) **float64 { return &d.Change },
+	"pct_chg":   func(d *StockDailyData) **float64 { return &d.PctChg },
+	"vol":       func(d *StockDailyData) **float64 { return &d.Vol },
+	"amount":    func(d *StockDailyData) **float64 { return &d.Amount },
+}
+
+// ImportRowError 导入时解析失败的行
+type ImportRowError struct {
+	Line  int    `json:"line"` // 行号，表头为第 1 行
+	Error string `json:"error"`
+}
+
+// DailyImportResult 日线 CSV 导入结果
+type DailyImportResult struct {
+	Rows     int              `json:"rows"`     // 数据行数，不含表头
+	Imported int              `json:"imported"` // 入库行数，skip 模式下已存在的行、写入失败被跳过的行不计入
+	Failed   int              `json:"failed"`   // 解析失败被跳过的行数
+	Errors   []ImportRowError `json:"errors"`   // 解析失败的行，最多 MaxImportErrors 条
+}
+
+// addError 记录一个解析失败的行
+func (r *DailyImportResult) addError(line int, format string, args ...interface{}) {
+	r.Failed++
+	if len(r.Errors) < MaxImportErrors {
+		r.Errors = append(r.Errors, ImportRowError{Line: line, Error: fmt.Sprintf(format, args...)})
+	}
+}
+
+// ImportDailyCSV 从 CSV 导入日线数据，逐行解析，每满 batch_size 行按 insert_mode 写入一次，不会将整个文件读入内存
+// 表头必须包含 ts_code、trade_date，其余列为 open、high、low、close、pre_close、change、pct_chg、vol、amount 中的任意几列，顺序不限；
+// 解析失败的行跳过并记录行号，写入数据库失败时停止导入，返回已导入部分的结果和错误
+func (f *DataFetcher) ImportDailyCSV(r io.Reader) (*DailyImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: 文件为空", ErrInvalidCSVHeader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+	columns, err := parseDailyImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	reader.FieldsPerRecord = len(header)
+
+	result := &DailyImportResult{Errors: []ImportRowError{}}
+	batch := make([]StockDailyData, 0, f.config.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		stored, err := f.batchInsertDailyData(batch)
+		result.Imported += stored
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.Is(err, csv.ErrFieldCount) && errors.As(err, &parseErr) {
+			// 列数不符只影响该行；其他格式错误（如引号不匹配）无法确定后续行的边界，停止导入
+			result.Rows++
+			result.addError(parseErr.StartLine, "列数为 %d，应为 %d", len(record), len(header))
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("读取 CSV 失败: %w", err)
+		}
+		result.Rows++
+		line, _ := reader.FieldPos(0)
+
+		data, err := parseDailyImportRecord(record, columns)
+		if err != nil {
+			result.addError(line, "%v", err)
+			continue
+		}
+		batch = append(batch, data)
+		if len(batch) >= f.config.BatchSize {
+			if err := flush(); err != nil {
+				return result, fmt.Errorf("写入日线数据失败: %w", err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, fmt.Errorf("写入日线数据失败: %w", err)
+	}
+
+	f.logger.Info("日线 CSV 导入完成",
+		zap.Int("rows", result.Rows),
+		zap.Int("imported", result.Imported),
+		zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+// parseDailyImportHeader 校验表头，返回各列的列名
+func parseDailyImportHeader(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Excel 导出的 UTF-8 BOM
+		}
+		if _, ok := dailyImportValues[name]; !ok && name != "ts_code" && name != "trade_date" {
+			return nil, fmt.Errorf("%w: 未知的列 %q", ErrInvalidCSVHeader, header[i])
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: 重复的列 %q", ErrInvalidCSVHeader, header[i])
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	for _, required := range []string{"ts_code", "trade_date"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("%w: 缺少 %s 列", ErrInvalidCSVHeader, required)
+		}
+	}
+	return columns, nil
+}
+
+// parseDailyImportRecord 将一行 CSV 解析为日线数据，数值列为空表示 null
+func parseDailyImportRecord(record, columns []string) (StockDailyData, error) {
+	var data StockDailyData
+	for i, name := range columns {
+		value := strings.TrimSpace(record[i])
+		switch name {
+		case "ts_code":
+			if !TSCodePattern.MatchString(value) {
+				return data, fmt.Errorf("股票代码格式错误: %q", value)
+			}
+			data.TSCode = value
+		case "trade_date":
+			if _, err := ParseDate(value); err != nil {
+				return data, fmt.Errorf("trade_date 格式错误，应为 YYYYMMDD: %q", value)
+			}
+			data.TradeDate = value
+		default:
+			if value == "" {
+				continue
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+				return data, fmt.Errorf("%s 不是有效的数值: %q", name, value)
+			}
+			*dailyImportValues[name](&data) = &number
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportDailyCSV 测试按批写入、跳过解析失败的行并返回行号
+func TestImportDailyCSV(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	fetcher.config.BatchSize = 2
+
+	csv := "\ufeffts_code,trade_date,close,vol\n" +
+		"000001.SZ,20231201,10.5,1000\n" +
+		"000001.SZ,2023-12-04,10.6,1000\n" +
+		"000002.SZ,20231201,,\n" +
+		"000002.SZ,20231204,abc,1000\n" +
+		"600000.SH,20231201,7.1\n" +
+		"600000.SH,20231204,7.2,2000\n"
+	result, err := fetcher.ImportDailyCSV(strings.NewReader(csv))
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Rows)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 3, result.Failed)
+	require.Len(t, result.Errors, 3)
+	assert.Equal(t, []int{3, 5, 6}, []int{result.Errors[0].Line, result.Errors[1].Line, result.Errors[2].Line})
+	assert.Contains(t, result.Errors[1].Error, "close")
+
+	var rows []models.StockDaily
+	require.NoError(t, fetcher.db.Order("ts_code").Find(&rows).Error)
+	require.Len(t, rows, 3)
+	assert.Nil(t, rows[1].Close)
+	require.NotNil(t, rows[2].Vol)
+	assert.Equal(t, 2000.0, *rows[2].Vol)
+
+	for _, header := range []string{"", "ts_code,close\n", "ts_code,trade_date,price\n", "ts_code,trade_date,close,CLOSE\n"} {
+		_, err := fetcher.ImportDailyCSV(strings.NewReader(header))
+		assert.ErrorIs(t, err, ErrInvalidCSVHeader, header)
+	}
+}
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"sort"
 	"stock_data/internal/config"
 	"stock_data/internal/database"
 	"stock_data/internal/models"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,7 +25,13 @@ type DataFetcher struct {
 	db            *gorm.DB
 	config        *config.FetcherConfig
 	logger        *zap.Logger
-	rateLimiter   *time.Ticker
+	rateLimiter   *RateLimiter
+	runningTasks  sync.Map      // 当前进程中正在执行的续传任务ID
+	taskSlots     chan struct{} // 全局任务名额，限制同时运行的抓取任务数
+	listeners     []ProgressListener
+	latestMu      sync.Mutex // 串行化 stock_latest 快照更新
+	throttle      *InsertThrottle
+	progress      *progressUpdater // 限制任务进度写库的频率
 }
 
 // NewDataFetcher 创建数据抓取服务
@@ -32,15 +41,69 @@ func NewDataFetcher(tushareClient *TushareClient, cfg *config.FetcherConfig, log
 		db:            database.GetDB(),
 		config:        cfg,
 		logger:        logger,
-		rateLimiter:   time.NewTicker(time.Minute / time.Duration(cfg.RateLimit)),
+		rateLimiter:   NewRateLimiter(cfg.RateLimit),
+		taskSlots:     make(chan struct{}, cfg.MaxConcurrentTasks),
+		throttle:      NewInsertThrottle(cfg.Concurrency, time.Duration(cfg.SlowInsertMs)*time.Millisecond),
+		progress:      newProgressUpdater(time.Duration(cfg.ProgressIntervalMs) * time.Millisecond),
 	}
 }
 
+// TryAcquireTask 尝试占用一个任务名额，名额已满时返回 false
+func (f *DataFetcher) TryAcquireTask() bool {
+	select {
+	case f.taskSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// AcquireTask 等待并占用一个任务名额，ctx 取消时返回错误
+func (f *DataFetcher) AcquireTask(ctx context.Context) error {
+	select {
+	case f.taskSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReleaseTask 释放任务名额
+func (f *DataFetcher) ReleaseTask() {
+	<-f.taskSlots
+}
+
+// TaskSlots 返回当前占用的任务名额数和上限
+func (f *DataFetcher) TaskSlots() (active, limit int) {
+	return len(f.taskSlots), cap(f.taskSlots)
+}
+
+// DefaultDateRange 返回配置的默认抓取日期范围，未配置结束日期时取 database.timezone 时区的当天
+func (f *DataFetcher) DefaultDateRange() (startDate, endDate string) {
+	endDate = f.config.EndDate
+	if endDate == "" {
+		endDate = time.Now().In(marketLocation).Format("20060102")
+	}
+	return f.config.StartDate, endDate
+}
+
+// CheckToken 校验 Tushare Token 是否可用
+func (f *DataFetcher) CheckToken(ctx context.Context) error {
+	if err := f.waitTushare(ctx); err != nil {
+		return err
+	}
+	return f.tushareClient.CheckToken(ctx)
+}
+
 // FetchStockBasic 抓取股票基本信息
-func (f *DataFetcher) FetchStockBasic() error {
+func (f *DataFetcher) FetchStockBasic(ctx context.Context) error {
 	f.logger.Info("开始抓取股票基本信息")
 
-	stocks, err := f.tushareClient.GetStockBasic()
+	if err := f.waitTushare(ctx); err != nil {
+		return err
+	}
+
+	stocks, err := f.tushareClient.GetStockBasic(ctx, NewStockBasicQuery(f.config))
 	if err != nil {
 		return fmt.Errorf("获取股票基本信息失败: %w", err)
 	}
@@ -56,19 +119,163 @@ func (f *DataFetcher) FetchStockBasic() error {
 	return nil
 }
 
+// FetchAllStockBasic 按上市状态分别抓取股票列表后合并保存，返回各状态的股票数
+// 已存储的股票按 ts_code 更新，状态变化（如 L→D）会同步到 list_status，便于回测时包含退市股票
+func (f *DataFetcher) FetchAllStockBasic(ctx context.Context, statuses []string) (map[string]int, error) {
+	if len(statuses) == 0 {
+		statuses = f.config.StockListStatuses
+	}
+	f.logger.Info("开始抓取全部状态的股票基本信息", zap.Strings("list_status", statuses))
+
+	counts := make(map[string]int, len(statuses))
+	merged := make(map[string]StockBasicData)
+	order := make([]string, 0)
+	for _, status := range statuses {
+		if err := f.waitTushare(ctx); err != nil {
+			return nil, err
+		}
+		stocks, err := f.tushareClient.GetStockBasic(ctx, StockBasicQuery{ListStatus: status, Market: f.config.StockMarket})
+		if err != nil {
+			return nil, fmt.Errorf("获取上市状态为 %s 的股票失败: %w", status, err)
+		}
+		counts[status] = len(stocks)
+		for _, stock := range stocks {
+			if _, ok := merged[stock.TSCode]; !ok {
+				order = append(order, stock.TSCode)
+			}
+			merged[stock.TSCode] = stock
+		}
+	}
+
+	all := make([]StockBasicData, 0, len(order))
+	for _, tsCode := range order {
+		all = append(all, merged[tsCode])
+	}
+	if err := f.batchInsertStockBasic(all); err != nil {
+		return nil, fmt.Errorf("保存股票基本信息失败: %w", err)
+	}
+
+	f.logger.Info("全部状态的股票基本信息抓取完成", zap.Int("total", len(all)), zap.Any("counts", counts))
+	return counts, nil
+}
+
+// FetchIndexBasic 抓取指数基本信息，market 为空时抓取全部市场
+func (f *DataFetcher) FetchIndexBasic(ctx context.Context, market string) (int, error) {
+	f.logger.Info("开始抓取指数基本信息", zap.String("market", market))
+
+	if err := f.waitTushare(ctx); err != nil {
+		return 0, err
+	}
+
+	indices, err := f.tushareClient.GetIndexBasic(ctx, market)
+	if err != nil {
+		return 0, fmt.Errorf("获取指数基本信息失败: %w", err)
+	}
+
+	stored, err := f.batchUpsertIndexBasic(indices)
+	if err != nil {
+		return 0, fmt.Errorf("保存指数基本信息失败: %w", err)
+	}
+
+	f.logger.Info("指数基本信息抓取完成", zap.Int("total", stored))
+	return stored, nil
+}
+
+// batchUpsertIndexBasic 按 insert_mode 批量保存指数基本信息（冲突键为 ts_code）
+func (f *DataFetcher) batchUpsertIndexBasic(indices []IndexBasicData) (int, error) {
+	if len(indices) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.IndexBasic, 0, len(indices))
+	for _, data := range indices {
+		records = append(records, models.IndexBasic{
+			TSCode:     data.TSCode,
+			Name:       data.Name,
+			FullName:   data.FullName,
+			Market:     data.Market,
+			Publisher:  data.Publisher,
+			IndexType:  data.IndexType,
+			Category:   data.Category,
+			BaseDate:   data.BaseDate,
+			BasePoint:  data.BasePoint,
+			ListDate:   data.ListDate,
+			WeightRule: data.WeightRule,
+			Desc:       data.Desc,
+			ExpDate:    data.ExpDate,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+		}
+		return tx.Where("ts_code IN ?", tsCodes)
+	})
+}
+
+// loadStocks 获取股票列表，开启自动刷新时会先确保 stock_basic 可用
+// 传入 tsCodes 时只返回指定股票
+func (f *DataFetcher) loadStocks(ctx context.Context, tsCodes ...string) ([]models.StockBasic, error) {
+	if f.config.AutoFetchStockBasic {
+		if err := f.ensureStockBasic(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	db := f.db
+	if len(tsCodes) > 0 {
+		db = db.Where("ts_code IN ?", tsCodes)
+	}
+
+	var stocks []models.StockBasic
+	if err := db.Find(&stocks).Error; err != nil {
+		return nil, fmt.Errorf("获取股票列表失败: %w", err)
+	}
+	return stocks, nil
+}
+
+// ensureStockBasic stock_basic 为空或超过 StockBasicMaxAge 天未更新时自动抓取股票基本信息
+func (f *DataFetcher) ensureStockBasic(ctx context.Context) error {
+	var row struct {
+		Count     int64
+		UpdatedAt sql.NullTime
+	}
+	if err := f.db.Model(&models.StockBasic{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS updated_at").
+		Scan(&row).Error; err != nil {
+		return fmt.Errorf("检查股票列表失败: %w", err)
+	}
+
+	switch {
+	case row.Count == 0:
+		f.logger.Info("股票列表为空，自动抓取股票基本信息")
+	case f.config.StockBasicMaxAge > 0 && row.UpdatedAt.Valid &&
+		time.Since(row.UpdatedAt.Time) > time.Duration(f.config.StockBasicMaxAge)*24*time.Hour:
+		f.logger.Info("股票列表已过期，自动刷新股票基本信息",
+			zap.Time("updated_at", row.UpdatedAt.Time),
+			zap.Int("max_age_days", f.config.StockBasicMaxAge))
+	default:
+		return nil
+	}
+
+	return f.FetchStockBasic(ctx)
+}
+
 // FetchDailyData 抓取日线数据
 func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
 	// 创建任务记录
 	task := &models.FetchTask{
-		TaskID:    fmt.Sprintf("task_%d", time.Now().Unix()),
+		TaskID:    newTaskID("task_"),
 		StartDate: startDate,
 		EndDate:   endDate,
-		Status:    "running",
+		Status:    models.TaskStatusRunning,
 		StartTime: time.Now(),
 	}
 
-	if err := f.db.Create(task).Error; err != nil {
-		return nil, fmt.Errorf("创建任务记录失败: %w", err)
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
 	}
 
 	f.logger.Info("开始抓取日线数据",
@@ -77,17 +284,22 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 		zap.String("end_date", endDate))
 
 	// 获取股票列表
-	var stocks []models.StockBasic
-	if err := f.db.Find(&stocks).Error; err != nil {
-		return nil, fmt.Errorf("获取股票列表失败: %w", err)
+	stocks, err := f.loadStocks(ctx)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
 	}
 
 	// 生成日期列表
-	dates := f.generateDateRange(startDate, endDate)
+	dates, err := f.generateDateRange(ctx, startDate, endDate)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
 
 	totalTasks := len(stocks) * len(dates)
 	task.TotalCount = totalTasks
-	f.db.Save(task)
+	f.saveTask(task)
 
 	f.logger.Info("任务规模",
 		zap.Int("stocks", len(stocks)),
@@ -96,6 +308,8 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 
 	// 并发抓取
 	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, f.config.Concurrency)
 
@@ -109,12 +323,17 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 				semaphore <- struct{}{}
 				defer func() { <-semaphore }()
 
-				<-f.rateLimiter.C
+				if err := f.waitTushare(ctx); err != nil {
+					atomic.AddInt64(&failedCount, 1)
+					return
+				}
 
 				// 抓取数据
-				if err := f.fetchAndSaveDailyData(tsCode, tradeDate); err != nil {
+				if err := f.fetchAndSaveDailyData(ctx, tsCode, tradeDate, rows); err != nil {
 					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, tradeDate, tsCode, err)
 					f.logger.Error("抓取失败",
+						zap.String("task_id", task.TaskID),
 						zap.String("ts_code", tsCode),
 						zap.String("trade_date", tradeDate),
 						zap.Error(err))
@@ -127,7 +346,7 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 				progress := int(total * 100 / int64(totalTasks))
 
 				if total%100 == 0 {
-					f.updateTaskProgress(task.ID, progress, int(successCount), int(failedCount))
+					f.updateTaskProgress(task, progress, int(successCount), int(failedCount), rows)
 					f.logger.Info("抓取进度",
 						zap.Int("progress", progress),
 						zap.Int64("success", successCount),
@@ -142,11 +361,12 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 	// 更新任务状态
 	now := time.Now()
 	task.EndTime = &now
-	task.Status = "completed"
+	f.transitionTask(task, models.TaskStatusCompleted)
 	task.Progress = 100
 	task.SuccessCount = int(successCount)
 	task.FailedCount = int(failedCount)
-	f.db.Save(task)
+	rows.applyTo(task)
+	f.saveTask(task)
 
 	f.logger.Info("日线数据抓取完成",
 		zap.String("task_id", task.TaskID),
@@ -157,106 +377,358 @@ func (f *DataFetcher) FetchDailyData(ctx context.Context, startDate, endDate str
 }
 
 // FetchDailyDataOptimized 优化版：按日期并发抓取
-func (f *DataFetcher) FetchDailyDataOptimized(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
-	// 创建任务记录
+// newestFirst 为 true 时从最近的交易日开始抓取，便于尽早使用最新数据
+func (f *DataFetcher) FetchDailyDataOptimized(ctx context.Context, startDate, endDate string, newestFirst bool) (*models.FetchTask, error) {
+	task, err := f.CreateDailyTask(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	f.RunDailyTask(ctx, task, newestFirst)
+	return task, nil
+}
+
+// CreateDailyTask 创建按日期抓取日线的任务记录，返回后即可用 task_id 查询进度
+// 任务登记为运行中，需随后调用 RunDailyTask 执行
+func (f *DataFetcher) CreateDailyTask(startDate, endDate string) (*models.FetchTask, error) {
+	task, err := f.createDailyTask(startDate, endDate, models.TaskStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与执行期间保持一致，避免任务创建后、开始执行前被续传
+	f.runningTasks.Store(task.TaskID, struct{}{})
+	return task, nil
+}
+
+// QueueDailyTask 为排队中的日线作业创建等待中的任务记录，入队时即可返回 task_id；
+// 作业开始执行时调用 StartDailyTask 再调用 RunDailyTask
+func (f *DataFetcher) QueueDailyTask(startDate, endDate string) (*models.FetchTask, error) {
+	return f.createDailyTask(startDate, endDate, models.TaskStatusPending)
+}
+
+// StartDailyTask 将 QueueDailyTask 创建的任务标记为运行中
+func (f *DataFetcher) StartDailyTask(task *models.FetchTask) error {
+	if _, loaded := f.runningTasks.LoadOrStore(task.TaskID, struct{}{}); loaded {
+		return fmt.Errorf("任务正在运行: %s", task.TaskID)
+	}
+	if err := task.TransitionTo(models.TaskStatusRunning); err != nil {
+		f.runningTasks.Delete(task.TaskID)
+		return err
+	}
+	task.StartTime = time.Now()
+	f.saveTask(task)
+	return nil
+}
+
+// newTaskID 生成带类型前缀的 task_id，使用纳秒时间戳，同一秒内创建的多个任务不会重复
+func newTaskID(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+}
+
+// createDailyTask 保存日线抓取任务记录
+func (f *DataFetcher) createDailyTask(startDate, endDate string, status models.TaskStatus) (*models.FetchTask, error) {
 	task := &models.FetchTask{
-		TaskID:    fmt.Sprintf("task_%d", time.Now().Unix()),
+		TaskID:    newTaskID("task_"),
 		StartDate: startDate,
 		EndDate:   endDate,
-		Status:    "running",
+		Status:    status,
 		StartTime: time.Now(),
 	}
 
 	if err := f.db.Create(task).Error; err != nil {
 		return nil, fmt.Errorf("创建任务记录失败: %w", err)
 	}
+	return task, nil
+}
+
+// RunDailyTask 执行 CreateDailyTask 创建的任务：生成交易日列表后并发抓取
+// newestFirst 为 true 时从最近的交易日开始抓取，便于尽早使用最新数据
+func (f *DataFetcher) RunDailyTask(ctx context.Context, task *models.FetchTask, newestFirst bool) {
+	defer f.runningTasks.Delete(task.TaskID)
 
 	// 生成日期列表
-	dates := f.generateDateRange(startDate, endDate)
+	dates, err := f.generateDateRange(ctx, task.StartDate, task.EndDate)
+	if err != nil {
+		f.failTask(task, err)
+		return
+	}
+	if newestFirst {
+		sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+	}
 	task.TotalCount = len(dates)
-	f.db.Save(task)
+	f.saveTask(task)
 
 	f.logger.Info("开始抓取日线数据（按日期）",
 		zap.String("task_id", task.TaskID),
-		zap.Int("total_dates", len(dates)))
+		zap.Int("total_dates", len(dates)),
+		zap.Bool("newest_first", newestFirst))
+
+	f.runDailyDates(ctx, task, dates, 0, false)
+}
+
+// FetchDailyResume 断点续传：继续执行中断的日线抓取任务，只抓取尚未完成的日期
+func (f *DataFetcher) FetchDailyResume(ctx context.Context, taskID string) (*models.FetchTask, error) {
+	task, err := f.GetTaskProgress(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在: %w", err)
+	}
+	if !strings.HasPrefix(task.TaskID, "task_") {
+		return nil, fmt.Errorf("只支持续传日线抓取任务: %s", taskID)
+	}
+
+	// 进程崩溃后任务状态会停留在 running，因此以内存中的运行记录判断是否正在执行
+	if _, loaded := f.runningTasks.LoadOrStore(task.TaskID, struct{}{}); loaded {
+		return nil, fmt.Errorf("任务正在运行: %s", taskID)
+	}
+	defer f.runningTasks.Delete(task.TaskID)
+
+	// 已完成的日期
+	var doneDates []string
+	if err := f.db.Model(&models.FetchTaskDate{}).
+		Where("task_id = ? AND status = ?", task.TaskID, models.TaskDateCompleted).
+		Pluck("date", &doneDates).Error; err != nil {
+		return nil, fmt.Errorf("获取任务检查点失败: %w", err)
+	}
+	done := make(map[string]bool, len(doneDates))
+	for _, date := range doneDates {
+		done[date] = true
+	}
+
+	dates, err := f.generateDateRange(ctx, task.StartDate, task.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make([]string, 0, len(dates))
+	for _, date := range dates {
+		if !done[date] {
+			remaining = append(remaining, date)
+		}
+	}
+
+	// 进程崩溃遗留的任务状态仍为 running，无需变更
+	if task.Status != models.TaskStatusRunning {
+		if err := task.TransitionTo(models.TaskStatusRunning); err != nil {
+			return nil, err
+		}
+	}
+	task.TotalCount = len(dates)
+	task.EndTime = nil
+	task.EstimatedEndTime = nil
+	f.saveTask(task)
+
+	f.logger.Info("继续抓取日线数据",
+		zap.String("task_id", task.TaskID),
+		zap.Int("total_dates", len(dates)),
+		zap.Int("done_dates", len(dates)-len(remaining)),
+		zap.Int("remaining_dates", len(remaining)))
+
+	f.clearFailures(task.TaskID)
+	f.runDailyDates(ctx, task, remaining, len(dates)-len(remaining), true)
+
+	return task, nil
+}
 
+// runDailyDates 并发抓取指定日期的日线数据，逐日记录检查点并在结束时更新任务状态
+// doneCount: 任务此前已完成的日期数（续传时计入成功数）
+// resume: 是否为续传，续传的日期可能在中断前已写入部分数据，保存时先删除该日期已有数据再写入，见 replaceDailyDate
+// 配置了 insert_workers 时抓取到的数据交给写入 worker 池保存，抓取 goroutine 不等待写入即可抓取下一个日期；
+// 每个日期的数据仍由同一个 worker 按原顺序写入，写入完成后才记录该日期的检查点
+func (f *DataFetcher) runDailyDates(ctx context.Context, task *models.FetchTask, dates []string, doneCount int, resume bool) {
 	// 使用 errgroup 并发抓取
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(f.config.Concurrency)
+	gate := f.throttle.gate()
 
-	var successCount, failedCount int64
+	var inserts *insertPool
+	if f.config.InsertWorkers > 0 {
+		inserts = newInsertPool(f.config.InsertWorkers)
+	}
+
+	successCount := int64(doneCount)
+	var failedCount int64
+	rows := newRowTracker(task.RowMetrics)
+	rows.apiCalls = task.APICalls // 续传任务在已有请求数上继续累计
+	ctx = withCallCounter(ctx, rows)
+
+	// finishDate 记录单个日期的检查点并更新进度
+	finishDate := func(date string, err error) {
+		if err != nil {
+			atomic.AddInt64(&failedCount, 1)
+			f.recordFailure(task.TaskID, date, "", err)
+			f.saveTaskDate(task.TaskID, date, models.TaskDateFailed)
+		} else {
+			atomic.AddInt64(&successCount, 1)
+			f.saveTaskDate(task.TaskID, date, models.TaskDateCompleted)
+		}
+
+		// 更新进度
+		total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+		progress := int(total * 100 / int64(task.TotalCount))
+		f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+	}
 
-	for i, date := range dates {
+	for _, date := range dates {
 		date := date
-		index := i
 
 		g.Go(func() error {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
+			// 数据库变慢时暂停派发
+			if err := gate.acquire(ctx); err != nil {
+				return err
 			}
+			defer gate.release()
 
-			<-f.rateLimiter.C
-
-			// 抓取该日期的所有数据
-			dailyData, err := f.tushareClient.GetDailyData(date, "")
-			if err != nil {
-				atomic.AddInt64(&failedCount, 1)
-				f.logger.Error("抓取日期数据失败",
-					zap.String("date", date),
-					zap.Error(err))
-				return nil // 不中断其他任务
+			// 限流
+			if err := f.waitTushare(ctx); err != nil {
+				return err
 			}
 
-			// 批量保存
-			if len(dailyData) > 0 {
-				if err := f.batchInsertDailyData(dailyData); err != nil {
-					atomic.AddInt64(&failedCount, 1)
-					f.logger.Error("保存日期数据失败",
-						zap.String("date", date),
-						zap.Error(err))
-				} else {
-					atomic.AddInt64(&successCount, 1)
-					f.logger.Info("日期数据保存成功",
-						zap.String("date", date),
-						zap.Int("count", len(dailyData)))
-				}
+			if inserts == nil {
+				finishDate(date, f.retryDate(ctx, task.TaskID, date, func() error {
+					return f.fetchAndSaveDailyDate(ctx, task.TaskID, date, rows, resume)
+				}))
+				return nil
 			}
 
-			// 更新进度
-			progress := (index + 1) * 100 / len(dates)
-			f.updateTaskProgress(task.ID, progress, int(successCount), int(failedCount))
-
-			return nil
+			var dailyData []StockDailyData
+			err := f.retryDate(ctx, task.TaskID, date, func() (err error) {
+				dailyData, err = f.fetchDailyDate(ctx, task.TaskID, date)
+				return err
+			})
+			if err != nil || len(dailyData) == 0 {
+				finishDate(date, err)
+				return nil
+			}
+			// 写入队列已满时等待，避免抓取速度远超写入速度时数据堆积在内存中
+			return inserts.submit(ctx, func() {
+				finishDate(date, f.saveDailyDate(task.TaskID, date, dailyData, rows, resume))
+			})
 		})
 	}
 
-	// 等待所有任务完成
-	if err := g.Wait(); err != nil {
-		f.logger.Error("抓取过程出错", zap.Error(err))
+	// 等待所有任务完成，已交给写入 worker 的数据全部写入后再更新任务状态
+	waitErr := g.Wait()
+	if inserts != nil {
+		inserts.close()
+	}
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
 	}
 
 	// 更新任务状态
 	now := time.Now()
 	task.EndTime = &now
-	task.Status = "completed"
-	task.Progress = 100
+	f.finishTask(task, waitErr)
 	task.SuccessCount = int(successCount)
 	task.FailedCount = int(failedCount)
-	f.db.Save(task)
+	rows.applyTo(task)
+	f.saveTask(task)
 
 	f.logger.Info("日线数据抓取完成",
 		zap.String("task_id", task.TaskID),
 		zap.Int64("success", successCount),
 		zap.Int64("failed", failedCount))
+}
 
-	return task, nil
+// retryDate 执行单个日期的抓取，失败时按 fetcher.date_retry 重新执行整个日期，返回最后一次的错误
+// 每次请求失败已由 TushareClient 按 tushare.retry 重试，这里是在其之上对整个日期的重试，重新执行前同样限流；
+// 重新请求不会改变结果的错误（见 retryableDateError）不重试
+func (f *DataFetcher) retryDate(ctx context.Context, taskID, date string, run func() error) error {
+	err := run()
+	for attempt := 1; err != nil && attempt <= f.config.DateRetry && retryableDateError(err); attempt++ {
+		f.logger.Warn("日期抓取失败，重新抓取该日期",
+			zap.String("task_id", taskID),
+			zap.String("date", date),
+			zap.Int("date_attempt", attempt),
+			zap.Int("date_retry", f.config.DateRetry),
+			zap.Error(err))
+		if waitErr := f.waitTushare(ctx); waitErr != nil {
+			return err
+		}
+		err = run()
+	}
+	return err
+}
+
+// retryableDateError 判断日期抓取失败后是否值得重新抓取：Tushare 返回的业务错误（如权限不足、参数错误）
+// 和不可重试的 HTTP 错误（如 401、404）重新请求结果相同，不重试
+func retryableDateError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return false
+	}
+	var httpErr *HTTPError
+	return !errors.As(err, &httpErr) || httpErr.Retryable()
+}
+
+// fetchAndSaveDailyDate 抓取并保存某个交易日的全部日线数据
+func (f *DataFetcher) fetchAndSaveDailyDate(ctx context.Context, taskID, date string, rows *rowTracker, replace bool) error {
+	dailyData, err := f.fetchDailyDate(ctx, taskID, date)
+	if err != nil || len(dailyData) == 0 {
+		return err
+	}
+	return f.saveDailyDate(taskID, date, dailyData, rows, replace)
+}
+
+// fetchDailyDate 抓取某个交易日的全部日线数据
+func (f *DataFetcher) fetchDailyDate(ctx context.Context, taskID, date string) ([]StockDailyData, error) {
+	dailyData, err := f.tushareClient.GetDailyData(ctx, date, "")
+	if err != nil {
+		f.logger.Error("抓取日期数据失败",
+			zap.String("task_id", taskID),
+			zap.String("date", date),
+			zap.Error(err))
+		return nil, err
+	}
+
+	// 无数据也算成功
+	if len(dailyData) == 0 {
+		f.logger.Debug("该日期无日线数据", zap.String("date", date))
+	}
+	return dailyData, nil
+}
+
+// saveDailyDate 保存某个交易日的日线数据并记录行数统计，replace 为 true 时先删除该日期已有的数据
+func (f *DataFetcher) saveDailyDate(taskID, date string, dailyData []StockDailyData, rows *rowTracker, replace bool) error {
+	var stored int
+	var err error
+	if replace {
+		_, stored, err = f.replaceDailyDate(f.db, date, dailyData)
+	} else {
+		stored, err = f.batchInsertDailyData(dailyData)
+	}
+	rows.record("daily", len(dailyData), stored)
+	if err != nil {
+		f.logger.Error("保存日期数据失败",
+			zap.String("task_id", taskID),
+			zap.String("date", date),
+			zap.Error(err))
+		return err
+	}
+
+	f.logger.Info("日期数据保存成功",
+		zap.String("date", date),
+		zap.Int("count", len(dailyData)))
+	return nil
+}
+
+// saveTaskDate 记录任务中单个日期的完成状态（断点续传检查点）
+func (f *DataFetcher) saveTaskDate(taskID, date, status string) {
+	err := f.db.Clauses(upsertClause(f.db, []string{"task_id", "date"}, "status", "updated_at")).Create(&models.FetchTaskDate{
+		TaskID: taskID,
+		Date:   date,
+		Status: status,
+	}).Error
+	if err != nil {
+		f.logger.Warn("保存任务检查点失败",
+			zap.String("task_id", taskID),
+			zap.String("date", date),
+			zap.Error(err))
+	}
 }
 
 // fetchAndSaveDailyData 抓取并保存单条日线数据
-func (f *DataFetcher) fetchAndSaveDailyData(tsCode, tradeDate string) error {
-	dailyData, err := f.tushareClient.GetDailyData(tradeDate, tsCode)
+func (f *DataFetcher) fetchAndSaveDailyData(ctx context.Context, tsCode, tradeDate string, rows *rowTracker) error {
+	dailyData, err := f.tushareClient.GetDailyData(ctx, tradeDate, tsCode)
 	if err != nil {
 		return err
 	}
@@ -265,7 +737,9 @@ func (f *DataFetcher) fetchAndSaveDailyData(tsCode, tradeDate string) error {
 		return nil
 	}
 
-	return f.batchInsertDailyData(dailyData)
+	stored, err := f.batchInsertDailyData(dailyData)
+	rows.record("daily", len(dailyData), stored)
+	return err
 }
 
 // batchInsertStockBasic 批量插入股票基本信息
@@ -294,8 +768,8 @@ func (f *DataFetcher) batchInsertStockBasic(stocks []StockBasicData) error {
 			})
 		}
 
-		// 使用 ON CONFLICT 处理重复数据（仅 PostgreSQL）
-		if err := f.db.CreateInBatches(records, batchSize).Error; err != nil {
+		// 已存在的股票按 ts_code 更新，便于定期刷新股票列表
+		if err := f.db.Clauses(upsertClause(f.db, []string{"ts_code"})).CreateInBatches(records, batchSize).Error; err != nil {
 			return err
 		}
 	}
@@ -303,9 +777,39 @@ func (f *DataFetcher) batchInsertStockBasic(stocks []StockBasicData) error {
 	return nil
 }
 
+// replaceDailyDate 在事务中删除某个交易日已有的日线数据后写入 dailyData，返回删除的行数和入库行数
+// 不依赖 (ts_code, trade_date) 唯一索引，重新抓取或续传中断的日期时不会留下重复数据
+func (f *DataFetcher) replaceDailyDate(db *gorm.DB, tradeDate string, dailyData []StockDailyData) (int64, int, error) {
+	date, err := ParseDate(tradeDate)
+	if err != nil {
+		return 0, 0, fmt.Errorf("日期格式错误: %w", err)
+	}
+
+	var deleted int64
+	var stored int
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("trade_date = ?", date).Delete(&models.StockDaily{})
+		if result.Error != nil {
+			return fmt.Errorf("删除已有数据失败: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+
+		var err error
+		stored, err = f.insertDailyData(tx, dailyData)
+		return err
+	})
+	return deleted, stored, err
+}
+
 // batchInsertDailyData 批量插入日线数据
-func (f *DataFetcher) batchInsertDailyData(dailyData []StockDailyData) error {
+func (f *DataFetcher) batchInsertDailyData(dailyData []StockDailyData) (int, error) {
+	return f.insertDailyData(f.db, dailyData)
+}
+
+// insertDailyData 使用指定连接（可为事务）批量插入日线数据
+func (f *DataFetcher) insertDailyData(db *gorm.DB, dailyData []StockDailyData) (int, error) {
 	batchSize := f.config.BatchSize
+	stored := 0
 
 	for i := 0; i < len(dailyData); i += batchSize {
 		end := i + batchSize
@@ -318,7 +822,7 @@ func (f *DataFetcher) batchInsertDailyData(dailyData []StockDailyData) error {
 
 		for _, data := range batch {
 			// 解析日期字符串为 time.Time
-			tradeDate, err := time.Parse("20060102", data.TradeDate)
+			tradeDate, err := ParseDate(data.TradeDate)
 			if err != nil {
 				f.logger.Warn("日期格式错误", zap.String("trade_date", data.TradeDate))
 			}
@@ -335,105 +839,350 @@ func (f *DataFetcher) batchInsertDailyData(dailyData []StockDailyData) error {
 				Vol:       data.Vol,
 				Amount:    data.Amount,
 			})
+			if f.config.ComputeAmplitude {
+				records[len(records)-1].Amplitude = dailyAmplitude(data.High, data.Low, data.PreClose)
+			}
 		}
 
-		if err := f.db.CreateInBatches(records, batchSize).Error; err != nil {
-			return err
+		n, err := saveRecords(f, db, records, batchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+			tsCodes := make([]string, 0, len(records))
+			dates := make([]time.Time, 0, len(records))
+			for _, r := range records {
+				tsCodes = append(tsCodes, r.TSCode)
+				dates = append(dates, r.TradeDate)
+			}
+			return codeDateScope(tx, "trade_date", tsCodes, dates)
+		})
+		if err != nil {
+			return stored, err
+		}
+		stored += n
+
+		// 快照只是查询加速，更新失败不影响日线入库；在保存点中执行，失败时不中止外层事务
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return f.refreshStockLatest(tx, records)
+		}); err != nil {
+			f.logger.Warn("更新最新行情快照失败", zap.Int("count", len(records)), zap.Error(err))
 		}
 	}
 
-	return nil
+	return stored, nil
+}
+
+// dailyAmplitude 计算振幅（%）：(最高价-最低价)/昨收价×100，任一价格缺失或昨收价不为正时返回 nil
+func dailyAmplitude(high, low, preClose *float64) *float64 {
+	if high == nil || low == nil || preClose == nil || *preClose <= 0 {
+		return nil
+	}
+	amplitude := (*high - *low) / *preClose * 100
+	return &amplitude
 }
 
-// updateTaskProgress 更新任务进度
-func (f *DataFetcher) updateTaskProgress(taskID uint, progress, successCount, failedCount int) {
-	f.db.Model(&models.FetchTask{}).Where("id = ?", taskID).Updates(map[string]interface{}{
-		"progress":      progress,
-		"success_count": successCount,
-		"failed_count":  failedCount,
+// updateTaskProgress 更新任务进度和预计完成时间，rows 不为空时同时更新行数统计
+// 写库频率受 fetcher.progress_interval_ms 限制，进度达到 100% 时立即写入
+func (f *DataFetcher) updateTaskProgress(task *models.FetchTask, progress, successCount, failedCount int, rows *rowTracker) {
+	now := time.Now()
+	f.progress.update(task.ID, progress >= 100, func() {
+		updates := map[string]interface{}{
+			"progress":           progress,
+			"success_count":      successCount,
+			"failed_count":       failedCount,
+			"estimated_end_time": f.estimateEndTime(task, successCount+failedCount, now),
+		}
+		if rows != nil {
+			// 行数统计在写入时读取，补写暂存的更新时同样是最新值
+			metrics, fetched, stored := rows.snapshot()
+			updates["rows_fetched"] = fetched
+			updates["rows_stored"] = stored
+			updates["row_metrics"] = metrics
+			updates["api_calls"] = rows.callCount()
+		}
+		f.db.Model(&models.FetchTask{}).Where("id = ?", task.ID).Updates(updates)
 	})
 }
 
-// generateDateRange 生成日期范围（使用真实交易日历）
-func (f *DataFetcher) generateDateRange(startDate, endDate string) []string {
-	// 调用 getTradeDates 获取真实交易日历
-	tradeDates, err := f.getTradeDates(startDate, endDate)
+// generateDateRange 生成日期范围（使用真实交易日历），获取失败时按 calendar_fallback 降级
+func (f *DataFetcher) generateDateRange(ctx context.Context, startDate, endDate string) ([]string, error) {
+	tradeDates, err := f.getTradeDates(ctx, startDate, endDate)
 	if err != nil {
-		f.logger.Error("获取交易日历失败，降级为周末过滤",
-			zap.String("start_date", startDate),
-			zap.String("end_date", endDate),
-			zap.Error(err))
+		return f.generateDateRangeFallback(startDate, endDate, err)
+	}
+	return tradeDates, nil
+}
 
-		// 降级方案：简单过滤周末
-		return f.generateDateRangeFallback(startDate, endDate)
+// waitTushare 每次调用 Tushare 前等待限流；熔断器打开时立即返回 ErrCircuitOpen，
+// errgroup 随之取消其余任务，避免在 Tushare 不可用时逐个日期超时。
+// 任务的 Tushare 请求数由客户端实际发出 HTTP 请求时计入，见 withCallCounter
+func (f *DataFetcher) waitTushare(ctx context.Context) error {
+	if f.tushareClient.CircuitOpen() {
+		return ErrCircuitOpen
 	}
+	return f.rateLimiter.Wait(ctx)
+}
 
-	return tradeDates
+// TaskContext 创建后台抓取任务使用的 context，配置了 task_timeout 时超时自动取消
+func (f *DataFetcher) TaskContext() (context.Context, context.CancelFunc) {
+	if f.config.TaskTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), time.Duration(f.config.TaskTimeout)*time.Minute)
 }
 
-// generateDateRangeFallback 生成日期范围的降级方案（仅过滤周末）
-func (f *DataFetcher) generateDateRangeFallback(startDate, endDate string) []string {
-	start, _ := time.Parse("20060102", startDate)
-	end, _ := time.Parse("20060102", endDate)
+// finishTask 根据执行结果结束任务：超时标记为 timeout，被取消（如服务关闭）标记为 interrupted，熔断导致提前终止时标记为失败，否则标记为完成
+func (f *DataFetcher) finishTask(task *models.FetchTask, waitErr error) {
+	if errors.Is(waitErr, context.DeadlineExceeded) {
+		f.transitionTask(task, models.TaskStatusTimeout)
+		task.ErrorMsg = fmt.Sprintf("任务执行超过 task_timeout（%d 分钟），已取消", f.config.TaskTimeout)
+		f.logger.Error("抓取任务超时，已取消",
+			zap.String("task_id", task.TaskID),
+			zap.Int("task_timeout_minutes", f.config.TaskTimeout),
+			zap.Duration("elapsed", time.Since(task.StartTime)),
+			zap.Int("success", task.SuccessCount),
+			zap.Int("total", task.TotalCount))
+		return
+	}
 
-	var dates []string
-	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		// 只包含工作日
-		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
-			dates = append(dates, d.Format("20060102"))
-		}
+	if errors.Is(waitErr, context.Canceled) {
+		f.transitionTask(task, models.TaskStatusInterrupted)
+		task.ErrorMsg = "服务关闭，任务被中断"
+		return
 	}
 
-	f.logger.Warn("使用降级方案生成日期列表",
-		zap.Int("date_count", len(dates)))
+	if errors.Is(waitErr, ErrCircuitOpen) {
+		f.transitionTask(task, models.TaskStatusFailed)
+		task.ErrorMsg = "Tushare 请求连续失败触发熔断，任务提前终止，可稍后重新抓取"
+		return
+	}
 
-	return dates
+	f.transitionTask(task, models.TaskStatusCompleted)
+	task.Progress = 100
 }
 
-// GetTaskProgress 获取任务进度
-func (f *DataFetcher) GetTaskProgress(taskID string) (*models.FetchTask, error) {
-	var task models.FetchTask
-	if err := f.db.Where("task_id = ?", taskID).First(&task).Error; err != nil {
+// failTask 任务无法开始抓取（如交易日历不可用）时标记为失败并保存，错误写入 error_msg
+func (f *DataFetcher) failTask(task *models.FetchTask, err error) {
+	now := time.Now()
+	task.EndTime = &now
+	f.transitionTask(task, models.TaskStatusFailed)
+	task.ErrorMsg = err.Error()
+	f.saveTask(task)
+	f.logger.Error("抓取任务失败", zap.String("task_id", task.TaskID), zap.Error(err))
+}
+
+// transitionTask 校验并变更任务状态，非法变更只记录日志
+func (f *DataFetcher) transitionTask(task *models.FetchTask, next models.TaskStatus) {
+	if err := task.TransitionTo(next); err != nil {
+		f.logger.Error("任务状态变更失败",
+			zap.String("task_id", task.TaskID),
+			zap.Error(err))
+	}
+}
+
+// GetTaskProgress 获取任务进度，运行中的任务同时返回当前有效并发数和预计还需的 Tushare 请求数
+func (f *DataFetcher) GetTaskProgress(taskID string) (*models.FetchTask, error) {
+	var task models.FetchTask
+	if err := f.db.Where("task_id = ?", taskID).First(&task).Error; err != nil {
 		return nil, err
 	}
+	f.fillRunningStatus(&task)
 	return &task, nil
 }
 
-// getTradeDates 获取交易日列表
-func (f *DataFetcher) getTradeDates(startDate, endDate string) ([]string, error) {
-	// 从 Tushare 获取交易日历
-	calData, err := f.tushareClient.GetTradeCal(startDate, endDate, 1) // 1 = 只获取交易日
-	if err != nil {
-		return nil, fmt.Errorf("调用 Tushare API 失败: %w", err)
+// GetDailyTaskStatus 获取日线抓取任务的进度，taskID 为空时返回最近启动的日线任务
+func (f *DataFetcher) GetDailyTaskStatus(taskID string) (*models.FetchTask, error) {
+	if taskID != "" {
+		if !strings.HasPrefix(taskID, summaryTaskPrefixes[PlanTypeDaily]) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return f.GetTaskProgress(taskID)
+	}
+
+	var task models.FetchTask
+	if err := f.db.Where("task_id LIKE ?", summaryTaskPrefixes[PlanTypeDaily]+"%").
+		Order("start_time desc, id desc").
+		First(&task).Error; err != nil {
+		return nil, err
+	}
+	f.fillRunningStatus(&task)
+	return &task, nil
+}
+
+// fillRunningStatus 为运行中的任务填充有效并发数和预计还需的 Tushare 请求数
+func (f *DataFetcher) fillRunningStatus(task *models.FetchTask) {
+	if task.Status != models.TaskStatusRunning {
+		return
 	}
+	task.EffectiveConcurrency = f.throttle.Limit()
+	task.EstimatedRemainingCalls = estimateRemainingCalls(task)
+}
+
+// tradeCalendarExchanges 合并交易日历的交易所，各交易所休市安排偶有不同
+var tradeCalendarExchanges = []string{"SSE", "SZSE"}
+
+// TradeDay 交易日及当日开市的交易所
+type TradeDay struct {
+	Date      string   // 交易日期 YYYYMMDD
+	Exchanges []string // 当日开市的交易所
+}
+
+// getTradeCalendar 获取各交易所的交易日历并取并集，按日期升序返回
+func (f *DataFetcher) getTradeCalendar(ctx context.Context, startDate, endDate string) ([]TradeDay, error) {
+	openExchanges := make(map[string][]string)
+	for _, exchange := range tradeCalendarExchanges {
+		if err := f.waitTushare(ctx); err != nil {
+			return nil, err
+		}
+		calData, err := f.tushareClient.GetExchangeTradeCal(ctx, exchange, startDate, endDate, 1) // 1 = 只获取交易日
+		if err != nil {
+			return nil, fmt.Errorf("调用 Tushare API 失败（%s）: %w", exchange, err)
+		}
 
-	// 提取交易日期
-	tradeDates := make([]string, 0, len(calData))
-	for _, cal := range calData {
-		if cal.IsOpen == 1 {
-			tradeDates = append(tradeDates, cal.CalDate)
+		for _, cal := range calData {
+			if cal.IsOpen == 1 {
+				openExchanges[cal.CalDate] = append(openExchanges[cal.CalDate], exchange)
+			}
 		}
 	}
 
+	days := make([]TradeDay, 0, len(openExchanges))
+	for date, exchanges := range openExchanges {
+		days = append(days, TradeDay{Date: date, Exchanges: exchanges})
+	}
+
 	// 按日期排序（从旧到新）
-	sort.Strings(tradeDates)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+
+	return days, nil
+}
+
+// getTradeDates 获取交易日列表：任一交易所开市即为交易日，避免漏抓或多抓
+func (f *DataFetcher) getTradeDates(ctx context.Context, startDate, endDate string) ([]string, error) {
+	days, err := f.getTradeCalendar(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	tradeDates := make([]string, 0, len(days))
+	for _, day := range days {
+		tradeDates = append(tradeDates, day.Date)
+	}
 
 	return tradeDates, nil
 }
 
+// IsTradeDay 根据交易日历判断指定日期是否为交易日
+func (f *DataFetcher) IsTradeDay(ctx context.Context, date string) (bool, error) {
+	tradeDates, err := f.getTradeDates(ctx, date, date)
+	if err != nil {
+		return false, err
+	}
+	return len(tradeDates) > 0, nil
+}
+
+// LatestTradeDate 根据交易日历获取截至今天（database.timezone）的最近一个交易日
+func (f *DataFetcher) LatestTradeDate(ctx context.Context) (string, error) {
+	today := time.Now().In(marketLocation)
+	// 最长的休市（春节）不超过两周，回看 30 天足够
+	tradeDates, err := f.getTradeDates(ctx, today.AddDate(0, 0, -30).Format("20060102"), today.Format("20060102"))
+	if err != nil {
+		return "", err
+	}
+	if len(tradeDates) == 0 {
+		return "", fmt.Errorf("最近 30 天没有交易日")
+	}
+	return tradeDates[len(tradeDates)-1], nil
+}
+
+// ErrNoDailyData Tushare 未返回指定交易日的日线数据
+var ErrNoDailyData = errors.New("Tushare 未返回该交易日的日线数据")
+
+// RefetchDailyDate 重新抓取指定交易日的日线数据：
+// 先从 Tushare 拉取数据（不复用去重缓存中的结果），再在事务中删除该日期已有数据并写入新数据
+// Tushare 未返回任何数据（如当日数据尚未更新）时返回 ErrNoDailyData，保留已有数据
+func (f *DataFetcher) RefetchDailyDate(ctx context.Context, tradeDate string) (int, error) {
+	if _, err := ParseDate(tradeDate); err != nil {
+		return 0, fmt.Errorf("日期格式错误: %w", err)
+	}
+
+	if err := f.waitTushare(ctx); err != nil {
+		return 0, err
+	}
+
+	dailyData, err := f.tushareClient.GetDailyData(bypassRequestCache(ctx), tradeDate, "")
+	if err != nil {
+		return 0, fmt.Errorf("获取日线数据失败: %w", err)
+	}
+	if len(dailyData) == 0 {
+		return 0, fmt.Errorf("%w: %s，已保留已有数据", ErrNoDailyData, tradeDate)
+	}
+
+	deleted, _, err := f.replaceDailyDate(f.db.WithContext(ctx), tradeDate, dailyData)
+	if err != nil {
+		return 0, err
+	}
+	f.logger.Info("已删除待重新抓取的日线数据",
+		zap.String("trade_date", tradeDate),
+		zap.Int64("deleted", deleted))
+
+	f.logger.Info("日线数据重新抓取完成",
+		zap.String("trade_date", tradeDate),
+		zap.Int("count", len(dailyData)))
+
+	return len(dailyData), nil
+}
+
+// MaxSyncFetchDates 同步抓取单只股票日线时允许的最大交易日数
+const MaxSyncFetchDates = 60
+
+// ErrRangeTooLarge 请求的日期范围超过上限
+var ErrRangeTooLarge = errors.New("日期范围过大")
+
+// FetchDailySync 按交易日逐日从 Tushare 拉取单只股票的日线并直接返回，不写入数据库
+// 交易日数超过 MaxSyncFetchDates 时返回错误
+func (f *DataFetcher) FetchDailySync(ctx context.Context, tsCode, startDate, endDate string) ([]StockDailyData, error) {
+	dates, err := f.generateDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) > MaxSyncFetchDates {
+		return nil, fmt.Errorf("%w: 包含 %d 个交易日，同步抓取最多 %d 个，请缩小范围或使用异步抓取", ErrRangeTooLarge, len(dates), MaxSyncFetchDates)
+	}
+
+	result := make([]StockDailyData, 0, len(dates))
+	for _, date := range dates {
+		if err := f.waitTushare(ctx); err != nil {
+			return nil, err
+		}
+
+		dailyData, err := f.tushareClient.GetDailyData(ctx, date, tsCode)
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s 日线数据失败: %w", date, err)
+		}
+		result = append(result, dailyData...)
+	}
+
+	f.logger.Info("同步抓取日线数据完成",
+		zap.String("ts_code", tsCode),
+		zap.Int("dates", len(dates)),
+		zap.Int("count", len(result)))
+
+	return result, nil
+}
+
 // FetchWeeklyData 抓取周线数据
 func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
 	// 创建任务记录
 	task := &models.FetchTask{
-		TaskID:    fmt.Sprintf("weekly_task_%d", time.Now().Unix()),
+		TaskID:    newTaskID("weekly_task_"),
 		StartDate: startDate,
 		EndDate:   endDate,
-		Status:    "running",
+		Status:    models.TaskStatusRunning,
 		StartTime: time.Now(),
 	}
 
-	if err := f.db.Create(task).Error; err != nil {
-		return nil, fmt.Errorf("创建任务记录失败: %w", err)
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
 	}
 
 	f.logger.Info("开始抓取周线数据",
@@ -442,33 +1191,46 @@ func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate st
 		zap.String("end_date", endDate))
 
 	// 生成周线日期范围（每周最后一个交易日）
-	dates := f.generateWeekDateRange(startDate, endDate)
+	dates := f.generateWeekDateRange(ctx, startDate, endDate)
 	task.TotalCount = len(dates)
-	f.db.Save(task)
+	f.saveTask(task)
 	f.logger.Info("任务规模",
 		zap.Int("weeks", len(dates)),
 		zap.Int("total_tasks", task.TotalCount))
 	// 并发抓取
 	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(f.config.Concurrency)
+	gate := f.throttle.gate()
 
 	for _, date := range dates {
 		week_date := date
 		g.Go(func() error {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
+			// 数据库变慢时暂停派发
+			if err := gate.acquire(ctx); err != nil {
+				return err
 			}
+			defer gate.release()
+
 			// 限流
-			<-f.rateLimiter.C
+			if err := f.waitTushare(ctx); err != nil {
+				return err
+			}
 
 			// 抓取周线数据
-			weeklyData, err := f.tushareClient.GetWeeklyData(week_date)
+			var weeklyData []StockWeeklyData
+			err := f.retryDate(ctx, task.TaskID, week_date, func() (err error) {
+				weeklyData, err = f.tushareClient.GetWeeklyData(ctx, week_date)
+				return err
+			})
 			if err != nil {
 				atomic.AddInt64(&failedCount, 1)
+				f.recordFailure(task.TaskID, week_date, "", err)
+				f.saveTaskDate(task.TaskID, week_date, models.TaskDateFailed)
 				f.logger.Error("抓取周线数据失败",
+					zap.String("task_id", task.TaskID),
 					zap.String("date", date),
 					zap.Error(err))
 				return nil // 不中断其他任务
@@ -476,13 +1238,19 @@ func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate st
 
 			// 批量保存
 			if len(weeklyData) > 0 {
-				if err := f.batchInsertWeeklyData(weeklyData); err != nil {
+				stored, err := f.batchInsertWeeklyData(weeklyData)
+				rows.record("weekly", len(weeklyData), stored)
+				if err != nil {
 					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, week_date, "", err)
+					f.saveTaskDate(task.TaskID, week_date, models.TaskDateFailed)
 					f.logger.Error("保存周线数据失败",
+						zap.String("task_id", task.TaskID),
 						zap.String("date", date),
 						zap.Error(err))
 				} else {
 					atomic.AddInt64(&successCount, 1)
+					f.saveTaskDate(task.TaskID, week_date, models.TaskDateCompleted)
 					f.logger.Info("周线数据保存成功",
 						zap.String("date", date),
 						zap.Int("count", len(weeklyData)))
@@ -490,6 +1258,7 @@ func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate st
 			} else {
 				// 无数据也算成功
 				atomic.AddInt64(&successCount, 1)
+				f.saveTaskDate(task.TaskID, week_date, models.TaskDateCompleted)
 				f.logger.Debug("该日期无周线数据",
 					zap.String("date", date))
 			}
@@ -497,25 +1266,26 @@ func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate st
 			// 更新进度
 			total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
 			progress := int(total * 100 / int64(task.TotalCount))
-			f.updateTaskProgress(task.ID, progress, int(successCount), int(failedCount))
+			f.updateTaskProgress(task, progress, int(successCount), int(failedCount), rows)
 
 			return nil
 		})
 	}
 
 	// 等待所有任务完成
-	if err := g.Wait(); err != nil {
-		f.logger.Error("抓取过程出错", zap.Error(err))
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
 	}
 
 	// 更新任务状态
 	now := time.Now()
 	task.EndTime = &now
-	task.Status = "completed"
-	task.Progress = 100
+	f.finishTask(task, waitErr)
 	task.SuccessCount = int(successCount)
 	task.FailedCount = int(failedCount)
-	f.db.Save(task)
+	rows.applyTo(task)
+	f.saveTask(task)
 
 	f.logger.Info("周线数据抓取完成",
 		zap.String("task_id", task.TaskID),
@@ -527,8 +1297,9 @@ func (f *DataFetcher) FetchWeeklyData(ctx context.Context, startDate, endDate st
 }
 
 // batchInsertWeeklyData 批量插入周线数据
-func (f *DataFetcher) batchInsertWeeklyData(weeklyData []StockWeeklyData) error {
+func (f *DataFetcher) batchInsertWeeklyData(weeklyData []StockWeeklyData) (int, error) {
 	batchSize := f.config.BatchSize
+	stored := 0
 
 	for i := 0; i < len(weeklyData); i += batchSize {
 		end := i + batchSize
@@ -541,14 +1312,14 @@ func (f *DataFetcher) batchInsertWeeklyData(weeklyData []StockWeeklyData) error
 
 		for _, data := range batch {
 			// 解析交易日期
-			tradeDate, err := time.Parse("20060102", data.TradeDate)
+			tradeDate, err := ParseDate(data.TradeDate)
 			if err != nil {
 				f.logger.Warn("周线交易日期格式错误", zap.String("trade_date", data.TradeDate))
 				continue
 			}
 
 			// 解析截至日期
-			endDate, err := time.Parse("20060102", data.EndDate)
+			endDate, err := ParseDate(data.EndDate)
 			if err != nil {
 				f.logger.Warn("周线end_date日期格式错误", zap.String("end_date", data.EndDate))
 				continue
@@ -588,18 +1359,29 @@ func (f *DataFetcher) batchInsertWeeklyData(weeklyData []StockWeeklyData) error
 			})
 		}
 
-		if err := f.db.CreateInBatches(records, batchSize).Error; err != nil {
-			return err
+		n, err := saveRecords(f, f.db, records, batchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+			tsCodes := make([]string, 0, len(records))
+			dates := make([]time.Time, 0, len(records))
+			for _, r := range records {
+				tsCodes = append(tsCodes, r.TSCode)
+				dates = append(dates, r.TradeDate)
+			}
+			return codeDateScope(tx, "trade_date", tsCodes, dates)
+		})
+		if err != nil {
+			return stored, err
 		}
+		stored += n
 	}
 
-	return nil
+	return stored, nil
 }
 
 // generateWeekDateRange 生成周线交易日期范围（每周最后一个交易日）
-func (f *DataFetcher) generateWeekDateRange(startDate, endDate string) []string {
+// 按 ISO 周分组，跨年的一周（如 2024-12-30 至 2025-01-03）视为同一周，取该周最后一个交易日，见 groupTradeWeeks
+func (f *DataFetcher) generateWeekDateRange(ctx context.Context, startDate, endDate string) []string {
 	// 获取所有交易日
-	allTradeDates, err := f.getTradeDates(startDate, endDate)
+	allTradeDates, err := f.getTradeDates(ctx, startDate, endDate)
 	if err != nil {
 		f.logger.Error("获取交易日历失败，降级为周末过滤",
 			zap.String("start_date", startDate),
@@ -609,55 +1391,18 @@ func (f *DataFetcher) generateWeekDateRange(startDate, endDate string) []string
 		// 降级方案：使用周末过滤并获取每周最后一个交易日
 		return f.generateWeekDateRangeFallback(startDate, endDate)
 	}
-	if len(allTradeDates) == 0 {
-		return []string{}
-	}
-	// 将字符串日期转换为 time.Time
-	var dates []time.Time
-	for _, dateStr := range allTradeDates {
-		if t, err := time.Parse("20060102", dateStr); err == nil {
-			dates = append(dates, t)
-		}
-	}
-	if len(dates) == 0 {
-		return []string{}
-	}
-
-	// 按周分组，获取每周最后一个交易日
-	var weekEndDates []string
-	currentWeekYear, currentWeekNum := dates[0].ISOWeek()
-	lastDateOfWeek := dates[0]
-
-	for _, date := range dates {
-		weekYear, weekNum := date.ISOWeek()
-
-		// 如果进入新的周
-		if weekYear != currentWeekYear || weekNum != currentWeekNum {
-			// 添加上周的最后一个交易日
-			weekEndDates = append(weekEndDates, lastDateOfWeek.Format("20060102"))
-
-			// 更新当前周信息
-			currentWeekYear = weekYear
-			currentWeekNum = weekNum
-		}
-
-		// 更新本周最后一个交易日
-		lastDateOfWeek = date
-	}
-
-	// 添加最后一周的最后一个交易日
-	weekEndDates = append(weekEndDates, lastDateOfWeek.Format("20060102"))
 
+	weekEnds := weekEndDates(allTradeDates)
 	f.logger.Info("生成周线交易日期完成",
-		zap.Int("total_weeks", len(weekEndDates)))
+		zap.Int("total_weeks", len(weekEnds)))
 
-	return weekEndDates
+	return weekEnds
 }
 
-// generateDateRangeFallback 生成日期范围的降级方案（仅过滤周末）
+// generateWeekDateRangeFallback 生成周线日期范围的降级方案：仅过滤周末，取每周最后一个工作日
 func (f *DataFetcher) generateWeekDateRangeFallback(startDate, endDate string) []string {
-	start, _ := time.Parse("20060102", startDate)
-	end, _ := time.Parse("20060102", endDate)
+	start, _ := ParseDate(startDate)
+	end, _ := ParseDate(endDate)
 
 	var dates []string
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
@@ -667,31 +1412,42 @@ func (f *DataFetcher) generateWeekDateRangeFallback(startDate, endDate string) [
 		}
 	}
 
+	weekEnds := weekEndDates(dates)
 	f.logger.Warn("使用降级方案生成日期列表",
-		zap.Int("date_count", len(dates)))
+		zap.Int("date_count", len(weekEnds)))
 
-	return dates
+	return weekEnds
+}
+
+// weekEndDates 返回每个 ISO 周的最后一个交易日，输入需按日期升序
+func weekEndDates(dates []string) []string {
+	weeks := groupTradeWeeks(dates)
+	result := make([]string, 0, len(weeks))
+	for _, week := range weeks {
+		result = append(result, week.Last().Format("20060102"))
+	}
+	return result
 }
 
 // FetchMonthlyData 抓取月线数据（仅获取每月最后一个交易日的数据）
 func (f *DataFetcher) FetchMonthlyData(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
 	// 创建任务记录
 	task := &models.FetchTask{
-		TaskID:    fmt.Sprintf("monthly_task_%d", time.Now().Unix()),
+		TaskID:    newTaskID("monthly_task_"),
 		StartDate: startDate,
 		EndDate:   endDate,
-		Status:    "running",
+		Status:    models.TaskStatusRunning,
 		StartTime: time.Now(),
 	}
 
-	if err := f.db.Create(task).Error; err != nil {
-		return nil, fmt.Errorf("创建任务记录失败: %w", err)
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
 	}
 
 	// 生成月末日期列表
 	monthEndDates := f.generateMonthEndDates(startDate, endDate)
 	task.TotalCount = len(monthEndDates)
-	f.db.Save(task)
+	f.saveTask(task)
 
 	f.logger.Info("开始抓取月线数据",
 		zap.String("task_id", task.TaskID),
@@ -700,27 +1456,40 @@ func (f *DataFetcher) FetchMonthlyData(ctx context.Context, startDate, endDate s
 	// 使用 errgroup 并发抓取
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(f.config.Concurrency)
+	gate := f.throttle.gate()
 
 	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
 
 	for i, date := range monthEndDates {
 		date := date
 		index := i
 
 		g.Go(func() error {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
+			// 数据库变慢时暂停派发
+			if err := gate.acquire(ctx); err != nil {
+				return err
 			}
+			defer gate.release()
 
-			<-f.rateLimiter.C
+			// 限流
+			if err := f.waitTushare(ctx); err != nil {
+				return err
+			}
 
 			// 抓取该月末日期的所有数据
-			monthlyData, err := f.tushareClient.GetMonthlyData(date, "")
+			var monthlyData []StockMonthlyData
+			err := f.retryDate(ctx, task.TaskID, date, func() (err error) {
+				monthlyData, err = f.tushareClient.GetMonthlyData(ctx, date, "")
+				return err
+			})
 			if err != nil {
 				atomic.AddInt64(&failedCount, 1)
+				f.recordFailure(task.TaskID, date, "", err)
+				f.saveTaskDate(task.TaskID, date, models.TaskDateFailed)
 				f.logger.Error("抓取月线数据失败",
+					zap.String("task_id", task.TaskID),
 					zap.String("date", date),
 					zap.Error(err))
 				return nil
@@ -728,40 +1497,50 @@ func (f *DataFetcher) FetchMonthlyData(ctx context.Context, startDate, endDate s
 
 			// 批量保存
 			if len(monthlyData) > 0 {
-				if err := f.batchInsertMonthlyData(monthlyData); err != nil {
+				stored, err := f.batchInsertMonthlyData(monthlyData)
+				rows.record("monthly", len(monthlyData), stored)
+				if err != nil {
 					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, date, "", err)
+					f.saveTaskDate(task.TaskID, date, models.TaskDateFailed)
 					f.logger.Error("保存月线数据失败",
+						zap.String("task_id", task.TaskID),
 						zap.String("date", date),
 						zap.Error(err))
 				} else {
 					atomic.AddInt64(&successCount, 1)
+					f.saveTaskDate(task.TaskID, date, models.TaskDateCompleted)
 					f.logger.Info("月线数据保存成功",
 						zap.String("date", date),
 						zap.Int("count", len(monthlyData)))
 				}
+			} else {
+				// 无数据时只记录检查点，不计入成功数
+				f.saveTaskDate(task.TaskID, date, models.TaskDateCompleted)
 			}
 
 			// 更新进度
 			progress := (index + 1) * 100 / len(monthEndDates)
-			f.updateTaskProgress(task.ID, progress, int(successCount), int(failedCount))
+			f.updateTaskProgress(task, progress, int(successCount), int(failedCount), rows)
 
 			return nil
 		})
 	}
 
 	// 等待所有任务完成
-	if err := g.Wait(); err != nil {
-		f.logger.Error("抓取过程出错", zap.Error(err))
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
 	}
 
 	// 更新任务状态
 	now := time.Now()
 	task.EndTime = &now
-	task.Status = "completed"
-	task.Progress = 100
+	f.finishTask(task, waitErr)
 	task.SuccessCount = int(successCount)
 	task.FailedCount = int(failedCount)
-	f.db.Save(task)
+	rows.applyTo(task)
+	f.saveTask(task)
 
 	f.logger.Info("月线数据抓取完成",
 		zap.String("task_id", task.TaskID),
@@ -774,8 +1553,8 @@ func (f *DataFetcher) FetchMonthlyData(ctx context.Context, startDate, endDate s
 // generateMonthEndDates 生成月末日期列表（每月最后一天）
 // Tushare月线接口会自动返回最后一个交易日的数据，所以只需传入每月最后一天即可
 func (f *DataFetcher) generateMonthEndDates(startDate, endDate string) []string {
-	start, _ := time.Parse("20060102", startDate)
-	end, _ := time.Parse("20060102", endDate)
+	start, _ := ParseDate(startDate)
+	end, _ := ParseDate(endDate)
 
 	var dates []string
 
@@ -811,8 +1590,9 @@ func (f *DataFetcher) generateMonthEndDates(startDate, endDate string) []string
 }
 
 // batchInsertMonthlyData 批量插入月线数据
-func (f *DataFetcher) batchInsertMonthlyData(monthlyData []StockMonthlyData) error {
+func (f *DataFetcher) batchInsertMonthlyData(monthlyData []StockMonthlyData) (int, error) {
 	batchSize := f.config.BatchSize
+	stored := 0
 
 	for i := 0; i < len(monthlyData); i += batchSize {
 		end := i + batchSize
@@ -858,10 +1638,939 @@ func (f *DataFetcher) batchInsertMonthlyData(monthlyData []StockMonthlyData) err
 			})
 		}
 
-		if err := f.db.CreateInBatches(records, batchSize).Error; err != nil {
-			return err
+		n, err := saveRecords(f, f.db, records, batchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+			tsCodes := make([]string, 0, len(records))
+			dates := make([]time.Time, 0, len(records))
+			for _, r := range records {
+				tsCodes = append(tsCodes, r.TSCode)
+				dates = append(dates, r.TradeDate)
+			}
+			return codeDateScope(tx, "trade_date", tsCodes, dates)
+		})
+		if err != nil {
+			return stored, err
 		}
+		stored += n
 	}
 
-	return nil
+	return stored, nil
+}
+
+// FetchFinaIndicator 抓取财务指标数据（按股票、报告期逐个抓取），tsCodes 为空时抓取全部股票
+func (f *DataFetcher) FetchFinaIndicator(ctx context.Context, startDate, endDate string, tsCodes []string) (*models.FetchTask, error) {
+	// 创建任务记录
+	task := &models.FetchTask{
+		TaskID:    newTaskID("fina_task_"),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Status:    models.TaskStatusRunning,
+		StartTime: time.Now(),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	// 获取股票列表
+	stocks, err := f.loadStocks(ctx, tsCodes...)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+
+	// 生成报告期列表
+	periods := f.generateQuarterEndDates(startDate, endDate)
+	task.TotalCount = len(stocks) * len(periods)
+	f.saveTask(task)
+
+	f.logger.Info("开始抓取财务指标数据",
+		zap.String("task_id", task.TaskID),
+		zap.Int("stocks", len(stocks)),
+		zap.Int("periods", len(periods)),
+		zap.Int("total_tasks", task.TotalCount))
+
+	// 使用 errgroup 并发抓取
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+
+	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
+
+	for _, stock := range stocks {
+		for _, period := range periods {
+			tsCode := stock.TSCode
+			period := period
+
+			g.Go(func() error {
+				// 限流
+				if err := f.waitTushare(ctx); err != nil {
+					return err
+				}
+
+				finaData, err := f.tushareClient.GetFinaIndicator(ctx, tsCode, period)
+				if err != nil {
+					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, period, tsCode, err)
+					f.logger.Error("抓取财务指标失败",
+						zap.String("task_id", task.TaskID),
+						zap.String("ts_code", tsCode),
+						zap.String("period", period),
+						zap.Error(err))
+					return nil
+				}
+
+				stored, err := f.batchInsertFinaIndicator(finaData)
+				rows.record("fina_indicator", len(finaData), stored)
+				if err != nil {
+					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, period, tsCode, err)
+					f.logger.Error("保存财务指标失败",
+						zap.String("task_id", task.TaskID),
+						zap.String("ts_code", tsCode),
+						zap.String("period", period),
+						zap.Error(err))
+				} else {
+					atomic.AddInt64(&successCount, 1)
+				}
+
+				// 更新进度
+				total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+				if total%100 == 0 {
+					progress := int(total * 100 / int64(task.TotalCount))
+					f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+				}
+
+				return nil
+			})
+		}
+	}
+
+	// 等待所有任务完成
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	// 更新任务状态
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	rows.applyTo(task)
+	f.saveTask(task)
+
+	f.logger.Info("财务指标数据抓取完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount))
+
+	return task, nil
+}
+
+// generateQuarterEndDates 生成报告期列表（每季度最后一天）
+func (f *DataFetcher) generateQuarterEndDates(startDate, endDate string) []string {
+	start, _ := ParseDate(startDate)
+	end, _ := ParseDate(endDate)
+
+	var periods []string
+
+	// 调整到所在季度第一天
+	current := time.Date(start.Year(), (start.Month()-1)/3*3+1, 1, 0, 0, 0, 0, start.Location())
+
+	for !current.After(end) {
+		quarterEnd := current.AddDate(0, 3, -1)
+		if !quarterEnd.Before(start) && !quarterEnd.After(end) {
+			periods = append(periods, quarterEnd.Format("20060102"))
+		}
+		current = current.AddDate(0, 3, 0)
+	}
+
+	return periods
+}
+
+// batchInsertFinaIndicator 批量插入财务指标数据（按 ts_code + end_date 更新已有记录）
+func (f *DataFetcher) batchInsertFinaIndicator(finaData []FinaIndicatorData) (int, error) {
+	if len(finaData) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.FinaIndicator, 0, len(finaData))
+	for _, data := range finaData {
+		endDate, err := ParseDate(data.EndDate)
+		if err != nil {
+			f.logger.Warn("财务指标报告期格式错误", zap.String("end_date", data.EndDate))
+			continue
+		}
+
+		records = append(records, models.FinaIndicator{
+			TSCode:            data.TSCode,
+			EndDate:           endDate,
+			AnnDate:           data.AnnDate,
+			EPS:               data.EPS,
+			DtEPS:             data.DtEPS,
+			BPS:               data.BPS,
+			OCFPS:             data.OCFPS,
+			ROE:               data.ROE,
+			ROEDt:             data.ROEDt,
+			ROA:               data.ROA,
+			GrossprofitMargin: data.GrossprofitMargin,
+			NetprofitMargin:   data.NetprofitMargin,
+			DebtToAssets:      data.DebtToAssets,
+			CurrentRatio:      data.CurrentRatio,
+			QuickRatio:        data.QuickRatio,
+			OrYoy:             data.OrYoy,
+			NetprofitYoy:      data.NetprofitYoy,
+		})
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code", "end_date"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.EndDate)
+		}
+		return codeDateScope(tx, "end_date", tsCodes, dates)
+	})
+}
+
+// FetchConcepts 抓取概念分类及申万一级行业成分，保存股票与分类的对应关系
+func (f *DataFetcher) FetchConcepts(ctx context.Context) (*models.FetchTask, error) {
+	// 创建任务记录
+	task := &models.FetchTask{
+		TaskID:    newTaskID("concept_task_"),
+		Status:    models.TaskStatusRunning,
+		StartTime: time.Now(),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
+	if err := f.waitTushare(ctx); err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+	concepts, err := f.tushareClient.GetConcepts(ctx)
+	if err != nil {
+		err = fmt.Errorf("获取概念分类失败: %w", err)
+		f.failTask(task, err)
+		return task, err
+	}
+
+	if err := f.waitTushare(ctx); err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+	industries, err := f.tushareClient.GetIndexClassify(ctx, "L1", "SW2021")
+	if err != nil {
+		err = fmt.Errorf("获取申万行业分类失败: %w", err)
+		f.failTask(task, err)
+		return task, err
+	}
+
+	task.TotalCount = len(concepts) + len(industries)
+	f.saveTask(task)
+
+	f.logger.Info("开始抓取概念及行业成分",
+		zap.String("task_id", task.TaskID),
+		zap.Int("concepts", len(concepts)),
+		zap.Int("industries", len(industries)))
+
+	// 使用 errgroup 并发抓取
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+
+	var successCount, failedCount int64
+
+	// 记录单个分类的抓取结果
+	record := func(classifyType, code string, err error) {
+		if err != nil {
+			atomic.AddInt64(&failedCount, 1)
+			f.logger.Error("抓取分类成分失败",
+				zap.String("task_id", task.TaskID),
+				zap.String("type", classifyType),
+				zap.String("code", code),
+				zap.Error(err))
+		} else {
+			atomic.AddInt64(&successCount, 1)
+		}
+
+		total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+		progress := int(total * 100 / int64(task.TotalCount))
+		f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+	}
+
+	for _, concept := range concepts {
+		concept := concept
+
+		g.Go(func() error {
+			// 限流
+			if err := f.waitTushare(ctx); err != nil {
+				return err
+			}
+
+			details, err := f.tushareClient.GetConceptDetail(ctx, concept.Code)
+			if err == nil {
+				records := make([]models.StockConcept, 0, len(details))
+				for _, detail := range details {
+					records = append(records, models.StockConcept{
+						TSCode: detail.TSCode,
+						Type:   models.ClassifyTypeConcept,
+						Code:   concept.Code,
+						Name:   concept.Name,
+					})
+				}
+				var stored int
+				stored, err = f.batchUpsertStockConcept(records)
+				rows.record("concept_detail", len(details), stored)
+			}
+
+			record(models.ClassifyTypeConcept, concept.Code, err)
+			return nil
+		})
+	}
+
+	for _, industry := range industries {
+		industry := industry
+
+		g.Go(func() error {
+			// 限流
+			if err := f.waitTushare(ctx); err != nil {
+				return err
+			}
+
+			members, err := f.tushareClient.GetIndexMember(ctx, industry.IndexCode)
+			if err == nil {
+				records := make([]models.StockConcept, 0, len(members))
+				for _, member := range members {
+					// 只保留当前成分股
+					if member.OutDate != "" && member.IsNew != "Y" {
+						continue
+					}
+					records = append(records, models.StockConcept{
+						TSCode: member.ConCode,
+						Type:   models.ClassifyTypeIndustry,
+						Code:   industry.IndexCode,
+						Name:   industry.IndustryName,
+					})
+				}
+				var stored int
+				stored, err = f.batchUpsertStockConcept(records)
+				rows.record("index_member", len(members), stored)
+			}
+
+			record(models.ClassifyTypeIndustry, industry.IndexCode, err)
+			return nil
+		})
+	}
+
+	// 等待所有任务完成
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	// 更新任务状态
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	rows.applyTo(task)
+	f.saveTask(task)
+
+	f.logger.Info("概念及行业成分抓取完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount))
+
+	return task, nil
+}
+
+// batchUpsertStockConcept 批量保存股票分类对应关系（已存在时更新名称）
+func (f *DataFetcher) batchUpsertStockConcept(records []models.StockConcept) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	if err := f.db.Clauses(upsertClause(f.db, []string{"ts_code", "type", "code"}, "name", "updated_at")).CreateInBatches(records, f.config.BatchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// minuteInsertBatchSize 分钟线单批插入上限，分钟线数据量大，单批过大会导致 SQL 过长
+const minuteInsertBatchSize = 500
+
+// FetchMinuteData 抓取分钟线数据（按股票、交易日逐个抓取）
+func (f *DataFetcher) FetchMinuteData(ctx context.Context, startDate, endDate, freq string) (*models.FetchTask, error) {
+	if !IsValidMinuteFreq(freq) {
+		return nil, fmt.Errorf("不支持的分钟线频率: %s", freq)
+	}
+
+	// 创建任务记录
+	task := &models.FetchTask{
+		TaskID:    newTaskID("minute_task_"),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Status:    models.TaskStatusRunning,
+		StartTime: time.Now(),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	// 获取股票列表
+	stocks, err := f.loadStocks(ctx)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+
+	// 生成日期列表
+	dates, err := f.generateDateRange(ctx, startDate, endDate)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+	task.TotalCount = len(stocks) * len(dates)
+	f.saveTask(task)
+
+	f.logger.Info("开始抓取分钟线数据",
+		zap.String("task_id", task.TaskID),
+		zap.String("freq", freq),
+		zap.Int("stocks", len(stocks)),
+		zap.Int("dates", len(dates)),
+		zap.Int("total_tasks", task.TotalCount))
+
+	// 使用 errgroup 并发抓取
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+	gate := f.throttle.gate()
+
+	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
+
+	for _, stock := range stocks {
+		for _, date := range dates {
+			tsCode := stock.TSCode
+			date := date
+
+			g.Go(func() error {
+				// 数据库变慢时暂停派发
+				if err := gate.acquire(ctx); err != nil {
+					return err
+				}
+				defer gate.release()
+
+				// 限流
+				if err := f.waitTushare(ctx); err != nil {
+					return err
+				}
+
+				minuteData, err := f.tushareClient.GetMinuteData(ctx, tsCode, date, freq)
+				if err != nil {
+					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, date, tsCode, err)
+					f.logger.Error("抓取分钟线数据失败",
+						zap.String("task_id", task.TaskID),
+						zap.String("ts_code", tsCode),
+						zap.String("date", date),
+						zap.Error(err))
+					return nil
+				}
+
+				stored, err := f.batchInsertMinuteData(minuteData, freq)
+				rows.record("stk_mins", len(minuteData), stored)
+				if err != nil {
+					atomic.AddInt64(&failedCount, 1)
+					f.recordFailure(task.TaskID, date, tsCode, err)
+					f.logger.Error("保存分钟线数据失败",
+						zap.String("task_id", task.TaskID),
+						zap.String("ts_code", tsCode),
+						zap.String("date", date),
+						zap.Error(err))
+				} else {
+					atomic.AddInt64(&successCount, 1)
+				}
+
+				// 更新进度
+				total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+				if total%100 == 0 {
+					progress := int(total * 100 / int64(task.TotalCount))
+					f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+				}
+
+				return nil
+			})
+		}
+	}
+
+	// 等待所有任务完成
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	// 更新任务状态
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	rows.applyTo(task)
+	f.saveTask(task)
+
+	f.logger.Info("分钟线数据抓取完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount))
+
+	return task, nil
+}
+
+// batchInsertMinuteData 批量插入分钟线数据
+func (f *DataFetcher) batchInsertMinuteData(minuteData []StockMinuteData, freq string) (int, error) {
+	batchSize := f.config.BatchSize
+	if batchSize > minuteInsertBatchSize {
+		batchSize = minuteInsertBatchSize
+	}
+	stored := 0
+
+	for i := 0; i < len(minuteData); i += batchSize {
+		end := i + batchSize
+		if end > len(minuteData) {
+			end = len(minuteData)
+		}
+
+		batch := minuteData[i:end]
+		records := make([]models.StockMinute, 0, len(batch))
+
+		for _, data := range batch {
+			tradeTime, err := time.ParseInLocation("2006-01-02 15:04:05", data.TradeTime, marketLocation)
+			if err != nil {
+				f.logger.Warn("分钟线交易时间格式错误", zap.String("trade_time", data.TradeTime))
+				continue
+			}
+
+			records = append(records, models.StockMinute{
+				TSCode:    data.TSCode,
+				Freq:      freq,
+				TradeTime: tradeTime,
+				Open:      data.Open,
+				High:      data.High,
+				Low:       data.Low,
+				Close:     data.Close,
+				Vol:       data.Vol,
+				Amount:    data.Amount,
+			})
+		}
+
+		n, err := saveRecords(f, f.db, records, batchSize, []string{"ts_code", "freq", "trade_time"}, func(tx *gorm.DB) *gorm.DB {
+			tsCodes := make([]string, 0, len(records))
+			dates := make([]time.Time, 0, len(records))
+			for _, r := range records {
+				tsCodes = append(tsCodes, r.TSCode)
+				dates = append(dates, r.TradeTime)
+			}
+			return codeDateScope(tx.Where("freq = ?", freq), "trade_time", tsCodes, dates)
+		})
+		if err != nil {
+			return stored, err
+		}
+		stored += n
+	}
+
+	return stored, nil
+}
+
+// FetchStockCompany 抓取上市公司基本信息（按股票逐个抓取）
+func (f *DataFetcher) FetchStockCompany(ctx context.Context) (*models.FetchTask, error) {
+	// 创建任务记录
+	task := &models.FetchTask{
+		TaskID:    newTaskID("company_task_"),
+		Status:    models.TaskStatusRunning,
+		StartTime: time.Now(),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	// 获取股票列表
+	stocks, err := f.loadStocks(ctx)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+
+	task.TotalCount = len(stocks)
+	f.saveTask(task)
+
+	f.logger.Info("开始抓取上市公司信息",
+		zap.String("task_id", task.TaskID),
+		zap.Int("stocks", len(stocks)))
+
+	// 使用 errgroup 并发抓取
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+
+	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	ctx = withCallCounter(ctx, rows)
+
+	for _, stock := range stocks {
+		tsCode := stock.TSCode
+
+		g.Go(func() error {
+			// 限流
+			if err := f.waitTushare(ctx); err != nil {
+				return err
+			}
+
+			companies, err := f.tushareClient.GetStockCompany(ctx, tsCode)
+			if err == nil {
+				var stored int
+				stored, err = f.batchUpsertStockCompany(companies)
+				rows.record("stock_company", len(companies), stored)
+			}
+
+			if err != nil {
+				atomic.AddInt64(&failedCount, 1)
+				f.recordFailure(task.TaskID, "", tsCode, err)
+				f.logger.Error("抓取上市公司信息失败",
+					zap.String("task_id", task.TaskID),
+					zap.String("ts_code", tsCode),
+					zap.Error(err))
+			} else {
+				atomic.AddInt64(&successCount, 1)
+			}
+
+			// 更新进度
+			total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+			if total%100 == 0 {
+				progress := int(total * 100 / int64(task.TotalCount))
+				f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+			}
+
+			return nil
+		})
+	}
+
+	// 等待所有任务完成
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	// 更新任务状态
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	rows.applyTo(task)
+	f.saveTask(task)
+
+	f.logger.Info("上市公司信息抓取完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount))
+
+	return task, nil
+}
+
+// batchUpsertStockCompany 按 insert_mode 批量保存上市公司信息（冲突键为 ts_code）
+func (f *DataFetcher) batchUpsertStockCompany(companies []StockCompanyData) (int, error) {
+	if len(companies) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.StockCompany, 0, len(companies))
+	for _, data := range companies {
+		records = append(records, models.StockCompany{
+			TSCode:        data.TSCode,
+			Exchange:      data.Exchange,
+			Chairman:      data.Chairman,
+			Manager:       data.Manager,
+			Secretary:     data.Secretary,
+			RegCapital:    data.RegCapital,
+			SetupDate:     data.SetupDate,
+			Province:      data.Province,
+			City:          data.City,
+			Website:       data.Website,
+			Email:         data.Email,
+			Employees:     data.Employees,
+			MainBusiness:  data.MainBusiness,
+			BusinessScope: data.BusinessScope,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+		}
+		return tx.Where("ts_code IN ?", tsCodes)
+	})
+}
+
+// FetchTopList 按交易日抓取龙虎榜数据
+func (f *DataFetcher) FetchTopList(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "top_list_task_",
+		name:      "龙虎榜数据",
+		unit:      unitDate,
+		startDate: startDate,
+		endDate:   endDate,
+		units:     f.tradeDateUnits(startDate, endDate),
+		fetch: func(ctx context.Context, date string, rows *rowTracker) (int, error) {
+			topList, err := f.tushareClient.GetTopList(ctx, date)
+			if err != nil {
+				return 0, err
+			}
+			// 当日无上榜股票也算成功
+			stored, err := f.batchUpsertTopList(topList)
+			rows.record("top_list", len(topList), stored)
+			return len(topList), err
+		},
+	})
+}
+
+// batchUpsertTopList 按 insert_mode 批量保存龙虎榜数据
+func (f *DataFetcher) batchUpsertTopList(topList []TopListData) (int, error) {
+	if len(topList) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.TopListEntry, 0, len(topList))
+	for _, data := range topList {
+		tradeDate, err := ParseDate(data.TradeDate)
+		if err != nil {
+			f.logger.Warn("龙虎榜交易日期格式错误", zap.String("trade_date", data.TradeDate))
+			continue
+		}
+
+		records = append(records, models.TopListEntry{
+			TradeDate:    tradeDate,
+			TSCode:       data.TSCode,
+			Reason:       data.Reason,
+			Name:         data.Name,
+			Close:        data.Close,
+			PctChange:    data.PctChange,
+			TurnoverRate: data.TurnoverRate,
+			Amount:       data.Amount,
+			LSell:        data.LSell,
+			LBuy:         data.LBuy,
+			LAmount:      data.LAmount,
+			NetAmount:    data.NetAmount,
+			NetRate:      data.NetRate,
+			AmountRate:   data.AmountRate,
+			FloatValues:  data.FloatValues,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"trade_date", "ts_code", "reason"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.TradeDate)
+		}
+		return codeDateScope(tx, "trade_date", tsCodes, dates)
+	})
+}
+
+// FetchBlockTrade 按交易日抓取大宗交易明细
+func (f *DataFetcher) FetchBlockTrade(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "block_trade_task_",
+		name:      "大宗交易数据",
+		unit:      unitDate,
+		startDate: startDate,
+		endDate:   endDate,
+		units:     f.tradeDateUnits(startDate, endDate),
+		fetch: func(ctx context.Context, date string, rows *rowTracker) (int, error) {
+			blockTrades, err := f.tushareClient.GetBlockTrade(ctx, date)
+			if err != nil {
+				return 0, err
+			}
+			// 当日无大宗交易也算成功，同样清除该日已有记录
+			stored, err := f.replaceBlockTrades(date, blockTrades)
+			rows.record("block_trade", len(blockTrades), stored)
+			return len(blockTrades), err
+		},
+	})
+}
+
+// replaceBlockTrades 在事务中删除该交易日已有的大宗交易记录后写入新数据
+// 大宗交易没有唯一键，整体替换使重复抓取不会产生重复记录
+func (f *DataFetcher) replaceBlockTrades(tradeDate string, blockTrades []BlockTradeData) (int, error) {
+	date, err := ParseDate(tradeDate)
+	if err != nil {
+		return 0, fmt.Errorf("日期格式错误: %w", err)
+	}
+
+	records := make([]models.BlockTrade, 0, len(blockTrades))
+	for _, data := range blockTrades {
+		if data.TradeDate != tradeDate {
+			f.logger.Warn("大宗交易日期与请求日期不一致",
+				zap.String("trade_date", data.TradeDate),
+				zap.String("date", tradeDate))
+			continue
+		}
+
+		records = append(records, models.BlockTrade{
+			TradeDate: date,
+			TSCode:    data.TSCode,
+			Price:     data.Price,
+			Vol:       data.Vol,
+			Amount:    data.Amount,
+			Buyer:     data.Buyer,
+			Seller:    data.Seller,
+		})
+	}
+	if err := roundDecimals(records, f.config.DecimalRounding); err != nil {
+		return 0, err
+	}
+
+	err = f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trade_date = ?", date).Delete(&models.BlockTrade{}).Error; err != nil {
+			return fmt.Errorf("删除已有数据失败: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(records, f.config.BatchSize).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// FetchMarginDetail 按交易日抓取融资融券交易明细
+func (f *DataFetcher) FetchMarginDetail(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "margin_task_",
+		name:      "融资融券数据",
+		unit:      unitDate,
+		startDate: startDate,
+		endDate:   endDate,
+		units:     f.tradeDateUnits(startDate, endDate),
+		fetch: func(ctx context.Context, date string, rows *rowTracker) (int, error) {
+			marginData, err := f.tushareClient.GetMarginDetail(ctx, date)
+			if err != nil {
+				return 0, err
+			}
+			// 当日无融资融券数据也算成功
+			stored, err := f.batchUpsertMarginDetail(marginData)
+			rows.record("margin_detail", len(marginData), stored)
+			return len(marginData), err
+		},
+	})
+}
+
+// batchUpsertMarginDetail 按 insert_mode 批量保存融资融券数据
+func (f *DataFetcher) batchUpsertMarginDetail(marginData []MarginDetailData) (int, error) {
+	if len(marginData) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.MarginDetail, 0, len(marginData))
+	for _, data := range marginData {
+		tradeDate, err := ParseDate(data.TradeDate)
+		if err != nil {
+			f.logger.Warn("融资融券交易日期格式错误", zap.String("trade_date", data.TradeDate))
+			continue
+		}
+
+		records = append(records, models.MarginDetail{
+			TSCode:    data.TSCode,
+			TradeDate: tradeDate,
+			Rzye:      data.Rzye,
+			Rqye:      data.Rqye,
+			Rzmre:     data.Rzmre,
+			Rqyl:      data.Rqyl,
+			Rzche:     data.Rzche,
+			Rqchl:     data.Rqchl,
+			Rqmcl:     data.Rqmcl,
+			Rzrqye:    data.Rzrqye,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.TradeDate)
+		}
+		return codeDateScope(tx, "trade_date", tsCodes, dates)
+	})
+}
+
+// FetchAdjFactor 按交易日抓取复权因子
+func (f *DataFetcher) FetchAdjFactor(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "adj_factor_task_",
+		name:      "复权因子",
+		unit:      unitDate,
+		startDate: startDate,
+		endDate:   endDate,
+		units:     f.tradeDateUnits(startDate, endDate),
+		fetch: func(ctx context.Context, date string, rows *rowTracker) (int, error) {
+			adjData, err := f.tushareClient.GetAdjFactor(ctx, date)
+			if err != nil {
+				return 0, err
+			}
+			// 当日无复权因子也算成功
+			stored, err := f.batchUpsertAdjFactor(adjData)
+			rows.record("adj_factor", len(adjData), stored)
+			return len(adjData), err
+		},
+	})
+}
+
+// batchUpsertAdjFactor 按 insert_mode 批量保存复权因子
+func (f *DataFetcher) batchUpsertAdjFactor(adjData []AdjFactorData) (int, error) {
+	if len(adjData) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.StockAdjFactor, 0, len(adjData))
+	for _, data := range adjData {
+		tradeDate, err := ParseDate(data.TradeDate)
+		if err != nil {
+			f.logger.Warn("复权因子交易日期格式错误", zap.String("trade_date", data.TradeDate))
+			continue
+		}
+
+		records = append(records, models.StockAdjFactor{
+			TSCode:    data.TSCode,
+			TradeDate: tradeDate,
+			AdjFactor: data.AdjFactor,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.TradeDate)
+		}
+		return codeDateScope(tx, "trade_date", tsCodes, dates)
+	})
 }
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestFetcher 创建使用内存 SQLite 的抓取服务，用于测试入库逻辑
+func newTestFetcher(t testing.TB, tables ...interface{}) *DataFetcher {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(tables...))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库只在单个连接内可见
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	cfg := &config.FetcherConfig{
+		Concurrency: 1,
+		BatchSize:   100,
+	}
+	return &DataFetcher{
+		db:          db,
+		config:      cfg,
+		logger:      zap.NewNop(),
+		rateLimiter: NewRateLimiter(0),
+		throttle:    NewInsertThrottle(cfg.Concurrency, 0),
+	}
+}
+
+// TestInsertDailyData_NullValues 测试 Tushare 返回 null 时入库为 NULL 而不是 0
+func TestInsertDailyData_NullValues(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+
+	data := &TushareData{
+		Fields: []string{"ts_code", "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount"},
+		Items: [][]interface{}{
+			{"000001.SZ", "20231201", nil, nil, 10.2, 10.8, nil, 0.0, nil, nil, nil},
+		},
+	}
+	dailyData, err := (&TushareClient{}).parseDailyData(data)
+	require.NoError(t, err)
+	count, err := fetcher.batchInsertDailyData(dailyData)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	var row struct {
+		Open   sql.NullFloat64
+		Vol    sql.NullFloat64
+		Low    sql.NullFloat64
+		Change sql.NullFloat64
+	}
+	require.NoError(t, fetcher.db.Model(&models.StockDaily{}).
+		Select("open, vol, low, change").
+		Where("ts_code = ?", "000001.SZ").
+		Scan(&row).Error)
+
+	assert.False(t, row.Open.Valid, "open 应为 NULL")
+	assert.False(t, row.Vol.Valid, "vol 应为 NULL")
+	assert.True(t, row.Low.Valid)
+	assert.Equal(t, 10.2, row.Low.Float64)
+	// 真实的 0 仍然保存为 0
+	assert.True(t, row.Change.Valid)
+	assert.Equal(t, 0.0, row.Change.Float64)
+
+	// 查询结果中缺失值为 nil
+	var stored models.StockDaily
+	require.NoError(t, fetcher.db.First(&stored).Error)
+	assert.Nil(t, stored.Open)
+	require.NotNil(t, stored.Close)
+	assert.Equal(t, 10.8, *stored.Close)
+}
+
+// TestAggregateWeeklyBar 测试日线聚合周线的取值规则
+func TestAggregateWeeklyBar(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	weekEnd := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	daily := []models.StockDaily{
+		{TSCode: "000001.SZ", Open: nil, High: f(10.5), Low: f(9.8), Close: f(10.2), PreClose: f(10.0), Vol: f(100), Amount: nil},
+		{TSCode: "000001.SZ", Open: f(10.3), High: f(11.0), Low: f(10.1), Close: f(10.9), Vol: nil, Amount: f(2000)},
+		{TSCode: "000001.SZ", Open: f(10.8), High: nil, Low: f(10.4), Close: nil, Vol: f(50), Amount: f(500)},
+	}
+
+	bar := aggregateWeeklyBar(daily, weekEnd)
+
+	assert.Equal(t, weekEnd, bar.TradeDate)
+	assert.Equal(t, 10.3, *bar.Open)
+	assert.Equal(t, 11.0, *bar.High)
+	assert.Equal(t, 9.8, *bar.Low)
+	assert.Equal(t, 10.9, *bar.Close)
+	assert.Equal(t, 150.0, *bar.Vol)
+	assert.Equal(t, 2500.0, *bar.Amount)
+	assert.InDelta(t, 0.9, *bar.Change, 1e-9)
+	assert.InDelta(t, 9.0, *bar.PctChg, 1e-9)
+	assert.Nil(t, bar.CloseQfq)
+}
+
+// TestGroupTradeWeeks 测试交易日按 ISO 周分组
+func TestGroupTradeWeeks(t *testing.T) {
+	weeks := groupTradeWeeks([]string{"20231127", "20231129", "20231201", "20231204", "20231208"})
+
+	require.Len(t, weeks, 2)
+	assert.Equal(t, "20231201", weeks[0].Last().Format("20060102"))
+	assert.Len(t, weeks[0].Dates, 3)
+	assert.Equal(t, "20231204", weeks[1].First().Format("20060102"))
+	assert.Equal(t, 49, weeks[1].Week)
+}
+
+// TestGenerateWeekDateRange_YearEnd 测试跨年的周按 ISO 周分组，取每周最后一个交易日
+func TestGenerateWeekDateRange_YearEnd(t *testing.T) {
+	// 2024-01-01、2025-01-01 元旦休市
+	tradeDates := []string{
+		"20231225", "20231226", "20231227", "20231228", "20231229",
+		"20240102", "20240103", "20240104", "20240105",
+		"20240108", "20240109", "20240110", "20240111", "20240112",
+		"20241223", "20241224", "20241225", "20241226", "20241227",
+		"20241230", "20241231", "20250102", "20250103",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		start, _ := req.Params["start_date"].(string)
+		end, _ := req.Params["end_date"].(string)
+
+		var items [][]interface{}
+		for _, date := range tradeDates {
+			if date >= start && date <= end {
+				items = append(items, []interface{}{req.Params["exchange"], date, 1.0})
+			}
+		}
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"exchange", "cal_date", "is_open"}, Items: items})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	// 2023-12-29 为 2023 年第 52 周的最后一个交易日，2024-01-01 起为 2024 年第 1 周
+	assert.Equal(t, []string{"20231229", "20240105", "20240112"}, fetcher.generateWeekDateRange(context.Background(), "20231225", "20240112"))
+	// 2024-12-30、2024-12-31 属于 2025 年第 1 周，与 2025 年 1 月初合并为一周
+	assert.Equal(t, []string{"20241227", "20250103"}, fetcher.generateWeekDateRange(context.Background(), "20241223", "20250103"))
+
+	// 降级方案按工作日分组，同样取每周最后一天
+	assert.Equal(t, []string{"20231229", "20240105", "20240112"}, fetcher.generateWeekDateRangeFallback("20231225", "20240112"))
+	assert.Equal(t, []string{"20241227", "20250103"}, fetcher.generateWeekDateRangeFallback("20241223", "20250103"))
+}
+
+// TestGetTradeCalendar_MergeExchanges 测试沪深交易日历不一致时取并集并记录开市的交易所
+func TestGetTradeCalendar_MergeExchanges(t *testing.T) {
+	calendars := map[string][][]interface{}{
+		"SSE":  {{"SSE", "20231201", 1.0}, {"SSE", "20231204", 1.0}},
+		"SZSE": {{"SZSE", "20231201", 1.0}, {"SZSE", "20231205", 1.0}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		exchange, _ := req.Params["exchange"].(string)
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"exchange", "cal_date", "is_open"},
+			Items:  calendars[exchange],
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	days, err := fetcher.getTradeCalendar(context.Background(), "20231201", "20231205")
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+	assert.Equal(t, TradeDay{Date: "20231201", Exchanges: []string{"SSE", "SZSE"}}, days[0])
+	assert.Equal(t, TradeDay{Date: "20231204", Exchanges: []string{"SSE"}}, days[1])
+	assert.Equal(t, TradeDay{Date: "20231205", Exchanges: []string{"SZSE"}}, days[2])
+
+	dates, err := fetcher.getTradeDates(context.Background(), "20231201", "20231205")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231201", "20231204", "20231205"}, dates)
+}
+
+// TestReplaceBlockTrades 测试重复抓取同一交易日时整体替换，价格、成交量相同的多笔交易均保留
+func TestReplaceBlockTrades(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.BlockTrade{})
+
+	price1, vol1, amount1 := 10.5, 100.0, 1050.0
+	price2, vol2, amount2 := 7.123, 50.0, 356.15
+	trades := []BlockTradeData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Price: &price1, Vol: &vol1, Amount: &amount1, Buyer: "机构专用", Seller: "中信证券上海分公司"},
+		{TSCode: "000001.SZ", TradeDate: "20231201", Price: &price1, Vol: &vol1, Amount: &amount1, Buyer: "机构专用", Seller: "中信证券上海分公司"},
+		// Tushare 返回 null 的字段保存为 NULL
+		{TSCode: "600000.SH", TradeDate: "20231201", Price: &price2, Vol: &vol2, Amount: &amount2, Buyer: "机构专用", Seller: "机构专用"},
+		{TSCode: "600036.SH", TradeDate: "20231201", Buyer: "机构专用", Seller: "机构专用"},
+	}
+	for i := 0; i < 2; i++ {
+		stored, err := fetcher.replaceBlockTrades("20231201", trades)
+		require.NoError(t, err)
+		assert.Equal(t, 4, stored)
+	}
+	_, err := fetcher.replaceBlockTrades("20231204", trades[:1])
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.BlockTrade{}).Count(&count).Error)
+	assert.Equal(t, int64(4), count, "20231204 的数据日期不一致，不写入")
+
+	var price float64
+	require.NoError(t, fetcher.db.Model(&models.BlockTrade{}).Where("ts_code = ?", "600000.SH").Pluck("price", &price).Error)
+	assert.Equal(t, 7.12, price)
+	var nullPrice models.BlockTrade
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "600036.SH").First(&nullPrice).Error)
+	assert.Nil(t, nullPrice.Price)
+	assert.Nil(t, nullPrice.Amount)
+
+	// 当日无大宗交易时清除已有记录
+	stored, err := fetcher.replaceBlockTrades("20231201", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+	require.NoError(t, fetcher.db.Model(&models.BlockTrade{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+// TestBatchUpsertTopList_RowMetrics 测试日期解析失败被丢弃的行不计入入库行数
+func TestBatchUpsertTopList_RowMetrics(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.TopListEntry{})
+
+	topList := []TopListData{
+		{TradeDate: "20231201", TSCode: "000001.SZ", Reason: "日涨幅偏离值达到7%"},
+		{TradeDate: "2023-12-01", TSCode: "000002.SZ", Reason: "日涨幅偏离值达到7%"},
+	}
+	stored, err := fetcher.batchUpsertTopList(topList)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	rows := newRowTracker(models.RowMetrics{"top_list": {Fetched: 10, Stored: 10}})
+	rows.record("top_list", len(topList), stored)
+	rows.record("daily", 3, 3)
+
+	task := &models.FetchTask{}
+	rows.applyTo(task)
+	assert.Equal(t, models.RowCount{Fetched: 12, Stored: 11}, task.RowMetrics["top_list"])
+	assert.Equal(t, int64(15), task.RowsFetched)
+	assert.Equal(t, int64(14), task.RowsStored)
+}
+
+// TestFinishTask_Timeout 测试任务超时后标记为 timeout 而不是 completed
+func TestFinishTask_Timeout(t *testing.T) {
+	fetcher := newTestFetcher(t)
+	fetcher.config.TaskTimeout = 1
+
+	task := &models.FetchTask{TaskID: "task_1", Status: models.TaskStatusRunning, StartTime: time.Now()}
+	fetcher.finishTask(task, context.DeadlineExceeded)
+
+	assert.Equal(t, models.TaskStatusTimeout, task.Status)
+	assert.Contains(t, task.ErrorMsg, "task_timeout")
+	assert.True(t, task.Status.CanTransitionTo(models.TaskStatusRunning))
+}
+
+// TestInFetchWindow 测试抓取时段按 Asia/Shanghai 判断，并支持跨越零点
+func TestInFetchWindow(t *testing.T) {
+	f := &DataFetcher{config: &config.FetcherConfig{}}
+	at := func(hour, minute int) time.Time {
+		// 北京时间 = UTC+8
+		return time.Date(2023, 12, 1, hour, minute, 0, 0, time.UTC).Add(-8 * time.Hour)
+	}
+
+	assert.True(t, f.InFetchWindow(at(10, 0)), "未配置时不限制")
+
+	f.config.AllowedHours = "18:00-23:00"
+	assert.False(t, f.InFetchWindow(at(17, 59)))
+	assert.True(t, f.InFetchWindow(at(18, 0)))
+	assert.True(t, f.InFetchWindow(at(22, 59)))
+	assert.False(t, f.InFetchWindow(at(23, 0)))
+
+	f.config.AllowedHours = "22:00-06:00"
+	assert.True(t, f.InFetchWindow(at(23, 30)))
+	assert.True(t, f.InFetchWindow(at(5, 59)))
+	assert.False(t, f.InFetchWindow(at(12, 0)))
+}
+
+// TestInsertDailyData_Amplitude 测试开启 compute_amplitude 时写入振幅，昨收价为 0 或缺失时为空
+func TestInsertDailyData_Amplitude(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+
+	assert.Nil(t, dailyAmplitude(price(10.5), price(9.8), price(0)))
+	assert.Nil(t, dailyAmplitude(price(10.5), nil, price(10)))
+	assert.InDelta(t, 7.0, *dailyAmplitude(price(10.5), price(9.8), price(10)), 1e-9)
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	incoming := []StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", High: price(10.5), Low: price(9.8), PreClose: price(10)},
+		{TSCode: "000002.SZ", TradeDate: "20231201", High: price(3.33), Low: price(3.01), PreClose: price(3.1)},
+		{TSCode: "000003.SZ", TradeDate: "20231201", High: price(5), Low: price(4), PreClose: price(0)},
+	}
+
+	// 未开启时不计算
+	_, err := fetcher.batchInsertDailyData(incoming[:1])
+	require.NoError(t, err)
+	var row models.StockDaily
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "000001.SZ").First(&row).Error)
+	assert.Nil(t, row.Amplitude)
+
+	fetcher.config.ComputeAmplitude = true
+	_, err = fetcher.batchInsertDailyData(incoming)
+	require.NoError(t, err)
+
+	amplitudes := make(map[string]*float64)
+	var rows []models.StockDaily
+	require.NoError(t, fetcher.db.Find(&rows).Error)
+	for _, r := range rows {
+		amplitudes[r.TSCode] = r.Amplitude
+	}
+	require.NotNil(t, amplitudes["000001.SZ"])
+	assert.Equal(t, 7.0, *amplitudes["000001.SZ"])
+	// 按 decimal(10,4) 舍入：0.32 / 3.1 × 100 = 10.32258…
+	require.NotNil(t, amplitudes["000002.SZ"])
+	assert.Equal(t, 10.3226, *amplitudes["000002.SZ"])
+	assert.Nil(t, amplitudes["000003.SZ"])
+}
+
+// TestRunDailyDates_DateRetry 测试日期第一次抓取失败后按 date_retry 重新抓取成功，未配置时计为失败；
+// Tushare 返回的业务错误不重新抓取
+func TestRunDailyDates_DateRetry(t *testing.T) {
+	cases := []struct {
+		name          string
+		dateRetry     int
+		insertWorkers int
+		apiError      bool
+		wantFailed    int
+		wantCalls     int64
+	}{
+		{name: "no_retry", dateRetry: 0, wantFailed: 1, wantCalls: 2},
+		{name: "retry", dateRetry: 1, wantFailed: 0, wantCalls: 3},
+		{name: "retry_insert_workers", dateRetry: 1, insertWorkers: 2, wantFailed: 0, wantCalls: 3},
+		{name: "api_error", dateRetry: 1, apiError: true, wantFailed: 1, wantCalls: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// 20231204 第一次请求返回错误，之后正常返回
+			var failed int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req TushareRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				date, _ := req.Params["trade_date"].(string)
+				if date == "20231204" && atomic.CompareAndSwapInt32(&failed, 0, 1) {
+					if tc.apiError {
+						json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+						return
+					}
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				dataBytes, _ := json.Marshal(TushareData{
+					Fields: []string{"ts_code", "trade_date", "close"},
+					Items:  [][]interface{}{{"000001.SZ", date, 10.5}},
+				})
+				json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+			}))
+			defer server.Close()
+
+			fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{}, &models.FetchTask{}, &models.FetchTaskDate{})
+			fetcher.config.DateRetry = tc.dateRetry
+			fetcher.config.InsertWorkers = tc.insertWorkers
+			fetcher.rateLimiter = NewRateLimiter(0)
+			fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+			dates := []string{"20231201", "20231204"}
+			task := &models.FetchTask{TaskID: "task_" + tc.name, Status: models.TaskStatusRunning, StartTime: time.Now(), TotalCount: len(dates)}
+			require.NoError(t, fetcher.db.Create(task).Error)
+			fetcher.runDailyDates(context.Background(), task, dates, 0, false)
+
+			assert.Equal(t, tc.wantFailed, task.FailedCount)
+			assert.Equal(t, len(dates)-tc.wantFailed, task.SuccessCount)
+			assert.Equal(t, tc.wantCalls, task.APICalls)
+
+			var count int64
+			require.NoError(t, fetcher.db.Model(&models.StockDaily{}).Count(&count).Error)
+			assert.Equal(t, int64(len(dates)-tc.wantFailed), count)
+		})
+	}
+}
+
+// TestRunDailyDates_ResumeReplacesPartialDate 测试续传时先删除中断前已写入的部分数据再写入，
+// 即使 insert_mode 为 skip 也不会保留中断前的旧数据
+func TestRunDailyDates_ResumeReplacesPartialDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "close"},
+			Items:  [][]interface{}{{"000001.SZ", "20231204", 10.5}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{}, &models.FetchTask{}, &models.FetchTaskDate{})
+	fetcher.config.InsertMode = InsertModeSkip
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+	// 中断前已写入部分数据
+	date, _ := ParseDate("20231204")
+	closePrice, stalePrice := 9.0, 8.0
+	require.NoError(t, fetcher.db.Create([]models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: date, Close: &closePrice},
+		{TSCode: "000002.SZ", TradeDate: date, Close: &stalePrice},
+	}).Error)
+
+	task := &models.FetchTask{TaskID: "task_resume", Status: models.TaskStatusRunning, StartTime: time.Now(), TotalCount: 2}
+	require.NoError(t, fetcher.db.Create(task).Error)
+	fetcher.runDailyDates(context.Background(), task, []string{"20231204"}, 1, true)
+
+	assert.Equal(t, 2, task.SuccessCount)
+	var stored []models.StockDaily
+	require.NoError(t, fetcher.db.Find(&stored).Error)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "000001.SZ", stored[0].TSCode)
+	assert.InDelta(t, 10.5, *stored[0].Close, 1e-9)
+}
+
+// TestFetchDailySync_ContextCancel 测试调用方的 ctx 传递到 Tushare 请求，取消后立即中止进行中的请求
+func TestFetchDailySync_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fetcher := newTestFetcher(t, &models.TradeCalendar{})
+	fetcher.config.CalendarFallback = "weekend_filter"
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 30}, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := fetcher.FetchDailySync(ctx, "000001.SZ", "20231201", "20231201")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// TestFetchConcepts_FailTask 测试获取分类列表失败时任务标记为失败，不会一直处于运行中
+func TestFetchConcepts_FailTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.FetchTask{})
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+	task, err := fetcher.FetchConcepts(context.Background())
+	require.Error(t, err)
+	require.NotNil(t, task)
+
+	var stored models.FetchTask
+	require.NoError(t, fetcher.db.Where("task_id = ?", task.TaskID).First(&stored).Error)
+	assert.Equal(t, models.TaskStatusFailed, stored.Status)
+	assert.Contains(t, stored.ErrorMsg, "获取概念分类失败")
+	assert.NotNil(t, stored.EndTime)
+}
+
+// TestRefetchDailyDate_NoData 测试 Tushare 未返回数据时重新抓取报错，不删除该日期已有的日线
+func TestRefetchDailyDate_NoData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"ts_code", "trade_date", "close"}})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	date, err := ParseDate("20231201")
+	require.NoError(t, err)
+	require.NoError(t, fetcher.db.Create(&models.StockDaily{TSCode: "000001.SZ", TradeDate: date}).Error)
+
+	_, err = fetcher.RefetchDailyDate(context.Background(), "20231201")
+	assert.ErrorIs(t, err, ErrNoDailyData)
+
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.StockDaily{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
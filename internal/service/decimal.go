@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// 数值写入前按列小数位数舍入的方式，对应 fetcher.decimal_rounding
+const (
+	DecimalRoundingHalfUp   = "half_up"   // 四舍五入（远离零），与 MySQL、PostgreSQL 对 decimal 的舍入一致
+	DecimalRoundingHalfEven = "half_even" // 银行家舍入，恰好一半时舍入到偶数
+	DecimalRoundingNone     = "none"      // 不处理，由数据库驱动和数据库自行舍入
+)
+
+// decimalTypePattern 匹配模型 gorm 标签中的 decimal(p,s) / numeric(p,s)
+var decimalTypePattern = regexp.MustCompile(`(?i)^\s*(?:decimal|numeric)\s*\(\s*(\d+)\s*,\s*(\d+)\s*\)`)
+
+// maxExactFloat 超过该值的浮点数已没有小数部分，无需舍入（同时排除 ±Inf）
+const maxExactFloat = 1 << 53
+
+// decimalField 模型中声明为 decimal 的浮点字段
+type decimalField struct {
+	index []int
+	scale int
+}
+
+// decimalFieldCache 按模型类型缓存 decimal 字段，避免每批数据重复解析
+var decimalFieldCache sync.Map // reflect.Type -> []decimalField
+
+// decimalFields 解析模型中声明为 decimal(p,s) 的 float64 / *float64 字段
+// 声明为 decimal 的字段不是浮点类型时返回错误，这说明模型与列类型不一致
+func decimalFields(modelType reflect.Type) ([]decimalField, error) {
+	if cached, ok := decimalFieldCache.Load(modelType); ok {
+		return cached.([]decimalField), nil
+	}
+
+	s, err := schema.Parse(reflect.New(modelType).Interface(), &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		return nil, fmt.Errorf("解析模型 %s 失败: %w", modelType.Name(), err)
+	}
+
+	var fields []decimalField
+	for _, field := range s.Fields {
+		match := decimalTypePattern.FindStringSubmatch(field.TagSettings["TYPE"])
+		if match == nil {
+			continue
+		}
+
+		fieldType := field.FieldType
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() != reflect.Float64 {
+			return nil, fmt.Errorf("模型 %s 的字段 %s 声明为 %s，但类型为 %s", modelType.Name(), field.Name, match[0], field.FieldType)
+		}
+
+		precision, _ := strconv.Atoi(match[1])
+		scale, _ := strconv.Atoi(match[2])
+		if scale > precision {
+			return nil, fmt.Errorf("模型 %s 的字段 %s 小数位数大于精度: %s", modelType.Name(), field.Name, match[0])
+		}
+		fields = append(fields, decimalField{index: field.StructField.Index, scale: scale})
+	}
+
+	decimalFieldCache.Store(modelType, fields)
+	return fields, nil
+}
+
+// roundDecimals 将记录中的 decimal 字段按列声明的小数位数舍入
+// 不同数据库和驱动对超出小数位数的值处理不一致（截断或按不同规则舍入），写入前统一舍入后各数据库存储的值相同
+func roundDecimals[T any](records []T, mode string) error {
+	if mode == DecimalRoundingNone || len(records) == 0 {
+		return nil
+	}
+
+	modelType := reflect.TypeOf(records).Elem()
+	if modelType.Kind() != reflect.Struct {
+		return nil
+	}
+	fields, err := decimalFields(modelType)
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+
+	for i := range records {
+		record := reflect.ValueOf(&records[i]).Elem()
+		for _, field := range fields {
+			value := record.FieldByIndex(field.index)
+			if value.Kind() != reflect.Ptr {
+				value.SetFloat(roundDecimal(value.Float(), field.scale, mode))
+				continue
+			}
+			// 指针字段通常与抓取到的原始数据共用，写入新值而不修改原值
+			if !value.IsNil() {
+				rounded := roundDecimal(value.Elem().Float(), field.scale, mode)
+				value.Set(reflect.ValueOf(&rounded))
+			}
+		}
+	}
+	return nil
+}
+
+// roundDecimal 按十进制小数位数舍入浮点数
+// 以浮点数的最短十进制表示（即写入数据库时的文本）为准进行舍入，10.125 按 half_up 得到 10.13，
+// 而不是按其二进制近似值 10.12499… 得到 10.12
+func roundDecimal(value float64, scale int, mode string) float64 {
+	if mode == DecimalRoundingNone || math.IsNaN(value) || math.Abs(value) > maxExactFloat {
+		return value
+	}
+
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+	if !ok {
+		return value
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	r.Mul(r, new(big.Rat).SetInt(unit))
+
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// 比较余数的两倍与分母，判断舍去部分是否大于、等于或小于一半
+		cmp := new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(r.Denom())
+		if cmp > 0 || (cmp == 0 && (mode != DecimalRoundingHalfEven || quo.Bit(0) == 1)) {
+			quo.Add(quo, big.NewInt(int64(rem.Sign())))
+		}
+	}
+
+	text := quo.String()
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+	if scale > 0 {
+		if len(text) <= scale {
+			text = strings.Repeat("0", scale-len(text)+1) + text
+		}
+		text = text[:len(text)-scale] + "." + text[len(text)-scale:]
+	}
+	if negative {
+		text = "-" + text
+	}
+	rounded, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return value
+	}
+	return rounded
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tushareFieldAliases 同一含义的列在不同 Tushare 接口中的其他名称，按 json 标签中的列名查找
+// 返回数据中没有该列时依次尝试别名，新接口命名不同或 Tushare 改名时在此补充即可
+var tushareFieldAliases = map[string][]string{
+	"vol":       {"volume"},
+	"amount":    {"amt"},
+	"pct_chg":   {"pct_change"},
+	"pre_close": {"preclose"},
+}
+
+// decodeTushareData 通用解析器：按 json 标签将 Tushare 返回的列映射到结构体字段
+// 没有同名列时按 tushareFieldAliases 使用别名列
+// 支持 string、float64、*float64、int 类型字段，缺失列或 null 值保持零值（*float64 为 nil）
+func decodeTushareData[T any](data *TushareData) ([]T, error) {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("解析目标必须是结构体: %s", typ)
+	}
+
+	fieldMap := make(map[string]int)
+	for i, field := range data.Fields {
+		fieldMap[field] = i
+	}
+
+	// 预先计算结构体字段对应的列索引
+	type column struct {
+		field int
+		index int
+	}
+	columns := make([]column, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name := tushareFieldName(typ.Field(i))
+		if name == "" {
+			continue
+		}
+		if index, ok := lookupTushareColumn(fieldMap, name); ok {
+			columns = append(columns, column{field: i, index: index})
+		}
+	}
+
+	result := make([]T, 0, len(data.Items))
+	for _, item := range data.Items {
+		var row T
+		value := reflect.ValueOf(&row).Elem()
+		for _, col := range columns {
+			field := value.Field(col.field)
+			switch field.Kind() {
+			case reflect.String:
+				field.SetString(getString(item, col.index))
+			case reflect.Float64:
+				field.SetFloat(getFloat(item, col.index))
+			case reflect.Int:
+				field.SetInt(int64(getFloat(item, col.index)))
+			case reflect.Ptr:
+				if field.Type().Elem().Kind() != reflect.Float64 {
+					return nil, fmt.Errorf("不支持的字段类型: %s.%s", typ.Name(), typ.Field(col.field).Name)
+				}
+				if v := getFloatPtr(item, col.index); v != nil {
+					field.Set(reflect.ValueOf(v))
+				}
+			default:
+				return nil, fmt.Errorf("不支持的字段类型: %s.%s", typ.Name(), typ.Field(col.field).Name)
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, nil
+}
+
+// lookupTushareColumn 返回列名对应的列索引，没有同名列时按别名表顺序查找
+func lookupTushareColumn(fieldMap map[string]int, name string) (int, bool) {
+	if index, ok := fieldMap[name]; ok {
+		return index, true
+	}
+	for _, alias := range tushareFieldAliases[name] {
+		if index, ok := fieldMap[alias]; ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// tushareFields 根据结构体 json 标签生成请求的 fields 参数
+func tushareFields(v interface{}) string {
+	typ := reflect.TypeOf(v)
+	names := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		if name := tushareFieldName(typ.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// tushareFieldName 获取结构体字段对应的 Tushare 列名
+func tushareFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "" || tag == "-" {
+		return ""
+	}
+	return strings.Split(tag, ",")[0]
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeTushareDataAliases 测试没有同名列时按别名列填充字段，同名列优先于别名列
+func TestDecodeTushareDataAliases(t *testing.T) {
+	data := &TushareData{
+		Fields: []string{"ts_code", "trade_date", "volume", "amt", "close"},
+		Items: [][]interface{}{
+			{"000001.SZ", "20231201", 12345.0, 6789.5, 10.2},
+			{"600000.SH", "20231201", nil, 100.0, 7.1},
+		},
+	}
+
+	rows, err := decodeTushareData[StockDailyData](data)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.NotNil(t, rows[0].Vol)
+	assert.Equal(t, 12345.0, *rows[0].Vol)
+	require.NotNil(t, rows[0].Amount)
+	assert.Equal(t, 6789.5, *rows[0].Amount)
+	assert.Nil(t, rows[1].Vol)
+	assert.Equal(t, 100.0, *rows[1].Amount)
+	assert.Nil(t, rows[0].Open)
+
+	// 同时返回原列名和别名时使用原列名
+	data = &TushareData{
+		Fields: []string{"ts_code", "vol", "volume"},
+		Items:  [][]interface{}{{"000001.SZ", 1.0, 2.0}},
+	}
+	rows, err = decodeTushareData[StockDailyData](data)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 1.0, *rows[0].Vol)
+}
+
+// TestDecodeTushareDataAliasesNonPointer 测试别名同样适用于非指针字段
+func TestDecodeTushareDataAliasesNonPointer(t *testing.T) {
+	data := &TushareData{
+		Fields: []string{"ts_code", "trade_time", "volume", "amt"},
+		Items:  [][]interface{}{{"000001.SZ", "2023-12-01 09:31:00", 300.0, 3060.0}},
+	}
+
+	rows, err := decodeTushareData[StockMinuteData](data)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 300.0, rows[0].Vol)
+	assert.Equal(t, 3060.0, rows[0].Amount)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"time"
+)
+
+// assumedRequestLatency 尚无实际耗时时假定的单次抓取耗时，用于估算并发受限时的吞吐
+const assumedRequestLatency = time.Second
+
+// estimateEndTime 估算任务的完成时间，total_count 未知时返回 nil
+// 尚未完成任何单元时按限流间隔与并发吞吐中较慢的一个估算；之后按已运行时长计算的实际平均耗时估算，
+// 但不快于限流允许的速率
+func (f *DataFetcher) estimateEndTime(task *models.FetchTask, completed int, now time.Time) *time.Time {
+	if task.TotalCount <= 0 {
+		return nil
+	}
+	remaining := task.TotalCount - completed
+	if remaining <= 0 {
+		return &now
+	}
+
+	var rateInterval time.Duration
+	if f.config.RateLimit > 0 {
+		rateInterval = time.Minute / time.Duration(f.config.RateLimit)
+	}
+
+	interval := rateInterval
+	if completed > 0 && !task.StartTime.IsZero() {
+		interval = max(now.Sub(task.StartTime)/time.Duration(completed), rateInterval)
+	} else {
+		concurrency := max(f.config.Concurrency, 1)
+		interval = max(assumedRequestLatency/time.Duration(concurrency), rateInterval)
+	}
+
+	end := now.Add(interval * time.Duration(remaining))
+	return &end
+}
+
+// estimateRemainingCalls 估算任务还需发起的 Tushare 请求数，total_count 未知时返回 nil
+// 每个日期/股票单元的请求数按已完成单元的平均请求数（含失败和重试前的请求）估算，尚无完成单元时按 1 次计
+func estimateRemainingCalls(task *models.FetchTask) *int64 {
+	if task.TotalCount <= 0 {
+		return nil
+	}
+	completed := int64(task.SuccessCount + task.FailedCount)
+	remaining := max(int64(task.TotalCount)-completed, 0)
+	if completed == 0 || task.APICalls == 0 {
+		return &remaining
+	}
+	// 向上取整，避免低估配额消耗
+	calls := (remaining*task.APICalls + completed - 1) / completed
+	return &calls
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEstimateEndTime 测试开始时按限流和并发估算，执行中按实际吞吐估算且不快于限流
+func TestEstimateEndTime(t *testing.T) {
+	f := &DataFetcher{config: &config.FetcherConfig{RateLimit: 120, Concurrency: 10}}
+	start := time.Date(2023, 12, 1, 18, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, f.estimateEndTime(&models.FetchTask{StartTime: start}, 0, start), "总数未知")
+
+	task := &models.FetchTask{TotalCount: 100, StartTime: start}
+
+	// 限流 120 次/分钟即 0.5 秒一次，慢于并发 10 时的 0.1 秒
+	eta := f.estimateEndTime(task, 0, start)
+	require.NotNil(t, eta)
+	assert.Equal(t, start.Add(50*time.Second), *eta)
+
+	// 实际 20 秒完成 10 个，每个 2 秒，剩余 90 个
+	now := start.Add(20 * time.Second)
+	eta = f.estimateEndTime(task, 10, now)
+	require.NotNil(t, eta)
+	assert.Equal(t, now.Add(180*time.Second), *eta)
+
+	// 实际吞吐快于限流时按限流估算
+	now = start.Add(time.Second)
+	eta = f.estimateEndTime(task, 10, now)
+	require.NotNil(t, eta)
+	assert.Equal(t, now.Add(45*time.Second), *eta)
+
+	eta = f.estimateEndTime(task, 100, now)
+	require.NotNil(t, eta)
+	assert.Equal(t, now, *eta)
+}
+
+// TestUpdateTaskProgress_EstimatedEndTime 测试保存任务和更新进度时写入预计完成时间
+func TestUpdateTaskProgress_EstimatedEndTime(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.FetchTask{})
+	fetcher.config.RateLimit = 60
+
+	task := &models.FetchTask{TaskID: "task_1", Status: models.TaskStatusRunning, StartTime: time.Now(), TotalCount: 10}
+	fetcher.saveTask(task)
+	require.NotNil(t, task.EstimatedEndTime)
+	initial := *task.EstimatedEndTime
+
+	task.StartTime = time.Now().Add(-time.Minute)
+	fetcher.updateTaskProgress(task, 50, 5, 0, nil)
+
+	stored, err := fetcher.GetTaskProgress("task_1")
+	require.NoError(t, err)
+	require.NotNil(t, stored.EstimatedEndTime)
+	// 实际每个耗时 12 秒，剩余 5 个约 60 秒，晚于按限流估算的 10 秒
+	assert.True(t, stored.EstimatedEndTime.After(initial))
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *stored.EstimatedEndTime, 5*time.Second)
+}
+
+// TestEstimateRemainingCalls 测试按已完成单元的平均请求数估算剩余请求数
+func TestEstimateRemainingCalls(t *testing.T) {
+	assert.Nil(t, estimateRemainingCalls(&models.FetchTask{}), "总数未知")
+
+	// 尚未完成任何单元时每个单元按 1 次计
+	calls := estimateRemainingCalls(&models.FetchTask{TotalCount: 100})
+	require.NotNil(t, calls)
+	assert.Equal(t, int64(100), *calls)
+
+	// 完成 30 个（含 2 个失败）共 45 次请求，平均 1.5 次，剩余 70 个约 105 次
+	calls = estimateRemainingCalls(&models.FetchTask{TotalCount: 100, SuccessCount: 28, FailedCount: 2, APICalls: 45})
+	require.NotNil(t, calls)
+	assert.Equal(t, int64(105), *calls)
+
+	// 向上取整
+	calls = estimateRemainingCalls(&models.FetchTask{TotalCount: 4, SuccessCount: 3, APICalls: 4})
+	require.NotNil(t, calls)
+	assert.Equal(t, int64(2), *calls)
+
+	calls = estimateRemainingCalls(&models.FetchTask{TotalCount: 10, SuccessCount: 10, APICalls: 10})
+	require.NotNil(t, calls)
+	assert.Equal(t, int64(0), *calls)
+}
+
+// TestGetDailyTaskStatus 测试返回最近的日线任务，运行中的任务包含预计剩余请求数
+func TestGetDailyTaskStatus(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.FetchTask{})
+
+	_, err := fetcher.GetDailyTaskStatus("")
+	require.Error(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	require.NoError(t, fetcher.db.Create(&[]models.FetchTask{
+		{TaskID: "task_1", Status: models.TaskStatusCompleted, StartTime: start, TotalCount: 5, SuccessCount: 5, APICalls: 5},
+		{TaskID: "task_2", Status: models.TaskStatusRunning, StartTime: start.Add(time.Minute), TotalCount: 10, SuccessCount: 4, APICalls: 4},
+		{TaskID: "weekly_task_3", Status: models.TaskStatusRunning, StartTime: start.Add(2 * time.Minute), TotalCount: 10},
+	}).Error)
+
+	task, err := fetcher.GetDailyTaskStatus("")
+	require.NoError(t, err)
+	assert.Equal(t, "task_2", task.TaskID)
+	assert.Equal(t, int64(4), task.APICalls)
+	require.NotNil(t, task.EstimatedRemainingCalls)
+	assert.Equal(t, int64(6), *task.EstimatedRemainingCalls)
+
+	// 已结束的任务不估算
+	task, err = fetcher.GetDailyTaskStatus("task_1")
+	require.NoError(t, err)
+	assert.Nil(t, task.EstimatedRemainingCalls)
+
+	// 非日线任务
+	_, err = fetcher.GetDailyTaskStatus("weekly_task_3")
+	require.Error(t, err)
+}
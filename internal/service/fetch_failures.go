@@ -0,0 +1,45 @@
+package service
+
+import (
+	"stock_data/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// MigrateFailures 创建 fetch_failures 表，开启 fetcher.record_failures 时在启动时调用
+func (f *DataFetcher) MigrateFailures() error {
+	return f.db.AutoMigrate(&models.FetchFailure{})
+}
+
+// recordFailure 记录重试后仍失败的抓取单元，未开启 fetcher.record_failures 时忽略
+// date 为交易日期或报告期，tsCode 为股票代码，按日期抓取全部股票时 tsCode 为空
+func (f *DataFetcher) recordFailure(taskID, date, tsCode string, err error) {
+	if !f.config.RecordFailures || err == nil {
+		return
+	}
+	failure := models.FetchFailure{
+		TaskID: taskID,
+		Date:   date,
+		TSCode: tsCode,
+		Error:  err.Error(),
+	}
+	if err := f.db.Create(&failure).Error; err != nil {
+		f.logger.Warn("保存失败记录失败",
+			zap.String("task_id", taskID),
+			zap.String("date", date),
+			zap.String("ts_code", tsCode),
+			zap.Error(err))
+	}
+}
+
+// clearFailures 删除任务的失败记录，续传时未完成的日期会重新抓取，仍失败的再次记录
+func (f *DataFetcher) clearFailures(taskID string) {
+	if !f.config.RecordFailures {
+		return
+	}
+	if err := f.db.Where("task_id = ?", taskID).Delete(&models.FetchFailure{}).Error; err != nil {
+		f.logger.Warn("清理失败记录失败",
+			zap.String("task_id", taskID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRecordFailures 测试开启 record_failures 后失败的抓取单元及错误写入 fetch_failures，未开启时不写入
+func TestRecordFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Params["ts_code"] == "600000.SH" {
+			json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+			return
+		}
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "close"},
+			Items:  [][]interface{}{{req.Params["ts_code"], "20231201", 10.2}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockBasic{}, &models.StockDailyAdj{}, &models.FetchTask{}, &models.FetchFailure{})
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	require.NoError(t, fetcher.db.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", Name: "平安银行", ListStatus: "L"},
+		{TSCode: "600000.SH", Name: "浦发银行", ListStatus: "L"},
+	}).Error)
+
+	task, err := fetcher.FetchDailyAdj(context.Background(), "20231201", "20231201", nil, AdjQfq)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.FailedCount)
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.FetchFailure{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	fetcher.config.RecordFailures = true
+	task, err = fetcher.FetchDailyAdj(context.Background(), "20231201", "20231201", nil, AdjQfq)
+	require.NoError(t, err)
+
+	var failures []models.FetchFailure
+	require.NoError(t, fetcher.db.Find(&failures).Error)
+	require.Len(t, failures, 1)
+	assert.Equal(t, task.TaskID, failures[0].TaskID)
+	assert.Equal(t, "600000.SH", failures[0].TSCode)
+	assert.Empty(t, failures[0].Date)
+	assert.Contains(t, failures[0].Error, "权限不足")
+	assert.False(t, failures[0].CreatedAt.IsZero())
+
+	fetcher.clearFailures(task.TaskID)
+	require.NoError(t, fetcher.db.Model(&models.FetchFailure{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
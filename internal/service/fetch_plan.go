@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+)
+
+// 抓取任务类型
+const (
+	PlanTypeDaily         = "daily"
+	PlanTypeWeekly        = "weekly"
+	PlanTypeMonthly       = "monthly"
+	PlanTypeFinaIndicator = "fina_indicator"
+	PlanTypeTopList       = "top_list"
+	PlanTypeBlockTrade    = "block_trade"
+	PlanTypeMargin        = "margin"
+	PlanTypeWeeklyDerive  = "weekly_derive"
+	PlanTypeAdjFactor     = "adj_factor"
+	PlanTypeDailyAdj      = "daily_adj"
+	PlanTypeFundDaily     = "fund_daily"
+)
+
+// FetchPlan 抓取任务预估（dry run 结果）
+type FetchPlan struct {
+	DataType         string  `json:"data_type"`         // 数据类型
+	StartDate        string  `json:"start_date"`        // 开始日期
+	EndDate          string  `json:"end_date"`          // 结束日期
+	DateCount        int     `json:"date_count"`        // 需抓取的日期/报告期数量
+	StockCount       int64   `json:"stock_count"`       // 股票数量
+	TotalTasks       int     `json:"total_tasks"`       // 预计任务数（即数据接口调用次数）
+	RateLimit        int     `json:"rate_limit"`        // 每分钟请求上限
+	EstimatedSeconds float64 `json:"estimated_seconds"` // 按限流估算的耗时（秒）
+}
+
+// PlanFetch 预估抓取任务规模，不创建任务也不调用行情数据接口
+// 日期列表仍会参考交易日历生成，与实际抓取保持一致；tsCodes 不为空时只统计指定股票
+func (f *DataFetcher) PlanFetch(ctx context.Context, dataType, startDate, endDate string, tsCodes []string) (*FetchPlan, error) {
+	var stockCount int64
+	db := f.db.Model(&models.StockBasic{})
+	if len(tsCodes) > 0 {
+		db = db.Where("ts_code IN ?", tsCodes)
+	}
+	if err := db.Count(&stockCount).Error; err != nil {
+		return nil, fmt.Errorf("获取股票数量失败: %w", err)
+	}
+
+	plan := &FetchPlan{
+		DataType:   dataType,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		StockCount: stockCount,
+		RateLimit:  f.config.RateLimit,
+	}
+
+	switch dataType {
+	case PlanTypeDaily, PlanTypeTopList, PlanTypeBlockTrade, PlanTypeMargin, PlanTypeAdjFactor, PlanTypeFundDaily:
+		dates, err := f.generateDateRange(ctx, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		plan.DateCount = len(dates)
+		plan.TotalTasks = plan.DateCount
+	case PlanTypeWeekly:
+		plan.DateCount = len(f.generateWeekDateRange(ctx, startDate, endDate))
+		plan.TotalTasks = plan.DateCount
+	case PlanTypeWeeklyDerive:
+		// 由本地日线聚合，不调用行情数据接口
+		weeks, err := f.tradeWeeks(ctx, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		plan.DateCount = len(weeks)
+	case PlanTypeMonthly:
+		plan.DateCount = len(f.generateMonthEndDates(startDate, endDate))
+		plan.TotalTasks = plan.DateCount
+	case PlanTypeDailyAdj:
+		// pro_bar 按股票一次请求整个日期范围
+		dates, err := f.generateDateRange(ctx, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		plan.DateCount = len(dates)
+		plan.TotalTasks = int(stockCount)
+	case PlanTypeFinaIndicator:
+		plan.DateCount = len(f.generateQuarterEndDates(startDate, endDate))
+		plan.TotalTasks = plan.DateCount * int(stockCount)
+	default:
+		return nil, fmt.Errorf("不支持的数据类型: %s", dataType)
+	}
+
+	// 并发数不影响总耗时上限，请求速率由限流器决定
+	if f.config.RateLimit > 0 {
+		interval := time.Minute / time.Duration(f.config.RateLimit)
+		plan.EstimatedSeconds = (time.Duration(plan.TotalTasks) * interval).Seconds()
+	}
+
+	return plan, nil
+}
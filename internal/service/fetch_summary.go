@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"stock_data/internal/models"
+	"time"
+)
+
+// summaryTaskPrefixes 各数据类型抓取任务的 task_id 前缀，汇总时按前缀区分任务类型
+var summaryTaskPrefixes = map[string]string{
+	PlanTypeDaily:   "task_",
+	PlanTypeWeekly:  "weekly_task_",
+	PlanTypeMonthly: "monthly_task_",
+}
+
+// FetchSummaryPeriod 单个周期的抓取结果
+type FetchSummaryPeriod struct {
+	Period    string `json:"period"`     // 周期：日为 YYYYMMDD，周为 ISO 周（如 2023-W52），月为 YYYYMM
+	StartDate string `json:"start_date"` // 周期第一天
+	EndDate   string `json:"end_date"`   // 周期最后一天
+	Dates     int    `json:"dates"`      // 有抓取记录的日期数
+	Completed int    `json:"completed"`  // 最近一次抓取成功的日期数
+	Failed    int    `json:"failed"`     // 最近一次抓取失败的日期数
+	Attempts  int    `json:"attempts"`   // 抓取次数，同一日期被多个任务抓取时分别计数
+	Status    string `json:"status"`     // completed：全部日期最近一次抓取成功；failed：存在失败的日期
+}
+
+// FetchSummary 按周期汇总的抓取结果，没有抓取记录的周期不返回
+type FetchSummary struct {
+	Type      string               `json:"type"`
+	StartDate string               `json:"start_date"`
+	EndDate   string               `json:"end_date"`
+	Periods   []FetchSummaryPeriod `json:"periods"`
+}
+
+// SummarizeFetches 根据任务检查点（fetch_task_dates）按日、周或月汇总抓取结果
+// 同一日期被多次抓取时以最近一次的结果为准，例如失败后重新抓取成功的日期计为成功
+func (f *DataFetcher) SummarizeFetches(dataType, startDate, endDate string) (*FetchSummary, error) {
+	prefix, ok := summaryTaskPrefixes[dataType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据类型: %s", dataType)
+	}
+
+	var checkpoints []models.FetchTaskDate
+	if err := f.db.Model(&models.FetchTaskDate{}).
+		Where("task_id LIKE ? AND date BETWEEN ? AND ?", prefix+"%", startDate, endDate).
+		Order("date, updated_at, id").
+		Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("查询任务检查点失败: %w", err)
+	}
+
+	latest := make(map[string]string) // 日期 -> 最近一次抓取的状态
+	periods := make(map[string]*FetchSummaryPeriod)
+	for _, checkpoint := range checkpoints {
+		date, err := ParseDate(checkpoint.Date)
+		if err != nil {
+			continue
+		}
+		period := summaryPeriod(dataType, date)
+		if existing, ok := periods[period.Period]; ok {
+			period = existing
+		} else {
+			periods[period.Period] = period
+		}
+		period.Attempts++
+		// 已按 updated_at 升序，后出现的记录覆盖之前的状态
+		latest[checkpoint.Date] = checkpoint.Status
+	}
+
+	for date, status := range latest {
+		d, _ := ParseDate(date)
+		period := periods[summaryPeriod(dataType, d).Period]
+		period.Dates++
+		if status == models.TaskDateCompleted {
+			period.Completed++
+		} else {
+			period.Failed++
+		}
+	}
+
+	summary := &FetchSummary{
+		Type:      dataType,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Periods:   make([]FetchSummaryPeriod, 0, len(periods)),
+	}
+	for _, period := range periods {
+		period.Status = models.TaskDateCompleted
+		if period.Failed > 0 {
+			period.Status = models.TaskDateFailed
+		}
+		summary.Periods = append(summary.Periods, *period)
+	}
+	sort.Slice(summary.Periods, func(i, j int) bool {
+		return summary.Periods[i].StartDate < summary.Periods[j].StartDate
+	})
+	return summary, nil
+}
+
+// summaryPeriod 返回日期所属的周期，周按 ISO 周（周一至周日）划分，与周线分组一致
+func summaryPeriod(dataType string, date time.Time) *FetchSummaryPeriod {
+	switch dataType {
+	case PlanTypeWeekly:
+		year, week := date.ISOWeek()
+		monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		return &FetchSummaryPeriod{
+			Period:    fmt.Sprintf("%d-W%02d", year, week),
+			StartDate: monday.Format("20060102"),
+			EndDate:   monday.AddDate(0, 0, 6).Format("20060102"),
+		}
+	case PlanTypeMonthly:
+		first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return &FetchSummaryPeriod{
+			Period:    first.Format("200601"),
+			StartDate: first.Format("20060102"),
+			EndDate:   first.AddDate(0, 1, -1).Format("20060102"),
+		}
+	default:
+		day := date.Format("20060102")
+		return &FetchSummaryPeriod{Period: day, StartDate: day, EndDate: day}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSummarizeFetches 测试按周汇总周线抓取结果，同一日期以最近一次抓取为准
+func TestSummarizeFetches(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.FetchTaskDate{})
+	earlier := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	require.NoError(t, fetcher.db.Create(&[]models.FetchTaskDate{
+		// 2023-W52 首次失败，之后重新抓取成功
+		{TaskID: "weekly_task_1", Date: "20231229", Status: models.TaskDateFailed, UpdatedAt: earlier},
+		{TaskID: "weekly_task_2", Date: "20231229", Status: models.TaskDateCompleted, UpdatedAt: later},
+		// 2024-W01 失败
+		{TaskID: "weekly_task_1", Date: "20240105", Status: models.TaskDateFailed, UpdatedAt: earlier},
+		// 日线、月线任务的检查点不计入周线汇总
+		{TaskID: "task_1", Date: "20240103", Status: models.TaskDateFailed, UpdatedAt: earlier},
+		{TaskID: "monthly_task_1", Date: "20231231", Status: models.TaskDateCompleted, UpdatedAt: earlier},
+		// 超出查询范围
+		{TaskID: "weekly_task_1", Date: "20240112", Status: models.TaskDateCompleted, UpdatedAt: earlier},
+	}).Error)
+
+	summary, err := fetcher.SummarizeFetches(PlanTypeWeekly, "20231225", "20240107")
+	require.NoError(t, err)
+	assert.Equal(t, []FetchSummaryPeriod{
+		{Period: "2023-W52", StartDate: "20231225", EndDate: "20231231", Dates: 1, Completed: 1, Attempts: 2, Status: models.TaskDateCompleted},
+		{Period: "2024-W01", StartDate: "20240101", EndDate: "20240107", Dates: 1, Failed: 1, Attempts: 1, Status: models.TaskDateFailed},
+	}, summary.Periods)
+
+	summary, err = fetcher.SummarizeFetches(PlanTypeMonthly, "20231201", "20231231")
+	require.NoError(t, err)
+	assert.Equal(t, []FetchSummaryPeriod{
+		{Period: "202312", StartDate: "20231201", EndDate: "20231231", Dates: 1, Completed: 1, Attempts: 1, Status: models.TaskDateCompleted},
+	}, summary.Periods)
+
+	_, err = fetcher.SummarizeFetches("quarterly", "20231201", "20231231")
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"stock_data/internal/config"
+	"time"
+)
+
+// InFetchWindow 判断 t 是否处于 allowed_hours 配置的抓取时段（按 database.timezone 时间，默认 Asia/Shanghai），未配置时始终允许
+func (f *DataFetcher) InFetchWindow(t time.Time) bool {
+	start, end, err := config.ParseAllowedHours(f.config.AllowedHours)
+	if err != nil || start < 0 {
+		return true
+	}
+
+	local := t.In(marketLocation)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// 跨越零点的时段，如 22:00-06:00
+	return minute >= start || minute < end
+}
+
+// AllowedHours 返回配置的抓取时段，为空表示不限制
+func (f *DataFetcher) AllowedHours() string {
+	return f.config.AllowedHours
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FetchFundBasic 抓取基金基本信息，market 为 E（场内）或 O（场外），为空时 Tushare 默认返回场内基金
+func (f *DataFetcher) FetchFundBasic(ctx context.Context, market string) (int, error) {
+	f.logger.Info("开始抓取基金基本信息", zap.String("market", market))
+
+	if err := f.waitTushare(ctx); err != nil {
+		return 0, err
+	}
+	funds, err := f.tushareClient.GetFundBasic(ctx, market)
+	if err != nil {
+		return 0, fmt.Errorf("获取基金基本信息失败: %w", err)
+	}
+
+	stored, err := f.batchUpsertFundBasic(funds)
+	if err != nil {
+		return 0, fmt.Errorf("保存基金基本信息失败: %w", err)
+	}
+
+	f.logger.Info("基金基本信息抓取完成", zap.Int("total", stored))
+	return stored, nil
+}
+
+// batchUpsertFundBasic 批量保存基金基本信息（已存在的基金按 ts_code 更新）
+func (f *DataFetcher) batchUpsertFundBasic(funds []FundBasicData) (int, error) {
+	if len(funds) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.FundBasic, 0, len(funds))
+	for _, data := range funds {
+		records = append(records, models.FundBasic{
+			TSCode:      data.TSCode,
+			Name:        data.Name,
+			Management:  data.Management,
+			Custodian:   data.Custodian,
+			FundType:    data.FundType,
+			FoundDate:   data.FoundDate,
+			DueDate:     data.DueDate,
+			ListDate:    data.ListDate,
+			IssueDate:   data.IssueDate,
+			DelistDate:  data.DelistDate,
+			IssueAmount: data.IssueAmount,
+			MFee:        data.MFee,
+			CFee:        data.CFee,
+			Benchmark:   data.Benchmark,
+			Status:      data.Status,
+			InvestType:  data.InvestType,
+			Type:        data.Type,
+			Market:      data.Market,
+		})
+	}
+	if err := roundDecimals(records, f.config.DecimalRounding); err != nil {
+		return 0, err
+	}
+
+	if err := f.db.Clauses(upsertClause(f.db, []string{"ts_code"})).CreateInBatches(records, f.config.BatchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// FetchFundDaily 按交易日抓取全部场内基金（ETF、LOF 等）的日线行情
+func (f *DataFetcher) FetchFundDaily(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	return f.runUnitTask(ctx, unitTask{
+		prefix:    "fund_daily_task_",
+		name:      "基金日线数据",
+		unit:      unitDate,
+		startDate: startDate,
+		endDate:   endDate,
+		units:     f.tradeDateUnits(startDate, endDate),
+		fetch: func(ctx context.Context, date string, rows *rowTracker) (int, error) {
+			fundData, err := f.tushareClient.GetFundDaily(ctx, date, "")
+			if err != nil {
+				return 0, err
+			}
+			// 当日无数据也算成功
+			stored, err := f.batchUpsertFundDaily(fundData)
+			rows.record("fund_daily", len(fundData), stored)
+			return len(fundData), err
+		},
+	})
+}
+
+// batchUpsertFundDaily 按 insert_mode 批量保存基金日线数据
+func (f *DataFetcher) batchUpsertFundDaily(fundData []FundDailyData) (int, error) {
+	records := make([]models.FundDaily, 0, len(fundData))
+	for _, data := range fundData {
+		tradeDate, err := ParseDate(data.TradeDate)
+		if err != nil {
+			f.logger.Warn("基金日线交易日期格式错误", zap.String("trade_date", data.TradeDate))
+			continue
+		}
+
+		records = append(records, models.FundDaily{
+			TSCode:    data.TSCode,
+			TradeDate: tradeDate,
+			Open:      data.Open,
+			High:      data.High,
+			Low:       data.Low,
+			Close:     data.Close,
+			PreClose:  data.PreClose,
+			Change:    data.Change,
+			PctChg:    data.PctChg,
+			Vol:       data.Vol,
+			Amount:    data.Amount,
+		})
+	}
+
+	return saveRecords(f, f.db, records, f.config.BatchSize, []string{"ts_code", "trade_date"}, func(tx *gorm.DB) *gorm.DB {
+		tsCodes := make([]string, 0, len(records))
+		dates := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			tsCodes = append(tsCodes, r.TSCode)
+			dates = append(dates, r.TradeDate)
+		}
+		return codeDateScope(tx, "trade_date", tsCodes, dates)
+	})
+}
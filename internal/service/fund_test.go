@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFetchFund 测试抓取基金列表并按交易日抓取场内基金日线，null 值保存为 NULL，失败的日期按 date_retry 重新抓取
+func TestFetchFund(t *testing.T) {
+	var failed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var data TushareData
+		switch req.APIName {
+		case "trade_cal":
+			data = TushareData{
+				Fields: []string{"exchange", "cal_date", "is_open"},
+				Items:  [][]interface{}{{req.Params["exchange"], "20231201", 1}, {req.Params["exchange"], "20231204", 1}},
+			}
+		case "fund_basic":
+			assert.Equal(t, "E", req.Params["market"])
+			data = TushareData{
+				Fields: []string{"ts_code", "name", "management", "fund_type", "list_date", "m_fee", "status", "market"},
+				Items: [][]interface{}{
+					{"510300.SH", "沪深300ETF", "华泰柏瑞基金", "股票型", "20120528", 0.5, "L", "E"},
+					{"159915.SZ", "创业板ETF", "易方达基金", "股票型", "20111209", nil, "L", "E"},
+				},
+			}
+		case "fund_daily":
+			assert.Nil(t, req.Params["ts_code"])
+			if req.Params["trade_date"] == "20231204" && atomic.CompareAndSwapInt32(&failed, 0, 1) {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			items := [][]interface{}{{"510300.SH", req.Params["trade_date"], 3.612, 3.658, 3.601, 3.645, 3.615, 0.03, 0.8299, 5200000.0, 1893210.456}}
+			if req.Params["trade_date"] == "20231204" {
+				items = append(items, []interface{}{"159915.SZ", "20231204", nil, nil, nil, 1.982, 1.99, -0.008, -0.402, 3100000.0, 615000.0})
+			}
+			data = TushareData{
+				Fields: []string{"ts_code", "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount"},
+				Items:  items,
+			}
+		default:
+			t.Errorf("unexpected api %s", req.APIName)
+		}
+		dataBytes, _ := json.Marshal(data)
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.FundBasic{}, &models.FundDaily{}, &models.FetchTask{})
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+	count, err := fetcher.FetchFundBasic(context.Background(), models.FundMarketExchange)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	var fund models.FundBasic
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "159915.SZ").First(&fund).Error)
+	assert.Equal(t, "易方达基金", fund.Management)
+	assert.Nil(t, fund.MFee)
+
+	fetcher.config.DateRetry = 1
+	task, err := fetcher.FetchFundDaily(context.Background(), "20231201", "20231204")
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+	assert.Equal(t, 2, task.SuccessCount)
+	assert.Equal(t, int64(3), task.APICalls)
+	assert.Equal(t, int64(3), task.RowsStored)
+
+	date, err := ParseDate("20231204")
+	require.NoError(t, err)
+	var bars []models.FundDaily
+	require.NoError(t, fetcher.db.Where("trade_date = ?", date).Order("ts_code").Find(&bars).Error)
+	require.Len(t, bars, 2)
+	assert.Equal(t, "159915.SZ", bars[0].TSCode)
+	assert.Nil(t, bars[0].Open)
+	assert.InDelta(t, 1.982, *bars[0].Close, 1e-9)
+	assert.InDelta(t, 3.645, *bars[1].Close, 1e-9)
+	assert.InDelta(t, 1893210.456, *bars[1].Amount, 1e-9)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FetchHSConst 抓取沪深股通成分，hsType 为空时抓取沪股通和深股通
+func (f *DataFetcher) FetchHSConst(ctx context.Context, hsType string) (int, error) {
+	hsTypes := []string{models.HSTypeSH, models.HSTypeSZ}
+	if hsType != "" {
+		hsTypes = []string{hsType}
+	}
+
+	f.logger.Info("开始抓取沪深股通成分", zap.Strings("hs_types", hsTypes))
+
+	var records []models.HSConst
+	for _, t := range hsTypes {
+		if err := f.waitTushare(ctx); err != nil {
+			return 0, err
+		}
+		data, err := f.tushareClient.GetHSConst(ctx, t)
+		if err != nil {
+			return 0, fmt.Errorf("获取沪深股通成分失败: %w", err)
+		}
+		for _, row := range data {
+			record, err := newHSConst(row)
+			if err != nil {
+				f.logger.Warn("沪深股通成分日期格式错误",
+					zap.String("ts_code", row.TSCode),
+					zap.String("in_date", row.InDate),
+					zap.String("out_date", row.OutDate))
+				continue
+			}
+			records = append(records, record)
+		}
+	}
+
+	if len(records) > 0 {
+		// 同一次调入的记录按 (ts_code, hs_type, in_date) 更新，剔除后补上 out_date
+		if err := f.db.Clauses(upsertClause(f.db, []string{"ts_code", "hs_type", "in_date"}, "out_date", "updated_at")).CreateInBatches(records, f.config.BatchSize).Error; err != nil {
+			return 0, fmt.Errorf("保存沪深股通成分失败: %w", err)
+		}
+	}
+
+	f.logger.Info("沪深股通成分抓取完成", zap.Int("total", len(records)))
+	return len(records), nil
+}
+
+// newHSConst 解析 Tushare 返回的日期，out_date 为空表示仍在名单中
+func newHSConst(data HSConstData) (models.HSConst, error) {
+	inDate, err := ParseDate(data.InDate)
+	if err != nil {
+		return models.HSConst{}, err
+	}
+
+	record := models.HSConst{
+		TSCode: data.TSCode,
+		HSType: data.HSType,
+		InDate: inDate,
+	}
+	if data.OutDate != "" {
+		outDate, err := ParseDate(data.OutDate)
+		if err != nil {
+			return models.HSConst{}, err
+		}
+		record.OutDate = &outDate
+	}
+	return record, nil
+}
+
+// ConnectEligibleCodes 返回 t 当天（database.timezone）在沪深股通名单中的股票代码子查询
+// 已纳入且 out_date 为空或晚于当天的视为可交易，hsType 为空时不区分沪股通和深股通
+func ConnectEligibleCodes(db *gorm.DB, hsType string, t time.Time) *gorm.DB {
+	local := t.In(marketLocation)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	query := db.Model(&models.HSConst{}).
+		Select("ts_code").
+		Where("in_date <= ? AND (out_date IS NULL OR out_date > ?)", day, day)
+	if hsType != "" {
+		query = query.Where("hs_type = ?", hsType)
+	}
+	return query
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFetchHSConst_EligibleCodes 测试抓取当前及历史成分后按剔除日期筛选当前可交易的股票
+func TestFetchHSConst_EligibleCodes(t *testing.T) {
+	rows := map[string][][]interface{}{
+		"1": {
+			{"600000.SH", "SH", "20141117", nil, "1"},
+			{"600519.SH", "SH", "20141117", nil, "1"},
+		},
+		"0": {
+			{"600010.SH", "SH", "20141117", "20200101", "0"},
+			// 剔除日期在未来，当前仍可交易
+			{"600036.SH", "SH", "20141117", "20991231", "0"},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hs_const", req.APIName)
+
+		isNew, _ := req.Params["is_new"].(string)
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "hs_type", "in_date", "out_date", "is_new"},
+			Items:  rows[isNew],
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.HSConst{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	count, err := fetcher.FetchHSConst(context.Background(), models.HSTypeSH)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	// 重复抓取按唯一索引更新，不产生重复记录
+	_, err = fetcher.FetchHSConst(context.Background(), models.HSTypeSH)
+	require.NoError(t, err)
+	var total int64
+	require.NoError(t, fetcher.db.Model(&models.HSConst{}).Count(&total).Error)
+	assert.Equal(t, int64(4), total)
+
+	var eligible []string
+	require.NoError(t, ConnectEligibleCodes(fetcher.db, "", time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)).
+		Order("ts_code").
+		Pluck("ts_code", &eligible).Error)
+	assert.Equal(t, []string{"600000.SH", "600036.SH", "600519.SH"}, eligible)
+
+	var szEligible []string
+	require.NoError(t, ConnectEligibleCodes(fetcher.db, models.HSTypeSZ, time.Now()).Pluck("ts_code", &szEligible).Error)
+	assert.Empty(t, szEligible)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// SimpleMovingAverage 计算简单移动平均，结果与输入等长
+// 前 window-1 个位置数据不足，返回 nil
+func SimpleMovingAverage(values []float64, window int) []*float64 {
+	result := make([]*float64, len(values))
+	if window <= 0 {
+		return result
+	}
+
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= window {
+			sum -= values[i-window]
+		}
+		if i >= window-1 {
+			avg := sum / float64(window)
+			result[i] = &avg
+		}
+	}
+	return result
+}
+
+// 复权方式
+const (
+	AdjNone = ""    // 不复权
+	AdjQfq  = "qfq" // 前复权
+	AdjHfq  = "hfq" // 后复权
+)
+
+// IsValidAdj 判断复权方式是否受支持
+func IsValidAdj(adj string) bool {
+	return adj == AdjNone || adj == AdjQfq || adj == AdjHfq
+}
+
+// CarryForwardFactors 为每个交易日匹配复权因子，当日缺失时沿用之前最近的因子
+// dates 与 factorDates 均需按日期升序，之前没有任何因子的日期返回 nil
+func CarryForwardFactors(dates []time.Time, factorDates []time.Time, factors []float64) []*float64 {
+	result := make([]*float64, len(dates))
+	j := -1
+	for i, d := range dates {
+		for j+1 < len(factorDates) && !factorDates[j+1].After(d) {
+			j++
+		}
+		if j >= 0 {
+			factor := factors[j]
+			result[i] = &factor
+		}
+	}
+	return result
+}
+
+// AdjustPrice 按复权因子计算复权价格：后复权为 价格×因子，前复权为 价格×因子/基准因子
+// 价格或因子缺失、前复权基准因子为 0 时返回 nil
+func AdjustPrice(price, factor *float64, adj string, baseFactor float64) *float64 {
+	if price == nil || factor == nil {
+		return nil
+	}
+
+	adjusted := *price * *factor
+	if adj == AdjQfq {
+		if baseFactor == 0 {
+			return nil
+		}
+		adjusted /= baseFactor
+	}
+	return &adjusted
+}
+
+// 行情一致性检查的异常类型
+const (
+	AnomalyChange = "change"  // change 与 close - pre_close 不一致
+	AnomalyPctChg = "pct_chg" // pct_chg 与 (close - pre_close) / pre_close × 100 不一致
+)
+
+// PriceAnomalies 检查涨跌额、涨跌幅与收盘价、昨收是否一致，返回不一致的字段
+// 参与计算的字段缺失时跳过对应检查；昨收为 0 时不检查涨跌幅
+func PriceAnomalies(close, preClose, change, pctChg *float64, epsilon, pctTolerance float64) []string {
+	if close == nil || preClose == nil {
+		return nil
+	}
+
+	var anomalies []string
+	expected := *close - *preClose
+	if change != nil && math.Abs(expected-*change) > epsilon {
+		anomalies = append(anomalies, AnomalyChange)
+	}
+	if pctChg != nil && *preClose != 0 {
+		expectedPct := expected / *preClose * 100
+		if math.Abs(expectedPct-*pctChg) > pctTolerance {
+			anomalies = append(anomalies, AnomalyPctChg)
+		}
+	}
+	return anomalies
+}
+
+// TradingDaysPerYear 年化收益使用的每年交易日数
+const TradingDaysPerYear = 252
+
+// AnnualizedReturn 将 periods 个交易日的累计收益按复利折算为年化收益
+// periods 不大于 0 或累计收益不大于 -100% 时无法折算，返回 nil
+func AnnualizedReturn(totalReturn float64, periods int) *float64 {
+	if periods <= 0 || totalReturn <= -1 {
+		return nil
+	}
+	annualized := math.Pow(1+totalReturn, float64(TradingDaysPerYear)/float64(periods)) - 1
+	return &annualized
+}
+
+// MaxDrawdown 计算序列的最大回撤（正数，如 0.25 表示回撤 25%），同时返回回撤起点（前高）和终点（谷底）的下标
+// 序列为空、不存在回撤或前高不为正时返回 0, -1, -1
+func MaxDrawdown(values []float64) (drawdown float64, peak, trough int) {
+	peak, trough = -1, -1
+	maxIndex := -1
+	for i, v := range values {
+		if maxIndex < 0 || v > values[maxIndex] {
+			maxIndex = i
+			continue
+		}
+		if values[maxIndex] <= 0 {
+			continue
+		}
+		if dd := (values[maxIndex] - v) / values[maxIndex]; dd > drawdown {
+			drawdown, peak, trough = dd, maxIndex, i
+		}
+	}
+	return drawdown, peak, trough
+}
+
+// LogReturn 计算 base 到 value 的对数收益 ln(value/base)，任一价格不为正时无法计算，返回 false
+func LogReturn(base, value float64) (float64, bool) {
+	if base <= 0 || value <= 0 {
+		return 0, false
+	}
+	return math.Log(value / base), true
+}
+
+// StdDev 计算样本标准差（除以 n-1），样本少于 2 个时返回 nil
+func StdDev(values []float64) *float64 {
+	if len(values) < 2 {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(values)-1))
+	return &stddev
+}
+
+// Correlation 计算两个等长序列的 Pearson 相关系数
+// 长度不一致、样本少于 2 个或任一序列方差为 0 时无法计算，返回 nil
+func Correlation(xs, ys []float64) *float64 {
+	n := len(xs)
+	if n != len(ys) || n < 2 {
+		return nil
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+	corr := cov / math.Sqrt(varX*varY)
+	return &corr
+}
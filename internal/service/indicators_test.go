@@ -0,0 +1,129 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSimpleMovingAverage 测试移动平均计算及数据不足时返回 nil
+func TestSimpleMovingAverage(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+
+	result := SimpleMovingAverage(values, 3)
+	assert.Len(t, result, 5)
+	assert.Nil(t, result[0])
+	assert.Nil(t, result[1])
+	assert.InDelta(t, 2.0, *result[2], 1e-9)
+	assert.InDelta(t, 3.0, *result[3], 1e-9)
+	assert.InDelta(t, 4.0, *result[4], 1e-9)
+
+	// 窗口大于数据长度时全部为 nil
+	for _, v := range SimpleMovingAverage(values, 10) {
+		assert.Nil(t, v)
+	}
+}
+
+// TestCarryForwardFactors 测试复权因子缺失时沿用之前最近的因子
+func TestCarryForwardFactors(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 12, d, 0, 0, 0, 0, time.UTC) }
+	dates := []time.Time{day(1), day(4), day(5), day(6)}
+	factorDates := []time.Time{day(2), day(5)}
+
+	result := CarryForwardFactors(dates, factorDates, []float64{1.5, 2.0})
+
+	assert.Nil(t, result[0])
+	assert.Equal(t, 1.5, *result[1])
+	assert.Equal(t, 2.0, *result[2])
+	assert.Equal(t, 2.0, *result[3])
+
+	price := 10.0
+	assert.InDelta(t, 20.0, *AdjustPrice(&price, result[3], AdjHfq, 0), 1e-9)
+	assert.InDelta(t, 7.5, *AdjustPrice(&price, result[1], AdjQfq, 2.0), 1e-9)
+	assert.Nil(t, AdjustPrice(&price, result[0], AdjQfq, 2.0))
+}
+
+// TestPriceAnomalies 测试涨跌额、涨跌幅一致性检查
+func TestPriceAnomalies(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	// 与收盘价、昨收一致（涨跌幅允许四舍五入误差）
+	assert.Empty(t, PriceAnomalies(f(10.5), f(10), f(0.5), f(5.0), 0.01, 0.01))
+
+	assert.Equal(t, []string{AnomalyChange}, PriceAnomalies(f(10.5), f(10), f(0.3), f(5.0), 0.01, 0.01))
+	assert.Equal(t, []string{AnomalyPctChg}, PriceAnomalies(f(10.5), f(10), f(0.5), f(3.0), 0.01, 0.01))
+	assert.Equal(t, []string{AnomalyChange, AnomalyPctChg}, PriceAnomalies(f(10.5), f(10), f(-0.5), f(-5.0), 0.01, 0.01))
+
+	// 缺失字段或昨收为 0 时跳过对应检查
+	assert.Empty(t, PriceAnomalies(nil, f(10), f(0.3), f(3.0), 0.01, 0.01))
+	assert.Empty(t, PriceAnomalies(f(10.5), f(10), nil, nil, 0.01, 0.01))
+	assert.Empty(t, PriceAnomalies(f(1), f(0), f(1), f(100), 0.01, 0.01))
+}
+
+// TestMaxDrawdown 测试最大回撤取前高到之后最低点的最大跌幅
+func TestMaxDrawdown(t *testing.T) {
+	drawdown, peak, trough := MaxDrawdown([]float64{10, 12, 9, 11, 13, 10.4, 14})
+	assert.InDelta(t, 0.25, drawdown, 1e-9)
+	assert.Equal(t, 1, peak)
+	assert.Equal(t, 2, trough)
+
+	// 单调上涨没有回撤
+	drawdown, peak, trough = MaxDrawdown([]float64{1, 2, 3})
+	assert.Zero(t, drawdown)
+	assert.Equal(t, -1, peak)
+	assert.Equal(t, -1, trough)
+}
+
+// TestAnnualizedReturn 测试按交易日数复利折算年化收益
+func TestAnnualizedReturn(t *testing.T) {
+	annualized := AnnualizedReturn(0.21, 2*TradingDaysPerYear)
+	if assert.NotNil(t, annualized) {
+		assert.InDelta(t, 0.1, *annualized, 1e-9)
+	}
+	assert.Nil(t, AnnualizedReturn(0.1, 0))
+	assert.Nil(t, AnnualizedReturn(-1, 10))
+}
+
+// TestStdDev 测试样本标准差按 n-1 计算，样本不足时为 nil
+func TestStdDev(t *testing.T) {
+	stddev := StdDev([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if assert.NotNil(t, stddev) {
+		assert.InDelta(t, math.Sqrt(32.0/7), *stddev, 1e-9)
+	}
+	assert.Nil(t, StdDev([]float64{0.01}))
+	assert.Nil(t, StdDev(nil))
+}
+
+// TestLogReturn 测试对数收益及价格不为正时无法计算
+func TestLogReturn(t *testing.T) {
+	r, ok := LogReturn(10, 11)
+	assert.True(t, ok)
+	assert.InDelta(t, math.Log(1.1), r, 1e-12)
+
+	_, ok = LogReturn(0, 11)
+	assert.False(t, ok)
+	_, ok = LogReturn(10, -1)
+	assert.False(t, ok)
+}
+
+// TestCorrelation 测试 Pearson 相关系数及无法计算的情况
+func TestCorrelation(t *testing.T) {
+	corr := Correlation([]float64{1, 2, 3, 4}, []float64{2, 4, 6, 8})
+	if assert.NotNil(t, corr) {
+		assert.InDelta(t, 1, *corr, 1e-12)
+	}
+	corr = Correlation([]float64{1, 2, 3, 4}, []float64{4, 3, 2, 1})
+	if assert.NotNil(t, corr) {
+		assert.InDelta(t, -1, *corr, 1e-12)
+	}
+	corr = Correlation([]float64{1, 2, 3, 4, 5}, []float64{2, 1, 4, 3, 5})
+	if assert.NotNil(t, corr) {
+		assert.InDelta(t, 0.8, *corr, 1e-12)
+	}
+
+	assert.Nil(t, Correlation([]float64{1, 2}, []float64{1}))
+	assert.Nil(t, Correlation([]float64{1}, []float64{1}))
+	assert.Nil(t, Correlation([]float64{1, 1, 1}, []float64{1, 2, 3}))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 数据已存在时的写入方式，对应 fetcher.insert_mode
+const (
+	InsertModeUpsert  = "upsert"  // 冲突时更新已有记录
+	InsertModeSkip    = "skip"    // 冲突时保留已有记录（ON CONFLICT DO NOTHING）
+	InsertModeReplace = "replace" // 先删除本批数据覆盖范围内的已有记录再写入
+)
+
+// saveRecords 按 insert_mode 写入一批记录，返回入库行数
+// conflictColumns 为唯一索引列；replaceScope 给出 replace 模式下需删除的已有记录范围
+// skip 模式只统计实际新增的行数；个别行写入失败时跳过这些行，见 createInBatches
+// 写入前按 decimal_rounding 将数值舍入到列声明的小数位数，见 roundDecimals
+// 写入耗时用于自适应调整抓取并发数，见 InsertThrottle
+func saveRecords[T any](f *DataFetcher, db *gorm.DB, records []T, batchSize int, conflictColumns []string, replaceScope func(tx *gorm.DB) *gorm.DB) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+	start := time.Now()
+	defer func() { f.throttle.Observe(time.Since(start)) }()
+	if err := roundDecimals(records, f.config.DecimalRounding); err != nil {
+		return 0, err
+	}
+
+	switch f.config.InsertMode {
+	case InsertModeSkip:
+		stored, err := createInBatches(f, db, records, batchSize, func(tx *gorm.DB, batch []T) (int64, error) {
+			result := tx.Clauses(skipClause(tx, conflictColumns)).Create(batch)
+			return result.RowsAffected, result.Error
+		})
+		if err != nil {
+			return 0, err
+		}
+		return int(stored), nil
+	case InsertModeReplace:
+		var stored int64
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var model T
+			if err := replaceScope(tx).Delete(&model).Error; err != nil {
+				return fmt.Errorf("删除已有数据失败: %w", err)
+			}
+			var err error
+			stored, err = createInBatches(f, tx, records, batchSize, func(tx *gorm.DB, batch []T) (int64, error) {
+				return int64(len(batch)), tx.Create(batch).Error
+			})
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return int(stored), nil
+	default:
+		stored, err := createInBatches(f, db, records, batchSize, func(tx *gorm.DB, batch []T) (int64, error) {
+			return int64(len(batch)), tx.Clauses(upsertClause(tx, conflictColumns)).Create(batch).Error
+		})
+		if err != nil {
+			return 0, err
+		}
+		return int(stored), nil
+	}
+}
+
+// createInBatches 分批写入记录，返回入库行数
+// 某批写入失败时二分拆成更小的子批重试，直到定位到单行，无法写入的行记录日志后跳过，
+// 避免个别异常行（如超出精度的数值）导致整批有效数据丢失
+// 一批数据全部写入失败时返回错误，这通常是数据库不可用或表结构问题而不是个别行的问题；连接断开或上下文取消时不再拆分
+// 每次尝试在单独的事务中执行，已处于事务中时使用保存点，失败的语句不会中止外层事务
+func createInBatches[T any](f *DataFetcher, db *gorm.DB, records []T, batchSize int, create func(tx *gorm.DB, batch []T) (int64, error)) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = len(records)
+	}
+
+	var total int64
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+		stored, failed, err := createRecovering(f, db, records[start:end], create)
+		total += stored
+		if err != nil && (failed == end-start || !isRowError(err)) {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// createRecovering 写入一批记录，失败时拆分重试，返回入库行数、跳过的行数和遇到的第一个错误
+func createRecovering[T any](f *DataFetcher, db *gorm.DB, batch []T, create func(tx *gorm.DB, batch []T) (int64, error)) (int64, int, error) {
+	var stored int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		stored, err = create(tx, batch)
+		return err
+	})
+	if err == nil {
+		return stored, 0, nil
+	}
+	if !isRowError(err) {
+		return 0, len(batch), err
+	}
+	if len(batch) == 1 {
+		f.logger.Warn("记录写入失败，已跳过", zap.Any("record", batch[0]), zap.Error(err))
+		return 0, 1, err
+	}
+
+	f.logger.Warn("批量写入失败，拆分后重试", zap.Int("batch", len(batch)), zap.Error(err))
+	mid := len(batch) / 2
+	leftStored, leftFailed, leftErr := createRecovering(f, db, batch[:mid], create)
+	if leftErr != nil && !isRowError(leftErr) {
+		return leftStored, len(batch) - int(leftStored), leftErr
+	}
+	rightStored, rightFailed, rightErr := createRecovering(f, db, batch[mid:], create)
+	if leftErr == nil {
+		leftErr = rightErr
+	}
+	return leftStored + rightStored, leftFailed + rightFailed, leftErr
+}
+
+// isRowError 判断写入错误是否可能由个别数据行引起，连接断开和上下文取消时拆分重试没有意义
+func isRowError(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, driver.ErrBadConn)
+}
+
+// distinctValues 按出现顺序去重，用于生成 replace 模式的删除条件
+func distinctValues[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	result := make([]T, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// codeDateScope replace 模式的删除范围：本批数据涉及的股票与日期
+func codeDateScope(tx *gorm.DB, dateColumn string, tsCodes []string, dates []time.Time) *gorm.DB {
+	return tx.Where("ts_code IN ? AND "+dateColumn+" IN ?", distinctValues(tsCodes), distinctValues(dates))
+}
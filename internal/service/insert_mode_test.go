@@ -0,0 +1,189 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedDailyRow 预置一条已存在的日线记录
+func seedDailyRow(t *testing.T, fetcher *DataFetcher, tsCode string, close float64) {
+	t.Helper()
+	require.NoError(t, fetcher.db.Create(&models.StockDaily{
+		TSCode:    tsCode,
+		TradeDate: time.Date(2023, 12, 1, 0, 0, 0, 0, Location()),
+		Close:     &close,
+	}).Error)
+}
+
+// loadDailyCloses 按股票代码读取 2023-12-01 的收盘价
+func loadDailyCloses(t *testing.T, fetcher *DataFetcher) map[string]float64 {
+	t.Helper()
+	var rows []models.StockDaily
+	require.NoError(t, fetcher.db.Order("ts_code").Find(&rows).Error)
+	closes := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		require.NotNil(t, row.Close)
+		closes[row.TSCode] = *row.Close
+	}
+	return closes
+}
+
+// TestInsertDailyData_InsertModes 测试 upsert/skip/replace 三种写入方式对已存在记录的处理
+func TestInsertDailyData_InsertModes(t *testing.T) {
+	newClose := 11.0
+	incoming := []StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &newClose},
+		{TSCode: "000002.SZ", TradeDate: "20231201", Close: &newClose},
+	}
+
+	tests := []struct {
+		mode   string
+		stored int
+		closes map[string]float64
+	}{
+		{mode: InsertModeUpsert, stored: 2, closes: map[string]float64{"000001.SZ": 11, "000002.SZ": 11, "600000.SH": 10}},
+		{mode: InsertModeSkip, stored: 1, closes: map[string]float64{"000001.SZ": 10, "000002.SZ": 11, "600000.SH": 10}},
+		{mode: InsertModeReplace, stored: 2, closes: map[string]float64{"000001.SZ": 11, "000002.SZ": 11, "600000.SH": 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+			fetcher.config.InsertMode = tt.mode
+			seedDailyRow(t, fetcher, "000001.SZ", 10)
+			// 不在本批数据中的股票不受影响
+			seedDailyRow(t, fetcher, "600000.SH", 10)
+
+			stored, err := fetcher.batchInsertDailyData(incoming)
+			require.NoError(t, err)
+			assert.Equal(t, tt.stored, stored)
+			assert.Equal(t, tt.closes, loadDailyCloses(t, fetcher))
+		})
+	}
+}
+
+// TestInsertDailyData_PoisonRow 测试个别行写入失败时只跳过这些行，同批其他行正常入库
+func TestInsertDailyData_PoisonRow(t *testing.T) {
+	closePrice := 10.0
+	incoming := []StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice},
+		{TSCode: "000002.SZ", TradeDate: "20231201", Close: &closePrice},
+		{TSCode: "BAD", TradeDate: "20231201", Close: &closePrice},
+		{TSCode: "600000.SH", TradeDate: "20231201", Close: &closePrice},
+		{TSCode: "600001.SH", TradeDate: "20231201", Close: &closePrice},
+	}
+
+	for _, mode := range []string{InsertModeUpsert, InsertModeSkip, InsertModeReplace} {
+		t.Run(mode, func(t *testing.T) {
+			fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+			fetcher.config.InsertMode = mode
+			// 用触发器模拟违反约束的异常行
+			require.NoError(t, fetcher.db.Exec(`CREATE TRIGGER reject_bad BEFORE INSERT ON stock_daily
+				WHEN NEW.ts_code = 'BAD' BEGIN SELECT RAISE(ABORT, 'bad row'); END`).Error)
+
+			stored, err := fetcher.batchInsertDailyData(incoming)
+			require.NoError(t, err)
+			assert.Equal(t, 4, stored)
+			assert.Equal(t, map[string]float64{"000001.SZ": 10, "000002.SZ": 10, "600000.SH": 10, "600001.SH": 10},
+				loadDailyCloses(t, fetcher))
+		})
+	}
+}
+
+// TestInsertDailyData_AllRowsFail 测试整批都无法写入时返回错误
+func TestInsertDailyData_AllRowsFail(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	require.NoError(t, fetcher.db.Exec(`CREATE TRIGGER reject_all BEFORE INSERT ON stock_daily
+		BEGIN SELECT RAISE(ABORT, 'table locked'); END`).Error)
+
+	closePrice := 10.0
+	_, err := fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice},
+		{TSCode: "000002.SZ", TradeDate: "20231201", Close: &closePrice},
+	})
+	assert.Error(t, err)
+}
+
+// TestInsertDailyData_RoundsDecimals 测试写入前按列声明的小数位数舍入，SQLite 不按 decimal 舍入，存储值即为舍入结果
+func TestInsertDailyData_RoundsDecimals(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	closePrice, pctChg, vol := 10.12345, 1.234567, 1234.5
+	_, err := fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice, PctChg: &pctChg, Vol: &vol},
+	})
+	require.NoError(t, err)
+
+	var row models.StockDaily
+	require.NoError(t, fetcher.db.First(&row).Error)
+	assert.Equal(t, 10.12, *row.Close)
+	assert.Equal(t, 1.2346, *row.PctChg)
+	assert.Equal(t, 1234.5, *row.Vol)
+	assert.Nil(t, row.Open)
+
+	var latest models.StockLatest
+	require.NoError(t, fetcher.db.First(&latest).Error)
+	assert.Equal(t, 10.12, *latest.Close)
+
+	// none 时原样交给数据库
+	fetcher.config.DecimalRounding = DecimalRoundingNone
+	_, err = fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000002.SZ", TradeDate: "20231201", Close: &closePrice},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10.12345, loadDailyCloses(t, fetcher)["000002.SZ"])
+}
+
+// TestBatchUpsertAdjFactor_InsertMode 测试按日抓取的附加数据同样遵循 insert_mode 并按列声明舍入
+func TestBatchUpsertAdjFactor_InsertMode(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockAdjFactor{})
+	fetcher.config.InsertMode = InsertModeSkip
+	existing, factor1, factor2 := 1.5, 2.0, 1.234567
+	require.NoError(t, fetcher.db.Create(&models.StockAdjFactor{
+		TSCode:    "000001.SZ",
+		TradeDate: time.Date(2023, 12, 1, 0, 0, 0, 0, Location()),
+		AdjFactor: &existing,
+	}).Error)
+
+	stored, err := fetcher.batchUpsertAdjFactor([]AdjFactorData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", AdjFactor: &factor1},
+		{TSCode: "000002.SZ", TradeDate: "20231201", AdjFactor: &factor2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	var rows []models.StockAdjFactor
+	require.NoError(t, fetcher.db.Order("ts_code").Find(&rows).Error)
+	require.Len(t, rows, 2)
+	require.NotNil(t, rows[0].AdjFactor)
+	require.NotNil(t, rows[1].AdjFactor)
+	assert.Equal(t, 1.5, *rows[0].AdjFactor)
+	assert.Equal(t, 1.2346, *rows[1].AdjFactor)
+}
+
+// TestRoundDecimal 测试按十进制表示舍入，不受二进制近似值影响
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		value float64
+		scale int
+		mode  string
+		want  float64
+	}{
+		{value: 10.12345, scale: 2, mode: DecimalRoundingHalfUp, want: 10.12},
+		{value: 10.125, scale: 2, mode: DecimalRoundingHalfUp, want: 10.13},
+		{value: 1.005, scale: 2, mode: DecimalRoundingHalfUp, want: 1.01},
+		{value: -1.005, scale: 2, mode: DecimalRoundingHalfUp, want: -1.01},
+		{value: 10.125, scale: 2, mode: DecimalRoundingHalfEven, want: 10.12},
+		{value: 10.135, scale: 2, mode: DecimalRoundingHalfEven, want: 10.14},
+		{value: 0.004, scale: 2, mode: DecimalRoundingHalfUp, want: 0},
+		{value: 0.00005, scale: 4, mode: DecimalRoundingHalfUp, want: 0.0001},
+		{value: 12345.6, scale: 0, mode: DecimalRoundingHalfUp, want: 12346},
+		{value: 1e20, scale: 2, mode: DecimalRoundingHalfUp, want: 1e20},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, roundDecimal(tt.value, tt.scale, tt.mode), "%v %s", tt.value, tt.mode)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// insertPool 固定数量的写入 worker，抓取 goroutine 将写入操作交给 worker 执行，抓取与写入并行
+// 每个写入操作由单个 worker 完整执行，同一批数据内的写入顺序和事务边界与直接写入相同
+type insertPool struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+// newInsertPool 创建并启动写入 worker，待执行的写入操作最多缓冲 workers 个
+func newInsertPool(workers int) *insertPool {
+	p := &insertPool{jobs: make(chan func(), workers)}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+// submit 提交写入操作，缓冲已满时等待，ctx 取消时返回错误且不执行该操作
+func (p *insertPool) submit(ctx context.Context, job func()) error {
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close 停止接收写入操作并等待已提交的操作全部完成
+func (p *insertPool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
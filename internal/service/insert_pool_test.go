@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newDailyServer 模拟 Tushare daily 接口，每个交易日返回 rows 只股票的日线
+func newDailyServer(tb testing.TB, rows int) *httptest.Server {
+	tb.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		date, _ := req.Params["trade_date"].(string)
+		items := make([][]interface{}, rows)
+		for i := range items {
+			items[i] = []interface{}{fmt.Sprintf("%06d.SZ", i+1), date, 10.5, 11.0, 10.2, 10.8, 10.6, 0.2, 1.89, 123456.78, 1234567.89}
+		}
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount"},
+			Items:  items,
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+// runTestDailyDates 使用指定的写入 worker 数执行日线抓取任务
+func runTestDailyDates(tb testing.TB, insertWorkers, rows int, dates []string) (*DataFetcher, *models.FetchTask) {
+	tb.Helper()
+	server := newDailyServer(tb, rows)
+
+	fetcher := newTestFetcher(tb, &models.StockDaily{}, &models.StockLatest{}, &models.FetchTask{}, &models.FetchTaskDate{})
+	fetcher.config.Concurrency = 4
+	fetcher.config.BatchSize = 500
+	fetcher.config.InsertWorkers = insertWorkers
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.throttle = NewInsertThrottle(fetcher.config.Concurrency, 0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	task := &models.FetchTask{TaskID: "task_1", Status: models.TaskStatusRunning, StartTime: time.Now(), TotalCount: len(dates)}
+	require.NoError(tb, fetcher.db.Create(task).Error)
+	fetcher.runDailyDates(context.Background(), task, dates, 0, false)
+	return fetcher, task
+}
+
+// TestRunDailyDates_InsertWorkers 测试写入 worker 池与直接写入的结果一致，且检查点在数据写入后记录
+func TestRunDailyDates_InsertWorkers(t *testing.T) {
+	dates := []string{"20231201", "20231204", "20231205", "20231206", "20231207"}
+
+	for _, workers := range []int{0, 2} {
+		t.Run(fmt.Sprintf("insert_workers=%d", workers), func(t *testing.T) {
+			fetcher, task := runTestDailyDates(t, workers, 120, dates)
+
+			assert.Equal(t, models.TaskStatusCompleted, task.Status)
+			assert.Equal(t, len(dates), task.SuccessCount)
+			assert.Equal(t, 0, task.FailedCount)
+			assert.Equal(t, int64(600), task.RowsStored)
+			assert.Equal(t, int64(len(dates)), task.APICalls)
+
+			var count int64
+			require.NoError(t, fetcher.db.Model(&models.StockDaily{}).Count(&count).Error)
+			assert.Equal(t, int64(600), count)
+
+			var completed int64
+			require.NoError(t, fetcher.db.Model(&models.FetchTaskDate{}).
+				Where("task_id = ? AND status = ?", task.TaskID, models.TaskDateCompleted).
+				Count(&completed).Error)
+			assert.Equal(t, int64(len(dates)), completed)
+		})
+	}
+}
+
+// BenchmarkRunDailyDates 对比直接写入与写入 worker 池的日线抓取耗时
+func BenchmarkRunDailyDates(b *testing.B) {
+	dates := []string{"20231201", "20231204", "20231205", "20231206", "20231207", "20231208", "20231211", "20231212"}
+
+	for _, workers := range []int{0, 2, 4} {
+		b.Run(fmt.Sprintf("insert_workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				runTestDailyDates(b, workers, 2000, dates)
+			}
+		})
+	}
+}
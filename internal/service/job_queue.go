@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stock_data/internal/models"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 作业类型
+const (
+	JobTypeDaily   = "daily"
+	JobTypeWeekly  = "weekly"
+	JobTypeMonthly = "monthly"
+)
+
+// jobPollInterval 没有新作业通知时 worker 重新检查队列的间隔
+const jobPollInterval = 30 * time.Second
+
+// IdempotencyKeyTTL 幂等键的有效期，超过后相同的键会创建新作业
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// ErrIdempotencyKeyMismatch 幂等键已用于其他作业类型或参数
+var ErrIdempotencyKeyMismatch = errors.New("Idempotency-Key 已用于参数不同的请求")
+
+// JobParams 作业的抓取参数，以 JSON 保存在 fetch_jobs.params 中
+type JobParams struct {
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	NewestFirst bool   `json:"newest_first,omitempty"` // 仅日线
+}
+
+// JobQueue 持久化的抓取作业队列，由固定数量的 worker 依次执行
+// 作业先写入 fetch_jobs 再执行，服务重启后排队中和被中断的作业会继续执行；
+// worker 执行作业时同样占用 max_concurrent_tasks 名额，与其他抓取接口共享上限
+type JobQueue struct {
+	fetcher *DataFetcher
+	db      *gorm.DB
+	logger  *zap.Logger
+	workers int
+
+	wake     chan struct{} // 有新作业时唤醒空闲的 worker
+	stopping chan struct{} // 关闭后 worker 不再领取新作业
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	// stopCtx 开始关闭时取消，用于放弃等待任务名额；runCtx 关闭超时后取消，用于中断执行中的作业
+	stopCtx context.Context
+	stop    context.CancelFunc
+	runCtx  context.Context
+	abort   context.CancelFunc
+}
+
+// NewJobQueue 创建作业队列，需调用 Start 启动 worker
+func NewJobQueue(fetcher *DataFetcher, workers int, logger *zap.Logger) *JobQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	q := &JobQueue{
+		fetcher:  fetcher,
+		db:       fetcher.db,
+		logger:   logger,
+		workers:  workers,
+		wake:     make(chan struct{}, workers),
+		stopping: make(chan struct{}),
+	}
+	q.stopCtx, q.stop = context.WithCancel(context.Background())
+	q.runCtx, q.abort = context.WithCancel(context.Background())
+	return q
+}
+
+// Migrate 创建 fetch_jobs 表，在启动作业队列前调用
+func (q *JobQueue) Migrate() error {
+	return q.db.AutoMigrate(&models.FetchJob{})
+}
+
+// Start 将上次退出时仍在执行的作业放回队列，然后启动 worker
+func (q *JobQueue) Start() error {
+	result := q.db.Model(&models.FetchJob{}).
+		Where("status = ?", models.JobStatusRunning).
+		Update("status", models.JobStatusQueued)
+	if result.Error != nil {
+		return fmt.Errorf("恢复未完成的作业失败: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		q.logger.Info("恢复上次未完成的作业", zap.Int64("count", result.RowsAffected))
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	q.logger.Info("作业队列已启动", zap.Int("workers", q.workers))
+	return nil
+}
+
+// Shutdown 停止领取新作业并等待执行中的作业结束
+// ctx 到期时中断执行中的作业，作业放回队列，日线任务在重启后从检查点续传
+func (q *JobQueue) Shutdown(ctx context.Context) error {
+	q.stopOnce.Do(func() {
+		close(q.stopping)
+		q.stop()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.logger.Warn("等待作业结束超时，中断执行中的作业")
+		q.abort()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Enqueue 保存作业并通知 worker 执行，返回排队中的作业
+func (q *JobQueue) Enqueue(jobType string, params JobParams) (*models.FetchJob, error) {
+	job, _, err := q.EnqueueIdempotent(jobType, params, "")
+	return job, err
+}
+
+// EnqueueIdempotent 同 Enqueue，idempotencyKey 不为空时 IdempotencyKeyTTL 内相同的键只创建一次作业，
+// 重复请求返回已有作业且 created 为 false；相同的键用于不同的作业类型或参数时返回 ErrIdempotencyKeyMismatch
+func (q *JobQueue) EnqueueIdempotent(jobType string, params JobParams, idempotencyKey string) (job *models.FetchJob, created bool, err error) {
+	switch jobType {
+	case JobTypeDaily, JobTypeWeekly, JobTypeMonthly:
+	default:
+		return nil, false, fmt.Errorf("未知的作业类型: %s", jobType)
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, false, fmt.Errorf("序列化作业参数失败: %w", err)
+	}
+	job = &models.FetchJob{
+		JobID:  fmt.Sprintf("job_%s_%d", jobType, time.Now().UnixNano()),
+		Type:   jobType,
+		Params: string(data),
+		Status: models.JobStatusQueued,
+	}
+
+	if idempotencyKey != "" {
+		existing, err := q.findByIdempotencyKey(idempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			if existing.Type != job.Type || existing.Params != job.Params {
+				return nil, false, ErrIdempotencyKeyMismatch
+			}
+			return existing, false, nil
+		}
+		job.IdempotencyKey = &idempotencyKey
+	}
+
+	// 日线作业入队时即创建等待中的任务，响应中返回 task_id，可在作业执行前开始轮询进度
+	var task *models.FetchTask
+	if jobType == JobTypeDaily {
+		if task, err = q.fetcher.QueueDailyTask(params.StartDate, params.EndDate); err != nil {
+			return nil, false, err
+		}
+		job.TaskID = task.TaskID
+	}
+
+	if err := q.db.Create(job).Error; err != nil {
+		if task != nil {
+			q.db.Delete(task)
+		}
+		// 并发请求使用相同的键时唯一索引冲突，返回先创建的作业
+		if idempotencyKey != "" {
+			if existing, findErr := q.findByIdempotencyKey(idempotencyKey); findErr == nil && existing != nil {
+				return existing, false, nil
+			}
+		}
+		return nil, false, fmt.Errorf("保存作业失败: %w", err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, true, nil
+}
+
+// findByIdempotencyKey 查询使用该键且未过期的作业，已过期的作业清除键后返回 nil，使键可以再次使用
+func (q *JobQueue) findByIdempotencyKey(key string) (*models.FetchJob, error) {
+	var job models.FetchJob
+	err := q.db.Where("idempotency_key = ?", key).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询幂等键失败: %w", err)
+	}
+
+	if time.Since(job.CreatedAt) <= IdempotencyKeyTTL {
+		return &job, nil
+	}
+	if err := q.db.Model(&models.FetchJob{}).Where("id = ?", job.ID).
+		Update("idempotency_key", nil).Error; err != nil {
+		return nil, fmt.Errorf("清除过期的幂等键失败: %w", err)
+	}
+	return nil, nil
+}
+
+// GetJob 按作业ID查询作业
+func (q *JobQueue) GetJob(jobID string) (*models.FetchJob, error) {
+	var job models.FetchJob
+	if err := q.db.Where("job_id = ?", jobID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// QueuedCount 返回排队中的作业数
+func (q *JobQueue) QueuedCount() (int64, error) {
+	var count int64
+	err := q.db.Model(&models.FetchJob{}).Where("status = ?", models.JobStatusQueued).Count(&count).Error
+	return count, err
+}
+
+// worker 按创建顺序领取并执行作业，队列为空时等待唤醒
+func (q *JobQueue) worker() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.stopping:
+			return
+		default:
+		}
+
+		job, err := q.claim()
+		if err != nil {
+			q.logger.Error("领取作业失败", zap.Error(err))
+		}
+		if job == nil {
+			select {
+			case <-q.stopping:
+				return
+			case <-q.wake:
+			case <-time.After(jobPollInterval):
+			}
+			continue
+		}
+
+		q.run(job)
+	}
+}
+
+// claim 将最早排队的作业标记为执行中并返回，队列为空时返回 nil
+// 以 status 作为更新条件，多个 worker 同时领取同一作业时只有一个成功
+func (q *JobQueue) claim() (*models.FetchJob, error) {
+	for {
+		var job models.FetchJob
+		err := q.db.Where("status = ?", models.JobStatusQueued).Order("id asc").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		result := q.db.Model(&models.FetchJob{}).
+			Where("id = ? AND status = ?", job.ID, models.JobStatusQueued).
+			Updates(map[string]interface{}{"status": models.JobStatusRunning, "started_at": now})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			job.Status = models.JobStatusRunning
+			job.StartedAt = &now
+			return &job, nil
+		}
+	}
+}
+
+// run 占用任务名额后执行作业并记录结果
+func (q *JobQueue) run(job *models.FetchJob) {
+	if err := q.fetcher.AcquireTask(q.stopCtx); err != nil {
+		// 关闭时仍在等待名额，放回队列
+		q.requeue(job)
+		return
+	}
+	defer q.fetcher.ReleaseTask()
+
+	ctx, cancel := q.fetcher.TaskContext()
+	defer cancel()
+	stopAbort := context.AfterFunc(q.runCtx, cancel)
+	defer stopAbort()
+
+	q.logger.Info("开始执行作业", zap.String("job_id", job.JobID), zap.String("type", job.Type))
+	task, err := q.execute(ctx, job)
+	if task != nil {
+		job.TaskID = task.TaskID
+		if err == nil {
+			err = taskError(task)
+		}
+	}
+
+	if q.runCtx.Err() != nil {
+		q.logger.Warn("作业被中断，重启后继续执行", zap.String("job_id", job.JobID), zap.String("task_id", job.TaskID))
+		q.requeue(job)
+		return
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = models.JobStatusCompleted
+	if err != nil {
+		job.Status = models.JobStatusFailed
+		job.ErrorMsg = err.Error()
+		q.logger.Error("作业执行失败", zap.String("job_id", job.JobID), zap.Error(err))
+	}
+	q.save(job)
+}
+
+// execute 按作业类型执行抓取，返回关联的抓取任务
+// 日线作业执行入队时创建的任务，被中断后重新执行时从该任务的检查点续传
+func (q *JobQueue) execute(ctx context.Context, job *models.FetchJob) (*models.FetchTask, error) {
+	var params JobParams
+	if err := json.Unmarshal([]byte(job.Params), &params); err != nil {
+		return nil, fmt.Errorf("解析作业参数失败: %w", err)
+	}
+
+	switch job.Type {
+	case JobTypeDaily:
+		if job.TaskID == "" {
+			// 升级前入队的作业没有预先创建任务
+			task, err := q.fetcher.QueueDailyTask(params.StartDate, params.EndDate)
+			if err != nil {
+				return nil, err
+			}
+			job.TaskID = task.TaskID
+			q.save(job)
+		}
+		task, err := q.fetcher.GetTaskProgress(job.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("任务不存在: %w", err)
+		}
+		if task.Status != models.TaskStatusPending {
+			return q.fetcher.FetchDailyResume(ctx, job.TaskID)
+		}
+		if err := q.fetcher.StartDailyTask(task); err != nil {
+			return task, err
+		}
+		q.fetcher.RunDailyTask(ctx, task, params.NewestFirst)
+		return task, nil
+	case JobTypeWeekly:
+		return q.fetcher.FetchWeeklyData(ctx, params.StartDate, params.EndDate)
+	case JobTypeMonthly:
+		return q.fetcher.FetchMonthlyData(ctx, params.StartDate, params.EndDate)
+	default:
+		return nil, fmt.Errorf("未知的作业类型: %s", job.Type)
+	}
+}
+
+// taskError 返回抓取任务未成功完成的原因，任务已完成时返回 nil
+// 抓取方法在任务失败、超时时只更新任务状态而不返回错误，作业状态以任务状态为准
+func taskError(task *models.FetchTask) error {
+	if task.Status == models.TaskStatusCompleted {
+		return nil
+	}
+	if task.ErrorMsg != "" {
+		return fmt.Errorf("任务 %s 未完成（%s）: %s", task.TaskID, task.Status, task.ErrorMsg)
+	}
+	return fmt.Errorf("任务 %s 未完成（%s）", task.TaskID, task.Status)
+}
+
+// requeue 将作业放回队列
+func (q *JobQueue) requeue(job *models.FetchJob) {
+	job.Status = models.JobStatusQueued
+	job.StartedAt = nil
+	q.save(job)
+}
+
+// save 保存作业状态，失败只记录日志
+func (q *JobQueue) save(job *models.FetchJob) {
+	if err := q.db.Save(job).Error; err != nil {
+		q.logger.Error("保存作业状态失败", zap.String("job_id", job.JobID), zap.Error(err))
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestJobQueue 创建使用内存 SQLite 的作业队列，Tushare 接口均返回空数据
+func newTestJobQueue(t *testing.T) *JobQueue {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"cal_date", "is_open"}})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := newTestFetcher(t, &models.FetchJob{}, &models.FetchTask{}, &models.FetchTaskDate{})
+	fetcher.taskSlots = make(chan struct{}, 1)
+	fetcher.rateLimiter = NewRateLimiter(6000)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	q := NewJobQueue(fetcher, 2, zap.NewNop())
+	t.Cleanup(func() { q.Shutdown(context.Background()) })
+	return q
+}
+
+// waitJobStatus 等待作业进入指定状态
+func waitJobStatus(t *testing.T, q *JobQueue, jobID, status string) *models.FetchJob {
+	t.Helper()
+	var job *models.FetchJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = q.GetJob(jobID)
+		return err == nil && job.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+// TestJobQueue_Enqueue 测试作业入队后由 worker 执行并关联抓取任务
+func TestJobQueue_Enqueue(t *testing.T) {
+	q := newTestJobQueue(t)
+	require.NoError(t, q.Start())
+
+	job, err := q.Enqueue(JobTypeWeekly, JobParams{StartDate: "20231201", EndDate: "20231231"})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusQueued, job.Status)
+	assert.Empty(t, job.TaskID)
+
+	done := waitJobStatus(t, q, job.JobID, models.JobStatusCompleted)
+	require.NotEmpty(t, done.TaskID)
+	assert.NotNil(t, done.FinishedAt)
+
+	task, err := q.fetcher.GetTaskProgress(done.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, "20231201", task.StartDate)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+
+	// 执行完毕后释放任务名额
+	active, _ := q.fetcher.TaskSlots()
+	assert.Equal(t, 0, active)
+
+	_, err = q.Enqueue("quarterly", JobParams{})
+	assert.Error(t, err)
+}
+
+// TestJobQueue_EnqueueDaily 测试日线作业入队时创建等待中的任务，执行时沿用该任务
+func TestJobQueue_EnqueueDaily(t *testing.T) {
+	q := newTestJobQueue(t)
+
+	job, err := q.Enqueue(JobTypeDaily, JobParams{StartDate: "20231201", EndDate: "20231231"})
+	require.NoError(t, err)
+	require.NotEmpty(t, job.TaskID)
+
+	task, err := q.fetcher.GetTaskProgress(job.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusPending, task.Status)
+
+	require.NoError(t, q.Start())
+	done := waitJobStatus(t, q, job.JobID, models.JobStatusCompleted)
+	assert.Equal(t, job.TaskID, done.TaskID)
+
+	task, err = q.fetcher.GetTaskProgress(job.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+
+	var count int64
+	require.NoError(t, q.db.Model(&models.FetchTask{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestJobQueue_TaskFailed 测试抓取任务失败时作业同样标记为失败并记录任务的错误信息
+func TestJobQueue_TaskFailed(t *testing.T) {
+	q := newTestJobQueue(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TushareResponse{Code: 40203, Msg: "抱歉，您没有访问该接口的权限"})
+	}))
+	t.Cleanup(server.Close)
+	q.fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+	require.NoError(t, q.Start())
+
+	job, err := q.Enqueue(JobTypeDaily, JobParams{StartDate: "20231201", EndDate: "20231231"})
+	require.NoError(t, err)
+
+	failed := waitJobStatus(t, q, job.JobID, models.JobStatusFailed)
+	assert.Contains(t, failed.ErrorMsg, job.TaskID)
+	assert.Contains(t, failed.ErrorMsg, "没有访问该接口的权限")
+
+	task, err := q.fetcher.GetTaskProgress(job.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusFailed, task.Status)
+}
+
+// TestJobQueue_RecoverOnStart 测试上次退出时排队中和执行中的作业在启动后继续执行
+func TestJobQueue_RecoverOnStart(t *testing.T) {
+	q := newTestJobQueue(t)
+	params, _ := json.Marshal(JobParams{StartDate: "20231201", EndDate: "20231231"})
+	require.NoError(t, q.db.Create(&[]models.FetchJob{
+		{JobID: "job_queued", Type: JobTypeMonthly, Params: string(params), Status: models.JobStatusQueued},
+		{JobID: "job_running", Type: JobTypeWeekly, Params: string(params), Status: models.JobStatusRunning},
+		{JobID: "job_unknown", Type: "quarterly", Params: string(params), Status: models.JobStatusRunning},
+	}).Error)
+
+	require.NoError(t, q.Start())
+
+	waitJobStatus(t, q, "job_queued", models.JobStatusCompleted)
+	waitJobStatus(t, q, "job_running", models.JobStatusCompleted)
+	failed := waitJobStatus(t, q, "job_unknown", models.JobStatusFailed)
+	assert.Contains(t, failed.ErrorMsg, "未知的作业类型")
+}
+
+// TestJobQueue_ShutdownWhileWaitingSlot 测试关闭时仍在等待任务名额的作业放回队列
+func TestJobQueue_ShutdownWhileWaitingSlot(t *testing.T) {
+	q := newTestJobQueue(t)
+	// 名额被其他抓取接口占用
+	require.True(t, q.fetcher.TryAcquireTask())
+	require.NoError(t, q.Start())
+
+	job, err := q.Enqueue(JobTypeWeekly, JobParams{StartDate: "20231201", EndDate: "20231231"})
+	require.NoError(t, err)
+	waitJobStatus(t, q, job.JobID, models.JobStatusRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, q.Shutdown(ctx))
+
+	stored, err := q.GetJob(job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusQueued, stored.Status)
+	assert.Nil(t, stored.StartedAt)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"stock_data/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// dataTables 新增抓取接口写入的数据表及日线任务的检查点表，由服务启动时创建；已有的表只补充缺失的列和索引
+var dataTables = []interface{}{
+	&models.FetchTaskDate{},
+	&models.FinaIndicator{},
+	&models.StockConcept{},
+	&models.StockMinute{},
+	&models.StockCompany{},
+	&models.TopListEntry{},
+	&models.MarginDetail{},
+	&models.StockAdjFactor{},
+	&models.IndexBasic{},
+	&models.HSConst{},
+	&models.TradeCalendar{},
+	&models.BlockTrade{},
+	&models.StockDailyAdj{},
+	&models.FundBasic{},
+	&models.FundDaily{},
+}
+
+// MigrateDataTables 创建新增抓取接口写入的数据表及检查点表，在启动时调用
+func MigrateDataTables(db *gorm.DB) error {
+	return db.AutoMigrate(dataTables...)
+}
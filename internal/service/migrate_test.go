@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestMigrateDataTables_Resume 测试空数据库只有原有的表时，启动迁移创建检查点表，中断的日线任务可以续传
+func TestMigrateDataTables_Resume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var data TushareData
+		switch req.APIName {
+		case "trade_cal":
+			data = TushareData{
+				Fields: []string{"exchange", "cal_date", "is_open"},
+				Items:  [][]interface{}{{req.Params["exchange"], "20231201", 1}, {req.Params["exchange"], "20231204", 1}},
+			}
+		case "daily":
+			assert.Equal(t, "20231204", req.Params["trade_date"])
+			data = TushareData{
+				Fields: []string{"ts_code", "trade_date", "close"},
+				Items:  [][]interface{}{{"000001.SZ", req.Params["trade_date"], 10.5}},
+			}
+		default:
+			t.Errorf("unexpected api %s", req.APIName)
+		}
+		dataBytes, _ := json.Marshal(data)
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.FetchTask{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	require.NoError(t, MigrateDataTables(fetcher.db))
+	require.NoError(t, MigrateStockLatest(fetcher.db))
+	for _, table := range dataTables {
+		assert.True(t, fetcher.db.Migrator().HasTable(table))
+	}
+
+	// 中断前已完成 20231201
+	task := &models.FetchTask{TaskID: newTaskID("task_"), StartDate: "20231201", EndDate: "20231204", Status: models.TaskStatusInterrupted, StartTime: time.Now()}
+	require.NoError(t, fetcher.db.Create(task).Error)
+	fetcher.saveTaskDate(task.TaskID, "20231201", models.TaskDateCompleted)
+
+	task, err := fetcher.FetchDailyResume(context.Background(), task.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+	assert.Equal(t, 2, task.SuccessCount)
+
+	var dates []models.FetchTaskDate
+	require.NoError(t, fetcher.db.Where("task_id = ?", task.TaskID).Order("date").Find(&dates).Error)
+	require.Len(t, dates, 2)
+	assert.Equal(t, "20231204", dates[1].Date)
+	assert.Equal(t, models.TaskDateCompleted, dates[1].Status)
+}
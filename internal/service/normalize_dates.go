@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"stock_data/internal/models"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// dateLayouts 可识别的日期格式，按顺序尝试；带时间的格式只保留日期部分
+var dateLayouts = []string{
+	"20060102",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// NormalizeDate 将日期字符串转换为 YYYYMMDD，无法识别或不是有效日期（如 20231340）时返回 false
+func NormalizeDate(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("20060102"), true
+		}
+	}
+	return "", false
+}
+
+// DateIssue 格式不规范的日期
+type DateIssue struct {
+	TSCode     string `json:"ts_code"`
+	Value      string `json:"value"`      // 原始值
+	Normalized string `json:"normalized"` // 规范化后的值，无法解析时为空
+}
+
+// NormalizeDatesResult 日期规范化结果
+type NormalizeDatesResult struct {
+	Fix     bool        `json:"fix"`     // 是否已写回规范化后的值
+	Scanned int         `json:"scanned"` // 检查的记录数
+	Valid   int         `json:"valid"`   // 已是 YYYYMMDD 的记录数
+	Empty   int         `json:"empty"`   // 日期为空的记录数，不视为问题
+	Fixable int         `json:"fixable"` // 可以转换为 YYYYMMDD 的记录数
+	Invalid int         `json:"invalid"` // 无法解析的记录数，需人工处理
+	Fixed   int         `json:"fixed"`   // 已更新的记录数
+	Issues  []DateIssue `json:"issues"`  // 可修复和无法解析的记录明细
+}
+
+// NormalizeListDates 检查 stock_basic.list_date 是否均为有效的 YYYYMMDD，记录格式不规范的值
+// fix 为 true 时将可识别的其他格式（如 2023-12-01）改写为 YYYYMMDD，无法解析的值保持不变
+func (f *DataFetcher) NormalizeListDates(fix bool) (*NormalizeDatesResult, error) {
+	result := &NormalizeDatesResult{Fix: fix, Issues: []DateIssue{}}
+	var fixes []DateIssue
+
+	var batch []models.StockBasic
+	err := f.db.Select("id, ts_code, list_date").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for _, stock := range batch {
+			result.Scanned++
+			if strings.TrimSpace(stock.ListDate) == "" {
+				result.Empty++
+				continue
+			}
+
+			normalized, ok := NormalizeDate(stock.ListDate)
+			if ok && normalized == stock.ListDate {
+				result.Valid++
+				continue
+			}
+
+			issue := DateIssue{TSCode: stock.TSCode, Value: stock.ListDate, Normalized: normalized}
+			result.Issues = append(result.Issues, issue)
+			if !ok {
+				result.Invalid++
+				f.logger.Warn("上市日期无法解析", zap.String("ts_code", stock.TSCode), zap.String("list_date", stock.ListDate))
+				continue
+			}
+			result.Fixable++
+			fixes = append(fixes, issue)
+			f.logger.Warn("上市日期格式不规范",
+				zap.String("ts_code", stock.TSCode),
+				zap.String("list_date", stock.ListDate),
+				zap.String("normalized", normalized))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询股票列表失败: %w", err)
+	}
+
+	if !fix {
+		return result, nil
+	}
+	for _, issue := range fixes {
+		if err := f.db.Model(&models.StockBasic{}).
+			Where("ts_code = ? AND list_date = ?", issue.TSCode, issue.Value).
+			Update("list_date", issue.Normalized).Error; err != nil {
+			return result, fmt.Errorf("更新 %s 的上市日期失败: %w", issue.TSCode, err)
+		}
+		result.Fixed++
+	}
+
+	f.logger.Info("上市日期规范化完成",
+		zap.Int("scanned", result.Scanned),
+		zap.Int("fixed", result.Fixed),
+		zap.Int("invalid", result.Invalid))
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNormalizeDate 测试常见日期格式转换为 YYYYMMDD
+func TestNormalizeDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{value: "20231201", want: "20231201", ok: true},
+		{value: " 20231201 ", want: "20231201", ok: true},
+		{value: "2023-12-01", want: "20231201", ok: true},
+		{value: "2023/12/1", want: "20231201", ok: true},
+		{value: "2023.12.01", want: "20231201", ok: true},
+		{value: "2023-12-01 00:00:00", want: "20231201", ok: true},
+		{value: "2023-12-01T00:00:00+08:00", want: "20231201", ok: true},
+		{value: "20231340", ok: false},
+		{value: "2023120", ok: false},
+		{value: "unknown", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeDate(tt.value)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+// TestNormalizeListDates 测试只检查时不修改数据，fix 时只改写可解析的值
+func TestNormalizeListDates(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockBasic{})
+	require.NoError(t, fetcher.db.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", ListDate: "19910403"},
+		{TSCode: "000002.SZ", ListDate: "1991-1-29"},
+		{TSCode: "000004.SZ", ListDate: "1991/13/1"},
+		{TSCode: "000005.SZ", ListDate: ""},
+	}).Error)
+
+	listDates := func() map[string]string {
+		var stocks []models.StockBasic
+		require.NoError(t, fetcher.db.Find(&stocks).Error)
+		dates := make(map[string]string, len(stocks))
+		for _, stock := range stocks {
+			dates[stock.TSCode] = stock.ListDate
+		}
+		return dates
+	}
+
+	result, err := fetcher.NormalizeListDates(false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Scanned)
+	assert.Equal(t, 1, result.Valid)
+	assert.Equal(t, 1, result.Empty)
+	assert.Equal(t, 1, result.Fixable)
+	assert.Equal(t, 1, result.Invalid)
+	assert.Equal(t, 0, result.Fixed)
+	assert.ElementsMatch(t, []DateIssue{
+		{TSCode: "000002.SZ", Value: "1991-1-29", Normalized: "19910129"},
+		{TSCode: "000004.SZ", Value: "1991/13/1"},
+	}, result.Issues)
+	assert.Equal(t, "1991-1-29", listDates()["000002.SZ"])
+
+	result, err = fetcher.NormalizeListDates(true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Fixed)
+	assert.Equal(t, map[string]string{
+		"000001.SZ": "19910403",
+		"000002.SZ": "19910129",
+		"000004.SZ": "1991/13/1",
+		"000005.SZ": "",
+	}, listDates())
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ProgressListener 任务状态监听器，任务进入结束状态并保存后回调
+// 回调在抓取流程中同步执行，耗时操作需自行异步处理
+type ProgressListener interface {
+	OnTaskFinished(task models.FetchTask)
+}
+
+// AddListener 注册任务状态监听器，需在启动抓取任务前调用
+func (f *DataFetcher) AddListener(listener ProgressListener) {
+	f.listeners = append(f.listeners, listener)
+}
+
+// saveTask 保存任务，任务已结束（完成、失败、超时等）时通知监听器
+func (f *DataFetcher) saveTask(task *models.FetchTask) {
+	// 运行中的任务确定总数后给出初始的预计完成时间，之后由 updateTaskProgress 按实际进度更新
+	if task.Status == models.TaskStatusRunning && task.EstimatedEndTime == nil {
+		task.EstimatedEndTime = f.estimateEndTime(task, task.SuccessCount+task.FailedCount, time.Now())
+	}
+	if task.Status != models.TaskStatusRunning && task.Status != models.TaskStatusPending {
+		f.progress.finish(task.ID)
+	}
+	f.db.Save(task)
+
+	if task.Status == models.TaskStatusRunning || task.Status == models.TaskStatusPending {
+		return
+	}
+	for _, listener := range f.listeners {
+		listener.OnTaskFinished(*task)
+	}
+}
+
+// TaskNotification 任务结束通知的请求体
+type TaskNotification struct {
+	Event          string     `json:"event"` // 任务状态，与 status 相同
+	TaskID         string     `json:"task_id"`
+	Status         string     `json:"status"`
+	StartDate      string     `json:"start_date"`
+	EndDate        string     `json:"end_date"`
+	TotalCount     int        `json:"total_count"`
+	SuccessCount   int        `json:"success_count"`
+	FailedCount    int        `json:"failed_count"`
+	RowsFetched    int64      `json:"rows_fetched"`
+	RowsStored     int64      `json:"rows_stored"`
+	ErrorMsg       string     `json:"error_msg"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        *time.Time `json:"end_time"`
+	ElapsedSeconds float64    `json:"elapsed_seconds"`
+}
+
+// newTaskNotification 由任务记录生成通知内容
+func newTaskNotification(task models.FetchTask) TaskNotification {
+	n := TaskNotification{
+		Event:        string(task.Status),
+		TaskID:       task.TaskID,
+		Status:       string(task.Status),
+		StartDate:    task.StartDate,
+		EndDate:      task.EndDate,
+		TotalCount:   task.TotalCount,
+		SuccessCount: task.SuccessCount,
+		FailedCount:  task.FailedCount,
+		RowsFetched:  task.RowsFetched,
+		RowsStored:   task.RowsStored,
+		ErrorMsg:     task.ErrorMsg,
+		StartTime:    task.StartTime,
+		EndTime:      task.EndTime,
+	}
+	if task.EndTime != nil {
+		n.ElapsedSeconds = task.EndTime.Sub(task.StartTime).Seconds()
+	}
+	return n
+}
+
+// WebhookNotifier 任务结束时 POST 任务摘要到配置的地址，失败时按指数退避重试
+type WebhookNotifier struct {
+	url        string
+	events     map[string]bool
+	retry      int
+	retryDelay time.Duration
+	client     *http.Client
+	logger     *zap.Logger
+}
+
+// NewWebhookNotifier 创建 Webhook 通知器
+func NewWebhookNotifier(cfg *config.NotifyConfig, logger *zap.Logger) *WebhookNotifier {
+	events := make(map[string]bool, len(cfg.Events))
+	for _, event := range cfg.Events {
+		events[event] = true
+	}
+	return &WebhookNotifier{
+		url:        cfg.WebhookURL,
+		events:     events,
+		retry:      cfg.Retry,
+		retryDelay: time.Duration(cfg.RetryDelay) * time.Second,
+		client:     &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+		logger:     logger,
+	}
+}
+
+// OnTaskFinished 异步投递通知，不阻塞抓取流程
+func (n *WebhookNotifier) OnTaskFinished(task models.FetchTask) {
+	if !n.events[string(task.Status)] {
+		return
+	}
+	go n.deliver(newTaskNotification(task))
+}
+
+// deliver 投递通知，第 i 次重试前等待 retryDelay×2^(i-1)，全部失败时记录错误日志
+func (n *WebhookNotifier) deliver(notification TaskNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		n.logger.Error("序列化任务通知失败", zap.String("task_id", notification.TaskID), zap.Error(err))
+		return err
+	}
+
+	var lastErr error
+	delay := n.retryDelay
+	for i := 0; i <= n.retry; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if lastErr = n.post(body); lastErr == nil {
+			n.logger.Info("任务通知已发送",
+				zap.String("task_id", notification.TaskID),
+				zap.String("event", notification.Event))
+			return nil
+		}
+		n.logger.Warn("发送任务通知失败",
+			zap.String("task_id", notification.TaskID),
+			zap.Int("attempt", i+1),
+			zap.Error(lastErr))
+	}
+
+	n.logger.Error("任务通知重试后仍失败，已放弃",
+		zap.String("task_id", notification.TaskID),
+		zap.String("event", notification.Event),
+		zap.Int("retry", n.retry),
+		zap.Error(lastErr))
+	return lastErr
+}
+
+// post 发送一次通知请求，非 2xx 响应视为失败
+func (n *WebhookNotifier) post(body []byte) error {
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("响应状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingListener 记录收到的任务结束回调
+type recordingListener struct {
+	tasks []models.FetchTask
+}
+
+func (l *recordingListener) OnTaskFinished(task models.FetchTask) {
+	l.tasks = append(l.tasks, task)
+}
+
+// TestSaveTask_NotifiesOnTerminalStatus 测试只有任务结束时才通知监听器
+func TestSaveTask_NotifiesOnTerminalStatus(t *testing.T) {
+	f := newTestFetcher(t, &models.FetchTask{})
+	listener := &recordingListener{}
+	f.AddListener(listener)
+
+	task := &models.FetchTask{TaskID: "daily_task_1", Status: models.TaskStatusRunning, StartTime: time.Now()}
+	require.NoError(t, f.db.Create(task).Error)
+
+	task.TotalCount = 10
+	f.saveTask(task)
+	assert.Empty(t, listener.tasks)
+
+	f.finishTask(task, nil)
+	f.saveTask(task)
+	require.Len(t, listener.tasks, 1)
+	assert.Equal(t, models.TaskStatusCompleted, listener.tasks[0].Status)
+}
+
+// TestWebhookNotifier_RetryWithBackoff 测试投递失败后重试直至成功
+func TestWebhookNotifier_RetryWithBackoff(t *testing.T) {
+	var attempts int32
+	var received TaskNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(&config.NotifyConfig{
+		WebhookURL: server.URL,
+		Events:     []string{"failed"},
+		Timeout:    5,
+		Retry:      3,
+	}, zap.NewNop())
+	notifier.retryDelay = time.Millisecond
+
+	end := time.Now()
+	task := models.FetchTask{
+		TaskID:       "daily_task_2",
+		Status:       models.TaskStatusFailed,
+		StartTime:    end.Add(-time.Minute),
+		EndTime:      &end,
+		SuccessCount: 8,
+		FailedCount:  2,
+		ErrorMsg:     "熔断",
+	}
+
+	require.NoError(t, notifier.deliver(newTaskNotification(task)))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, "failed", received.Event)
+	assert.Equal(t, "daily_task_2", received.TaskID)
+	assert.Equal(t, 2, received.FailedCount)
+	assert.InDelta(t, 60, received.ElapsedSeconds, 0.001)
+
+	// 重试次数用尽后返回最后一次错误
+	notifier.retry = 0
+	atomic.StoreInt32(&attempts, 0)
+	assert.Error(t, notifier.deliver(newTaskNotification(task)))
+}
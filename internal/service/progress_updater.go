@@ -0,0 +1,109 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// progressUpdater 按任务限制进度写库的频率：距上次写入不足 interval 时只保留最新的一次更新，
+// 到期后由定时器补写，进度达到 100% 时立即写入。调用方的计数器不受影响，只减少写库次数
+type progressUpdater struct {
+	interval time.Duration
+	mu       sync.Mutex
+	tasks    map[uint]*taskProgressState
+}
+
+// taskProgressState 单个任务的写入状态，mu 串行化该任务的写入，避免较早的进度覆盖较新的进度
+type taskProgressState struct {
+	mu      sync.Mutex
+	last    time.Time
+	pending func() // 尚未写入的最新一次更新
+	timer   *time.Timer
+}
+
+// newProgressUpdater 创建进度写入节流器，interval 为 0 时每次更新都直接写入
+func newProgressUpdater(interval time.Duration) *progressUpdater {
+	return &progressUpdater{
+		interval: interval,
+		tasks:    make(map[uint]*taskProgressState),
+	}
+}
+
+// update 写入或暂存任务进度，final 为 true 时（进度达到 100%）立即写入并取消暂存的更新
+// u 为 nil（未通过 NewDataFetcher 创建）时直接写入
+func (u *progressUpdater) update(taskID uint, final bool, write func()) {
+	if u == nil || u.interval <= 0 {
+		write()
+		return
+	}
+
+	state := u.state(taskID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	elapsed := time.Since(state.last)
+	if final || elapsed >= u.interval {
+		if state.timer != nil {
+			state.timer.Stop()
+			state.timer = nil
+		}
+		state.pending = nil
+		state.last = time.Now()
+		write()
+		return
+	}
+
+	state.pending = write
+	if state.timer == nil {
+		state.timer = time.AfterFunc(u.interval-elapsed, func() { u.flush(state) })
+	}
+}
+
+// flush 写入定时器到期时暂存的更新
+func (u *progressUpdater) flush(state *taskProgressState) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.timer = nil
+	if state.pending == nil {
+		return
+	}
+	state.pending()
+	state.pending = nil
+	state.last = time.Now()
+}
+
+// finish 丢弃任务暂存的更新，任务结束保存最终状态前调用，避免之后补写的旧进度覆盖最终结果
+func (u *progressUpdater) finish(taskID uint) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	state, ok := u.tasks[taskID]
+	delete(u.tasks, taskID)
+	u.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+	state.pending = nil
+}
+
+// state 返回任务的写入状态，不存在时创建
+func (u *progressUpdater) state(taskID uint) *taskProgressState {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	state, ok := u.tasks[taskID]
+	if !ok {
+		state = &taskProgressState{}
+		u.tasks[taskID] = state
+	}
+	return state
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProgressUpdater 测试间隔内的更新只补写最新一次，100% 立即写入，任务结束后不再补写
+func TestProgressUpdater(t *testing.T) {
+	updater := newProgressUpdater(200 * time.Millisecond)
+
+	var mu sync.Mutex
+	var written []int
+	write := func(progress int) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			written = append(written, progress)
+		}
+	}
+	writes := func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), written...)
+	}
+
+	// 首次更新直接写入，间隔内的更新合并为最后一次
+	for progress := 1; progress <= 5; progress++ {
+		updater.update(1, false, write(progress))
+	}
+	assert.Equal(t, []int{1}, writes())
+	assert.Eventually(t, func() bool { return len(writes()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{1, 5}, writes())
+
+	// 100% 立即写入，并取消暂存的更新
+	updater.update(1, false, write(6))
+	updater.update(1, true, write(100))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, []int{1, 5, 100}, writes())
+
+	// 任务结束后丢弃暂存的更新
+	updater.update(2, false, write(10))
+	updater.update(2, false, write(20))
+	updater.finish(2)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, []int{1, 5, 100, 10}, writes())
+}
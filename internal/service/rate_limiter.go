@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimiter 全局共享的请求限流器
+// 所有抓取任务共用同一个实例，按调用顺序依次分配请求时间槽，
+// 保证无论同时运行多少任务，相邻两次请求的间隔都不小于 interval
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time   // 下一个可用的请求时间槽
+	released []time.Time // 等待期间 ctx 取消而退回的时间槽，按时间升序，优先分配
+}
+
+// NewRateLimiter 创建限流器
+// perMinute: 每分钟最大请求数，<= 0 表示不限流
+func NewRateLimiter(perMinute int) *RateLimiter {
+	var interval time.Duration
+	if perMinute > 0 {
+		interval = time.Minute / time.Duration(perMinute)
+	}
+	return &RateLimiter{interval: interval}
+}
+
+// Wait 等待获取一次请求许可，ctx 取消时立即返回错误
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if l.interval <= 0 {
+		return nil
+	}
+
+	slot := l.reserve()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.release(slot)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve 预约时间槽：优先使用尚未到期的退回时间槽，否则顺延分配新的时间槽
+func (l *RateLimiter) reserve() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for len(l.released) > 0 {
+		slot := l.released[0]
+		l.released = l.released[1:]
+		if !slot.Before(now) {
+			return slot
+		}
+	}
+
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	return slot
+}
+
+// release 退回未使用的时间槽，供之后的请求使用，避免取消的等待白白占用请求配额
+// 最后分配的时间槽直接回收，其余的按时间顺序放入 released
+func (l *RateLimiter) release(slot time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.next.Equal(slot.Add(l.interval)) {
+		l.next = slot
+		return
+	}
+	i := sort.Search(len(l.released), func(i int) bool { return !l.released[i].Before(slot) })
+	l.released = append(l.released, time.Time{})
+	copy(l.released[i+1:], l.released[i:])
+	l.released[i] = slot
+}
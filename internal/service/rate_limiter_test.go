@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimiter_SharedAcrossTasks 测试多个并发任务共享限流器时总请求速率不超限
+func TestRateLimiter_SharedAcrossTasks(t *testing.T) {
+	limiter := NewRateLimiter(1200) // 每 50ms 一次
+	interval := time.Minute / 1200
+
+	var mu sync.Mutex
+	var calls []time.Time
+
+	// 模拟两个同时运行的抓取任务，每个任务内部再并发请求
+	var wg sync.WaitGroup
+	for task := 0; task < 2; task++ {
+		for worker := 0; worker < 5; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 2; i++ {
+					require.NoError(t, limiter.Wait(context.Background()))
+					mu.Lock()
+					calls = append(calls, time.Now())
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	require.Len(t, calls, 20)
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Before(calls[j]) })
+
+	// 相邻请求间隔不小于限流间隔（留少量计时误差）
+	for i := 1; i < len(calls); i++ {
+		assert.GreaterOrEqual(t, calls[i].Sub(calls[i-1]), interval-5*time.Millisecond)
+	}
+
+	// 总耗时不小于 (n-1) * interval，即整体速率不超过限制
+	assert.GreaterOrEqual(t, calls[len(calls)-1].Sub(calls[0]), time.Duration(len(calls)-1)*interval-5*time.Millisecond)
+}
+
+// TestRateLimiter_ContextCancel 测试等待期间 ctx 取消立即返回
+func TestRateLimiter_ContextCancel(t *testing.T) {
+	limiter := NewRateLimiter(1) // 每分钟一次
+
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestRateLimiter_ReleaseOnCancel 测试等待期间 ctx 取消时退回预约的时间槽，不推迟之后的请求
+func TestRateLimiter_ReleaseOnCancel(t *testing.T) {
+	limiter := NewRateLimiter(600) // 每 100ms 一次
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	// 预约第二、三个时间槽后全部取消
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- limiter.Wait(ctx) }()
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	for i := 0; i < 2; i++ {
+		require.ErrorIs(t, <-errs, context.Canceled)
+	}
+
+	// 取消的时间槽已退回，下一次请求仍使用第二个时间槽（约 100ms 后），而不是第四个
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+
+	// 之后的请求仍保持限流间隔
+	start = time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RawResponseStore 将 Tushare 原始响应保存到 raw_responses 表，按条数和保留天数清理旧记录
+// 每次请求都会写库，只应在排查数据问题时开启
+type RawResponseStore struct {
+	db       *gorm.DB
+	maxCount int
+	maxAge   time.Duration
+	logger   *zap.Logger
+}
+
+// NewRawResponseStore 创建原始响应存储
+func NewRawResponseStore(db *gorm.DB, cfg *config.TushareConfig, logger *zap.Logger) *RawResponseStore {
+	return &RawResponseStore{
+		db:       db,
+		maxCount: cfg.RawResponseMaxCount,
+		maxAge:   time.Duration(cfg.RawResponseMaxAge) * 24 * time.Hour,
+		logger:   logger,
+	}
+}
+
+// Migrate 创建 raw_responses 表，仅在开启原始响应保存时调用，避免未使用该功能时建表
+func (s *RawResponseStore) Migrate() error {
+	return s.db.AutoMigrate(&models.RawResponse{})
+}
+
+// paramsHash 返回请求参数的 JSON 及其 SHA-256，map 序列化时键有序，相同参数得到相同的哈希
+func paramsHash(params map[string]interface{}) (string, string) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", ""
+	}
+	sum := sha256.Sum256(data)
+	return string(data), hex.EncodeToString(sum[:])
+}
+
+// RecordResponse 保存一次响应并清理超出保留范围的记录，失败只记录日志，不影响抓取
+func (s *RawResponseStore) RecordResponse(apiName string, params map[string]interface{}, fields string, resp *TushareResponse) {
+	paramsJSON, hash := paramsHash(params)
+	record := &models.RawResponse{
+		APIName:    apiName,
+		ParamsHash: hash,
+		Params:     paramsJSON,
+		Fields:     fields,
+		Code:       resp.Code,
+		Msg:        resp.Msg,
+		Data:       string(resp.Data),
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		s.logger.Warn("保存 Tushare 原始响应失败", zap.String("api_name", apiName), zap.Error(err))
+		return
+	}
+	if err := s.prune(time.Now()); err != nil {
+		s.logger.Warn("清理 Tushare 原始响应失败", zap.Error(err))
+	}
+}
+
+// prune 删除超过保留天数的记录，以及按 id 倒序超出保留条数的记录
+func (s *RawResponseStore) prune(now time.Time) error {
+	if s.maxAge > 0 {
+		if err := s.db.Where("created_at < ?", now.Add(-s.maxAge)).Delete(&models.RawResponse{}).Error; err != nil {
+			return err
+		}
+	}
+	if s.maxCount > 0 {
+		var ids []uint
+		if err := s.db.Model(&models.RawResponse{}).Order("id desc").Offset(s.maxCount).Limit(1).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			return s.db.Where("id <= ?", ids[0]).Delete(&models.RawResponse{}).Error
+		}
+	}
+	return nil
+}
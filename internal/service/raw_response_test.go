@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRawResponseStore 测试开启后按接口名和参数哈希保存原始 data，并按条数清理旧记录
+func TestRawResponseStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "adj_factor"},
+			Items:  [][]interface{}{{"000001.SZ", req.Params["trade_date"], 108.031}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t)
+	cfg := &config.TushareConfig{Token: "secret_token", BaseURL: server.URL, Timeout: 5, RawResponseMaxCount: 2, RawResponseMaxAge: 3}
+	store := NewRawResponseStore(fetcher.db, cfg, zap.NewNop())
+	require.NoError(t, store.Migrate())
+	client := NewTushareClient(cfg, zap.NewNop())
+	client.SetRecorder(store)
+
+	for _, date := range []string{"20231201", "20231204", "20231201"} {
+		_, err := client.GetAdjFactor(context.Background(), date)
+		require.NoError(t, err)
+	}
+
+	// 只保留最近 2 条
+	var records []models.RawResponse
+	require.NoError(t, fetcher.db.Order("id").Find(&records).Error)
+	require.Len(t, records, 2)
+	assert.Equal(t, "adj_factor", records[0].APIName)
+	assert.Contains(t, records[0].Params, "20231204")
+	assert.Contains(t, records[1].Data, "108.031")
+	assert.NotContains(t, records[1].Params, "secret_token")
+
+	// 相同参数的哈希相同
+	_, hash := paramsHash(map[string]interface{}{"trade_date": "20231201"})
+	assert.Equal(t, hash, records[1].ParamsHash)
+	assert.NotEqual(t, records[0].ParamsHash, records[1].ParamsHash)
+
+	// 超过保留天数的记录被清理
+	require.NoError(t, fetcher.db.Model(&models.RawResponse{}).Where("id = ?", records[0].ID).
+		Update("created_at", time.Now().AddDate(0, 0, -4)).Error)
+	require.NoError(t, store.prune(time.Now()))
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.RawResponse{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
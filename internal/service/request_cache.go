@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// requestCache Tushare 相同请求（接口名、参数、字段均相同）的短期去重
+// 并发的相同请求合并为一次网络请求；成功的结果在 ttl 内直接复用，失败的结果不缓存
+// 返回的 *TushareData 由多个调用方共享，调用方只能读取
+type requestCache struct {
+	ttl     time.Duration
+	group   singleflight.Group
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// cachedResponse 缓存的响应数据及过期时间
+type cachedResponse struct {
+	data    *TushareData
+	expires time.Time
+}
+
+// newRequestCache 创建请求去重缓存，ttl 不大于 0 时返回 nil，表示不去重
+func newRequestCache(ttl time.Duration) *requestCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &requestCache{ttl: ttl, entries: make(map[string]cachedResponse)}
+}
+
+// bypassCacheKey context 中跳过请求去重的标记的键
+type bypassCacheKey struct{}
+
+// bypassRequestCache 返回跳过请求去重的 context：重新抓取、数据核对等显式调用须取得 Tushare 当前的数据，
+// 不合并到其他调用方的请求，也不复用有效期内的结果
+func bypassRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// requestCacheBypassed 判断 context 是否要求跳过请求去重
+func requestCacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// requestKey 按接口名、参数和字段计算请求的哈希，参数的键按 json.Marshal 排序，与传入顺序无关
+func requestKey(apiName string, params map[string]interface{}, fields string) string {
+	paramsJSON, _ := json.Marshal(params)
+	sum := sha256.Sum256([]byte(apiName + "\x00" + fields + "\x00" + string(paramsJSON)))
+	return hex.EncodeToString(sum[:])
+}
+
+// do 返回 key 对应的缓存结果，没有时调用 fetch；同一 key 的并发调用只执行一次 fetch，共享其结果和错误
+// shared 表示结果来自缓存或其他调用方发起的请求
+func (c *requestCache) do(key string, fetch func() (*TushareData, error)) (data *TushareData, shared bool, err error) {
+	if data, ok := c.get(key, time.Now()); ok {
+		return data, true, nil
+	}
+
+	result, err, shared := c.group.Do(key, func() (interface{}, error) {
+		data, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, data, time.Now())
+		return data, nil
+	})
+	if err != nil {
+		return nil, shared, err
+	}
+	return result.(*TushareData), shared, nil
+}
+
+// get 返回未过期的缓存结果
+func (c *requestCache) get(key string, now time.Time) (*TushareData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// set 缓存一次成功的结果，同时清理已过期的结果
+// ttl 很短，缓存中通常只有最近几秒的请求，逐个检查的开销可以忽略
+func (c *requestCache) set(key string, data *TushareData, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedResponse{data: data, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRequestCache 测试并发的相同请求合并为一次，结果在有效期内复用，失败、不同参数及显式跳过去重的请求不复用
+func TestRequestCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Params["exchange"] == "BSE" {
+			json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+			return
+		}
+		// 等待其余并发请求到达
+		time.Sleep(50 * time.Millisecond)
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"exchange", "cal_date", "is_open"},
+			Items:  [][]interface{}{{req.Params["exchange"], req.Params["start_date"], 1}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	client := NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5, DedupTTLMs: 300}, zap.NewNop())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cal, err := client.GetExchangeTradeCal(context.Background(), "SSE", "20231201", "20231201", 1)
+			assert.NoError(t, err)
+			assert.Len(t, cal, 1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 有效期内复用，参数不同时重新请求
+	_, err := client.GetExchangeTradeCal(context.Background(), "SSE", "20231201", "20231201", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	_, err = client.GetExchangeTradeCal(context.Background(), "SZSE", "20231201", "20231201", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// 失败的结果不缓存
+	for i := 0; i < 2; i++ {
+		_, err = client.GetExchangeTradeCal(context.Background(), "BSE", "20231201", "20231201", 1)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	// 过期后重新请求
+	time.Sleep(350 * time.Millisecond)
+	_, err = client.GetExchangeTradeCal(context.Background(), "SSE", "20231201", "20231201", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+
+	// 显式跳过去重时不复用有效期内的结果
+	_, err = client.GetExchangeTradeCal(bypassRequestCache(context.Background()), "SSE", "20231201", "20231201", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+// TestRequestCache_CallCount 测试任务请求数按实际发出的 HTTP 请求计入：重试计入，复用的结果不计入
+func TestRequestCache_CallCount(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"exchange", "cal_date", "is_open"},
+			Items:  [][]interface{}{{"SSE", "20231201", 1}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	client := NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5, Retry: 1, DedupTTLMs: 300}, zap.NewNop())
+	client.retryDelay = time.Millisecond
+	rows := newRowTracker(nil)
+	ctx := withCallCounter(context.Background(), rows)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetExchangeTradeCal(ctx, "SSE", "20231201", "20231201", 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(2), rows.callCount())
+
+	// 不属于任务的请求不计数
+	_, err := client.GetExchangeTradeCal(context.Background(), "SZSE", "20231201", "20231201", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rows.callCount())
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"stock_data/internal/models"
+	"sync"
+)
+
+// rowTracker 并发安全地累计任务内各接口返回与入库的行数，以及发起的 Tushare 请求数
+// 二者不一致说明有数据在解析或入库时被丢弃，而 success_count 只统计日期/股票数，无法反映这种情况
+type rowTracker struct {
+	mu       sync.Mutex
+	metrics  models.RowMetrics
+	apiCalls int64
+}
+
+// newRowTracker 创建行数统计，续传任务传入已有统计继续累计
+func newRowTracker(initial models.RowMetrics) *rowTracker {
+	metrics := make(models.RowMetrics, len(initial))
+	for api, count := range initial {
+		metrics[api] = count
+	}
+	return &rowTracker{metrics: metrics}
+}
+
+// record 累计某个接口一次调用返回的行数和入库的行数
+func (r *rowTracker) record(api string, fetched, stored int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.metrics[api]
+	count.Fetched += int64(fetched)
+	count.Stored += int64(stored)
+	r.metrics[api] = count
+}
+
+// call 累计一次 Tushare 请求，请求失败也消耗配额，因此在发起请求前计数
+// 由客户端每次发出 HTTP 请求时调用，重试计入请求数，复用相同请求结果时不计入
+func (r *rowTracker) call() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiCalls++
+}
+
+// callCount 返回已发起的 Tushare 请求数
+func (r *rowTracker) callCount() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.apiCalls
+}
+
+// callCounterKey 任务请求计数在 context 中的键
+type callCounterKey struct{}
+
+// withCallCounter 返回携带任务请求计数的 context，客户端用该 context 发出的 HTTP 请求计入 rows
+func withCallCounter(ctx context.Context, rows *rowTracker) context.Context {
+	return context.WithValue(ctx, callCounterKey{}, rows)
+}
+
+// countCall 累计 context 所属任务的 Tushare 请求数，不属于任务的请求不计数
+func countCall(ctx context.Context) {
+	if rows, ok := ctx.Value(callCounterKey{}).(*rowTracker); ok {
+		rows.call()
+	}
+}
+
+// snapshot 返回统计副本及全部接口的合计
+func (r *rowTracker) snapshot() (metrics models.RowMetrics, fetched, stored int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics = make(models.RowMetrics, len(r.metrics))
+	for api, count := range r.metrics {
+		metrics[api] = count
+		fetched += count.Fetched
+		stored += count.Stored
+	}
+	return metrics, fetched, stored
+}
+
+// applyTo 将统计写入任务记录
+func (r *rowTracker) applyTo(task *models.FetchTask) {
+	task.RowMetrics, task.RowsFetched, task.RowsStored = r.snapshot()
+	task.APICalls = r.callCount()
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RefreshStockBasic 重新抓取股票列表，返回本次新增（此前不在 stock_basic 中）的股票
+// 已有股票按 ts_code 更新，新上市的股票直接插入
+func (f *DataFetcher) RefreshStockBasic(ctx context.Context) ([]models.StockBasic, error) {
+	var existing []string
+	if err := f.db.Model(&models.StockBasic{}).Pluck("ts_code", &existing).Error; err != nil {
+		return nil, fmt.Errorf("查询已有股票失败: %w", err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, tsCode := range existing {
+		known[tsCode] = true
+	}
+
+	if err := f.FetchStockBasic(ctx); err != nil {
+		return nil, err
+	}
+
+	var stocks []models.StockBasic
+	if err := f.db.Order("list_date desc, ts_code").Find(&stocks).Error; err != nil {
+		return nil, fmt.Errorf("查询股票列表失败: %w", err)
+	}
+	added := make([]models.StockBasic, 0)
+	for _, stock := range stocks {
+		if !known[stock.TSCode] {
+			added = append(added, stock)
+		}
+	}
+	return added, nil
+}
+
+// RunStockBasicRefresh 每隔 interval 刷新一次股票列表，发现新上市的股票时记录日志，ctx 取消时返回
+// 新股在下一次按股票抓取日线时自动纳入，无需人工刷新 stock_basic
+func (f *DataFetcher) RunStockBasicRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		added, err := f.RefreshStockBasic(ctx)
+		if err != nil {
+			f.logger.Error("定时刷新股票列表失败", zap.Error(err))
+			continue
+		}
+		for _, stock := range added {
+			f.logger.Info("发现新上市股票",
+				zap.String("ts_code", stock.TSCode),
+				zap.String("name", stock.Name),
+				zap.String("list_date", stock.ListDate))
+		}
+		f.logger.Info("定时刷新股票列表完成", zap.Int("new_listings", len(added)))
+	}
+}
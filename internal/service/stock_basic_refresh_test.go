@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRefreshStockBasic 测试刷新时插入新上市的股票并返回，已有股票按 ts_code 更新
+func TestRefreshStockBasic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "symbol", "name", "list_date", "list_status"},
+			Items: [][]interface{}{
+				{"000001.SZ", "000001", "平安银行", "19910403", "L"},
+				{"301589.SZ", "301589", "诺瓦星云", "20240208", "L"},
+				{"603275.SH", "603275", "众辰科技", "20231207", "L"},
+			},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockBasic{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+	require.NoError(t, fetcher.db.Create(&models.StockBasic{TSCode: "000001.SZ", Name: "平安银行（旧）", ListDate: "19910403", ListStatus: "L"}).Error)
+
+	added, err := fetcher.RefreshStockBasic(context.Background())
+	require.NoError(t, err)
+	require.Len(t, added, 2)
+	// 按上市日期倒序
+	assert.Equal(t, "301589.SZ", added[0].TSCode)
+	assert.Equal(t, "603275.SH", added[1].TSCode)
+	assert.Equal(t, "20231207", added[1].ListDate)
+
+	var existing models.StockBasic
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "000001.SZ").First(&existing).Error)
+	assert.Equal(t, "平安银行", existing.Name)
+
+	// 再次刷新没有新股
+	added, err = fetcher.RefreshStockBasic(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, added)
+}
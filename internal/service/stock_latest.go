@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// refreshStockLatest 用本批写入的日线更新 stock_latest：只更新本批日期不早于已有快照的股票
+// 快照内容从 stock_daily 回读，保证与按 insert_mode 处理后实际存储的数据一致（如 skip 模式保留的旧记录）
+// 并发写入不同日期时由 latestMu 串行化，避免较早日期覆盖较新的快照
+func (f *DataFetcher) refreshStockLatest(db *gorm.DB, records []models.StockDaily) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	f.latestMu.Lock()
+	defer f.latestMu.Unlock()
+
+	// 本批数据中每只股票的最新日期
+	newest := make(map[string]time.Time, len(records))
+	for _, r := range records {
+		if r.TradeDate.After(newest[r.TSCode]) {
+			newest[r.TSCode] = r.TradeDate
+		}
+	}
+	tsCodes := make([]string, 0, len(newest))
+	for tsCode := range newest {
+		tsCodes = append(tsCodes, tsCode)
+	}
+
+	var current []models.StockLatest
+	if err := db.Select("ts_code, trade_date").Where("ts_code IN ?", tsCodes).Find(&current).Error; err != nil {
+		return err
+	}
+	for _, latest := range current {
+		if newest[latest.TSCode].Before(latest.TradeDate) {
+			delete(newest, latest.TSCode)
+		}
+	}
+
+	// 一批数据通常只有一个日期，按日期回读
+	byDate := make(map[time.Time][]string)
+	for tsCode, date := range newest {
+		byDate[date] = append(byDate[date], tsCode)
+	}
+
+	snapshots := make([]models.StockLatest, 0, len(newest))
+	for date, codes := range byDate {
+		var rows []models.StockDaily
+		if err := db.Where("trade_date = ? AND ts_code IN ?", date, codes).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			snapshots = append(snapshots, newStockLatest(row))
+		}
+	}
+
+	return upsertStockLatest(db, snapshots, f.config.BatchSize)
+}
+
+// MigrateStockLatest 创建 stock_latest 表，在启动时调用
+// 快照为空而 stock_daily 已有数据时（如升级后首次启动）按 stock_daily 回填全部股票的最新快照
+func MigrateStockLatest(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if err := db.AutoMigrate(&models.StockLatest{}); err != nil {
+		return err
+	}
+	if !migrator.HasTable(&models.StockDaily{}) {
+		return nil
+	}
+
+	var snapshots, daily int64
+	if err := db.Model(&models.StockLatest{}).Limit(1).Count(&snapshots).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.StockDaily{}).Limit(1).Count(&daily).Error; err != nil {
+		return err
+	}
+	if snapshots > 0 || daily == 0 {
+		return nil
+	}
+
+	rows, err := latestDaily(db, nil)
+	if err != nil {
+		return err
+	}
+	return upsertStockLatest(db, newStockLatests(rows), 1000)
+}
+
+// RebuildStockLatest 按 stock_daily 重新生成指定股票的最新快照，用于删除日线数据之后
+// 已没有日线的股票会从 stock_latest 中移除
+func RebuildStockLatest(db *gorm.DB, tsCodes []string) error {
+	if len(tsCodes) == 0 {
+		return nil
+	}
+
+	rows, err := latestDaily(db, tsCodes)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Where("ts_code IN ?", tsCodes).Delete(&models.StockLatest{}).Error; err != nil {
+		return fmt.Errorf("删除最新行情快照失败: %w", err)
+	}
+	return upsertStockLatest(db, newStockLatests(rows), 1000)
+}
+
+// latestDaily 查询股票最新交易日的日线，tsCodes 为空时查询全部股票
+func latestDaily(db *gorm.DB, tsCodes []string) ([]models.StockDaily, error) {
+	newest := db.Model(&models.StockDaily{}).
+		Select("ts_code, MAX(trade_date) AS max_date").
+		Group("ts_code")
+	if len(tsCodes) > 0 {
+		newest = newest.Where("ts_code IN ?", tsCodes)
+	}
+
+	var rows []models.StockDaily
+	if err := db.Table(models.StockDaily{}.TableName()+" AS d").
+		Select("d.*").
+		Joins("JOIN (?) AS m ON m.ts_code = d.ts_code AND m.max_date = d.trade_date", newest).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询最新日线失败: %w", err)
+	}
+	return rows, nil
+}
+
+// upsertStockLatest 按 ts_code 写入或覆盖最新快照
+func upsertStockLatest(db *gorm.DB, snapshots []models.StockLatest, batchSize int) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return db.Clauses(upsertClause(db, []string{"ts_code"}, "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount", "updated_at")).CreateInBatches(snapshots, batchSize).Error
+}
+
+// newStockLatests 由日线记录批量生成快照
+func newStockLatests(rows []models.StockDaily) []models.StockLatest {
+	snapshots := make([]models.StockLatest, 0, len(rows))
+	for _, row := range rows {
+		snapshots = append(snapshots, newStockLatest(row))
+	}
+	return snapshots
+}
+
+// newStockLatest 由日线记录生成快照
+func newStockLatest(daily models.StockDaily) models.StockLatest {
+	return models.StockLatest{
+		TSCode:    daily.TSCode,
+		TradeDate: daily.TradeDate,
+		Open:      daily.Open,
+		High:      daily.High,
+		Low:       daily.Low,
+		Close:     daily.Close,
+		PreClose:  daily.PreClose,
+		Change:    daily.Change,
+		PctChg:    daily.PctChg,
+		Vol:       daily.Vol,
+		Amount:    daily.Amount,
+	}
+}
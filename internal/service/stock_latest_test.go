@@ -0,0 +1,123 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadLatestDates 按股票代码读取 stock_latest 的交易日期
+func loadLatestDates(t *testing.T, fetcher *DataFetcher) map[string]string {
+	t.Helper()
+	var rows []models.StockLatest
+	require.NoError(t, fetcher.db.Find(&rows).Error)
+	dates := make(map[string]string, len(rows))
+	for _, row := range rows {
+		dates[row.TSCode] = row.TradeDate.Format("20060102")
+	}
+	return dates
+}
+
+// TestRefreshStockLatest 测试写入日线后快照只前进不后退，删除后按 stock_daily 重建
+func TestRefreshStockLatest(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	closeA, closeB := 10.0, 11.0
+
+	_, err := fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231204", Close: &closeA},
+		{TSCode: "000002.SZ", TradeDate: "20231204", Close: &closeA},
+	})
+	require.NoError(t, err)
+
+	// 补抓较早的日期不会覆盖已有的较新快照
+	_, err = fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closeB},
+		{TSCode: "600000.SH", TradeDate: "20231201", Close: &closeB},
+	})
+	require.NoError(t, err)
+
+	_, err = fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000002.SZ", TradeDate: "20231205", Close: &closeB},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"000001.SZ": "20231204",
+		"000002.SZ": "20231205",
+		"600000.SH": "20231201",
+	}, loadLatestDates(t, fetcher))
+
+	var latest models.StockLatest
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "000002.SZ").First(&latest).Error)
+	require.NotNil(t, latest.Close)
+	assert.Equal(t, closeB, *latest.Close)
+
+	// 删除最新日线后回退到剩余的最新日期，没有日线的股票被移除
+	require.NoError(t, fetcher.db.Where("trade_date >= ?", time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC)).
+		Delete(&models.StockDaily{}).Error)
+	require.NoError(t, RebuildStockLatest(fetcher.db, []string{"000001.SZ", "000002.SZ"}))
+
+	assert.Equal(t, map[string]string{
+		"000001.SZ": "20231201",
+		"600000.SH": "20231201",
+	}, loadLatestDates(t, fetcher))
+}
+
+// TestRebuildStockLatest_TablePrefix 测试配置表名前缀后建表、建索引和关联查询使用带前缀的表名
+func TestRebuildStockLatest_TablePrefix(t *testing.T) {
+	models.SetTablePrefix("sd_")
+	t.Cleanup(func() { models.SetTablePrefix("") })
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	assert.True(t, fetcher.db.Migrator().HasTable("sd_stock_daily"))
+	assert.False(t, fetcher.db.Migrator().HasTable("stock_daily"))
+	assert.True(t, fetcher.db.Migrator().HasIndex(&models.StockLatest{}, "idx_sd_stock_latest_ts_code"))
+
+	closePrice := 10.0
+	_, err := fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice},
+	})
+	require.NoError(t, err)
+	require.NoError(t, RebuildStockLatest(fetcher.db, []string{"000001.SZ"}))
+	assert.Equal(t, map[string]string{"000001.SZ": "20231201"}, loadLatestDates(t, fetcher))
+}
+
+// TestMigrateStockLatest 测试启动时建表并按 stock_daily 回填快照，已有快照时不重复回填
+func TestMigrateStockLatest(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{})
+	day := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, fetcher.db.Create(&[]models.StockDaily{
+		{TSCode: "000001.SZ", TradeDate: day},
+		{TSCode: "000001.SZ", TradeDate: day.AddDate(0, 0, 3)},
+		{TSCode: "600000.SH", TradeDate: day},
+	}).Error)
+
+	require.NoError(t, MigrateStockLatest(fetcher.db))
+	assert.Equal(t, map[string]string{
+		"000001.SZ": "20231204",
+		"600000.SH": "20231201",
+	}, loadLatestDates(t, fetcher))
+
+	require.NoError(t, fetcher.db.Where("ts_code = ?", "600000.SH").Delete(&models.StockLatest{}).Error)
+	require.NoError(t, MigrateStockLatest(fetcher.db))
+	assert.Equal(t, map[string]string{"000001.SZ": "20231204"}, loadLatestDates(t, fetcher))
+}
+
+// TestInsertDailyData_StockLatestFailure 测试快照更新失败时日线照常入库
+func TestInsertDailyData_StockLatestFailure(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.StockDaily{})
+	closePrice := 10.0
+
+	stored, err := fetcher.batchInsertDailyData([]StockDailyData{
+		{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.StockDaily{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
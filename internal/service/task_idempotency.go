@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateIdempotencyKey 已有任务使用相同的 Idempotency-Key
+var ErrDuplicateIdempotencyKey = errors.New("相同 Idempotency-Key 的任务已存在")
+
+// idempotencyKeyContext context 中 Idempotency-Key 的键
+type idempotencyKeyContext struct{}
+
+// idempotency 创建任务的请求携带的 Idempotency-Key 及请求参数哈希
+type idempotency struct {
+	key  string
+	hash string
+}
+
+// WithIdempotencyKey 返回携带 Idempotency-Key 的 context，抓取方法创建任务时将键保存到 fetch_tasks.idempotency_key，
+// 由唯一索引保证相同的键只创建一个任务；paramsHash 为 IdempotencyParamsHash 计算的请求参数哈希，一并保存用于比对后续请求；
+// key 为空时原样返回
+func WithIdempotencyKey(ctx context.Context, key, paramsHash string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyContext{}, idempotency{key: key, hash: paramsHash})
+}
+
+// idempotencyFrom 返回 context 携带的 Idempotency-Key 及请求参数哈希，未携带时为空
+func idempotencyFrom(ctx context.Context) idempotency {
+	value, _ := ctx.Value(idempotencyKeyContext{}).(idempotency)
+	return value
+}
+
+// IdempotencyParamsHash 返回请求参数 JSON 的 SHA-256，params 应为校验并补全默认值后的请求
+func IdempotencyParamsHash(params interface{}) string {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// createTask 保存新建的任务记录，ctx 携带 Idempotency-Key 时一并保存键和请求参数哈希
+// 并发请求使用相同的键时唯一索引冲突，返回 ErrDuplicateIdempotencyKey，不会重复执行
+func (f *DataFetcher) createTask(ctx context.Context, task *models.FetchTask) error {
+	if idem := idempotencyFrom(ctx); idem.key != "" {
+		task.IdempotencyKey = &idem.key
+		task.IdempotencyHash = idem.hash
+	}
+
+	if err := f.db.Create(task).Error; err != nil {
+		if task.IdempotencyKey != nil {
+			if existing, findErr := f.FindTaskByIdempotencyKey(*task.IdempotencyKey); findErr == nil && existing != nil {
+				return ErrDuplicateIdempotencyKey
+			}
+		}
+		return fmt.Errorf("创建任务记录失败: %w", err)
+	}
+	return nil
+}
+
+// FindTaskByIdempotencyKey 查询使用该键且未过期（IdempotencyKeyTTL）的任务，没有时返回 nil
+// 已过期的任务清除键后返回 nil，使键可以再次使用
+func (f *DataFetcher) FindTaskByIdempotencyKey(key string) (*models.FetchTask, error) {
+	var task models.FetchTask
+	err := f.db.Where("idempotency_key = ?", key).First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询幂等键失败: %w", err)
+	}
+
+	if time.Since(task.CreatedAt) <= IdempotencyKeyTTL {
+		f.fillRunningStatus(&task)
+		return &task, nil
+	}
+	if err := f.db.Model(&models.FetchTask{}).Where("id = ?", task.ID).
+		Update("idempotency_key", nil).Error; err != nil {
+		return nil, fmt.Errorf("清除过期的幂等键失败: %w", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateTask_IdempotencyKey 测试相同 Idempotency-Key 只创建一个任务，过期的键可以再次使用
+func TestCreateTask_IdempotencyKey(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.FetchTask{})
+	ctx := WithIdempotencyKey(context.Background(), "top-list-2023", IdempotencyParamsHash(map[string]string{"start_date": "20230101"}))
+
+	first := &models.FetchTask{TaskID: "top_list_task_1", Status: models.TaskStatusRunning}
+	require.NoError(t, fetcher.createTask(ctx, first))
+	require.NotNil(t, first.IdempotencyKey)
+	assert.Len(t, first.IdempotencyHash, 64)
+
+	second := &models.FetchTask{TaskID: "top_list_task_2", Status: models.TaskStatusRunning}
+	assert.ErrorIs(t, fetcher.createTask(ctx, second), ErrDuplicateIdempotencyKey)
+
+	// 不带键的任务不受影响
+	require.NoError(t, fetcher.createTask(context.Background(), &models.FetchTask{TaskID: "top_list_task_3"}))
+
+	existing, err := fetcher.FindTaskByIdempotencyKey("top-list-2023")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "top_list_task_1", existing.TaskID)
+
+	require.NoError(t, fetcher.db.Model(first).UpdateColumn("created_at", time.Now().Add(-IdempotencyKeyTTL-time.Minute)).Error)
+	existing, err = fetcher.FindTaskByIdempotencyKey("top-list-2023")
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	require.NoError(t, fetcher.createTask(ctx, second))
+
+	var count int64
+	require.NoError(t, fetcher.db.Model(&models.FetchTask{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
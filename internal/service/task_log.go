@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"stock_data/internal/models"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+)
+
+// taskLogKey 日志中标识所属任务的字段名
+const taskLogKey = "task_id"
+
+// taskLogCore 把带 task_id 字段的警告及错误日志写入 task_logs，供按任务查询
+// task_id 可以在记录日志时传入，也可以通过 logger.With 预先附加
+type taskLogCore struct {
+	db     *gorm.DB
+	taskID string
+	fields []zapcore.Field
+}
+
+// MigrateTaskLogs 创建 task_logs 表，在启动时调用
+func MigrateTaskLogs(db *gorm.DB) error {
+	return db.AutoMigrate(&models.TaskLog{})
+}
+
+// TaskLogOption 返回在原有输出之外同时把任务日志写入 task_logs 的 logger 选项
+func TaskLogOption(db *gorm.DB) zap.Option {
+	return zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, &taskLogCore{db: db})
+	})
+}
+
+// Enabled 只记录警告及以上级别
+func (c *taskLogCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.WarnLevel
+}
+
+// With 返回附加了字段的 core，字段中的 task_id 作为之后日志所属的任务
+func (c *taskLogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &taskLogCore{db: c.db, taskID: c.taskID}
+	clone.fields = append(clone.fields, c.fields...)
+	for _, field := range fields {
+		if taskID, ok := taskIDField(field); ok {
+			clone.taskID = taskID
+			continue
+		}
+		clone.fields = append(clone.fields, field)
+	}
+	return clone
+}
+
+// Check 级别满足时加入待写入的 core
+func (c *taskLogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write 写入一条任务日志，没有 task_id 的日志忽略
+// 写入失败时返回错误，由 zap 输出到 logger 的 ErrorOutput（默认 stderr），不再经 logger 记录，避免数据库不可用时日志递归
+func (c *taskLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	taskID := c.taskID
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		if id, ok := taskIDField(field); ok {
+			taskID = id
+			continue
+		}
+		field.AddTo(enc)
+	}
+	if taskID == "" {
+		return nil
+	}
+
+	log := models.TaskLog{
+		TaskID:    taskID,
+		Level:     entry.Level.String(),
+		Message:   entry.Message,
+		CreatedAt: entry.Time,
+	}
+	if len(enc.Fields) > 0 {
+		if data, err := json.Marshal(enc.Fields); err == nil {
+			log.Fields = string(data)
+		}
+	}
+	if err := c.db.Create(&log).Error; err != nil {
+		return fmt.Errorf("写入任务日志失败: %w", err)
+	}
+	return nil
+}
+
+// Sync 直接写入数据库，无需刷新
+func (c *taskLogCore) Sync() error {
+	return nil
+}
+
+// taskIDField 判断字段是否为 task_id 并返回其值
+func taskIDField(field zapcore.Field) (string, bool) {
+	if field.Key != taskLogKey || field.Type != zapcore.StringType {
+		return "", false
+	}
+	return field.String, true
+}
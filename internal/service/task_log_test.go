@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestTaskLogCore 测试只记录带 task_id 的警告及错误日志，task_id 可在记录时传入或通过 With 附加
+func TestTaskLogCore(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.TaskLog{})
+	logger := zap.NewNop().WithOptions(TaskLogOption(fetcher.db))
+
+	logger.Info("普通日志", zap.String("task_id", "task_1"))
+	logger.Error("无任务的错误", zap.Error(errors.New("boom")))
+	logger.Warn("保存失败", zap.String("task_id", "task_1"), zap.String("date", "20231201"))
+	logger.With(zap.String("task_id", "task_2"), zap.String("ts_code", "000001.SZ")).
+		Error("抓取失败", zap.Error(errors.New("timeout")))
+
+	var logs []models.TaskLog
+	require.NoError(t, fetcher.db.Order("id").Find(&logs).Error)
+	require.Len(t, logs, 2)
+
+	assert.Equal(t, "task_1", logs[0].TaskID)
+	assert.Equal(t, "warn", logs[0].Level)
+	assert.Equal(t, "保存失败", logs[0].Message)
+	assert.JSONEq(t, `{"date":"20231201"}`, logs[0].Fields)
+
+	assert.Equal(t, "task_2", logs[1].TaskID)
+	assert.Equal(t, "error", logs[1].Level)
+	assert.JSONEq(t, `{"ts_code":"000001.SZ","error":"timeout"}`, logs[1].Fields)
+}
+
+// TestTaskLogCore_WriteError 测试任务日志写库失败时输出到 logger 的 ErrorOutput，原有输出不受影响
+func TestTaskLogCore_WriteError(t *testing.T) {
+	// 未创建 task_logs 表，写入失败
+	fetcher := newTestFetcher(t)
+	var errOut, out bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&out), zapcore.DebugLevel)
+	logger := zap.New(core, zap.ErrorOutput(zapcore.AddSync(&errOut))).WithOptions(TaskLogOption(fetcher.db))
+
+	logger.Error("抓取失败", zap.String("task_id", "task_1"))
+
+	assert.Contains(t, out.String(), "抓取失败")
+	assert.Contains(t, errOut.String(), "写入任务日志失败")
+}
+
+// TestTaskLogsDuringFetch 测试任务执行中单只股票抓取失败的错误日志按任务写入 task_logs
+func TestTaskLogsDuringFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Params["ts_code"] == "600000.SH" {
+			json.NewEncoder(w).Encode(TushareResponse{Code: 2002, Msg: "权限不足"})
+			return
+		}
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "close"},
+			Items:  [][]interface{}{{req.Params["ts_code"], "20231201", 10.2}},
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockBasic{}, &models.StockDailyAdj{}, &models.FetchTask{}, &models.TaskLog{})
+	fetcher.logger = zap.NewNop().WithOptions(TaskLogOption(fetcher.db))
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	require.NoError(t, fetcher.db.Create(&[]models.StockBasic{
+		{TSCode: "000001.SZ", Name: "平安银行", ListStatus: "L"},
+		{TSCode: "600000.SH", Name: "浦发银行", ListStatus: "L"},
+	}).Error)
+
+	task, err := fetcher.FetchDailyAdj(context.Background(), "20231201", "20231201", nil, AdjQfq)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.FailedCount)
+
+	var logs []models.TaskLog
+	require.NoError(t, fetcher.db.Where("task_id = ?", task.TaskID).Find(&logs).Error)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "error", logs[0].Level)
+	assert.Contains(t, logs[0].Fields, "600000.SH")
+	assert.Contains(t, logs[0].Fields, "权限不足")
+}
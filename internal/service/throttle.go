@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// insertLatencyWeight 入库耗时滑动平均中最新一次耗时的权重
+const insertLatencyWeight = 0.3
+
+// InsertThrottle 根据入库耗时自适应调整抓取并发数
+// 所有任务共用同一个实例：数据库变慢（平滑后的入库耗时超过阈值）时将有效并发数减半，
+// 各任务已在执行的日期数达到有效并发数时暂停派发新日期；耗时回落到阈值一半以下后每次加 1，直到恢复为配置的并发数。
+// 两次调整之间至少间隔一个阈值时长，使调整后的耗时能反映到滑动平均中
+type InsertThrottle struct {
+	mu         sync.Mutex
+	threshold  time.Duration // 入库耗时阈值，<= 0 表示不限制
+	max        int           // 配置的并发数
+	limit      int           // 当前有效并发数
+	latency    time.Duration // 平滑后的入库耗时
+	lastAdjust time.Time
+	changed    chan struct{} // 有效并发数变化或有日期执行结束时关闭，唤醒等待派发的任务
+}
+
+// NewInsertThrottle 创建自适应并发控制
+// concurrency: 配置的并发数；threshold: 入库耗时阈值，<= 0 表示不根据耗时调整
+func NewInsertThrottle(concurrency int, threshold time.Duration) *InsertThrottle {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &InsertThrottle{
+		threshold: threshold,
+		max:       concurrency,
+		limit:     concurrency,
+		changed:   make(chan struct{}),
+	}
+}
+
+// Observe 记录一次入库耗时并按需调整有效并发数
+func (t *InsertThrottle) Observe(elapsed time.Duration) {
+	if t.threshold <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.latency == 0 {
+		t.latency = elapsed
+	} else {
+		t.latency = time.Duration(insertLatencyWeight*float64(elapsed) + (1-insertLatencyWeight)*float64(t.latency))
+	}
+
+	now := time.Now()
+	if now.Sub(t.lastAdjust) < t.threshold {
+		return
+	}
+	switch {
+	case t.latency > t.threshold && t.limit > 1:
+		t.limit = max(1, t.limit/2)
+	case t.latency < t.threshold/2 && t.limit < t.max:
+		t.limit++
+	default:
+		return
+	}
+	t.lastAdjust = now
+	t.notify()
+}
+
+// Limit 返回当前有效并发数
+func (t *InsertThrottle) Limit() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+// notify 唤醒等待派发的任务，调用方需持有锁
+func (t *InsertThrottle) notify() {
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// throttleGate 单个任务的派发控制，记录任务正在执行的日期数
+type throttleGate struct {
+	throttle *InsertThrottle
+	active   int
+}
+
+// gate 为任务创建派发控制
+func (t *InsertThrottle) gate() *throttleGate {
+	return &throttleGate{throttle: t}
+}
+
+// acquire 等待任务正在执行的日期数低于有效并发数后占用一个名额，ctx 取消时返回错误
+func (g *throttleGate) acquire(ctx context.Context) error {
+	t := g.throttle
+	for {
+		t.mu.Lock()
+		if g.active < t.limit {
+			g.active++
+			t.mu.Unlock()
+			return nil
+		}
+		changed := t.changed
+		t.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release 释放名额
+func (g *throttleGate) release() {
+	t := g.throttle
+	t.mu.Lock()
+	g.active--
+	t.notify()
+	t.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInsertThrottle_AdjustsLimit 测试入库变慢时有效并发数减半，恢复后逐步回升到配置值
+func TestInsertThrottle_AdjustsLimit(t *testing.T) {
+	threshold := 20 * time.Millisecond
+	throttle := NewInsertThrottle(8, threshold)
+	assert.Equal(t, 8, throttle.Limit())
+
+	throttle.Observe(100 * time.Millisecond)
+	assert.Equal(t, 4, throttle.Limit())
+
+	// 两次调整之间至少间隔一个阈值时长
+	throttle.Observe(100 * time.Millisecond)
+	assert.Equal(t, 4, throttle.Limit())
+
+	for throttle.Limit() > 1 {
+		time.Sleep(threshold)
+		throttle.Observe(100 * time.Millisecond)
+	}
+	assert.Equal(t, 1, throttle.Limit())
+
+	for i := 0; i < 50 && throttle.Limit() < 8; i++ {
+		time.Sleep(threshold)
+		throttle.Observe(time.Millisecond)
+	}
+	assert.Equal(t, 8, throttle.Limit())
+}
+
+// TestInsertThrottle_Disabled 测试阈值为 0 时不调整并发数
+func TestInsertThrottle_Disabled(t *testing.T) {
+	throttle := NewInsertThrottle(4, 0)
+	throttle.Observe(time.Hour)
+	assert.Equal(t, 4, throttle.Limit())
+}
+
+// TestThrottleGate_PausesDispatch 测试已执行的日期数达到有效并发数时暂停派发，名额释放后继续
+func TestThrottleGate_PausesDispatch(t *testing.T) {
+	throttle := NewInsertThrottle(2, 10*time.Millisecond)
+	throttle.Observe(time.Second)
+	require.Equal(t, 1, throttle.Limit())
+
+	gate := throttle.gate()
+	require.NoError(t, gate.acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, gate.acquire(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- gate.acquire(context.Background()) }()
+	gate.release()
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("释放名额后仍未派发")
+	}
+
+	// 其他任务的名额单独计算
+	assert.NoError(t, throttle.gate().acquire(context.Background()))
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"stock_data/internal/config"
+	"time"
+)
+
+// marketLocation 解析 YYYYMMDD 日期及判断「当天」使用的时区，与数据库会话时区（database.timezone）一致
+// 日期按该时区的零点写入 date 列，数据库按会话时区取日期部分，两者不一致时会差一天
+var marketLocation = config.DefaultLocation()
+
+// SetLocation 设置日期解析使用的时区，应在启动时、开始处理请求前调用
+func SetLocation(loc *time.Location) {
+	marketLocation = loc
+}
+
+// Location 返回日期解析使用的时区
+func Location() *time.Location {
+	return marketLocation
+}
+
+// ParseDate 按 database.timezone 解析 YYYYMMDD 日期，返回该时区当天零点
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("20060102", value, marketLocation)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"stock_data/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseDate_RoundTrip 测试 20231201 写入后读回，按配置的时区仍为 12 月 1 日而不是 11 月 30 日
+func TestParseDate_RoundTrip(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("缺少时区数据库")
+	}
+
+	for _, loc := range []*time.Location{Location(), newYork} {
+		t.Run(loc.String(), func(t *testing.T) {
+			original := Location()
+			SetLocation(loc)
+			t.Cleanup(func() { SetLocation(original) })
+
+			date, err := ParseDate("20231201")
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, loc), date)
+
+			fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+			closePrice := 10.5
+			_, err = fetcher.batchInsertDailyData([]StockDailyData{{TSCode: "000001.SZ", TradeDate: "20231201", Close: &closePrice}})
+			require.NoError(t, err)
+
+			var stored models.StockDaily
+			require.NoError(t, fetcher.db.Where("trade_date = ?", date).First(&stored).Error)
+			assert.Equal(t, "20231201", stored.TradeDate.In(Location()).Format("20060102"))
+		})
+	}
+}
+
+// TestDefaultDateRange_Location 测试未配置结束日期时按 database.timezone 取当天，与服务器本地时区无关
+func TestDefaultDateRange_Location(t *testing.T) {
+	east, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skip("缺少时区数据库")
+	}
+	west, err := time.LoadLocation("Pacific/Pago_Pago")
+	if err != nil {
+		t.Skip("缺少时区数据库")
+	}
+
+	fetcher := newTestFetcher(t)
+	fetcher.config.StartDate = "20230101"
+	fetcher.config.EndDate = ""
+	original := Location()
+	t.Cleanup(func() { SetLocation(original) })
+
+	ends := make([]string, 0, 2)
+	for _, loc := range []*time.Location{east, west} {
+		SetLocation(loc)
+		start, end := fetcher.DefaultDateRange()
+		assert.Equal(t, "20230101", start)
+		assert.Equal(t, time.Now().In(loc).Format("20060102"), end)
+		ends = append(ends, end)
+	}
+	// 两个时区相差 25 小时，当天总是不同的日期
+	assert.NotEqual(t, ends[0], ends[1])
+}
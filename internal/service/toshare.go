@@ -2,23 +2,46 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"stock_data/internal/config"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
 )
 
 // TushareClient Tushare API 客户端
 type TushareClient struct {
-	token   string
-	baseURL string
-	timeout time.Duration
-	retry   int
-	client  *http.Client
+	token      string
+	baseURL    string
+	userAgent  string
+	timeout    time.Duration
+	retry      int
+	retryDelay time.Duration // 无 Retry-After 提示时的首次重试间隔，之后每次翻倍
+	client     *http.Client
+	breaker    *gobreaker.CircuitBreaker
+	recorder   ResponseRecorder // 不为空时交给它保存每次请求的原始响应
+	cache      *requestCache    // 相同请求的短期去重，为空时不去重
+	logger     *zap.Logger
+}
+
+// ResponseRecorder 保存 Tushare 原始响应，用于排查数据问题
+type ResponseRecorder interface {
+	// RecordResponse 在每次收到 JSON 响应后调用（含重试和 code 非 0 的响应），params 中的 Token 已脱敏
+	RecordResponse(apiName string, params map[string]interface{}, fields string, resp *TushareResponse)
 }
 
+// ErrCircuitOpen Tushare 连续请求失败触发熔断，冷却期内的请求直接返回该错误
+var ErrCircuitOpen = errors.New("Tushare 请求连续失败，已熔断")
+
 // TushareRequest Tushare API 请求结构
 type TushareRequest struct {
 	APIName string                 `json:"api_name"`
@@ -34,6 +57,59 @@ type TushareResponse struct {
 	Data json.RawMessage `json:"data"`
 }
 
+// APIError Tushare 接口返回的业务错误（code 非 0）
+type APIError struct {
+	APIName string
+	Code    int
+	Msg     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API 返回错误: %s", e.Msg)
+}
+
+// 重试等待
+const (
+	defaultRetryDelay = time.Second     // 指数退避的首次等待时间
+	maxRetryAfter     = 2 * time.Minute // Retry-After 的等待上限，避免异常值长时间阻塞任务
+	rateLimitCode     = 40203           // Tushare 接口访问频率超限的错误码
+	rateLimitWait     = time.Minute     // 频率限制按分钟计数，收到 rateLimitCode 时等待一个周期
+)
+
+// maxBodySnippet 错误信息中保留的响应体长度上限（字节）
+const maxBodySnippet = 200
+
+// HTTPError Tushare 返回了非 200 状态码或非 JSON 响应（如代理返回的 HTML 502 页面）
+type HTTPError struct {
+	StatusCode int
+	Snippet    string        // 截断后的响应体
+	RetryAfter time.Duration // 响应 Retry-After 头给出的等待时间，未提供时为 0
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP 响应异常: 状态码 %d, 响应内容: %s", e.StatusCode, e.Snippet)
+}
+
+// Retryable 是否可重试：5xx、429 以及状态码为 200 但响应体不是 JSON 的情况可重试
+func (e *HTTPError) Retryable() bool {
+	return e.StatusCode == http.StatusOK ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError
+}
+
+// bodySnippet 截取响应体开头用于错误信息，合并空白并保证不截断多字节字符
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) <= maxBodySnippet {
+		return snippet
+	}
+	cut := maxBodySnippet
+	for cut > 0 && !utf8.RuneStart(snippet[cut]) {
+		cut--
+	}
+	return snippet[:cut] + "..."
+}
+
 // TushareData 数据结构
 type TushareData struct {
 	Fields []string        `json:"fields"`
@@ -42,17 +118,17 @@ type TushareData struct {
 
 // StockDailyData 日线数据
 type StockDailyData struct {
-	TSCode    string  `json:"ts_code"`
-	TradeDate string  `json:"trade_date"`
-	Open      float64 `json:"open"`
-	High      float64 `json:"high"`
-	Low       float64 `json:"low"`
-	Close     float64 `json:"close"`
-	PreClose  float64 `json:"pre_close"`
-	Change    float64 `json:"change"`
-	PctChg    float64 `json:"pct_chg"`
-	Vol       float64 `json:"vol"`
-	Amount    float64 `json:"amount"`
+	TSCode    string   `json:"ts_code"`
+	TradeDate string   `json:"trade_date"`
+	Open      *float64 `json:"open"`
+	High      *float64 `json:"high"`
+	Low       *float64 `json:"low"`
+	Close     *float64 `json:"close"`
+	PreClose  *float64 `json:"pre_close"`
+	Change    *float64 `json:"change"`
+	PctChg    *float64 `json:"pct_chg"`
+	Vol       *float64 `json:"vol"`
+	Amount    *float64 `json:"amount"`
 }
 
 // StockBasicData 股票基本信息
@@ -82,31 +158,31 @@ type StockWeeklyData struct {
 	EndDate   string `json:"end_date"`   // 计算截至日期
 
 	// 未复权价格
-	Open     float64 `json:"open"`
-	High     float64 `json:"high"`
-	Low      float64 `json:"low"`
-	Close    float64 `json:"close"`
-	PreClose float64 `json:"pre_close"`
+	Open     *float64 `json:"open"`
+	High     *float64 `json:"high"`
+	Low      *float64 `json:"low"`
+	Close    *float64 `json:"close"`
+	PreClose *float64 `json:"pre_close"`
 
 	// 前复权价格
-	OpenQfq  float64 `json:"open_qfq"`
-	HighQfq  float64 `json:"high_qfq"`
-	LowQfq   float64 `json:"low_qfq"`
-	CloseQfq float64 `json:"close_qfq"`
+	OpenQfq  *float64 `json:"open_qfq"`
+	HighQfq  *float64 `json:"high_qfq"`
+	LowQfq   *float64 `json:"low_qfq"`
+	CloseQfq *float64 `json:"close_qfq"`
 
 	// 后复权价格
-	OpenHfq  float64 `json:"open_hfq"`
-	HighHfq  float64 `json:"high_hfq"`
-	LowHfq   float64 `json:"low_hfq"`
-	CloseHfq float64 `json:"close_hfq"`
+	OpenHfq  *float64 `json:"open_hfq"`
+	HighHfq  *float64 `json:"high_hfq"`
+	LowHfq   *float64 `json:"low_hfq"`
+	CloseHfq *float64 `json:"close_hfq"`
 
 	// 成交数据
-	Vol    float64 `json:"vol"`
-	Amount float64 `json:"amount"`
+	Vol    *float64 `json:"vol"`
+	Amount *float64 `json:"amount"`
 
 	// 涨跌数据
-	Change float64 `json:"change"`
-	PctChg float64 `json:"pct_chg"`
+	Change *float64 `json:"change"`
+	PctChg *float64 `json:"pct_chg"`
 }
 
 // StockMonthlyData 月线数据
@@ -116,48 +192,379 @@ type StockMonthlyData struct {
 	EndDate   time.Time `json:"end_date"`
 
 	// 未复权价格
-	Open     float64 `json:"open"`
-	High     float64 `json:"high"`
-	Low      float64 `json:"low"`
-	Close    float64 `json:"close"`
-	PreClose float64 `json:"pre_close"`
+	Open     *float64 `json:"open"`
+	High     *float64 `json:"high"`
+	Low      *float64 `json:"low"`
+	Close    *float64 `json:"close"`
+	PreClose *float64 `json:"pre_close"`
 
 	// 前复权价格
-	OpenQfq  float64 `json:"open_qfq"`
-	HighQfq  float64 `json:"high_qfq"`
-	LowQfq   float64 `json:"low_qfq"`
-	CloseQfq float64 `json:"close_qfq"`
+	OpenQfq  *float64 `json:"open_qfq"`
+	HighQfq  *float64 `json:"high_qfq"`
+	LowQfq   *float64 `json:"low_qfq"`
+	CloseQfq *float64 `json:"close_qfq"`
 
 	// 后复权价格
-	OpenHfq  float64 `json:"open_hfq"`
-	HighHfq  float64 `json:"high_hfq"`
-	LowHfq   float64 `json:"low_hfq"`
-	CloseHfq float64 `json:"close_hfq"`
+	OpenHfq  *float64 `json:"open_hfq"`
+	HighHfq  *float64 `json:"high_hfq"`
+	LowHfq   *float64 `json:"low_hfq"`
+	CloseHfq *float64 `json:"close_hfq"`
 
 	// 成交数据
-	Vol    float64 `json:"vol"`
-	Amount float64 `json:"amount"`
+	Vol    *float64 `json:"vol"`
+	Amount *float64 `json:"amount"`
 
 	// 涨跌数据
-	Change float64 `json:"change"`
-	PctChg float64 `json:"pct_chg"`
+	Change *float64 `json:"change"`
+	PctChg *float64 `json:"pct_chg"`
+}
+
+// FinaIndicatorData 财务指标数据
+type FinaIndicatorData struct {
+	TSCode            string  `json:"ts_code"`            // 股票代码
+	AnnDate           string  `json:"ann_date"`           // 公告日期
+	EndDate           string  `json:"end_date"`           // 报告期
+	EPS               float64 `json:"eps"`                // 基本每股收益
+	DtEPS             float64 `json:"dt_eps"`             // 稀释每股收益
+	BPS               float64 `json:"bps"`                // 每股净资产
+	OCFPS             float64 `json:"ocfps"`              // 每股经营活动产生的现金流量净额
+	ROE               float64 `json:"roe"`                // 净资产收益率
+	ROEDt             float64 `json:"roe_dt"`             // 净资产收益率（扣除非经常损益）
+	ROA               float64 `json:"roa"`                // 总资产报酬率
+	GrossprofitMargin float64 `json:"grossprofit_margin"` // 销售毛利率
+	NetprofitMargin   float64 `json:"netprofit_margin"`   // 销售净利率
+	DebtToAssets      float64 `json:"debt_to_assets"`     // 资产负债率
+	CurrentRatio      float64 `json:"current_ratio"`      // 流动比率
+	QuickRatio        float64 `json:"quick_ratio"`        // 速动比率
+	OrYoy             float64 `json:"or_yoy"`             // 营业收入同比增长率
+	NetprofitYoy      float64 `json:"netprofit_yoy"`      // 归母净利润同比增长率
+}
+
+// ConceptData 概念分类
+type ConceptData struct {
+	Code string `json:"code"` // 概念分类ID
+	Name string `json:"name"` // 概念分类名称
+	Src  string `json:"src"`  // 来源
+}
+
+// ConceptDetailData 概念股明细
+type ConceptDetailData struct {
+	ID          string `json:"id"`           // 概念代码
+	ConceptName string `json:"concept_name"` // 概念名称
+	TSCode      string `json:"ts_code"`      // 股票代码
+	Name        string `json:"name"`         // 股票名称
+}
+
+// IndexClassifyData 申万行业分类
+type IndexClassifyData struct {
+	IndexCode    string `json:"index_code"`    // 指数代码
+	IndustryName string `json:"industry_name"` // 行业名称
+	Level        string `json:"level"`         // 行业级别
+	IndustryCode string `json:"industry_code"` // 行业代码
+	Src          string `json:"src"`           // 行业分类来源
+}
+
+// IndexMemberData 申万行业成分
+type IndexMemberData struct {
+	IndexCode string `json:"index_code"` // 指数代码
+	ConCode   string `json:"con_code"`   // 成分股代码
+	InDate    string `json:"in_date"`    // 纳入日期
+	OutDate   string `json:"out_date"`   // 剔除日期
+	IsNew     string `json:"is_new"`     // 是否最新 Y是 N否
+}
+
+// StockMinuteData 分钟线数据
+type StockMinuteData struct {
+	TSCode    string  `json:"ts_code"`    // 股票代码
+	TradeTime string  `json:"trade_time"` // 交易时间 YYYY-MM-DD HH:MM:SS
+	Open      float64 `json:"open"`
+	Close     float64 `json:"close"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Vol       float64 `json:"vol"`
+	Amount    float64 `json:"amount"`
+}
+
+// minuteFreqs 支持的分钟线频率
+var minuteFreqs = map[string]bool{
+	"1min":  true,
+	"5min":  true,
+	"15min": true,
+	"30min": true,
+	"60min": true,
 }
 
+// IsValidMinuteFreq 判断分钟线频率是否受支持
+func IsValidMinuteFreq(freq string) bool {
+	return minuteFreqs[freq]
+}
+
+// 连接池默认值，高并发抓取时比 Go 默认值（每个主机 2 个空闲连接）更大以复用长连接
+const (
+	defaultMaxIdleConns        = 200
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 120 // 秒
+)
+
+// 熔断默认值
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 // 秒
+)
+
+// StockCompanyData 上市公司基本信息
+type StockCompanyData struct {
+	TSCode        string  `json:"ts_code"`        // 股票代码
+	Exchange      string  `json:"exchange"`       // 交易所代码
+	Chairman      string  `json:"chairman"`       // 法人代表
+	Manager       string  `json:"manager"`        // 总经理
+	Secretary     string  `json:"secretary"`      // 董秘
+	RegCapital    float64 `json:"reg_capital"`    // 注册资本（万元）
+	SetupDate     string  `json:"setup_date"`     // 注册日期
+	Province      string  `json:"province"`       // 所在省份
+	City          string  `json:"city"`           // 所在城市
+	Website       string  `json:"website"`        // 公司主页
+	Email         string  `json:"email"`          // 电子邮件
+	Employees     int     `json:"employees"`      // 员工人数
+	MainBusiness  string  `json:"main_business"`  // 主要业务及产品
+	BusinessScope string  `json:"business_scope"` // 经营范围
+}
+
+// TopListData 龙虎榜每日明细
+type TopListData struct {
+	TradeDate    string  `json:"trade_date"`    // 交易日期
+	TSCode       string  `json:"ts_code"`       // 股票代码
+	Name         string  `json:"name"`          // 名称
+	Close        float64 `json:"close"`         // 收盘价
+	PctChange    float64 `json:"pct_change"`    // 涨跌幅
+	TurnoverRate float64 `json:"turnover_rate"` // 换手率
+	Amount       float64 `json:"amount"`        // 总成交额
+	LSell        float64 `json:"l_sell"`        // 龙虎榜卖出额
+	LBuy         float64 `json:"l_buy"`         // 龙虎榜买入额
+	LAmount      float64 `json:"l_amount"`      // 龙虎榜成交额
+	NetAmount    float64 `json:"net_amount"`    // 龙虎榜净买入额
+	NetRate      float64 `json:"net_rate"`      // 龙虎榜净买额占比
+	AmountRate   float64 `json:"amount_rate"`   // 龙虎榜成交额占比
+	FloatValues  float64 `json:"float_values"`  // 当日流通市值
+	Reason       string  `json:"reason"`        // 上榜理由
+}
+
+// BlockTradeData 大宗交易明细
+type BlockTradeData struct {
+	TSCode    string   `json:"ts_code"`    // 股票代码
+	TradeDate string   `json:"trade_date"` // 交易日期
+	Price     *float64 `json:"price"`      // 成交价（元）
+	Vol       *float64 `json:"vol"`        // 成交量（万股）
+	Amount    *float64 `json:"amount"`     // 成交金额（万元）
+	Buyer     string   `json:"buyer"`      // 买方营业部
+	Seller    string   `json:"seller"`     // 卖方营业部
+}
+
+// MarginDetailData 融资融券交易明细
+type MarginDetailData struct {
+	TradeDate string  `json:"trade_date"` // 交易日期
+	TSCode    string  `json:"ts_code"`    // 股票代码
+	Rzye      float64 `json:"rzye"`       // 融资余额（元）
+	Rqye      float64 `json:"rqye"`       // 融券余额（元）
+	Rzmre     float64 `json:"rzmre"`      // 融资买入额（元）
+	Rqyl      float64 `json:"rqyl"`       // 融券余量（股）
+	Rzche     float64 `json:"rzche"`      // 融资偿还额（元）
+	Rqchl     float64 `json:"rqchl"`      // 融券偿还量（股）
+	Rqmcl     float64 `json:"rqmcl"`      // 融券卖出量（股）
+	Rzrqye    float64 `json:"rzrqye"`     // 融资融券余额（元）
+}
+
+// IndexBasicData 指数基本信息
+type IndexBasicData struct {
+	TSCode     string  `json:"ts_code"`     // 指数代码
+	Name       string  `json:"name"`        // 简称
+	FullName   string  `json:"fullname"`    // 全称
+	Market     string  `json:"market"`      // 市场
+	Publisher  string  `json:"publisher"`   // 发布方
+	IndexType  string  `json:"index_type"`  // 指数风格
+	Category   string  `json:"category"`    // 指数类别
+	BaseDate   string  `json:"base_date"`   // 基期
+	BasePoint  float64 `json:"base_point"`  // 基点
+	ListDate   string  `json:"list_date"`   // 发布日期
+	WeightRule string  `json:"weight_rule"` // 加权方式
+	Desc       string  `json:"desc"`        // 描述
+	ExpDate    string  `json:"exp_date"`    // 终止日期
+}
+
+// HSConstData 沪深股通成分
+type HSConstData struct {
+	TSCode  string `json:"ts_code"`  // 股票代码
+	HSType  string `json:"hs_type"`  // 类型 SH 沪股通 SZ 深股通
+	InDate  string `json:"in_date"`  // 纳入日期
+	OutDate string `json:"out_date"` // 剔除日期
+	IsNew   string `json:"is_new"`   // 是否最新 1是 0否
+}
+
+// AdjFactorData 复权因子
+type AdjFactorData struct {
+	TSCode    string   `json:"ts_code"`    // 股票代码
+	TradeDate string   `json:"trade_date"` // 交易日期
+	AdjFactor *float64 `json:"adj_factor"` // 复权因子
+}
+
+// FundBasicData 基金基本信息
+type FundBasicData struct {
+	TSCode      string   `json:"ts_code"`      // 基金代码
+	Name        string   `json:"name"`         // 简称
+	Management  string   `json:"management"`   // 管理人
+	Custodian   string   `json:"custodian"`    // 托管人
+	FundType    string   `json:"fund_type"`    // 投资类型
+	FoundDate   string   `json:"found_date"`   // 成立日期
+	DueDate     string   `json:"due_date"`     // 到期日期
+	ListDate    string   `json:"list_date"`    // 上市时间
+	IssueDate   string   `json:"issue_date"`   // 发行日期
+	DelistDate  string   `json:"delist_date"`  // 退市日期
+	IssueAmount *float64 `json:"issue_amount"` // 发行份额（亿）
+	MFee        *float64 `json:"m_fee"`        // 管理费
+	CFee        *float64 `json:"c_fee"`        // 托管费
+	Benchmark   string   `json:"benchmark"`    // 业绩比较基准
+	Status      string   `json:"status"`       // 存续状态 D摘牌 I发行 L已上市
+	InvestType  string   `json:"invest_type"`  // 投资风格
+	Type        string   `json:"type"`         // 基金类型
+	Market      string   `json:"market"`       // E场内 O场外
+}
+
+// FundDailyData 场内基金日线行情
+type FundDailyData struct {
+	TSCode    string   `json:"ts_code"`    // 基金代码
+	TradeDate string   `json:"trade_date"` // 交易日期
+	Open      *float64 `json:"open"`       // 开盘价
+	High      *float64 `json:"high"`       // 最高价
+	Low       *float64 `json:"low"`        // 最低价
+	Close     *float64 `json:"close"`      // 收盘价
+	PreClose  *float64 `json:"pre_close"`  // 昨收价
+	Change    *float64 `json:"change"`     // 涨跌额
+	PctChg    *float64 `json:"pct_chg"`    // 涨跌幅
+	Vol       *float64 `json:"vol"`        // 成交量（手）
+	Amount    *float64 `json:"amount"`     // 成交额（千元）
+}
+
+// defaultUserAgent 未配置 user_agent 时使用的 User-Agent
+const defaultUserAgent = "stock_data/1.0"
+
 // NewTushareClient 创建 Tushare 客户端
-func NewTushareClient(cfg *config.TushareConfig) *TushareClient {
+func NewTushareClient(cfg *config.TushareConfig, logger *zap.Logger) *TushareClient {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
 	return &TushareClient{
-		token:   cfg.Token,
-		baseURL: cfg.BaseURL,
-		timeout: time.Duration(cfg.Timeout) * time.Second,
-		retry:   cfg.Retry,
+		token:      cfg.Token,
+		baseURL:    cfg.BaseURL,
+		userAgent:  userAgent,
+		timeout:    time.Duration(cfg.Timeout) * time.Second,
+		retry:      cfg.Retry,
+		retryDelay: defaultRetryDelay,
+		logger:     logger,
 		client: &http.Client{
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
+			Timeout:   time.Duration(cfg.Timeout) * time.Second,
+			Transport: newTransport(cfg),
+		},
+		breaker: newBreaker(cfg),
+		cache:   newRequestCache(time.Duration(cfg.DedupTTLMs) * time.Millisecond),
+	}
+}
+
+// newBreaker 创建熔断器：连续失败达到阈值后熔断，冷却期后放行一个探测请求
+// Tushare 返回的业务错误（如权限不足、参数错误）说明服务可用，不计为失败
+func newBreaker(cfg *config.TushareConfig) *gobreaker.CircuitBreaker {
+	threshold := cfg.BreakerThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	cooldown := cfg.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "tushare",
+		MaxRequests: 1,
+		Timeout:     time.Duration(cooldown) * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= uint32(threshold)
+		},
+		IsSuccessful: func(err error) bool {
+			var apiErr *APIError
+			return err == nil || errors.As(err, &apiErr)
 		},
+	})
+}
+
+// SetRecorder 设置原始响应的保存方式，应在开始请求前调用
+func (c *TushareClient) SetRecorder(recorder ResponseRecorder) {
+	c.recorder = recorder
+}
+
+// CircuitOpen 熔断器是否处于打开状态
+func (c *TushareClient) CircuitOpen() bool {
+	return c.breaker.State() == gobreaker.StateOpen
+}
+
+// newTransport 根据配置创建 HTTP 连接池
+func newTransport(cfg *config.TushareConfig) *http.Transport {
+	maxIdleConns := cfg.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
 	}
+	maxIdleConnsPerHost := cfg.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	idleConnTimeout := cfg.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = time.Duration(idleConnTimeout) * time.Second
+	return transport
 }
 
-// request 发送请求
-func (c *TushareClient) request(apiName string, params map[string]interface{}, fields string) (*TushareData, error) {
+// request 发送请求，ctx 取消时中止进行中的请求和重试等待
+// 开启 dedup_ttl_ms 时相同的请求在有效期内只发送一次，见 requestCache；
+// 合并的并发请求使用第一个调用方的 ctx，其被取消时其余调用方同样收到取消错误；
+// ctx 由 bypassRequestCache 生成时直接发送
+func (c *TushareClient) request(ctx context.Context, apiName string, params map[string]interface{}, fields string) (*TushareData, error) {
+	if c.cache == nil || requestCacheBypassed(ctx) {
+		return c.send(ctx, apiName, params, fields)
+	}
+
+	data, shared, err := c.cache.do(requestKey(apiName, params, fields), func() (*TushareData, error) {
+		return c.send(ctx, apiName, params, fields)
+	})
+	if shared && err == nil {
+		c.logger.Debug("复用相同 Tushare 请求的结果",
+			zap.String("api_name", apiName),
+			zap.Any("params", redactParams(params, c.token)))
+	}
+	return data, err
+}
+
+// send 经熔断器发送请求
+func (c *TushareClient) send(ctx context.Context, apiName string, params map[string]interface{}, fields string) (*TushareData, error) {
+	result, err := c.breaker.Execute(func() (interface{}, error) {
+		return c.doRequestWithRetry(ctx, apiName, params, fields)
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, apiName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TushareData), nil
+}
+
+// doRequestWithRetry 发送请求并按配置重试
+func (c *TushareClient) doRequestWithRetry(ctx context.Context, apiName string, params map[string]interface{}, fields string) (*TushareData, error) {
 	reqData := TushareRequest{
 		APIName: apiName,
 		Token:   c.token,
@@ -170,17 +577,39 @@ func (c *TushareClient) request(apiName string, params map[string]interface{}, f
 		return nil, fmt.Errorf("序列化请求失败: %w", err)
 	}
 
+	// 请求体中含 Token，日志只记录接口名和参数
+	c.logger.Debug("请求 Tushare 接口",
+		zap.String("api_name", apiName),
+		zap.Any("params", redactParams(params, c.token)),
+		zap.String("fields", fields))
+
 	var resp *TushareResponse
 	var lastErr error
 
 	// 重试机制
 	for i := 0; i <= c.retry; i++ {
-		resp, lastErr = c.doRequest(jsonData)
+		countCall(ctx)
+		resp, lastErr = c.doRequest(ctx, jsonData)
+		if lastErr == nil && c.recorder != nil {
+			c.recorder.RecordResponse(apiName, redactParams(params, c.token), fields, resp)
+		}
 		if lastErr == nil && resp.Code == 0 {
 			break
 		}
+		var httpErr *HTTPError
+		if errors.As(lastErr, &httpErr) && !httpErr.Retryable() {
+			break
+		}
 		if i < c.retry {
-			time.Sleep(time.Second * time.Duration(i+1))
+			wait := c.retryWait(i, resp, lastErr)
+			c.logger.Debug("Tushare 请求失败，等待后重试",
+				zap.String("api_name", apiName),
+				zap.Int("attempt", i+1),
+				zap.Duration("wait", wait),
+				zap.Error(lastErr))
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -189,7 +618,7 @@ func (c *TushareClient) request(apiName string, params map[string]interface{}, f
 	}
 
 	if resp.Code != 0 {
-		return nil, fmt.Errorf("API 返回错误: %s", resp.Msg)
+		return nil, &APIError{APIName: apiName, Code: resp.Code, Msg: resp.Msg}
 	}
 
 	var data TushareData
@@ -200,14 +629,79 @@ func (c *TushareClient) request(apiName string, params map[string]interface{}, f
 	return &data, nil
 }
 
+// redactedToken 日志中替代 Token 的占位符
+const redactedToken = "******"
+
+// redactParams 复制请求参数，值中出现的 Token 替换为占位符，避免写入日志
+func redactParams(params map[string]interface{}, token string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if str, ok := v.(string); ok && token != "" && strings.Contains(str, token) {
+			v = strings.ReplaceAll(str, token, redactedToken)
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// retryWait 计算第 attempt 次失败后的等待时间：优先使用 429 响应的 Retry-After，
+// Tushare 返回频率超限错误码时等待到下一个计数周期，否则按 retryDelay 指数退避
+func (c *TushareClient) retryWait(attempt int, resp *TushareResponse, err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter
+	}
+	if err == nil && resp != nil && resp.Code == rateLimitCode {
+		return rateLimitWait
+	}
+	return c.retryDelay << attempt
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数和 HTTP 日期两种格式，超过 maxRetryAfter 时取上限
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = at.Sub(now)
+	}
+
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return wait
+}
+
+// sleepContext 等待 d，ctx 先结束时返回 ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // doRequest 执行 HTTP 请求
-func (c *TushareClient) doRequest(jsonData []byte) (*TushareResponse, error) {
-	req, err := http.NewRequest("POST", c.baseURL, bytes.NewBuffer(jsonData))
+func (c *TushareClient) doRequest(ctx context.Context, jsonData []byte) (*TushareResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
 
 	httpResp, err := c.client.Do(req)
 	if err != nil {
@@ -220,6 +714,14 @@ func (c *TushareClient) doRequest(jsonData []byte) (*TushareResponse, error) {
 		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
 
+	if httpResp.StatusCode != http.StatusOK || !json.Valid(body) {
+		return nil, &HTTPError{
+			StatusCode: httpResp.StatusCode,
+			Snippet:    bodySnippet(body),
+			RetryAfter: parseRetryAfter(httpResp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
 	var resp TushareResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("解析响应失败: %w", err)
@@ -228,22 +730,56 @@ func (c *TushareClient) doRequest(jsonData []byte) (*TushareResponse, error) {
 	return &resp, nil
 }
 
-// GetStockBasic 获取股票基本信息
-func (c *TushareClient) GetStockBasic() ([]StockBasicData, error) {
+// StockBasicQuery 股票基本信息查询条件
+type StockBasicQuery struct {
+	ListStatus string // 上市状态 L上市 D退市 P暂停上市，为空默认 L
+	Market     string // 市场类别 主板/创业板/科创板/CDR/北交所，为空获取全部市场
+}
+
+// NewStockBasicQuery 根据抓取配置生成股票基本信息查询条件
+func NewStockBasicQuery(cfg *config.FetcherConfig) StockBasicQuery {
+	return StockBasicQuery{
+		ListStatus: cfg.StockListStatus,
+		Market:     cfg.StockMarket,
+	}
+}
+
+// stockBasicFields 股票列表请求的字段，Tushare 默认输出不包含 list_status
+const stockBasicFields = "ts_code,symbol,name,area,industry,market,list_date,list_status"
+
+// GetStockBasic 获取股票基本信息，返回数据未带上市状态时以请求的状态补全
+func (c *TushareClient) GetStockBasic(ctx context.Context, query StockBasicQuery) ([]StockBasicData, error) {
+	listStatus := query.ListStatus
+	if listStatus == "" {
+		listStatus = "L" // 默认只获取上市状态的股票
+	}
+
 	params := map[string]interface{}{
-		"list_status": "L", // 只获取上市状态的股票
+		"list_status": listStatus,
+	}
+	if query.Market != "" {
+		params["market"] = query.Market
 	}
 
-	data, err := c.request("stock_basic", params, "")
+	data, err := c.request(ctx, "stock_basic", params, stockBasicFields)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.parseStockBasic(data)
+	stocks, err := c.parseStockBasic(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stocks {
+		if stocks[i].ListStatus == "" {
+			stocks[i].ListStatus = listStatus
+		}
+	}
+	return stocks, nil
 }
 
 // GetDailyData 获取日线数据
-func (c *TushareClient) GetDailyData(tradeDate string, tsCode string) ([]StockDailyData, error) {
+func (c *TushareClient) GetDailyData(ctx context.Context, tradeDate string, tsCode string) ([]StockDailyData, error) {
 	params := map[string]interface{}{}
 
 	if tradeDate != "" {
@@ -253,7 +789,7 @@ func (c *TushareClient) GetDailyData(tradeDate string, tsCode string) ([]StockDa
 		params["ts_code"] = tsCode
 	}
 
-	data, err := c.request("daily", params, "")
+	data, err := c.request(ctx, "daily", params, "")
 	if err != nil {
 		return nil, err
 	}
@@ -269,6 +805,12 @@ func (c *TushareClient) parseStockBasic(data *TushareData) ([]StockBasicData, er
 	for i, field := range data.Fields {
 		fieldMap[field] = i
 	}
+	// 未返回的字段取 -1，避免误取第 0 列（ts_code）
+	for _, field := range strings.Split(stockBasicFields, ",") {
+		if _, ok := fieldMap[field]; !ok {
+			fieldMap[field] = -1
+		}
+	}
 
 	for _, item := range data.Items {
 		stock := StockBasicData{
@@ -300,15 +842,15 @@ func (c *TushareClient) parseDailyData(data *TushareData) ([]StockDailyData, err
 		daily := StockDailyData{
 			TSCode:    getString(item, fieldMap["ts_code"]),
 			TradeDate: getString(item, fieldMap["trade_date"]),
-			Open:      getFloat(item, fieldMap["open"]),
-			High:      getFloat(item, fieldMap["high"]),
-			Low:       getFloat(item, fieldMap["low"]),
-			Close:     getFloat(item, fieldMap["close"]),
-			PreClose:  getFloat(item, fieldMap["pre_close"]),
-			Change:    getFloat(item, fieldMap["change"]),
-			PctChg:    getFloat(item, fieldMap["pct_chg"]),
-			Vol:       getFloat(item, fieldMap["vol"]),
-			Amount:    getFloat(item, fieldMap["amount"]),
+			Open:      getFloatPtr(item, fieldMap["open"]),
+			High:      getFloatPtr(item, fieldMap["high"]),
+			Low:       getFloatPtr(item, fieldMap["low"]),
+			Close:     getFloatPtr(item, fieldMap["close"]),
+			PreClose:  getFloatPtr(item, fieldMap["pre_close"]),
+			Change:    getFloatPtr(item, fieldMap["change"]),
+			PctChg:    getFloatPtr(item, fieldMap["pct_chg"]),
+			Vol:       getFloatPtr(item, fieldMap["vol"]),
+			Amount:    getFloatPtr(item, fieldMap["amount"]),
 		}
 		result = append(result, daily)
 	}
@@ -316,13 +858,19 @@ func (c *TushareClient) parseDailyData(data *TushareData) ([]StockDailyData, err
 	return result, nil
 }
 
-// GetTradeCal 获取交易日历
+// GetTradeCal 获取上交所交易日历
 // startDate: 开始日期 YYYYMMDD
 // endDate: 结束日期 YYYYMMDD
 // isOpen: 是否只获取交易日 1-交易日 0-休市日 空-全部
-func (c *TushareClient) GetTradeCal(startDate, endDate string, isOpen int) ([]TradeCal, error) {
+func (c *TushareClient) GetTradeCal(ctx context.Context, startDate, endDate string, isOpen int) ([]TradeCal, error) {
+	return c.GetExchangeTradeCal(ctx, "SSE", startDate, endDate, isOpen)
+}
+
+// GetExchangeTradeCal 获取指定交易所的交易日历
+// exchange: 交易所 SSE上交所 SZSE深交所
+func (c *TushareClient) GetExchangeTradeCal(ctx context.Context, exchange, startDate, endDate string, isOpen int) ([]TradeCal, error) {
 	params := map[string]interface{}{
-		"exchange": "SSE", // 上交所
+		"exchange": exchange,
 	}
 
 	if startDate != "" {
@@ -335,7 +883,7 @@ func (c *TushareClient) GetTradeCal(startDate, endDate string, isOpen int) ([]Tr
 		params["is_open"] = isOpen
 	}
 
-	data, err := c.request("trade_cal", params, "")
+	data, err := c.request(ctx, "trade_cal", params, "")
 	if err != nil {
 		return nil, err
 	}
@@ -352,6 +900,13 @@ func (c *TushareClient) parseTradeCal(data *TushareData) ([]TradeCal, error) {
 		fieldMap[field] = i
 	}
 
+	// 未返回的字段取 -1，避免误取第 0 列（如未请求 pretrade_date 时）
+	for _, field := range []string{"exchange", "cal_date", "is_open", "pretrade_date"} {
+		if _, ok := fieldMap[field]; !ok {
+			fieldMap[field] = -1
+		}
+	}
+
 	for _, item := range data.Items {
 		cal := TradeCal{
 			Exchange:     getString(item, fieldMap["exchange"]),
@@ -367,7 +922,7 @@ func (c *TushareClient) parseTradeCal(data *TushareData) ([]TradeCal, error) {
 
 // GetWeeklyData 获取周线数据
 // tradeDate: 交易日期 YYYYMMDD
-func (c *TushareClient) GetWeeklyData(tradeDate string) ([]StockWeeklyData, error) {
+func (c *TushareClient) GetWeeklyData(ctx context.Context, tradeDate string) ([]StockWeeklyData, error) {
 	params := map[string]interface{}{
 		"freq": "week", // 频率：周
 	}
@@ -375,7 +930,7 @@ func (c *TushareClient) GetWeeklyData(tradeDate string) ([]StockWeeklyData, erro
 		params["trade_date"] = tradeDate
 	}
 
-	data, err := c.request("stk_week_month_adj", params, "")
+	data, err := c.request(ctx, "stk_week_month_adj", params, "")
 	if err != nil {
 		return nil, err
 	}
@@ -399,31 +954,31 @@ func (c *TushareClient) parseWeeklyData(data *TushareData) ([]StockWeeklyData, e
 			EndDate:   getString(item, fieldMap["end_date"]),
 
 			// 未复权价格
-			Open:     getFloat(item, fieldMap["open"]),
-			High:     getFloat(item, fieldMap["high"]),
-			Low:      getFloat(item, fieldMap["low"]),
-			Close:    getFloat(item, fieldMap["close"]),
-			PreClose: getFloat(item, fieldMap["pre_close"]),
+			Open:     getFloatPtr(item, fieldMap["open"]),
+			High:     getFloatPtr(item, fieldMap["high"]),
+			Low:      getFloatPtr(item, fieldMap["low"]),
+			Close:    getFloatPtr(item, fieldMap["close"]),
+			PreClose: getFloatPtr(item, fieldMap["pre_close"]),
 
 			// 前复权价格
-			OpenQfq:  getFloat(item, fieldMap["open_qfq"]),
-			HighQfq:  getFloat(item, fieldMap["high_qfq"]),
-			LowQfq:   getFloat(item, fieldMap["low_qfq"]),
-			CloseQfq: getFloat(item, fieldMap["close_qfq"]),
+			OpenQfq:  getFloatPtr(item, fieldMap["open_qfq"]),
+			HighQfq:  getFloatPtr(item, fieldMap["high_qfq"]),
+			LowQfq:   getFloatPtr(item, fieldMap["low_qfq"]),
+			CloseQfq: getFloatPtr(item, fieldMap["close_qfq"]),
 
 			// 后复权价格
-			OpenHfq:  getFloat(item, fieldMap["open_hfq"]),
-			HighHfq:  getFloat(item, fieldMap["high_hfq"]),
-			LowHfq:   getFloat(item, fieldMap["low_hfq"]),
-			CloseHfq: getFloat(item, fieldMap["close_hfq"]),
+			OpenHfq:  getFloatPtr(item, fieldMap["open_hfq"]),
+			HighHfq:  getFloatPtr(item, fieldMap["high_hfq"]),
+			LowHfq:   getFloatPtr(item, fieldMap["low_hfq"]),
+			CloseHfq: getFloatPtr(item, fieldMap["close_hfq"]),
 
 			// 成交数据
-			Vol:    getFloat(item, fieldMap["vol"]),
-			Amount: getFloat(item, fieldMap["amount"]),
+			Vol:    getFloatPtr(item, fieldMap["vol"]),
+			Amount: getFloatPtr(item, fieldMap["amount"]),
 
 			// 涨跌数据
-			Change: getFloat(item, fieldMap["change"]),
-			PctChg: getFloat(item, fieldMap["pct_chg"]),
+			Change: getFloatPtr(item, fieldMap["change"]),
+			PctChg: getFloatPtr(item, fieldMap["pct_chg"]),
 		}
 		result = append(result, weekly)
 	}
@@ -434,7 +989,7 @@ func (c *TushareClient) parseWeeklyData(data *TushareData) ([]StockWeeklyData, e
 // GetMonthlyData 获取月线数据（月线复权行情）
 // tradeDate: 交易日期（月末最后一个交易日），格式 YYYYMMDD
 // tsCode: 股票代码，为空则获取该日期所有股票
-func (c *TushareClient) GetMonthlyData(tradeDate string, tsCode string) ([]StockMonthlyData, error) {
+func (c *TushareClient) GetMonthlyData(ctx context.Context, tradeDate string, tsCode string) ([]StockMonthlyData, error) {
 	params := map[string]interface{}{
 		"freq": "month", // 频率：月
 	}
@@ -447,7 +1002,7 @@ func (c *TushareClient) GetMonthlyData(tradeDate string, tsCode string) ([]Stock
 	}
 
 	// 调用 Tushare 月线复权行情接口
-	data, err := c.request("stk_week_month_adj", params, "")
+	data, err := c.request(ctx, "stk_week_month_adj", params, "")
 	if err != nil {
 		return nil, err
 	}
@@ -471,31 +1026,31 @@ func (c *TushareClient) parseMonthlyData(data *TushareData) ([]StockMonthlyData,
 			EndDate:   getTime(item, fieldMap["end_date"]),
 
 			// 未复权价格
-			Open:     getFloat(item, fieldMap["open"]),
-			High:     getFloat(item, fieldMap["high"]),
-			Low:      getFloat(item, fieldMap["low"]),
-			Close:    getFloat(item, fieldMap["close"]),
-			PreClose: getFloat(item, fieldMap["pre_close"]),
+			Open:     getFloatPtr(item, fieldMap["open"]),
+			High:     getFloatPtr(item, fieldMap["high"]),
+			Low:      getFloatPtr(item, fieldMap["low"]),
+			Close:    getFloatPtr(item, fieldMap["close"]),
+			PreClose: getFloatPtr(item, fieldMap["pre_close"]),
 
 			// 前复权价格
-			OpenQfq:  getFloat(item, fieldMap["open_qfq"]),
-			HighQfq:  getFloat(item, fieldMap["high_qfq"]),
-			LowQfq:   getFloat(item, fieldMap["low_qfq"]),
-			CloseQfq: getFloat(item, fieldMap["close_qfq"]),
+			OpenQfq:  getFloatPtr(item, fieldMap["open_qfq"]),
+			HighQfq:  getFloatPtr(item, fieldMap["high_qfq"]),
+			LowQfq:   getFloatPtr(item, fieldMap["low_qfq"]),
+			CloseQfq: getFloatPtr(item, fieldMap["close_qfq"]),
 
 			// 后复权价格
-			OpenHfq:  getFloat(item, fieldMap["open_hfq"]),
-			HighHfq:  getFloat(item, fieldMap["high_hfq"]),
-			LowHfq:   getFloat(item, fieldMap["low_hfq"]),
-			CloseHfq: getFloat(item, fieldMap["close_hfq"]),
+			OpenHfq:  getFloatPtr(item, fieldMap["open_hfq"]),
+			HighHfq:  getFloatPtr(item, fieldMap["high_hfq"]),
+			LowHfq:   getFloatPtr(item, fieldMap["low_hfq"]),
+			CloseHfq: getFloatPtr(item, fieldMap["close_hfq"]),
 
 			// 成交数据
-			Vol:    getFloat(item, fieldMap["vol"]),
-			Amount: getFloat(item, fieldMap["amount"]),
+			Vol:    getFloatPtr(item, fieldMap["vol"]),
+			Amount: getFloatPtr(item, fieldMap["amount"]),
 
 			// 涨跌数据
-			Change: getFloat(item, fieldMap["change"]),
-			PctChg: getFloat(item, fieldMap["pct_chg"]),
+			Change: getFloatPtr(item, fieldMap["change"]),
+			PctChg: getFloatPtr(item, fieldMap["pct_chg"]),
 		}
 		result = append(result, monthly)
 	}
@@ -503,6 +1058,308 @@ func (c *TushareClient) parseMonthlyData(data *TushareData) ([]StockMonthlyData,
 	return result, nil
 }
 
+// GetFinaIndicator 获取财务指标数据
+// tsCode: 股票代码
+// period: 报告期（季度末日期），格式 YYYYMMDD，为空则获取全部报告期
+func (c *TushareClient) GetFinaIndicator(ctx context.Context, tsCode, period string) ([]FinaIndicatorData, error) {
+	params := map[string]interface{}{
+		"ts_code": tsCode,
+	}
+	if period != "" {
+		params["period"] = period
+	}
+
+	// 接口字段较多，只请求 FinaIndicatorData 中定义的字段
+	data, err := c.request(ctx, "fina_indicator", params, tushareFields(FinaIndicatorData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[FinaIndicatorData](data)
+}
+
+// GetConcepts 获取概念分类列表
+func (c *TushareClient) GetConcepts(ctx context.Context) ([]ConceptData, error) {
+	params := map[string]interface{}{
+		"src": "ts",
+	}
+
+	data, err := c.request(ctx, "concept", params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[ConceptData](data)
+}
+
+// GetConceptDetail 获取概念股明细
+// conceptID: 概念分类ID（来自 GetConcepts）
+func (c *TushareClient) GetConceptDetail(ctx context.Context, conceptID string) ([]ConceptDetailData, error) {
+	params := map[string]interface{}{
+		"id": conceptID,
+	}
+
+	data, err := c.request(ctx, "concept_detail", params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[ConceptDetailData](data)
+}
+
+// GetIndexClassify 获取申万行业分类
+// level: 行业级别 L1/L2/L3
+// src: 分类来源 SW2014/SW2021
+func (c *TushareClient) GetIndexClassify(ctx context.Context, level, src string) ([]IndexClassifyData, error) {
+	params := map[string]interface{}{
+		"level": level,
+		"src":   src,
+	}
+
+	data, err := c.request(ctx, "index_classify", params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[IndexClassifyData](data)
+}
+
+// GetIndexMember 获取申万行业成分股
+// indexCode: 行业指数代码（来自 GetIndexClassify）
+func (c *TushareClient) GetIndexMember(ctx context.Context, indexCode string) ([]IndexMemberData, error) {
+	params := map[string]interface{}{
+		"index_code": indexCode,
+	}
+
+	data, err := c.request(ctx, "index_member", params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[IndexMemberData](data)
+}
+
+// GetMinuteData 获取单只股票单日的分钟线数据
+// tsCode: 股票代码
+// tradeDate: 交易日期 YYYYMMDD
+// freq: 分钟频度 1min/5min/15min/30min/60min
+func (c *TushareClient) GetMinuteData(ctx context.Context, tsCode, tradeDate, freq string) ([]StockMinuteData, error) {
+	if !IsValidMinuteFreq(freq) {
+		return nil, fmt.Errorf("不支持的分钟线频率: %s", freq)
+	}
+
+	date, err := ParseDate(tradeDate)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式错误: %w", err)
+	}
+
+	day := date.Format("2006-01-02")
+	params := map[string]interface{}{
+		"ts_code":    tsCode,
+		"freq":       freq,
+		"start_date": day + " 09:00:00",
+		"end_date":   day + " 15:30:00",
+	}
+
+	data, err := c.request(ctx, "stk_mins", params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[StockMinuteData](data)
+}
+
+// GetStockCompany 获取上市公司基本信息
+func (c *TushareClient) GetStockCompany(ctx context.Context, tsCode string) ([]StockCompanyData, error) {
+	params := map[string]interface{}{
+		"ts_code": tsCode,
+	}
+
+	data, err := c.request(ctx, "stock_company", params, tushareFields(StockCompanyData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[StockCompanyData](data)
+}
+
+// GetTopList 获取龙虎榜每日明细
+// tradeDate: 交易日期 YYYYMMDD
+func (c *TushareClient) GetTopList(ctx context.Context, tradeDate string) ([]TopListData, error) {
+	params := map[string]interface{}{
+		"trade_date": tradeDate,
+	}
+
+	data, err := c.request(ctx, "top_list", params, tushareFields(TopListData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[TopListData](data)
+}
+
+// GetBlockTrade 获取大宗交易明细
+// tradeDate: 交易日期 YYYYMMDD
+func (c *TushareClient) GetBlockTrade(ctx context.Context, tradeDate string) ([]BlockTradeData, error) {
+	params := map[string]interface{}{
+		"trade_date": tradeDate,
+	}
+
+	data, err := c.request(ctx, "block_trade", params, tushareFields(BlockTradeData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[BlockTradeData](data)
+}
+
+// GetMarginDetail 获取融资融券交易明细
+// tradeDate: 交易日期 YYYYMMDD
+func (c *TushareClient) GetMarginDetail(ctx context.Context, tradeDate string) ([]MarginDetailData, error) {
+	params := map[string]interface{}{
+		"trade_date": tradeDate,
+	}
+
+	data, err := c.request(ctx, "margin_detail", params, tushareFields(MarginDetailData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[MarginDetailData](data)
+}
+
+// GetIndexBasic 获取指数基本信息
+// market: 市场 SSE上交所 SZSE深交所 CSI中证 SW申万 等，为空获取全部市场
+func (c *TushareClient) GetIndexBasic(ctx context.Context, market string) ([]IndexBasicData, error) {
+	params := map[string]interface{}{}
+	if market != "" {
+		params["market"] = market
+	}
+
+	data, err := c.request(ctx, "index_basic", params, tushareFields(IndexBasicData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[IndexBasicData](data)
+}
+
+// GetHSConst 获取沪深股通成分，包括当前成分和已剔除的历史记录
+// hsType: SH 沪股通 SZ 深股通
+func (c *TushareClient) GetHSConst(ctx context.Context, hsType string) ([]HSConstData, error) {
+	var result []HSConstData
+	// is_new 默认只返回当前成分，历史记录需单独请求
+	for _, isNew := range []string{"1", "0"} {
+		params := map[string]interface{}{
+			"hs_type": hsType,
+			"is_new":  isNew,
+		}
+
+		data, err := c.request(ctx, "hs_const", params, tushareFields(HSConstData{}))
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := decodeTushareData[HSConstData](data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rows...)
+	}
+	return result, nil
+}
+
+// GetAdjFactor 获取指定交易日全部股票的复权因子
+// tradeDate: 交易日期 YYYYMMDD
+func (c *TushareClient) GetAdjFactor(ctx context.Context, tradeDate string) ([]AdjFactorData, error) {
+	params := map[string]interface{}{
+		"trade_date": tradeDate,
+	}
+
+	data, err := c.request(ctx, "adj_factor", params, tushareFields(AdjFactorData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[AdjFactorData](data)
+}
+
+// GetFundBasic 获取基金基本信息
+// market: 交易市场 E场内 O场外，为空时 Tushare 默认返回场内基金
+func (c *TushareClient) GetFundBasic(ctx context.Context, market string) ([]FundBasicData, error) {
+	params := map[string]interface{}{}
+	if market != "" {
+		params["market"] = market
+	}
+
+	data, err := c.request(ctx, "fund_basic", params, tushareFields(FundBasicData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[FundBasicData](data)
+}
+
+// GetFundDaily 获取场内基金（ETF、LOF 等）日线行情
+// tradeDate: 交易日期 YYYYMMDD，获取当日全部基金；tsCode 不为空时只获取该基金
+func (c *TushareClient) GetFundDaily(ctx context.Context, tradeDate, tsCode string) ([]FundDailyData, error) {
+	params := map[string]interface{}{
+		"trade_date": tradeDate,
+	}
+	if tsCode != "" {
+		params["ts_code"] = tsCode
+	}
+
+	data, err := c.request(ctx, "fund_daily", params, tushareFields(FundDailyData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[FundDailyData](data)
+}
+
+// GetProBar 获取单只股票在日期范围内的日线，adj 为 qfq/hfq 时返回 Tushare 计算好的复权价格
+// tsCode: 股票代码
+// startDate, endDate: 日期范围 YYYYMMDD
+// adj: 复权方式，为空不复权
+func (c *TushareClient) GetProBar(ctx context.Context, tsCode, startDate, endDate, adj string) ([]StockDailyData, error) {
+	if !IsValidAdj(adj) {
+		return nil, fmt.Errorf("不支持的复权方式: %s", adj)
+	}
+
+	params := map[string]interface{}{
+		"ts_code":    tsCode,
+		"start_date": startDate,
+		"end_date":   endDate,
+		"asset":      "E",
+		"freq":       "D",
+	}
+	if adj != AdjNone {
+		params["adj"] = adj
+	}
+
+	data, err := c.request(ctx, "pro_bar", params, tushareFields(StockDailyData{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTushareData[StockDailyData](data)
+}
+
+// CheckToken 用一次最小的 trade_cal 请求校验 Token 是否可用
+// Token 无效或权限不足时返回 *APIError
+func (c *TushareClient) CheckToken(ctx context.Context) error {
+	today := time.Now().Format("20060102")
+	params := map[string]interface{}{
+		"exchange":   "SSE",
+		"start_date": today,
+		"end_date":   today,
+	}
+
+	_, err := c.request(bypassRequestCache(ctx), "trade_cal", params, "cal_date")
+	return err
+}
+
 // 辅助函数
 func getString(item []interface{}, index int) string {
 	if index < 0 || index >= len(item) || item[index] == nil {
@@ -530,13 +1387,29 @@ func getFloat(item []interface{}, index int) float64 {
 	}
 }
 
+// getFloatPtr 获取可空数值，null 或缺失时返回 nil，用于区分真实的 0 与缺失值
+func getFloatPtr(item []interface{}, index int) *float64 {
+	if index < 0 || index >= len(item) || item[index] == nil {
+		return nil
+	}
+	switch v := item[index].(type) {
+	case float64:
+		return &v
+	case int:
+		f := float64(v)
+		return &f
+	default:
+		return nil
+	}
+}
+
 func getTime(item []interface{}, index int) time.Time {
 	if index < 0 || index >= len(item) || item[index] == nil {
 		return time.Time{}
 	}
 	if str, ok := item[index].(string); ok {
 		// Tushare 日期格式: YYYYMMDD
-		if t, err := time.Parse("20060102", str); err == nil {
+		if t, err := ParseDate(str); err == nil {
 			return t
 		}
 	}
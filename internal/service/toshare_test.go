@@ -1,15 +1,23 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestGetDailyData_Success 测试成功获取日线数据
@@ -60,10 +68,10 @@ func TestGetDailyData_Success(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
 	// 执行测试
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	// 验证结果
 	require.NoError(t, err)
@@ -73,15 +81,15 @@ func TestGetDailyData_Success(t *testing.T) {
 	// 验证第一条数据
 	assert.Equal(t, "000001.SZ", data[0].TSCode)
 	assert.Equal(t, "20231201", data[0].TradeDate)
-	assert.Equal(t, 10.5, data[0].Open)
-	assert.Equal(t, 11.0, data[0].High)
-	assert.Equal(t, 10.2, data[0].Low)
-	assert.Equal(t, 10.8, data[0].Close)
-	assert.Equal(t, 10.6, data[0].PreClose)
-	assert.Equal(t, 0.2, data[0].Change)
-	assert.Equal(t, 1.89, data[0].PctChg)
-	assert.Equal(t, 123456.78, data[0].Vol)
-	assert.Equal(t, 1234567.89, data[0].Amount)
+	assert.Equal(t, 10.5, *data[0].Open)
+	assert.Equal(t, 11.0, *data[0].High)
+	assert.Equal(t, 10.2, *data[0].Low)
+	assert.Equal(t, 10.8, *data[0].Close)
+	assert.Equal(t, 10.6, *data[0].PreClose)
+	assert.Equal(t, 0.2, *data[0].Change)
+	assert.Equal(t, 1.89, *data[0].PctChg)
+	assert.Equal(t, 123456.78, *data[0].Vol)
+	assert.Equal(t, 1234567.89, *data[0].Amount)
 
 	// 验证第二条数据
 	assert.Equal(t, "000002.SZ", data[1].TSCode)
@@ -118,10 +126,10 @@ func TestGetDailyData_WithTSCode(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
 	// 测试按股票代码查询
-	data, err := client.GetDailyData("", "000001.SZ")
+	data, err := client.GetDailyData(context.Background(), "", "000001.SZ")
 
 	require.NoError(t, err)
 	require.Len(t, data, 1)
@@ -159,9 +167,9 @@ func TestGetDailyData_WithBothParams(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "000001.SZ")
+	data, err := client.GetDailyData(context.Background(), "20231201", "000001.SZ")
 
 	require.NoError(t, err)
 	require.Len(t, data, 1)
@@ -189,9 +197,9 @@ func TestGetDailyData_EmptyResult(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	require.NoError(t, err)
 	require.NotNil(t, data)
@@ -218,9 +226,9 @@ func TestGetDailyData_APIError(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	require.Error(t, err)
 	assert.Nil(t, data)
@@ -235,9 +243,9 @@ func TestGetDailyData_NetworkError(t *testing.T) {
 		Timeout: 1, // 1秒超时
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	require.Error(t, err)
 	assert.Nil(t, data)
@@ -277,9 +285,9 @@ func TestGetDailyData_RetryMechanism(t *testing.T) {
 		Timeout: 30,
 		Retry:   3, // 重试3次
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	// 应该成功（第3次重试成功）
 	require.NoError(t, err)
@@ -287,6 +295,42 @@ func TestGetDailyData_RetryMechanism(t *testing.T) {
 	assert.Equal(t, 3, callCount)
 }
 
+// TestGetDailyData_HTMLBadGateway 测试代理返回 HTML 502 页面时报错包含状态码和响应片段，并按可重试错误重试
+func TestGetDailyData_HTMLBadGateway(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>\n<head><title>502 Bad Gateway</title></head>\n<body>" + strings.Repeat("nginx ", 100) + "</body>\n</html>"))
+	}))
+	defer server.Close()
+
+	cfg := &config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+		Retry:   1,
+	}
+	client := NewTushareClient(cfg, zap.NewNop())
+
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
+
+	require.Error(t, err)
+	assert.Nil(t, data)
+	assert.Contains(t, err.Error(), "502")
+	assert.Contains(t, err.Error(), "<head><title>502 Bad Gateway</title></head>")
+
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	assert.True(t, httpErr.Retryable())
+	assert.LessOrEqual(t, len(httpErr.Snippet), maxBodySnippet+len("..."))
+
+	// 首次请求加一次重试
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
 // TestGetDailyData_NullValues 测试处理 null 值
 func TestGetDailyData_NullValues(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -312,18 +356,19 @@ func TestGetDailyData_NullValues(t *testing.T) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	require.NoError(t, err)
 	require.Len(t, data, 1)
 
-	// null 值应该被转换为 0
-	assert.Equal(t, 0.0, data[0].Open)
-	assert.Equal(t, 0.0, data[0].High)
-	assert.Equal(t, 10.2, data[0].Low)
-	assert.Equal(t, 10.8, data[0].Close)
+	// null 值应保持为 nil，与真实的 0 区分
+	assert.Nil(t, data[0].Open)
+	assert.Nil(t, data[0].High)
+	assert.Nil(t, data[0].Vol)
+	assert.Equal(t, 10.2, *data[0].Low)
+	assert.Equal(t, 10.8, *data[0].Close)
 }
 
 // TestGetDailyData_Timeout 测试超时
@@ -341,15 +386,258 @@ func TestGetDailyData_Timeout(t *testing.T) {
 		Timeout: 1, // 1秒超时
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
-	data, err := client.GetDailyData("20231201", "")
+	data, err := client.GetDailyData(context.Background(), "20231201", "")
 
 	require.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "deadline exceeded")
 }
 
+// TestGetFinaIndicator_Success 测试获取财务指标数据
+func TestGetFinaIndicator_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		// 验证请求参数与字段列表
+		assert.Equal(t, "fina_indicator", req.APIName)
+		assert.Equal(t, "000001.SZ", req.Params["ts_code"])
+		assert.Equal(t, "20231231", req.Params["period"])
+		assert.Contains(t, req.Fields, "roe")
+		assert.Contains(t, req.Fields, "debt_to_assets")
+
+		// 列顺序与结构体字段顺序不同，且包含未定义的列
+		mockData := TushareData{
+			Fields: []string{"end_date", "ts_code", "ann_date", "roe", "grossprofit_margin", "debt_to_assets", "unknown_field"},
+			Items: [][]interface{}{
+				{"20231231", "000001.SZ", "20240315", 9.84, nil, 91.52, "x"},
+			},
+		}
+
+		dataBytes, _ := json.Marshal(mockData)
+		resp := TushareResponse{Code: 0, Msg: "success", Data: dataBytes}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	cfg := &config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 30,
+		Retry:   0,
+	}
+	client := NewTushareClient(cfg, zap.NewNop())
+
+	data, err := client.GetFinaIndicator(context.Background(), "000001.SZ", "20231231")
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, "000001.SZ", data[0].TSCode)
+	assert.Equal(t, "20231231", data[0].EndDate)
+	assert.Equal(t, "20240315", data[0].AnnDate)
+	assert.Equal(t, 9.84, data[0].ROE)
+	assert.Equal(t, 0.0, data[0].GrossprofitMargin)
+	assert.Equal(t, 91.52, data[0].DebtToAssets)
+}
+
+// TestGetStockBasic_QueryFromConfig 测试股票列表请求参数与配置一致
+func TestGetStockBasic_QueryFromConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.FetcherConfig
+		listStatus string
+		market     interface{}
+	}{
+		{name: "默认上市全部市场", cfg: config.FetcherConfig{}, listStatus: "L", market: nil},
+		{name: "仅主板", cfg: config.FetcherConfig{StockListStatus: "L", StockMarket: "主板"}, listStatus: "L", market: "主板"},
+		{name: "退市股票", cfg: config.FetcherConfig{StockListStatus: "D"}, listStatus: "D", market: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req TushareRequest
+				json.NewDecoder(r.Body).Decode(&req)
+
+				assert.Equal(t, "stock_basic", req.APIName)
+				assert.Equal(t, tt.listStatus, req.Params["list_status"])
+				assert.Equal(t, tt.market, req.Params["market"])
+
+				mockData := TushareData{
+					Fields: []string{"ts_code", "symbol", "name", "market", "list_status"},
+					Items: [][]interface{}{
+						{"600000.SH", "600000", "浦发银行", "主板", tt.listStatus},
+					},
+				}
+
+				dataBytes, _ := json.Marshal(mockData)
+				resp := TushareResponse{Code: 0, Msg: "success", Data: dataBytes}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(resp)
+			}))
+			defer server.Close()
+
+			client := NewTushareClient(&config.TushareConfig{
+				Token:   "test_token",
+				BaseURL: server.URL,
+				Timeout: 30,
+			}, zap.NewNop())
+
+			data, err := client.GetStockBasic(context.Background(), NewStockBasicQuery(&tt.cfg))
+
+			require.NoError(t, err)
+			require.Len(t, data, 1)
+			assert.Equal(t, tt.listStatus, data[0].ListStatus)
+		})
+	}
+}
+
+// TestGetMinuteData_Success 测试获取分钟线数据
+func TestGetMinuteData_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		assert.Equal(t, "stk_mins", req.APIName)
+		assert.Equal(t, "600000.SH", req.Params["ts_code"])
+		assert.Equal(t, "30min", req.Params["freq"])
+		assert.Equal(t, "2023-12-01 09:00:00", req.Params["start_date"])
+		assert.Equal(t, "2023-12-01 15:30:00", req.Params["end_date"])
+
+		mockData := TushareData{
+			Fields: []string{"ts_code", "trade_time", "close", "open", "high", "low", "vol", "amount"},
+			Items: [][]interface{}{
+				{"600000.SH", "2023-12-01 10:00:00", 7.12, 7.10, 7.15, 7.08, 1234500.0, 8790000.0},
+			},
+		}
+
+		dataBytes, _ := json.Marshal(mockData)
+		resp := TushareResponse{Code: 0, Msg: "success", Data: dataBytes}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 30,
+	}, zap.NewNop())
+
+	data, err := client.GetMinuteData(context.Background(), "600000.SH", "20231201", "30min")
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, "2023-12-01 10:00:00", data[0].TradeTime)
+	assert.Equal(t, 7.10, data[0].Open)
+	assert.Equal(t, 7.12, data[0].Close)
+
+	// 不支持的频率直接返回错误
+	_, err = client.GetMinuteData(context.Background(), "600000.SH", "20231201", "2min")
+	require.Error(t, err)
+}
+
+// TestTushareClient_KeepAlive 测试连续请求复用同一个连接
+func TestTushareClient_KeepAlive(t *testing.T) {
+	var newConns int64
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockData := TushareData{
+			Fields: []string{"ts_code", "trade_date", "close"},
+			Items:  [][]interface{}{{"000001.SZ", "20231201", 10.8}},
+		}
+
+		dataBytes, _ := json.Marshal(mockData)
+		resp := TushareResponse{Code: 0, Msg: "success", Data: dataBytes}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&newConns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 30,
+	}, zap.NewNop())
+
+	for i := 0; i < 10; i++ {
+		_, err := client.GetDailyData(context.Background(), "20231201", "")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(1), atomic.LoadInt64(&newConns))
+}
+
+// TestTushareClient_CircuitBreaker 测试连续失败后熔断，熔断期间不再请求服务端
+func TestTushareClient_CircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := &config.TushareConfig{
+		Token:            "test_token",
+		BaseURL:          server.URL,
+		Timeout:          5,
+		Retry:            0,
+		BreakerThreshold: 2,
+		BreakerCooldown:  60,
+	}
+	client := NewTushareClient(cfg, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetDailyData(context.Background(), "20231201", "")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.True(t, client.CircuitOpen())
+
+	_, err := client.GetDailyData(context.Background(), "20231201", "")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// TestTushareClient_CircuitBreakerIgnoresAPIError 测试 Tushare 业务错误不触发熔断
+func TestTushareClient_CircuitBreakerIgnoresAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TushareResponse{Code: 40203, Msg: "抱歉，您没有访问该接口的权限"})
+	}))
+	defer server.Close()
+
+	cfg := &config.TushareConfig{
+		Token:            "test_token",
+		BaseURL:          server.URL,
+		Timeout:          5,
+		Retry:            0,
+		BreakerThreshold: 1,
+	}
+	client := NewTushareClient(cfg, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetDailyData(context.Background(), "20231201", "")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 40203, apiErr.Code)
+	}
+	assert.False(t, client.CircuitOpen())
+}
+
 // Benchmark 性能测试
 func BenchmarkGetDailyData(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -378,10 +666,161 @@ func BenchmarkGetDailyData(b *testing.B) {
 		Timeout: 30,
 		Retry:   0,
 	}
-	client := NewTushareClient(cfg)
+	client := NewTushareClient(cfg, zap.NewNop())
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		client.GetDailyData("20231201", "")
+		client.GetDailyData(context.Background(), "20231201", "")
+	}
+}
+
+// TestRequest_UserAgentAndRedactedLog 测试请求携带配置的 User-Agent，调试日志记录接口名和参数且不包含 Token
+func TestRequest_UserAgentAndRedactedLog(t *testing.T) {
+	const token = "secret_token_123"
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"ts_code"}, Items: [][]interface{}{}})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	client := NewTushareClient(&config.TushareConfig{
+		Token:     token,
+		BaseURL:   server.URL,
+		Timeout:   5,
+		UserAgent: "stock_data_test/2.0",
+	}, zap.New(core))
+
+	// 参数中误带 Token 时同样需要脱敏
+	_, err := client.request(context.Background(), "daily", map[string]interface{}{"trade_date": "20231201", "note": "token=" + token}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "stock_data_test/2.0", userAgent)
+
+	entries := logs.FilterMessage("请求 Tushare 接口").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "daily", fields["api_name"])
+	params, ok := fields["params"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "20231201", params["trade_date"])
+	assert.Equal(t, "token="+redactedToken, params["note"])
+
+	for _, entry := range logs.All() {
+		encoded, err := json.Marshal(entry.ContextMap())
+		require.NoError(t, err)
+		assert.NotContains(t, entry.Message+string(encoded), token)
+	}
+
+	// 未配置时使用默认 User-Agent
+	client = NewTushareClient(&config.TushareConfig{Token: token, BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+	_, err = client.request(context.Background(), "daily", map[string]interface{}{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, defaultUserAgent, userAgent)
+}
+
+// TestRequest_RetryAfter429 测试 429 响应按 Retry-After 等待后重试，而不是使用默认退避
+func TestRequest_RetryAfter429(t *testing.T) {
+	var attempts, noHint int32
+	var retriedAt time.Time
+	firstAt := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&noHint) == 1 {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			firstAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		retriedAt = time.Now()
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"ts_code"}, Items: [][]interface{}{}})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	client := NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+		Retry:   2,
+	}, zap.NewNop())
+	// 默认退避很长，若未使用 Retry-After 测试会超时
+	client.retryDelay = time.Hour
+
+	_, err := client.request(context.Background(), "daily", map[string]interface{}{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.GreaterOrEqual(t, retriedAt.Sub(firstAt), time.Second)
+	assert.Less(t, retriedAt.Sub(firstAt), 5*time.Second)
+
+	// 无提示时按指数退避；ctx 取消时立即停止等待
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	atomic.StoreInt32(&attempts, 0)
+	atomic.StoreInt32(&noHint, 1)
+	start := time.Now()
+	_, err = client.request(ctx, "daily", map[string]interface{}{}, "")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+// TestParseRetryAfter 测试解析秒数与 HTTP 日期格式的 Retry-After
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, maxRetryAfter, parseRetryAfter("86400", now))
+}
+
+// TestFetchAllStockBasic 测试按上市状态分别抓取后合并，状态变化时更新已存储的 list_status
+func TestFetchAllStockBasic(t *testing.T) {
+	byStatus := map[string][][]interface{}{
+		"L": {{"000001.SZ", "000001", "平安银行"}, {"600000.SH", "600000", "浦发银行"}},
+		"D": {{"000003.SZ", "000003", "PT金田A"}},
+		"P": {},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Fields, "list_status")
+
+		status, _ := req.Params["list_status"].(string)
+		// 不返回 list_status 字段时以请求的状态补全
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "symbol", "name"},
+			Items:  byStatus[status],
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockBasic{})
+	fetcher.config.StockListStatuses = []string{"L", "D", "P"}
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+	require.NoError(t, fetcher.db.Create(&models.StockBasic{TSCode: "000003.SZ", Name: "PT金田A", ListStatus: "L"}).Error)
+
+	counts, err := fetcher.FetchAllStockBasic(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"L": 2, "D": 1, "P": 0}, counts)
+
+	var stocks []models.StockBasic
+	require.NoError(t, fetcher.db.Order("ts_code").Find(&stocks).Error)
+	statuses := make(map[string]string, len(stocks))
+	for _, stock := range stocks {
+		statuses[stock.TSCode] = stock.ListStatus
 	}
+	assert.Equal(t, map[string]string{"000001.SZ": "L", "000003.SZ": "D", "600000.SH": "L"}, statuses)
 }
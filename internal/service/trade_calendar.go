@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FetchTradeCalendar 抓取沪深交易所的交易日历（含休市日及上一个交易日）并保存到 trade_calendar，返回保存的条数
+func (f *DataFetcher) FetchTradeCalendar(ctx context.Context, startDate, endDate string) (int, error) {
+	f.logger.Info("开始抓取交易日历",
+		zap.String("start_date", startDate),
+		zap.String("end_date", endDate))
+
+	var records []models.TradeCalendar
+	for _, exchange := range tradeCalendarExchanges {
+		if err := f.waitTushare(ctx); err != nil {
+			return 0, err
+		}
+		calData, err := f.tushareClient.GetExchangeTradeCal(ctx, exchange, startDate, endDate, 0) // 0 = 包含休市日
+		if err != nil {
+			return 0, fmt.Errorf("获取交易日历失败（%s）: %w", exchange, err)
+		}
+		for _, cal := range calData {
+			if cal.Exchange == "" {
+				cal.Exchange = exchange
+			}
+			records = append(records, models.TradeCalendar{
+				Exchange:     cal.Exchange,
+				CalDate:      cal.CalDate,
+				IsOpen:       cal.IsOpen,
+				PreTradeDate: cal.PreTradeDate,
+			})
+		}
+	}
+
+	if len(records) > 0 {
+		// 交易所调整休市安排后重新抓取会更新已有记录
+		if err := f.db.Clauses(upsertClause(f.db, []string{"exchange", "cal_date"}, "is_open", "pretrade_date", "updated_at")).CreateInBatches(records, f.config.BatchSize).Error; err != nil {
+			return 0, fmt.Errorf("保存交易日历失败: %w", err)
+		}
+	}
+
+	f.logger.Info("交易日历抓取完成", zap.Int("total", len(records)))
+	return len(records), nil
+}
+
+// PreviousTradingDay 返回 date（YYYYMMDD）之前最近的一个交易日，date 本身为休市日时同样适用
+// 优先使用已存储交易日历的 pretrade_date，沪深交易所不一致时取较晚的日期（任一交易所开市即为交易日）；
+// 未存储该日期时从 Tushare 交易日历查询
+func (f *DataFetcher) PreviousTradingDay(ctx context.Context, date string) (string, error) {
+	day, err := ParseDate(date)
+	if err != nil {
+		return "", fmt.Errorf("日期格式错误，应为 YYYYMMDD: %s", date)
+	}
+
+	var preTradeDates []string
+	if err := f.db.Model(&models.TradeCalendar{}).
+		Where("cal_date = ? AND pretrade_date <> ''", date).
+		Order("pretrade_date desc").
+		Limit(1).
+		Pluck("pretrade_date", &preTradeDates).Error; err != nil {
+		return "", fmt.Errorf("查询交易日历失败: %w", err)
+	}
+	if len(preTradeDates) > 0 {
+		return preTradeDates[0], nil
+	}
+
+	// 最长的休市（春节）不超过两周，回看 30 天足够
+	tradeDates, err := f.getTradeDates(ctx, day.AddDate(0, 0, -30).Format("20060102"), day.AddDate(0, 0, -1).Format("20060102"))
+	if err != nil {
+		return "", err
+	}
+	if len(tradeDates) == 0 {
+		return "", fmt.Errorf("%s 之前 30 天内没有交易日", date)
+	}
+	return tradeDates[len(tradeDates)-1], nil
+}
+
+// MaxNextTradingDays 单次查询后续交易日的数量上限，约一年的交易日
+const MaxNextTradingDays = 250
+
+// NextTradingDays 返回 from（YYYYMMDD，不含）之后的 count 个交易日，任一交易所开市即为交易日
+// 优先使用已存储的交易日历：日历从 from 起逐日连续存储，遇到未存储的日期即停止（缺失的日期可能是交易日）；
+// 已存储的交易日不足 count 个且 fetchMissing 为 true 时，从 Tushare 抓取该范围的交易日历并保存后重新查询。
+// 交易所尚未发布足够远的日历时返回的交易日少于 count 个
+func (f *DataFetcher) NextTradingDays(ctx context.Context, from string, count int, fetchMissing bool) ([]string, error) {
+	day, err := ParseDate(from)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式错误，应为 YYYYMMDD: %s", from)
+	}
+
+	// 每周 5 个交易日，另加 30 天覆盖长假
+	end := day.AddDate(0, 0, count*7/5+30).Format("20060102")
+	tradeDates, err := f.storedNextTradingDays(ctx, day, end, count)
+	if err != nil || len(tradeDates) == count || !fetchMissing {
+		return tradeDates, err
+	}
+
+	if _, err := f.FetchTradeCalendar(ctx, day.AddDate(0, 0, 1).Format("20060102"), end); err != nil {
+		return nil, err
+	}
+	return f.storedNextTradingDays(ctx, day, end, count)
+}
+
+// storedNextTradingDays 按已存储的交易日历返回 day 之后、不晚于 end 的至多 count 个交易日，遇到未存储的日期即停止
+func (f *DataFetcher) storedNextTradingDays(ctx context.Context, day time.Time, end string, count int) ([]string, error) {
+	var calendar []struct {
+		CalDate string
+		IsOpen  int
+	}
+	if err := f.db.WithContext(ctx).Model(&models.TradeCalendar{}).
+		Select("cal_date, MAX(is_open) AS is_open").
+		Where("cal_date > ? AND cal_date <= ?", day.Format("20060102"), end).
+		Group("cal_date").
+		Order("cal_date").
+		Scan(&calendar).Error; err != nil {
+		return nil, fmt.Errorf("查询交易日历失败: %w", err)
+	}
+
+	tradeDates := make([]string, 0, count)
+	for _, cal := range calendar {
+		day = day.AddDate(0, 0, 1)
+		if cal.CalDate != day.Format("20060102") {
+			break
+		}
+		if cal.IsOpen == 1 {
+			tradeDates = append(tradeDates, cal.CalDate)
+			if len(tradeDates) == count {
+				break
+			}
+		}
+	}
+	return tradeDates, nil
+}
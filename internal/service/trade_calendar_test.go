@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestTradeCalendar 测试保存交易日历并按 pretrade_date 查询上一个交易日，未存储的日期从 Tushare 查询
+func TestTradeCalendar(t *testing.T) {
+	calendar := [][]interface{}{
+		{"20231229", 1.0, "20231228"},
+		{"20231230", 0.0, "20231229"},
+		{"20231231", 0.0, "20231229"},
+		{"20240101", 0.0, "20231229"},
+		{"20240102", 1.0, "20231229"},
+		{"20240103", 1.0, "20240102"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		start, _ := req.Params["start_date"].(string)
+		end, _ := req.Params["end_date"].(string)
+		_, openOnly := req.Params["is_open"]
+
+		var items [][]interface{}
+		for _, day := range calendar {
+			if day[0].(string) >= start && day[0].(string) <= end && (!openOnly || day[1] == 1.0) {
+				items = append(items, append([]interface{}{req.Params["exchange"]}, day...))
+			}
+		}
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"exchange", "cal_date", "is_open", "pretrade_date"}, Items: items})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.TradeCalendar{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	count, err := fetcher.FetchTradeCalendar(context.Background(), "20231229", "20240102")
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+
+	var days []models.TradeCalendar
+	require.NoError(t, fetcher.db.Where("exchange = ?", "SSE").Order("cal_date").Find(&days).Error)
+	require.Len(t, days, 5)
+	assert.Equal(t, "20240101", days[3].CalDate)
+	assert.Equal(t, 0, days[3].IsOpen)
+	assert.Equal(t, "20231229", days[3].PreTradeDate)
+
+	// 重复抓取更新已有记录
+	count, err = fetcher.FetchTradeCalendar(context.Background(), "20231229", "20240102")
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+	var total int64
+	require.NoError(t, fetcher.db.Model(&models.TradeCalendar{}).Count(&total).Error)
+	assert.Equal(t, int64(10), total)
+
+	// 交易日与休市日都按已存储的 pretrade_date 返回
+	prev, err := fetcher.PreviousTradingDay(context.Background(), "20240102")
+	require.NoError(t, err)
+	assert.Equal(t, "20231229", prev)
+	prev, err = fetcher.PreviousTradingDay(context.Background(), "20240101")
+	require.NoError(t, err)
+	assert.Equal(t, "20231229", prev)
+
+	// 未存储的日期从 Tushare 查询
+	prev, err = fetcher.PreviousTradingDay(context.Background(), "20240104")
+	require.NoError(t, err)
+	assert.Equal(t, "20240103", prev)
+
+	_, err = fetcher.PreviousTradingDay(context.Background(), "2024-01-04")
+	assert.Error(t, err)
+}
+
+// TestNextTradingDays 测试不抓取缺失的日历时只按已存储的交易日历返回后续交易日，遇到未存储的日期即停止
+func TestNextTradingDays(t *testing.T) {
+	fetcher := newTestFetcher(t, &models.TradeCalendar{})
+	var records []models.TradeCalendar
+	for _, exchange := range []string{"SSE", "SZSE"} {
+		for _, day := range []struct {
+			date   string
+			isOpen int
+		}{{"20231229", 1}, {"20231230", 0}, {"20231231", 0}, {"20240101", 0}, {"20240102", 1}, {"20240103", 1}} {
+			records = append(records, models.TradeCalendar{Exchange: exchange, CalDate: day.date, IsOpen: day.isOpen})
+		}
+	}
+	require.NoError(t, fetcher.db.Create(&records).Error)
+
+	days, err := fetcher.NextTradingDays(context.Background(), "20231228", 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229", "20240102"}, days)
+
+	// 已存储的日历不足时返回的交易日少于 count 个
+	days, err = fetcher.NextTradingDays(context.Background(), "20231229", 5, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20240102", "20240103"}, days)
+
+	// 中间缺失的日期可能是交易日，之后的日期不返回
+	require.NoError(t, fetcher.db.Where("cal_date = ?", "20231231").Delete(&models.TradeCalendar{}).Error)
+	days, err = fetcher.NextTradingDays(context.Background(), "20231228", 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229"}, days)
+
+	_, err = fetcher.NextTradingDays(context.Background(), "2023-12-28", 2, false)
+	assert.Error(t, err)
+}
+
+// TestNextTradingDays_FetchMissing 测试已存储的交易日不足时从 Tushare 抓取交易日历并保存，足够时不再请求
+func TestNextTradingDays_FetchMissing(t *testing.T) {
+	calendar := [][]interface{}{
+		{"20231229", 1.0, "20231228"},
+		{"20231230", 0.0, "20231229"},
+		{"20231231", 0.0, "20231229"},
+		{"20240101", 0.0, "20231229"},
+		{"20240102", 1.0, "20231229"},
+		{"20240103", 1.0, "20240102"},
+	}
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "trade_cal", req.APIName)
+		start, _ := req.Params["start_date"].(string)
+		end, _ := req.Params["end_date"].(string)
+
+		var items [][]interface{}
+		for _, day := range calendar {
+			if day[0].(string) >= start && day[0].(string) <= end {
+				items = append(items, append([]interface{}{req.Params["exchange"]}, day...))
+			}
+		}
+		dataBytes, _ := json.Marshal(TushareData{Fields: []string{"exchange", "cal_date", "is_open", "pretrade_date"}, Items: items})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.TradeCalendar{})
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{Token: "test_token", BaseURL: server.URL, Timeout: 5}, zap.NewNop())
+
+	// 交易所未发布的日期不返回
+	days, err := fetcher.NextTradingDays(context.Background(), "20231228", 5, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229", "20240102", "20240103"}, days)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	var stored int64
+	require.NoError(t, fetcher.db.Model(&models.TradeCalendar{}).Count(&stored).Error)
+	assert.Equal(t, int64(12), stored)
+
+	// 已存储的日历足够时不再请求
+	days, err = fetcher.NextTradingDays(context.Background(), "20231228", 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20231229", "20240102"}, days)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
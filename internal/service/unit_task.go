@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// 抓取单元的类型，决定失败记录及日志中单元所在的字段
+const (
+	unitDate   = "date"    // 按交易日抓取全部股票
+	unitTSCode = "ts_code" // 按股票抓取整个日期范围
+)
+
+// unitTask 按单元（交易日或股票）并发抓取的任务
+type unitTask struct {
+	prefix    string // task_id 前缀，如 block_trade_task_
+	name      string // 日志中的数据名称，如 大宗交易数据
+	unit      string // 单元类型：unitDate 或 unitTSCode
+	startDate string
+	endDate   string
+
+	// units 生成抓取单元列表，失败时任务标记为失败
+	units func(ctx context.Context) ([]string, error)
+	// fetch 抓取并保存单个单元，返回 Tushare 返回的行数；调用前已完成限流，行数由 fetch 自行记录到 rows
+	fetch func(ctx context.Context, unit string, rows *rowTracker) (int, error)
+}
+
+// runUnitTask 创建任务记录后按单元并发抓取，逐个单元更新进度，结束时按等待结果更新任务状态
+// 按日期抓取的单元失败时按 fetcher.date_retry 重新抓取（见 retryDate），仍失败只计入失败数并记录失败明细，
+// 不中断其他单元；限流等待失败（取消、熔断）时提前终止
+func (f *DataFetcher) runUnitTask(ctx context.Context, spec unitTask) (*models.FetchTask, error) {
+	// 创建任务记录
+	task := &models.FetchTask{
+		TaskID:    newTaskID(spec.prefix),
+		StartDate: spec.startDate,
+		EndDate:   spec.endDate,
+		Status:    models.TaskStatusRunning,
+		StartTime: time.Now(),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	units, err := spec.units(ctx)
+	if err != nil {
+		f.failTask(task, err)
+		return task, err
+	}
+	task.TotalCount = len(units)
+	f.saveTask(task)
+
+	f.logger.Info("开始抓取"+spec.name,
+		zap.String("task_id", task.TaskID),
+		zap.Int("total", len(units)))
+
+	// 使用 errgroup 并发抓取
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+
+	var successCount, failedCount int64
+	rows := newRowTracker(nil)
+	gctx = withCallCounter(gctx, rows)
+
+	for _, unit := range units {
+		unit := unit
+
+		g.Go(func() error {
+			// 限流
+			if err := f.waitTushare(gctx); err != nil {
+				return err
+			}
+
+			var count int
+			run := func() (err error) {
+				count, err = spec.fetch(gctx, unit, rows)
+				return err
+			}
+			var err error
+			if spec.unit == unitDate {
+				err = f.retryDate(gctx, task.TaskID, unit, run)
+			} else {
+				err = run()
+			}
+			if err != nil {
+				atomic.AddInt64(&failedCount, 1)
+				if spec.unit == unitTSCode {
+					f.recordFailure(task.TaskID, "", unit, err)
+				} else {
+					f.recordFailure(task.TaskID, unit, "", err)
+				}
+				f.logger.Error("抓取"+spec.name+"失败",
+					zap.String("task_id", task.TaskID),
+					zap.String(spec.unit, unit),
+					zap.Error(err))
+			} else {
+				atomic.AddInt64(&successCount, 1)
+				f.logger.Debug(spec.name+"保存成功",
+					zap.String(spec.unit, unit),
+					zap.Int("count", count))
+			}
+
+			// 更新进度
+			total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+			progress := int(total * 100 / int64(task.TotalCount))
+			f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), rows)
+
+			return nil
+		})
+	}
+
+	// 等待所有任务完成
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("抓取过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	// 更新任务状态
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	rows.applyTo(task)
+	f.saveTask(task)
+
+	f.logger.Info(spec.name+"抓取完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount))
+
+	return task, nil
+}
+
+// tradeDateUnits 返回按交易日生成抓取单元的 units 函数
+func (f *DataFetcher) tradeDateUnits(startDate, endDate string) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		return f.generateDateRange(ctx, startDate, endDate)
+	}
+}
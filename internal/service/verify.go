@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"stock_data/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultVerifyTolerance 核对日线时默认允许的绝对误差，只用于消除浮点误差
+const DefaultVerifyTolerance = 1e-6
+
+// dailyVerifyFields 核对的日线字段，与 Tushare daily 接口返回的字段一致（振幅为本地计算，不核对）
+var dailyVerifyFields = []struct {
+	name  string
+	value func(d *models.StockDaily) *float64
+}{
+	{"open", func(d *models.StockDaily) *float64 { return d.Open }},
+	{"high", func(d *models.StockDaily) *float64 { return d.High }},
+	{"low", func(d *models.StockDaily) *float64 { return d.Low }},
+	{"close", func(d *models.StockDaily) *float64 { return d.Close }},
+	{"pre_close", func(d *models.StockDaily) *float64 { return d.PreClose }},
+	{"change", func(d *models.StockDaily) *float64 { return d.Change }},
+	{"pct_chg", func(d *models.StockDaily) *float64 { return d.PctChg }},
+	{"vol", func(d *models.StockDaily) *float64 { return d.Vol }},
+	{"amount", func(d *models.StockDaily) *float64 { return d.Amount }},
+}
+
+// VerifyDiff 单个字段的差异，任一方为 null 时 diff 为空
+type VerifyDiff struct {
+	Field   string   `json:"field"`
+	Stored  *float64 `json:"stored"`  // 数据库中的值
+	Tushare *float64 `json:"tushare"` // Tushare 返回的值（已按列小数位数舍入）
+	Diff    *float64 `json:"diff"`    // stored - tushare
+}
+
+// VerifyResult 日线核对结果
+type VerifyResult struct {
+	TSCode    string       `json:"ts_code"`
+	TradeDate string       `json:"trade_date"`
+	Stored    bool         `json:"stored"`  // 数据库中是否有该行
+	Fetched   bool         `json:"fetched"` // Tushare 是否返回该行
+	Match     bool         `json:"match"`   // 两边都有该行且各字段差异均在误差范围内
+	Tolerance float64      `json:"tolerance"`
+	Checked   int          `json:"checked"` // 核对的字段数
+	Diffs     []VerifyDiff `json:"diffs"`
+}
+
+// VerifyDaily 从 Tushare 实时拉取单只股票单日的日线，与数据库中的记录逐字段比较
+// Tushare 的值先按写入时的规则舍入到列声明的小数位数（见 roundDecimals），再与存储值比较，
+// 差值绝对值超过 tolerance 或一方为 null 时记为差异
+func (f *DataFetcher) VerifyDaily(ctx context.Context, tsCode, tradeDate string, tolerance float64) (*VerifyResult, error) {
+	date, err := ParseDate(tradeDate)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式错误: %w", err)
+	}
+
+	result := &VerifyResult{
+		TSCode:    tsCode,
+		TradeDate: tradeDate,
+		Tolerance: tolerance,
+		Diffs:     []VerifyDiff{},
+	}
+
+	var stored models.StockDaily
+	err = f.db.WithContext(ctx).Where("ts_code = ? AND trade_date = ?", tsCode, date).First(&stored).Error
+	switch {
+	case err == nil:
+		result.Stored = true
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("查询日线数据失败: %w", err)
+	}
+
+	if err := f.waitTushare(ctx); err != nil {
+		return nil, err
+	}
+	dailyData, err := f.tushareClient.GetDailyData(bypassRequestCache(ctx), tradeDate, tsCode)
+	if err != nil {
+		return nil, fmt.Errorf("获取日线数据失败: %w", err)
+	}
+
+	var fetched []models.StockDaily
+	for _, data := range dailyData {
+		if data.TSCode == tsCode && data.TradeDate == tradeDate {
+			fetched = append(fetched, models.StockDaily{
+				TSCode:    data.TSCode,
+				TradeDate: date,
+				Open:      data.Open,
+				High:      data.High,
+				Low:       data.Low,
+				Close:     data.Close,
+				PreClose:  data.PreClose,
+				Change:    data.Change,
+				PctChg:    data.PctChg,
+				Vol:       data.Vol,
+				Amount:    data.Amount,
+			})
+			break
+		}
+	}
+	result.Fetched = len(fetched) > 0
+
+	if !result.Stored || !result.Fetched {
+		return result, nil
+	}
+	if err := roundDecimals(fetched, f.config.DecimalRounding); err != nil {
+		return nil, err
+	}
+
+	for _, field := range dailyVerifyFields {
+		storedValue, fetchedValue := field.value(&stored), field.value(&fetched[0])
+		result.Checked++
+		if diff, ok := compareVerifyValues(storedValue, fetchedValue, tolerance); !ok {
+			result.Diffs = append(result.Diffs, VerifyDiff{
+				Field:   field.name,
+				Stored:  storedValue,
+				Tushare: fetchedValue,
+				Diff:    diff,
+			})
+		}
+	}
+	result.Match = len(result.Diffs) == 0
+	return result, nil
+}
+
+// compareVerifyValues 比较两个可能为 null 的值，返回差值及是否在误差范围内，两者都为 null 时视为一致
+func compareVerifyValues(stored, fetched *float64, tolerance float64) (*float64, bool) {
+	if stored == nil || fetched == nil {
+		return nil, stored == nil && fetched == nil
+	}
+	diff := *stored - *fetched
+	return &diff, math.Abs(diff) <= tolerance
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"stock_data/internal/config"
+	"stock_data/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestVerifyDaily 测试按列小数位数舍入后逐字段比较，返回超出误差的字段
+func TestVerifyDaily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TushareRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var items [][]interface{}
+		if req.Params["ts_code"] == "000001.SZ" {
+			// close 10.504 舍入后为 10.50 与存储值一致，vol 与存储值不同，amount 为 null
+			items = append(items, []interface{}{"000001.SZ", "20231201", 10.2, 10.8, 10.1, 10.504, 10.3, 0.2, 1.9417, 123456.0, nil})
+		}
+		dataBytes, _ := json.Marshal(TushareData{
+			Fields: []string{"ts_code", "trade_date", "open", "high", "low", "close", "pre_close", "change", "pct_chg", "vol", "amount"},
+			Items:  items,
+		})
+		json.NewEncoder(w).Encode(TushareResponse{Code: 0, Msg: "success", Data: dataBytes})
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, &models.StockDaily{}, &models.StockLatest{})
+	fetcher.config.DecimalRounding = DecimalRoundingHalfUp
+	fetcher.rateLimiter = NewRateLimiter(0)
+	fetcher.tushareClient = NewTushareClient(&config.TushareConfig{
+		Token:   "test_token",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}, zap.NewNop())
+
+	open, high, low, closePrice, preClose, change, pctChg, vol, amount := 10.2, 10.8, 10.1, 10.5, 10.3, 0.2, 1.9417, 123400.0, 5000.0
+	_, err := fetcher.batchInsertDailyData([]StockDailyData{{
+		TSCode: "000001.SZ", TradeDate: "20231201",
+		Open: &open, High: &high, Low: &low, Close: &closePrice, PreClose: &preClose,
+		Change: &change, PctChg: &pctChg, Vol: &vol, Amount: &amount,
+	}})
+	require.NoError(t, err)
+
+	result, err := fetcher.VerifyDaily(context.Background(), "000001.SZ", "20231201", DefaultVerifyTolerance)
+	require.NoError(t, err)
+	assert.True(t, result.Stored)
+	assert.True(t, result.Fetched)
+	assert.False(t, result.Match)
+	assert.Equal(t, 9, result.Checked)
+	require.Len(t, result.Diffs, 2)
+	assert.Equal(t, "vol", result.Diffs[0].Field)
+	require.NotNil(t, result.Diffs[0].Diff)
+	assert.InDelta(t, -56.0, *result.Diffs[0].Diff, 1e-9)
+	assert.Equal(t, "amount", result.Diffs[1].Field)
+	assert.Nil(t, result.Diffs[1].Tushare)
+	assert.Nil(t, result.Diffs[1].Diff)
+
+	// 误差范围足够大时 vol 视为一致，null 仍记为差异
+	result, err = fetcher.VerifyDaily(context.Background(), "000001.SZ", "20231201", 100)
+	require.NoError(t, err)
+	require.Len(t, result.Diffs, 1)
+	assert.Equal(t, "amount", result.Diffs[0].Field)
+
+	// 数据库和 Tushare 都没有的行
+	result, err = fetcher.VerifyDaily(context.Background(), "000002.SZ", "20231201", DefaultVerifyTolerance)
+	require.NoError(t, err)
+	assert.False(t, result.Stored)
+	assert.False(t, result.Fetched)
+	assert.False(t, result.Match)
+	assert.Empty(t, result.Diffs)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_data/internal/models"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+// DeriveLimitation 由日线聚合周线的局限说明，随接口响应返回
+const DeriveLimitation = "周线由已存储的未复权日线聚合生成，不包含前复权/后复权价格（*_qfq、*_hfq 为空）；pre_close 取本周首个交易日的昨收，与 Tushare 周线的除权昨收可能不同；停牌期间缺失的日线不会被补齐"
+
+// tradeWeek 一个 ISO 周内的交易日
+type tradeWeek struct {
+	Year  int
+	Week  int
+	Dates []time.Time // 本周交易日（从旧到新）
+}
+
+// First 本周第一个交易日
+func (w tradeWeek) First() time.Time {
+	return w.Dates[0]
+}
+
+// Last 本周最后一个交易日，作为周线的 trade_date
+func (w tradeWeek) Last() time.Time {
+	return w.Dates[len(w.Dates)-1]
+}
+
+// DeriveWeekly 根据已存储的日线数据聚合生成周线并写入 stock_weekly，不消耗 Tushare 行情接口额度
+// 开始/结束日期会扩展到所在 ISO 周的完整范围，按交易日历分周；每周在事务中先删除对应股票已有的周线再写入
+func (f *DataFetcher) DeriveWeekly(ctx context.Context, startDate, endDate string) (*models.FetchTask, error) {
+	weeks, err := f.tradeWeeks(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &models.FetchTask{
+		TaskID:     newTaskID("weekly_derive_task_"),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Status:     models.TaskStatusRunning,
+		StartTime:  time.Now(),
+		TotalCount: len(weeks),
+	}
+
+	if err := f.createTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	f.logger.Info("开始由日线聚合周线",
+		zap.String("task_id", task.TaskID),
+		zap.String("start_date", startDate),
+		zap.String("end_date", endDate),
+		zap.Int("weeks", len(weeks)))
+
+	var successCount, failedCount int64
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.Concurrency)
+
+	for _, week := range weeks {
+		week := week
+		g.Go(func() error {
+			// 超时或取消后不再处理剩余的周
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			count, err := f.deriveWeek(ctx, week)
+			if err != nil {
+				atomic.AddInt64(&failedCount, 1)
+				f.logger.Error("聚合周线失败",
+					zap.String("task_id", task.TaskID),
+					zap.String("week_end", week.Last().Format("20060102")),
+					zap.Error(err))
+			} else {
+				atomic.AddInt64(&successCount, 1)
+				f.logger.Debug("周线聚合完成",
+					zap.String("week_end", week.Last().Format("20060102")),
+					zap.Int("count", count))
+			}
+
+			total := atomic.LoadInt64(&successCount) + atomic.LoadInt64(&failedCount)
+			progress := int(total * 100 / int64(task.TotalCount))
+			f.updateTaskProgress(task, progress, int(atomic.LoadInt64(&successCount)), int(atomic.LoadInt64(&failedCount)), nil)
+
+			return nil
+		})
+	}
+
+	waitErr := g.Wait()
+	if waitErr != nil {
+		f.logger.Error("聚合过程出错", zap.String("task_id", task.TaskID), zap.Error(waitErr))
+	}
+
+	now := time.Now()
+	task.EndTime = &now
+	f.finishTask(task, waitErr)
+	task.SuccessCount = int(successCount)
+	task.FailedCount = int(failedCount)
+	f.saveTask(task)
+
+	f.logger.Info("周线聚合完成",
+		zap.String("task_id", task.TaskID),
+		zap.Int64("success", successCount),
+		zap.Int64("failed", failedCount),
+		zap.Duration("elapsed", time.Since(task.StartTime)))
+
+	return task, nil
+}
+
+// tradeWeeks 获取日期范围所覆盖的 ISO 周及每周的交易日
+func (f *DataFetcher) tradeWeeks(ctx context.Context, startDate, endDate string) ([]tradeWeek, error) {
+	start, err := ParseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("开始日期格式错误: %w", err)
+	}
+	end, err := ParseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("结束日期格式错误: %w", err)
+	}
+
+	// 扩展到完整的 ISO 周（周一至周日），保证首尾两周的周线完整
+	start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	end = end.AddDate(0, 0, (7-int(end.Weekday()))%7)
+
+	dates, err := f.generateDateRange(ctx, start.Format("20060102"), end.Format("20060102"))
+	if err != nil {
+		return nil, err
+	}
+	return groupTradeWeeks(dates), nil
+}
+
+// groupTradeWeeks 按 ISO 周对交易日分组，输入需按日期升序
+// 分组键为 ISOWeek 返回的 ISO 年和周数，而不是日历年：2024-12-30、2024-12-31 属于 2025 年第 1 周，
+// 与 2025 年 1 月初的交易日同组；2024-01-01 属于 2024 年第 1 周，不会与 2023 年 12 月末合并
+func groupTradeWeeks(dates []string) []tradeWeek {
+	var weeks []tradeWeek
+	for _, date := range dates {
+		d, err := ParseDate(date)
+		if err != nil {
+			continue
+		}
+		year, week := d.ISOWeek()
+		if n := len(weeks); n > 0 && weeks[n-1].Year == year && weeks[n-1].Week == week {
+			weeks[n-1].Dates = append(weeks[n-1].Dates, d)
+			continue
+		}
+		weeks = append(weeks, tradeWeek{Year: year, Week: week, Dates: []time.Time{d}})
+	}
+	return weeks
+}
+
+// deriveWeek 聚合单个 ISO 周的日线并写入周线表，返回写入的周线条数
+func (f *DataFetcher) deriveWeek(ctx context.Context, week tradeWeek) (int, error) {
+	var daily []models.StockDaily
+	if err := f.db.WithContext(ctx).
+		Where("trade_date BETWEEN ? AND ?", week.First(), week.Last()).
+		Order("ts_code, trade_date").
+		Find(&daily).Error; err != nil {
+		return 0, fmt.Errorf("查询日线数据失败: %w", err)
+	}
+	if len(daily) == 0 {
+		return 0, nil
+	}
+
+	var records []models.StockWeekly
+	tsCodes := make([]string, 0)
+	for i := 0; i < len(daily); {
+		j := i
+		for j < len(daily) && daily[j].TSCode == daily[i].TSCode {
+			j++
+		}
+		records = append(records, aggregateWeeklyBar(daily[i:j], week.Last()))
+		tsCodes = append(tsCodes, daily[i].TSCode)
+		i = j
+	}
+
+	err := f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trade_date BETWEEN ? AND ? AND ts_code IN ?", week.First(), week.Last(), tsCodes).
+			Delete(&models.StockWeekly{}).Error; err != nil {
+			return fmt.Errorf("删除已有周线失败: %w", err)
+		}
+		if err := tx.CreateInBatches(records, f.config.BatchSize).Error; err != nil {
+			return fmt.Errorf("写入周线失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(records), nil
+}
+
+// aggregateWeeklyBar 将同一股票一周内的日线（按日期升序）聚合为周线
+// open 取首个非空开盘价，close 取最后一个非空收盘价，high/low 取极值，vol/amount 求和
+func aggregateWeeklyBar(daily []models.StockDaily, weekEnd time.Time) models.StockWeekly {
+	bar := models.StockWeekly{
+		TSCode:    daily[0].TSCode,
+		TradeDate: weekEnd,
+		EndDate:   weekEnd,
+		PreClose:  daily[0].PreClose,
+	}
+
+	for _, d := range daily {
+		if bar.Open == nil {
+			bar.Open = d.Open
+		}
+		if d.Close != nil {
+			bar.Close = d.Close
+		}
+		if d.High != nil && (bar.High == nil || *d.High > *bar.High) {
+			bar.High = d.High
+		}
+		if d.Low != nil && (bar.Low == nil || *d.Low < *bar.Low) {
+			bar.Low = d.Low
+		}
+		bar.Vol = addFloatPtr(bar.Vol, d.Vol)
+		bar.Amount = addFloatPtr(bar.Amount, d.Amount)
+	}
+
+	if bar.Close != nil && bar.PreClose != nil {
+		change := *bar.Close - *bar.PreClose
+		bar.Change = &change
+		if *bar.PreClose != 0 {
+			pctChg := change / *bar.PreClose * 100
+			bar.PctChg = &pctChg
+		}
+	}
+
+	return bar
+}
+
+// addFloatPtr 累加可空数值，两者均为空时返回 nil
+func addFloatPtr(sum, v *float64) *float64 {
+	if v == nil {
+		return sum
+	}
+	if sum == nil {
+		total := *v
+		return &total
+	}
+	total := *sum + *v
+	return &total
+}